@@ -0,0 +1,349 @@
+package handler
+
+import (
+	"net/http"
+	"spider-go/api/middleware"
+	api_model "spider-go/api/model"
+	"spider-go/asset"
+	"spider-go/domain"
+	"spider-go/logger"
+	"spider-go/model"
+	"spider-go/usecase"
+	"spider-go/utils/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccountManagementHandler struct {
+	accountManagementUsecase domain.AccountManagementUsecase
+	log                      *logger.Logger
+}
+
+func NewAccountManagementHandler(accountManagementUsecase domain.AccountManagementUsecase) *AccountManagementHandler {
+	return &AccountManagementHandler{
+		accountManagementUsecase: accountManagementUsecase,
+		log:                      logger.L().Named("AccountManagementHandler"),
+	}
+}
+
+// =========================================================
+// account list
+// =========================================================
+func (h *AccountManagementHandler) GetAccountListHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.GetAccountListRequester
+	var resp api_model.GetAccountListResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[GetAccountListHandler] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[GetAccountListHandler] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	filter := model.AccountListFilter{
+		Keyword: req.Data.Keyword,
+		Role:    req.Data.Role,
+		Status:  req.Data.Status,
+		Page:    req.Data.Page,
+		Size:    req.Data.Size,
+	}
+
+	accountList, total, err := h.accountManagementUsecase.GetAccountList(ctx, filter)
+	if err != nil {
+		log.Errorf("[GetAccountListHandler] usecase request failed: %+v", err)
+		assetError := h.mapErrorAccountManagement(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Data.AccountList = make([]api_model.AccountInfo, 0, len(accountList))
+	for _, account := range accountList {
+		resp.Data.AccountList = append(resp.Data.AccountList, h.prepareAccountInfo(account))
+	}
+	resp.Data.Total = total
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (h *AccountManagementHandler) prepareAccountInfo(account model.Account) api_model.AccountInfo {
+	status := account.Status
+	if status == "" {
+		status = model.ACCOUNT_STATUS_ACTIVE
+	}
+
+	return api_model.AccountInfo{
+		Username:  account.Username,
+		Title:     account.Title,
+		FirstName: account.FirstName,
+		LastName:  account.LastName,
+		Age:       account.Age,
+		MobileNO:  account.MobileNO,
+		Email:     account.Email,
+		Role:      account.Role,
+		Status:    status,
+	}
+}
+
+// =========================================================
+// enable / disable account
+// =========================================================
+func (h *AccountManagementHandler) UpdateAccountStatusHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.UpdateAccountStatusRequester
+	var resp api_model.UpdateAccountStatusResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[UpdateAccountStatusHandler] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[UpdateAccountStatusHandler] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	loginUser := middleware.GetLoginUser(ctx)
+
+	if err := h.accountManagementUsecase.UpdateAccountStatus(ctx, loginUser, req.Data.Username, req.Data.Status); err != nil {
+		log.Errorf("[UpdateAccountStatusHandler] usecase request failed: %+v", err)
+		assetError := h.mapErrorAccountManagement(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// =========================================================
+// change account role
+// =========================================================
+func (h *AccountManagementHandler) UpdateAccountRoleHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.UpdateAccountRoleRequester
+	var resp api_model.UpdateAccountRoleResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[UpdateAccountRoleHandler] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[UpdateAccountRoleHandler] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	loginUser := middleware.GetLoginUser(ctx)
+
+	if err := h.accountManagementUsecase.UpdateAccountRole(ctx, loginUser, req.Data.Username, req.Data.Role); err != nil {
+		log.Errorf("[UpdateAccountRoleHandler] usecase request failed: %+v", err)
+		assetError := h.mapErrorAccountManagement(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// *********************************************************
+
+// =========================================================
+// update own profile
+// =========================================================
+func (h *AccountManagementHandler) UpdateProfileHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.UpdateProfileRequester
+	var resp api_model.UpdateProfileResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[UpdateProfileHandler] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[UpdateProfileHandler] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	profile := model.AccountProfile{
+		Title:     req.Data.Title,
+		FirstName: req.Data.FirstName,
+		LastName:  req.Data.LastName,
+		Age:       req.Data.Age,
+		MobileNO:  req.Data.MobileNO,
+		Email:     req.Data.Email,
+	}
+
+	loginUser := middleware.GetLoginUser(ctx)
+
+	if err := h.accountManagementUsecase.UpdateProfile(ctx, loginUser.Username, profile); err != nil {
+		log.Errorf("[UpdateProfileHandler] usecase request failed: %+v", err)
+		assetError := h.mapErrorAccountManagement(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// =========================================================
+// change own password
+// =========================================================
+func (h *AccountManagementHandler) ChangePasswordHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.ChangePasswordRequester
+	var resp api_model.ChangePasswordResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[ChangePasswordHandler] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[ChangePasswordHandler] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	loginUser := middleware.GetLoginUser(ctx)
+
+	if err := h.accountManagementUsecase.ChangePassword(ctx, loginUser.Username, req.Data.OldPassword, req.Data.NewPassword, req.Data.ConfirmPassword); err != nil {
+		log.Errorf("[ChangePasswordHandler] usecase request failed: %+v", err)
+		assetError := h.mapErrorAccountManagement(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// =========================================================
+// reset second factor of account
+// =========================================================
+func (h *AccountManagementHandler) ResetMFAHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.ResetMFARequester
+	var resp api_model.ResetMFAResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[ResetMFAHandler] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[ResetMFAHandler] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	loginUser := middleware.GetLoginUser(ctx)
+
+	if err := h.accountManagementUsecase.ResetMFA(ctx, loginUser, req.Data.Username); err != nil {
+		log.Errorf("[ResetMFAHandler] usecase request failed: %+v", err)
+		assetError := h.mapErrorAccountManagement(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// *********************************************************
+
+func (h *AccountManagementHandler) mapErrorAccountManagement(err error) *asset.ErrorCode {
+	switch err {
+	case usecase.ErrorAccountManagementInvalidData:
+		return &asset.E().RequestDataFail
+	case usecase.ErrorAccountManagementAccountNotFound:
+		return &asset.E().AccountNotFound
+	case usecase.ErrorAccountManagementInsufficientRights:
+		return &asset.E().InsufficientUserRights
+	case usecase.ErrorAccountManagementConfirmPasswordNotMatch:
+		return &asset.E().PasswordMatchingError
+	case usecase.ErrorAccountManagementInvalidPassword:
+		return &asset.E().InvalidLoginAccount
+	case usecase.ErrorAccountManagementHashPasswordFail:
+		return &asset.E().HashingError
+	case usecase.ErrorAccountManagementPasswordNotQualify:
+		return &asset.E().PasswordQualifyError
+	case usecase.ErrorAccountManagementMongoConnection:
+		return &asset.E().ErrorSpiderDB
+	case usecase.ErrorAccountManagementTempDataConnection:
+		return &asset.E().ErrorTempDB
+	default:
+		return &asset.E().GeneralSystemError
+	}
+}
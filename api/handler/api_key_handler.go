@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"net/http"
+	"spider-go/api/middleware"
+	api_model "spider-go/api/model"
+	"spider-go/asset"
+	"spider-go/domain"
+	"spider-go/logger"
+	"spider-go/model"
+	"spider-go/usecase"
+	"spider-go/utils/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	apiKeyUsecase domain.APIKeyUsecase
+	log           *logger.Logger
+}
+
+func NewAPIKeyHandler(apiKeyUsecase domain.APIKeyUsecase) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUsecase: apiKeyUsecase,
+		log:           logger.L().Named("APIKeyHandler"),
+	}
+}
+
+// =========================================================
+// create api key
+// =========================================================
+func (h *APIKeyHandler) CreateAPIKeyHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.CreateAPIKeyRequester
+	var resp api_model.CreateAPIKeyResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[CreateAPIKeyHandler] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[CreateAPIKeyHandler] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	loginUser := middleware.GetLoginUser(ctx)
+
+	rawKey, apiKey, err := h.apiKeyUsecase.CreateAPIKey(ctx, loginUser, req.Data.Name, req.Data.Scopes, req.Data.ExpiresAt)
+	if err != nil {
+		log.Errorf("[CreateAPIKeyHandler] usecase request failed: %+v", err)
+		assetError := h.mapErrorAPIKey(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Data.APIKey = rawKey
+	resp.Data.Info = h.prepareAPIKeyInfo(*apiKey)
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// =========================================================
+// api key list
+// =========================================================
+func (h *APIKeyHandler) GetAPIKeyListHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.GetAPIKeyListRequester
+	var resp api_model.GetAPIKeyListResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[GetAPIKeyListHandler] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[GetAPIKeyListHandler] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	apiKeyList, total, err := h.apiKeyUsecase.GetAPIKeyList(ctx, req.Data.Page, req.Data.Size)
+	if err != nil {
+		log.Errorf("[GetAPIKeyListHandler] usecase request failed: %+v", err)
+		assetError := h.mapErrorAPIKey(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Data.APIKeyList = make([]api_model.APIKeyInfo, 0, len(apiKeyList))
+	for _, apiKey := range apiKeyList {
+		resp.Data.APIKeyList = append(resp.Data.APIKeyList, h.prepareAPIKeyInfo(apiKey))
+	}
+	resp.Data.Total = total
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// =========================================================
+// revoke api key
+// =========================================================
+func (h *APIKeyHandler) RevokeAPIKeyHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.RevokeAPIKeyRequester
+	var resp api_model.RevokeAPIKeyResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[RevokeAPIKeyHandler] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[RevokeAPIKeyHandler] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	loginUser := middleware.GetLoginUser(ctx)
+
+	if err := h.apiKeyUsecase.RevokeAPIKey(ctx, loginUser, req.Data.KeyID); err != nil {
+		log.Errorf("[RevokeAPIKeyHandler] usecase request failed: %+v", err)
+		assetError := h.mapErrorAPIKey(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// *********************************************************
+
+func (h *APIKeyHandler) prepareAPIKeyInfo(apiKey model.APIKey) api_model.APIKeyInfo {
+	return api_model.APIKeyInfo{
+		KeyID:      apiKey.KeyID,
+		Name:       apiKey.Name,
+		Scopes:     apiKey.Scopes,
+		CreatedBy:  apiKey.CreatedBy,
+		CreatedAt:  apiKey.CreatedAt,
+		ExpiresAt:  apiKey.ExpiresAt,
+		LastUsedAt: apiKey.LastUsedAt,
+		Revoked:    apiKey.Revoked,
+	}
+}
+
+func (h *APIKeyHandler) mapErrorAPIKey(err error) *asset.ErrorCode {
+	switch err {
+	case usecase.ErrorAPIKeyInvalidData:
+		return &asset.E().RequestDataFail
+	case usecase.ErrorAPIKeyInsufficientRights:
+		return &asset.E().InsufficientUserRights
+	case usecase.ErrorAPIKeyNotFound:
+		return &asset.E().APIKeyNotFound
+	case usecase.ErrorAPIKeyMongoConnection:
+		return &asset.E().ErrorSpiderDB
+	default:
+		return &asset.E().GeneralSystemError
+	}
+}
@@ -19,14 +19,16 @@ const (
 )
 
 type CreateAccoutHandler struct {
-	authUseCase domain.Authorities
-	log         *logger.Logger
+	authUseCase      domain.Authorities
+	getRsaKeyUsecase domain.GetRsaKeyUsecase
+	log              *logger.Logger
 }
 
-func NewCreateAccountHandler(authUsecase domain.Authorities) *CreateAccoutHandler {
+func NewCreateAccountHandler(authUsecase domain.Authorities, getRsaKeyUsecase domain.GetRsaKeyUsecase) *CreateAccoutHandler {
 	return &CreateAccoutHandler{
-		authUseCase: authUsecase,
-		log:         logger.L().Named("CreateAccoutHandler"),
+		authUseCase:      authUsecase,
+		getRsaKeyUsecase: getRsaKeyUsecase,
+		log:              logger.L().Named("CreateAccoutHandler"),
 	}
 }
 
@@ -53,9 +55,20 @@ func (h *CreateAccoutHandler) CreateAccout(ctx *gin.Context) {
 		return
 	}
 
+	// password and confirm password are encrypted by the same public key
+	plainTexts, err := h.getRsaKeyUsecase.DecryptWithRsaKey(ctx, req.Data.SearchKey, req.Data.Password, req.Data.ConfirmPassword)
+	if err != nil {
+		log.Errorf("decrypt password failed: %+v", err)
+		assetError := mapErrorDecryptCredential(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
 	preAccount := h.prepareAccount(req)
 
-	if err := h.authUseCase.CreateAccout(ctx, preAccount, req.Data.Password, req.Data.ConfirmPassword); err != nil {
+	if err := h.authUseCase.CreateAccout(ctx, preAccount, plainTexts[0], plainTexts[1]); err != nil {
 		log.Errorf("usecase request failed: %+v", err)
 		assetError := h.mapErrorCreateAccount(err)
 		resp.Header.ErrorCode = assetError.ErrorCode
@@ -79,7 +92,7 @@ func (h *CreateAccoutHandler) prepareAccount(reqAccount api_model.CreateAccoutRe
 		LastName:  reqAccount.Data.LastName,
 		Age:       reqAccount.Data.Age,
 		MobileNO:  reqAccount.Data.MobileNO,
-		Role:      reqAccount.Data.Role,
+		Email:     reqAccount.Data.Email,
 	}
 	return account
 }
@@ -92,6 +105,10 @@ func (h *CreateAccoutHandler) mapErrorCreateAccount(err error) *asset.ErrorCode
 		return &asset.E().ErrorSpiderDB
 	case usecase.ErrorAuthoritiesHashPasswordFail:
 		return &asset.E().HashingError
+	case usecase.ErrorAuthoritiesPasswordNotQualify:
+		return &asset.E().PasswordQualifyError
+	case usecase.ErrorAuthoritiesUsernameNotQualify:
+		return &asset.E().UsernameQualifyError
 	default:
 		return &asset.E().GeneralSystemError
 	}
@@ -68,3 +68,19 @@ func (h *RsaHandler) mappingRequestRsaPublicKey(err error) *asset.ErrorCode {
 		return &asset.E().GeneralSystemError
 	}
 }
+
+// mapErrorDecryptCredential map error of decrypt credential that encrypted by rsa public key
+func mapErrorDecryptCredential(err error) *asset.ErrorCode {
+	switch err {
+	case usecase.ErrorRSAKeyExpired:
+		return &asset.E().RSAKeyExpired
+	case usecase.ErrorRSAKeyReplayed:
+		return &asset.E().RSAKeyReplayed
+	case usecase.ErrorDecryptRSA:
+		return &asset.E().DecriptionError
+	case usecase.ErrorGetRSAKey:
+		return &asset.E().ErrorTempDB
+	default:
+		return &asset.E().GeneralSystemError
+	}
+}
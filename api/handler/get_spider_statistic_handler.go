@@ -2,6 +2,7 @@ package handler
 
 import (
 	"net/http"
+	"spider-go/api/middleware"
 	api_model "spider-go/api/model"
 	"spider-go/asset"
 	"spider-go/domain"
@@ -13,16 +14,18 @@ import (
 )
 
 type GetSpiderStatisticsHandler struct {
-	spiderStatisticsUsecase domain.StatisticsUsecase
-	getFamilyListUsecase    domain.GetFamilyListUsecase
-	log                     *logger.Logger
+	spiderStatisticsUsecase        domain.StatisticsUsecase
+	getFamilyListUsecase           domain.GetFamilyListUsecase
+	spiderStatisticsRebuildUsecase domain.SpiderStatisticsRebuildUsecase
+	log                            *logger.Logger
 }
 
-func NewGetSpiderStatisricsHandler(spiderStatisticsUsecase domain.StatisticsUsecase, getFamilyListUsecase domain.GetFamilyListUsecase) *GetSpiderStatisticsHandler {
+func NewGetSpiderStatisricsHandler(spiderStatisticsUsecase domain.StatisticsUsecase, getFamilyListUsecase domain.GetFamilyListUsecase, spiderStatisticsRebuildUsecase domain.SpiderStatisticsRebuildUsecase) *GetSpiderStatisticsHandler {
 	return &GetSpiderStatisticsHandler{
-		spiderStatisticsUsecase: spiderStatisticsUsecase,
-		getFamilyListUsecase:    getFamilyListUsecase,
-		log:                     logger.L().Named("GetSpiderStatisticsHandler"),
+		spiderStatisticsUsecase:        spiderStatisticsUsecase,
+		getFamilyListUsecase:           getFamilyListUsecase,
+		spiderStatisticsRebuildUsecase: spiderStatisticsRebuildUsecase,
+		log:                            logger.L().Named("GetSpiderStatisticsHandler"),
 	}
 }
 
@@ -78,7 +81,7 @@ func (h *GetSpiderStatisticsHandler) GetFamilyListhandler(ctx *gin.Context) {
 		return
 	}
 
-	familyList, err := h.getFamilyListUsecase.Execute(ctx, req.Data.Page, req.Data.Size)
+	familyList, total, err := h.getFamilyListUsecase.Execute(ctx, req.Data.SortBy, req.Data.Page, req.Data.Size)
 	if err != nil {
 		log.Errorf("[GetFamilyListhandler] usecase request failed: %+v", err)
 		assetError := h.mapGetFamilyListhandlerError(err)
@@ -91,6 +94,7 @@ func (h *GetSpiderStatisticsHandler) GetFamilyListhandler(ctx *gin.Context) {
 	resp.Header.ErrorCode = SUCCESS_CODE
 	resp.Header.Message = SUCCESS_MESSAGE
 	resp.Data.FamilyList = familyList
+	resp.Data.Total = total
 
 	ctx.JSON(http.StatusOK, resp)
 
@@ -98,9 +102,52 @@ func (h *GetSpiderStatisticsHandler) GetFamilyListhandler(ctx *gin.Context) {
 
 func (h *GetSpiderStatisticsHandler) mapGetFamilyListhandlerError(err error) *asset.ErrorCode {
 	switch err {
+	case usecase.ErrorGetFamilyListInvalidSortBy:
+		return &asset.E().InvalidFamilyListSort
 	case usecase.ErrorMongoTechnicalFail:
 		return &asset.E().ErrorSpiderDB
 	default:
 		return &asset.E().GeneralSystemError
 	}
 }
+
+// RebuildSpiderStatisticsHandler recompute statistics from spider info, it is dry run unless apply is set
+func (h *GetSpiderStatisticsHandler) RebuildSpiderStatisticsHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.RebuildSpiderStatisticsRequester
+	var resp api_model.RebuildSpiderStatisticsResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[RebuildSpiderStatisticsHandler] should bind request failed: %+v", err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, err)
+		return
+	}
+
+	log.Infof("[RebuildSpiderStatisticsHandler] `%v` rebuild spider statistics, apply: %v", middleware.GetLoginUser(ctx).Username, req.Data.Apply)
+
+	report, err := h.spiderStatisticsRebuildUsecase.RebuildSpiderStatistics(ctx, !req.Data.Apply)
+	if err != nil {
+		log.Errorf("[RebuildSpiderStatisticsHandler] usecase request failed: %+v", err)
+		assetError := h.mapRebuildSpiderStatisticsError(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+	resp.Data = report
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (h *GetSpiderStatisticsHandler) mapRebuildSpiderStatisticsError(err error) *asset.ErrorCode {
+	switch err {
+	case usecase.ErrorSpiderStatisticsRebuildMongoConnection:
+		return &asset.E().ErrorSpiderDB
+	default:
+		return &asset.E().GeneralSystemError
+	}
+}
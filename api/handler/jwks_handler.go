@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+	api_model "spider-go/api/model"
+	"spider-go/asset"
+	"spider-go/domain"
+	"spider-go/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// max age of jwks response, verifier should fetch again when found unknown kid
+const JWKS_CACHE_CONTROL = "public, max-age=300"
+
+type JWKSHandler struct {
+	signingKeyUsecase domain.SigningKeyUsecase
+	log               *logger.Logger
+}
+
+func NewJWKSHandler(signingKeyUsecase domain.SigningKeyUsecase) *JWKSHandler {
+	return &JWKSHandler{
+		signingKeyUsecase: signingKeyUsecase,
+		log:               logger.L().Named("JWKSHandler"),
+	}
+}
+
+// GetJWKSHandler response standard json web key set, it is not wrap with header and data
+func (h *JWKSHandler) GetJWKSHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	jwks, err := h.signingKeyUsecase.GetJWKS(ctx)
+	if err != nil {
+		log.Errorf("[GetJWKSHandler] usecase request failed: %+v", err)
+		var resp api_model.GetJWKSErrorResponser
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+
+	ctx.Header("Cache-Control", JWKS_CACHE_CONTROL)
+	ctx.JSON(http.StatusOK, jwks)
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"fmt"
 	"net/http"
+	"spider-go/api/middleware"
 	api_model "spider-go/api/model"
 	"spider-go/asset"
 	"spider-go/domain"
@@ -18,12 +19,14 @@ import (
 
 type LoginHandler struct {
 	AuthoritiesUsecase domain.Authorities
+	GetRsaKeyUsecase   domain.GetRsaKeyUsecase
 	log                *logger.Logger
 }
 
-func NewLoginHandler(authoritailUsecase domain.Authorities) *LoginHandler {
+func NewLoginHandler(authoritailUsecase domain.Authorities, getRsaKeyUsecase domain.GetRsaKeyUsecase) *LoginHandler {
 	return &LoginHandler{
 		AuthoritiesUsecase: authoritailUsecase,
+		GetRsaKeyUsecase:   getRsaKeyUsecase,
 		log:                logger.L().Named("LoginHandler"),
 	}
 }
@@ -52,7 +55,18 @@ func (h *LoginHandler) Login(ctx *gin.Context) {
 		return
 	}
 
-	accountInfo, token, err := h.AuthoritiesUsecase.Login(ctx, req.Data.Username, req.Data.Password)
+	// password is encrypted by public key of search key
+	plainTexts, err := h.GetRsaKeyUsecase.DecryptWithRsaKey(ctx, req.Data.SearchKey, req.Data.Password)
+	if err != nil {
+		log.Errorf("decrypt password failed: %+v", err)
+		assetError := mapErrorDecryptCredential(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	accountInfo, tokenPair, challenge, err := h.AuthoritiesUsecase.Login(ctx, req.Data.Username, plainTexts[0], ctx.ClientIP())
 	if err != nil {
 		log.Errorf("usecase request failed: %+v", err)
 		assetError := h.mapErrorLogin(err)
@@ -62,17 +76,26 @@ func (h *LoginHandler) Login(ctx *gin.Context) {
 		return
 	}
 
-	resp.Data = h.prepareAccountInfoResponse(*accountInfo, token)
+	if challenge != nil {
+		resp.Data.MFA = &api_model.MFAChallengeInfo{
+			MFAToken:   challenge.Token,
+			OtpauthURI: challenge.EnrollURI,
+			ExpiresAt:  challenge.ExpiresAt,
+		}
+	} else {
+		resp.Data = h.prepareAccountInfoResponse(*accountInfo, *tokenPair)
+	}
 
 	resp.Header.ErrorCode = SUCCESS_CODE
 	resp.Header.Message = SUCCESS_MESSAGE
 
-	log.Infof("response: %+v", resp)
+	// data of response has access token, refresh token and mfa token, only header is logged
+	log.Infof("login of user `%v` success, mfa required: %v, response header: %+v", req.Data.Username, challenge != nil, resp.Header)
 
 	ctx.JSON(http.StatusOK, resp)
 }
 
-func (h *LoginHandler) prepareAccountInfoResponse(accountInfo model.Account, token string) (respData api_model.LoginInfo) {
+func (h *LoginHandler) prepareAccountInfoResponse(accountInfo model.Account, tokenPair model.TokenPair) (respData api_model.LoginInfo) {
 	respData = api_model.LoginInfo{
 		User: api_model.User{
 			ID:        uuid.GernerateUUID32(),
@@ -82,7 +105,8 @@ func (h *LoginHandler) prepareAccountInfoResponse(accountInfo model.Account, tok
 			CreatedAt: time.Now(),
 		},
 		BackendToken: api_model.BackendToken{
-			Token: token,
+			Token:        tokenPair.AccessToken,
+			RefreshToken: tokenPair.RefreshToken,
 		},
 	}
 
@@ -93,13 +117,209 @@ func (h *LoginHandler) mapErrorLogin(err error) *asset.ErrorCode {
 	switch err {
 	case usecase.ErrorAuthoritiesInvalidPassword, usecase.ErrorAuthoritiesFindAccountNotFound:
 		return &asset.E().InvalidLoginAccount
+	case usecase.ErrorAuthoritiesAccountDisabled:
+		return &asset.E().AccountDisabled
+	case usecase.ErrorAuthoritiesAccountLocked:
+		return &asset.E().AccountLocked
+	case usecase.ErrorAuthoritiesInvalidMFAToken:
+		return &asset.E().InvalidMFAToken
+	case usecase.ErrorAuthoritiesInvalidMFACode:
+		return &asset.E().InvalidMFACode
+	case usecase.ErrorAuthoritiesMongoConnection:
+		return &asset.E().ErrorSpiderDB
+	case usecase.ErrorAuthoritiesTempDataConnection:
+		return &asset.E().ErrorTempDB
+	default:
+		return &asset.E().GeneralSystemError
+	}
+}
+
+// =========================================================
+// verify second factor of login
+// =========================================================
+func (h *LoginHandler) VerifyMFA(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.VerifyMFARequester
+	var resp api_model.LoginResponse
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[VerifyMFA] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[VerifyMFA] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	accountInfo, tokenPair, recoveryCodes, err := h.AuthoritiesUsecase.VerifyMFA(ctx, req.Data.MFAToken, req.Data.Code, ctx.ClientIP())
+	if err != nil {
+		log.Errorf("[VerifyMFA] usecase request failed: %+v", err)
+		assetError := h.mapErrorLogin(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Data = h.prepareAccountInfoResponse(*accountInfo, *tokenPair)
+	resp.Data.RecoveryCodes = recoveryCodes
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// =========================================================
+// refresh token
+// =========================================================
+func (h *LoginHandler) RefreshToken(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.RefreshTokenRequester
+	var resp api_model.RefreshTokenResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[RefreshToken] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[RefreshToken] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	tokenPair, err := h.AuthoritiesUsecase.RefreshToken(ctx, req.Data.RefreshToken)
+	if err != nil {
+		log.Errorf("[RefreshToken] usecase request failed: %+v", err)
+		assetError := h.mapErrorRefreshToken(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Data = api_model.BackendToken{
+		Token:        tokenPair.AccessToken,
+		RefreshToken: tokenPair.RefreshToken,
+	}
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (h *LoginHandler) mapErrorRefreshToken(err error) *asset.ErrorCode {
+	switch err {
+	case usecase.ErrorAuthoritiesInvalidRefreshToken:
+		return &asset.E().InvalidRefreshToken
+	case usecase.ErrorAuthoritiesRefreshTokenReused:
+		return &asset.E().RefreshTokenReused
+	case usecase.ErrorAuthoritiesAccountDisabled:
+		return &asset.E().AccountDisabled
 	case usecase.ErrorAuthoritiesMongoConnection:
 		return &asset.E().ErrorSpiderDB
+	case usecase.ErrorAuthoritiesTempDataConnection:
+		return &asset.E().ErrorTempDB
 	default:
 		return &asset.E().GeneralSystemError
 	}
 }
 
+// *************************************************
+
+// =========================================================
+// logout
+// =========================================================
+func (h *LoginHandler) Logout(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var resp api_model.LogoutResponser
+
+	loginUser := middleware.GetLoginUser(ctx)
+
+	if err := h.AuthoritiesUsecase.Logout(ctx, loginUser); err != nil {
+		log.Errorf("[Logout] usecase request failed: %+v", err)
+		assetError := h.mapErrorRevokeSession(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// =========================================================
+// revoke all sessions of user
+// =========================================================
+func (h *LoginHandler) RevokeUserSessions(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.RevokeUserSessionsRequester
+	var resp api_model.RevokeUserSessionsResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[RevokeUserSessions] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[RevokeUserSessions] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	loginUser := middleware.GetLoginUser(ctx)
+
+	if err := h.AuthoritiesUsecase.RevokeAllSessions(ctx, req.Data.Username, loginUser.Username); err != nil {
+		log.Errorf("[RevokeUserSessions] usecase request failed: %+v", err)
+		assetError := h.mapErrorRevokeSession(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (h *LoginHandler) mapErrorRevokeSession(err error) *asset.ErrorCode {
+	switch err {
+	case usecase.ErrorAuthoritiesTempDataConnection:
+		return &asset.E().ErrorTempDB
+	default:
+		return &asset.E().GeneralSystemError
+	}
+}
+
+// *************************************************
+
 func (h *LoginHandler) VerifyLogin(ctx *gin.Context) {
 
 	resp := api_model.VerifyLoginResponser{
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"net/http"
+	"spider-go/api/middleware"
+	api_model "spider-go/api/model"
+	"spider-go/asset"
+	"spider-go/domain"
+	"spider-go/logger"
+	"spider-go/usecase"
+	"spider-go/utils/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MFAHandler struct {
+	mfaUsecase domain.MFAUsecase
+	log        *logger.Logger
+}
+
+func NewMFAHandler(mfaUsecase domain.MFAUsecase) *MFAHandler {
+	return &MFAHandler{
+		mfaUsecase: mfaUsecase,
+		log:        logger.L().Named("MFAHandler"),
+	}
+}
+
+// =========================================================
+// enroll own second factor
+// =========================================================
+func (h *MFAHandler) EnrollMFAHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var resp api_model.EnrollMFAResponser
+
+	loginUser := middleware.GetLoginUser(ctx)
+
+	otpauthURI, secret, err := h.mfaUsecase.EnrollMFA(ctx, loginUser.Username)
+	if err != nil {
+		log.Errorf("[EnrollMFAHandler] usecase request failed: %+v", err)
+		assetError := h.mapErrorMFA(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Data = api_model.EnrollMFAResponseData{
+		OtpauthURI: otpauthURI,
+		Secret:     secret,
+	}
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// =========================================================
+// activate own second factor
+// =========================================================
+func (h *MFAHandler) ActivateMFAHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.ActivateMFARequester
+	var resp api_model.ActivateMFAResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[ActivateMFAHandler] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[ActivateMFAHandler] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	loginUser := middleware.GetLoginUser(ctx)
+
+	recoveryCodes, err := h.mfaUsecase.ActivateMFA(ctx, loginUser.Username, req.Data.Code)
+	if err != nil {
+		log.Errorf("[ActivateMFAHandler] usecase request failed: %+v", err)
+		assetError := h.mapErrorMFA(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Data.RecoveryCodes = recoveryCodes
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// =========================================================
+// disable own second factor
+// =========================================================
+func (h *MFAHandler) DisableMFAHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.DisableMFARequester
+	var resp api_model.DisableMFAResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[DisableMFAHandler] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[DisableMFAHandler] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	loginUser := middleware.GetLoginUser(ctx)
+
+	if err := h.mfaUsecase.DisableMFA(ctx, loginUser.Username, req.Data.Code); err != nil {
+		log.Errorf("[DisableMFAHandler] usecase request failed: %+v", err)
+		assetError := h.mapErrorMFA(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// *********************************************************
+
+func (h *MFAHandler) mapErrorMFA(err error) *asset.ErrorCode {
+	switch err {
+	case usecase.ErrorMFAInvalidCode:
+		return &asset.E().InvalidMFACode
+	case usecase.ErrorMFAAlreadyEnabled:
+		return &asset.E().MFAAlreadyEnabled
+	case usecase.ErrorMFANotEnabled:
+		return &asset.E().MFANotEnabled
+	case usecase.ErrorMFARequiredByRole:
+		return &asset.E().MFARequiredByRole
+	case usecase.ErrorMFAEnrollExpired:
+		return &asset.E().MFAEnrollExpired
+	case usecase.ErrorMFAAccountNotFound:
+		return &asset.E().AccountNotFound
+	case usecase.ErrorMFAMongoConnection:
+		return &asset.E().ErrorSpiderDB
+	case usecase.ErrorMFATempDataConnection:
+		return &asset.E().ErrorTempDB
+	default:
+		return &asset.E().GeneralSystemError
+	}
+}
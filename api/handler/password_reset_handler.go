@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"net/http"
+	api_model "spider-go/api/model"
+	"spider-go/asset"
+	"spider-go/domain"
+	"spider-go/logger"
+	"spider-go/usecase"
+	"spider-go/utils/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PasswordResetHandler struct {
+	passwordResetUsecase domain.PasswordResetUsecase
+	getRsaKeyUsecase     domain.GetRsaKeyUsecase
+	log                  *logger.Logger
+}
+
+func NewPasswordResetHandler(passwordResetUsecase domain.PasswordResetUsecase, getRsaKeyUsecase domain.GetRsaKeyUsecase) *PasswordResetHandler {
+	return &PasswordResetHandler{
+		passwordResetUsecase: passwordResetUsecase,
+		getRsaKeyUsecase:     getRsaKeyUsecase,
+		log:                  logger.L().Named("PasswordResetHandler"),
+	}
+}
+
+// =========================================================
+// request password reset
+// =========================================================
+func (h *PasswordResetHandler) RequestPasswordResetHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.RequestPasswordResetRequester
+	var resp api_model.RequestPasswordResetResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[RequestPasswordResetHandler] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[RequestPasswordResetHandler] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	if err := h.passwordResetUsecase.RequestPasswordReset(ctx, req.Data.Username); err != nil {
+		log.Errorf("[RequestPasswordResetHandler] usecase request failed: %+v", err)
+		assetError := h.mapErrorPasswordReset(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// =========================================================
+// confirm password reset
+// =========================================================
+func (h *PasswordResetHandler) ConfirmPasswordResetHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.ConfirmPasswordResetRequester
+	var resp api_model.ConfirmPasswordResetResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[ConfirmPasswordResetHandler] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[ConfirmPasswordResetHandler] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	// new password and confirm password are encrypted by the same public key
+	plainTexts, err := h.getRsaKeyUsecase.DecryptWithRsaKey(ctx, req.Data.SearchKey, req.Data.NewPassword, req.Data.ConfirmPassword)
+	if err != nil {
+		log.Errorf("[ConfirmPasswordResetHandler] decrypt password failed: %+v", err)
+		assetError := mapErrorDecryptCredential(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	if err := h.passwordResetUsecase.ConfirmPasswordReset(ctx, req.Data.Token, plainTexts[0], plainTexts[1]); err != nil {
+		log.Errorf("[ConfirmPasswordResetHandler] usecase request failed: %+v", err)
+		assetError := h.mapErrorPasswordReset(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// *********************************************************
+
+func (h *PasswordResetHandler) mapErrorPasswordReset(err error) *asset.ErrorCode {
+	switch err {
+	case usecase.ErrorPasswordResetInvalidToken:
+		return &asset.E().InvalidResetToken
+	case usecase.ErrorPasswordResetConfirmPasswordNotMatch:
+		return &asset.E().PasswordMatchingError
+	case usecase.ErrorPasswordResetPasswordNotQualify:
+		return &asset.E().PasswordQualifyError
+	case usecase.ErrorPasswordResetHashPasswordFail:
+		return &asset.E().HashingError
+	case usecase.ErrorPasswordResetNotifyFail:
+		return &asset.E().SendNotificationError
+	case usecase.ErrorPasswordResetMongoConnection:
+		return &asset.E().ErrorSpiderDB
+	case usecase.ErrorPasswordResetTempDataConnection:
+		return &asset.E().ErrorTempDB
+	default:
+		return &asset.E().GeneralSystemError
+	}
+}
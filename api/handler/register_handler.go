@@ -2,6 +2,7 @@ package handler
 
 import (
 	"net/http"
+	"spider-go/api/middleware"
 	api_model "spider-go/api/model"
 	"spider-go/asset"
 	"spider-go/domain"
@@ -48,7 +49,9 @@ func (h *RegisterHandler) RegisterHandler(ctx *gin.Context) {
 		return
 	}
 
-	spiderUUID, err := h.registerSpiderUsecase.Register(ctx, req.Data, req.Header.Username)
+	loginUser := middleware.GetLoginUser(ctx)
+
+	spiderUUID, status, err := h.registerSpiderUsecase.Register(ctx, req.Data, loginUser)
 	if err != nil {
 		assetError := h.mapRegisterStatisticsInfoHandlerError(err)
 		resp.Header.ErrorCode = assetError.ErrorCode
@@ -60,6 +63,7 @@ func (h *RegisterHandler) RegisterHandler(ctx *gin.Context) {
 
 	resp.Data = req.Data
 	resp.Data.SpiderUUID = spiderUUID
+	resp.Data.Status = status
 	resp.Header.ErrorCode = SUCCESS_CODE
 	resp.Header.Message = SUCCESS_MESSAGE
 
@@ -2,10 +2,11 @@ package handler
 
 import (
 	api_model "spider-go/api/model"
+	"spider-go/domain"
 	"spider-go/model"
 )
 
-func mapSpiderInfoModel(data *model.SpiderInfo) *api_model.SpiderInfo {
+func mapSpiderInfoModel(data *model.SpiderInfo, imageURLSigner domain.ImageURLSigner) *api_model.SpiderInfo {
 
 	var address []api_model.Address
 
@@ -33,6 +34,9 @@ func mapSpiderInfoModel(data *model.SpiderInfo) *api_model.SpiderInfo {
 		address = append(address, tempAddress)
 	}
 
+	// caller may pass range variable of list, so version is copied instead of pointing into data
+	version := data.Version
+
 	RespSpiderInfo := api_model.SpiderInfo{
 		SpiderUUID:     data.SpiderUUID,
 		Family:         data.Family,
@@ -49,8 +53,33 @@ func mapSpiderInfoModel(data *model.SpiderInfo) *api_model.SpiderInfo {
 		Designate:      data.Designate,
 		Address:        address,
 		Paper:          data.Paper,
-		Image:          data.ImageFile,
+		Image:          mapSpiderImageURL(data, imageURLSigner),
+		ImageFile:      data.ImageFile,
+		Status:         data.Status,
+		Version:        &version,
+	}
+
+	if data.Review != nil {
+		RespSpiderInfo.Review = &api_model.Review{
+			Comment:    data.Review.Comment,
+			ReviewedBy: data.Review.ReviewedBy,
+			ReviewedAt: data.Review.ReviewedAt,
+		}
 	}
 
 	return &RespSpiderInfo
 }
+
+// image of spider info that is not public is given as signed url, it can be read without login until url expire
+func mapSpiderImageURL(data *model.SpiderInfo, imageURLSigner domain.ImageURLSigner) []string {
+	if len(data.ImageFile) == 0 {
+		return nil
+	}
+
+	imageURLs := make([]string, 0, len(data.ImageFile))
+	for _, imageName := range data.ImageFile {
+		imageURLs = append(imageURLs, imageURLSigner.ImageURL(data.SpiderUUID, imageName, data.IsPublic()))
+	}
+
+	return imageURLs
+}
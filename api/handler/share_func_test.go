@@ -0,0 +1,52 @@
+package handler
+
+import (
+	mock_domain "spider-go/domain/mock"
+	"spider-go/model"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+)
+
+// ======================================================================
+// TestMapSpiderInfoModel
+// ======================================================================
+
+// list handler map range variable of list, every record keep its own version
+func TestMapSpiderInfoModel_VersionOfList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	imageURLSigner := mock_domain.NewMockImageURLSigner(ctrl)
+
+	spiderInfoList := []model.SpiderInfo{
+		{SpiderUUID: "SPIDER_1", Version: 1},
+		{SpiderUUID: "SPIDER_2", Version: 2},
+		{SpiderUUID: "SPIDER_3", Version: 3},
+	}
+
+	var versions []*int64
+	for _, spiderInfo := range spiderInfoList {
+		versions = append(versions, mapSpiderInfoModel(&spiderInfo, imageURLSigner).Version)
+	}
+
+	for i, version := range versions {
+		if version == nil {
+			t.Errorf("mapSpiderInfoModel() version of `%v` is nil", spiderInfoList[i].SpiderUUID)
+			continue
+		}
+		if *version != spiderInfoList[i].Version {
+			t.Errorf("mapSpiderInfoModel() version of `%v` = %v, want %v", spiderInfoList[i].SpiderUUID, *version, spiderInfoList[i].Version)
+		}
+	}
+
+	// changing record after mapping does not change response
+	spiderInfo := model.SpiderInfo{SpiderUUID: "SPIDER_4", Version: 4}
+	resp := mapSpiderInfoModel(&spiderInfo, imageURLSigner)
+	spiderInfo.Version = 5
+	if *resp.Version != 4 {
+		t.Errorf("mapSpiderInfoModel() version = %v, want 4", *resp.Version)
+	}
+}
+
+// **********************************************************************
@@ -2,7 +2,9 @@ package handler
 
 import (
 	"fmt"
+	"io"
 	"net/http"
+	"spider-go/api/middleware"
 	api_model "spider-go/api/model"
 	"spider-go/asset"
 	"spider-go/domain"
@@ -10,6 +12,7 @@ import (
 	"spider-go/model"
 	"spider-go/usecase"
 	"spider-go/utils/validator"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,13 +20,15 @@ import (
 type SpiderInfoHandler struct {
 	spiderInfoUsecase      domain.SpiderInfoUsecase
 	thaiGeographiesUsecase domain.ThaiGeographiesUsecase
+	imageURLSigner         domain.ImageURLSigner
 	log                    *logger.Logger
 }
 
-func NewSpiderInfoHandler(spiderInfoUsecase domain.SpiderInfoUsecase, thaiGeographiesUsecase domain.ThaiGeographiesUsecase) *SpiderInfoHandler {
+func NewSpiderInfoHandler(spiderInfoUsecase domain.SpiderInfoUsecase, thaiGeographiesUsecase domain.ThaiGeographiesUsecase, imageURLSigner domain.ImageURLSigner) *SpiderInfoHandler {
 	return &SpiderInfoHandler{
 		spiderInfoUsecase:      spiderInfoUsecase,
 		thaiGeographiesUsecase: thaiGeographiesUsecase,
+		imageURLSigner:         imageURLSigner,
 		log:                    logger.L().Named("SpiderInfoHandler"),
 	}
 }
@@ -64,16 +69,8 @@ func (h *SpiderInfoHandler) GetOneSpiderInfoHandler(ctx *gin.Context) {
 		return
 	}
 
-	var spiderInfo *model.SpiderInfo
-	var err error
-	err = nil
-	// if req.Header.Username == "" || req.Header.UserKey == "" {
-	// 	spiderInfo, err = h.spiderInfoUsecase.GetSpiderInfoUsecase(ctx, req.Data.SpiderUUID, "", "", false)
-	// } else {
-	// 	spiderInfo, err = h.spiderInfoUsecase.GetSpiderInfoUsecase(ctx, req.Data.SpiderUUID, req.Header.Username, req.Header.UserKey, true)
-	// }
-
-	spiderInfo, err = h.spiderInfoUsecase.GetSpiderInfoUsecase(ctx, req.Data.SpiderUUID, req.Header.Username)
+	// this handler is used in both public and login route, login user is nil when not login
+	spiderInfo, err := h.spiderInfoUsecase.GetSpiderInfoUsecase(ctx, req.Data.SpiderUUID, middleware.GetLoginUser(ctx))
 
 	if err != nil {
 		log.Errorf("[GetOneSpiderInfoHandler] get spider info usecase failed, error: %v", err)
@@ -86,7 +83,7 @@ func (h *SpiderInfoHandler) GetOneSpiderInfoHandler(ctx *gin.Context) {
 
 	resp.Header.ErrorCode = SUCCESS_CODE
 	resp.Header.Message = ""
-	resp.Data = *mapSpiderInfoModel(spiderInfo)
+	resp.Data = *mapSpiderInfoModel(spiderInfo, h.imageURLSigner)
 	ctx.JSON(http.StatusOK, resp)
 
 }
@@ -137,12 +134,13 @@ func (h *SpiderInfoHandler) GetSpiderImagesHandler(ctx *gin.Context) {
 		return
 	}
 
-	spiderImageEndcode, err := h.spiderInfoUsecase.GetSpiderImagesUsecase(ctx, req.Data.SpiderImageList)
+	spiderImageEndcode, err := h.spiderInfoUsecase.GetSpiderImagesUsecase(ctx, req.Data.SpiderUUID, req.Data.SpiderImageList)
 	if err != nil {
-		// it's only one error, no need to map
-		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
-		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
-		ctx.AbortWithStatusJSON(asset.E().GeneralSystemError.StatusCode, resp)
+		log.Errorf("[GetSpiderImagesHandler] get spider images usecase failed, error: %v", err)
+		assetErr := h.mapGetSpiderImagesHandlerError(err)
+		resp.Header.ErrorCode = assetErr.ErrorCode
+		resp.Header.Message = assetErr.ErrorMessageEN
+		ctx.JSON(assetErr.StatusCode, resp)
 		return
 	}
 
@@ -153,6 +151,21 @@ func (h *SpiderInfoHandler) GetSpiderImagesHandler(ctx *gin.Context) {
 
 }
 
+func (h *SpiderInfoHandler) mapGetSpiderImagesHandlerError(err error) *asset.ErrorCode {
+	switch err {
+	case usecase.ErrorSpiderInfoUsecaseSpiderNotFound:
+		return &asset.E().SpiderNotFound
+	case usecase.ErrorSpiderInfoUsecaseAccountInsufficientPermissions:
+		return &asset.E().InsufficientUserRights
+	case usecase.ErrorSpiderInfoUsecaseImageNotFound:
+		return &asset.E().SpiderImageNotFound
+	case usecase.ErrorMongoConnection:
+		return &asset.E().ErrorSpiderDB
+	default:
+		return &asset.E().GeneralSystemError
+	}
+}
+
 func (h *SpiderInfoHandler) mapGetSpiderImagesHandler(SpiderImageEndcodeList []model.SpiderImageList) []api_model.SpiderImageList {
 	var responseData []api_model.SpiderImageList
 	for _, data := range SpiderImageEndcodeList {
@@ -167,6 +180,159 @@ func (h *SpiderInfoHandler) mapGetSpiderImagesHandler(SpiderImageEndcodeList []m
 
 // *************************************************
 
+// =========================================================
+// get spider image file
+// =========================================================
+
+// GetSpiderImageFileHandler stream image with conditional and range request support,
+// image of spider info that is not public require expires and signature of signed url,
+// resized image is selected by size query, e.g. size=thumbnail
+func (h *SpiderInfoHandler) GetSpiderImageFileHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var resp api_model.GetSpiderImageFileResponser
+
+	param := model.OpenSpiderImageParam{
+		SpiderUUID: ctx.Param("spider_uuid"),
+		ImageName:  ctx.Param("image_name"),
+		Size:       ctx.Query("size"),
+		Expires:    ctx.Query("expires"),
+		Signature:  ctx.Query("signature"),
+	}
+
+	imageFile, err := h.spiderInfoUsecase.OpenSpiderImage(ctx, param)
+	if err != nil {
+		log.Errorf("[GetSpiderImageFileHandler] open spider image usecase failed, error: %v", err)
+		assetErr := h.mapGetSpiderImageFileHandlerError(err)
+		resp.Header.ErrorCode = assetErr.ErrorCode
+		resp.Header.Message = assetErr.ErrorMessageEN
+		ctx.AbortWithStatusJSON(assetErr.StatusCode, resp)
+		return
+	}
+	defer imageFile.Content.Close()
+
+	// content type and etag are set before serve content so it is not sniffed and conditional request is checked
+	ctx.Header("Content-Type", imageFile.Object.ContentType)
+	if imageFile.Object.ETag != "" {
+		ctx.Header("ETag", imageFile.Object.ETag)
+	}
+
+	if imageFile.Public {
+		ctx.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int64(imageFile.MaxAge.Seconds())))
+	} else {
+		ctx.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", int64(imageFile.MaxAge.Seconds())))
+	}
+
+	// storage content is seekable (file or s3 object that is read by ranged get),
+	// so conditional and range request are served without reading whole image to memory
+	if content, ok := imageFile.Content.(io.ReadSeeker); ok {
+		http.ServeContent(ctx.Writer, ctx.Request, imageFile.Object.Name, imageFile.Object.ModTime, content)
+		return
+	}
+
+	// content that can not be seeked is streamed as whole image
+	ctx.Header("Accept-Ranges", "none")
+	if imageFile.Object.Size > 0 {
+		ctx.Header("Content-Length", strconv.FormatInt(imageFile.Object.Size, 10))
+	}
+	ctx.Status(http.StatusOK)
+
+	if ctx.Request.Method == http.MethodHead {
+		return
+	}
+
+	if _, err := io.Copy(ctx.Writer, imageFile.Content); err != nil {
+		log.Errorf("[GetSpiderImageFileHandler] stream spider image `%v` failed, error: %v", param.ImageName, err)
+	}
+}
+
+func (h *SpiderInfoHandler) mapGetSpiderImageFileHandlerError(err error) *asset.ErrorCode {
+	switch err {
+	case usecase.ErrorSpiderInfoUsecaseSpiderNotFound:
+		return &asset.E().SpiderNotFound
+	case usecase.ErrorSpiderInfoUsecaseImageNotFound:
+		return &asset.E().SpiderImageNotFound
+	case usecase.ErrorSpiderInfoUsecaseImageURLInvalid:
+		return &asset.E().SpiderImageURLInvalid
+	case usecase.ErrorSpiderInfoUsecaseValidateDataFail:
+		return &asset.E().RequestDataFail
+	case usecase.ErrorMongoConnection:
+		return &asset.E().ErrorSpiderDB
+	default:
+		return &asset.E().GeneralSystemError
+	}
+}
+
+// *************************************************
+
+// =========================================================
+// suggest spider position from image exif
+// =========================================================
+func (h *SpiderInfoHandler) SuggestSpiderPositionHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.SuggestSpiderPositionRequester
+	var resp api_model.SuggestSpiderPositionResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(asset.E().GeneralSystemError.StatusCode, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	suggestion, err := h.spiderInfoUsecase.SuggestSpiderPosition(ctx, req.Data.SpiderUUID)
+	if err != nil {
+		log.Errorf("[SuggestSpiderPositionHandler] suggest spider position usecase failed, error: %v", err)
+		assetErr := h.mapSuggestSpiderPositionHandlerError(err)
+		resp.Header.ErrorCode = assetErr.ErrorCode
+		resp.Header.Message = assetErr.ErrorMessageEN
+		ctx.JSON(assetErr.StatusCode, resp)
+		return
+	}
+
+	var position []api_model.Position
+	for _, v := range suggestion.Position {
+		position = append(position, api_model.Position{
+			Name:      v.Name,
+			Latitude:  v.Latitude,
+			Longitude: v.Longitude,
+		})
+	}
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = ""
+	resp.Data.Position = position
+	resp.Data.Altitude = suggestion.Altitude
+	resp.Data.TakenAt = suggestion.TakenAt
+	resp.Data.ImageExif = suggestion.Images
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (h *SpiderInfoHandler) mapSuggestSpiderPositionHandlerError(err error) *asset.ErrorCode {
+	switch err {
+	case usecase.ErrorSpiderInfoUsecaseSpiderNotFound:
+		return &asset.E().SpiderNotFound
+	case usecase.ErrorSpiderInfoUsecaseNoImageExif:
+		return &asset.E().NoSpiderImageExif
+	case usecase.ErrorMongoConnection:
+		return &asset.E().ErrorSpiderDB
+	default:
+		return &asset.E().GeneralSystemError
+	}
+}
+
+// *************************************************
+
 // =========================================================
 // get spider info list manager
 // =========================================================
@@ -194,28 +360,22 @@ func (h *SpiderInfoHandler) GetSpiderInfoListManagerHandler(ctx *gin.Context) {
 		return
 	}
 
-	// username := req.Header.Username
-	// key := req.Header.UserKey
-	// page := req.Data.Page
-	// size := req.Data.Size
-
-	err := fmt.Errorf("test: JWT")
-	// spiderInfoListManager, err := h.spiderInfoUsecase.GetSpiderInfoListManager(ctx, username, key, page, size)
+	spiderInfoListManager, err := h.spiderInfoUsecase.GetSpiderInfoListManager(ctx, req.Data.Page, req.Data.Size)
 	if err != nil {
 		log.Errorf("GetSpiderInfoListManager return error: %+v", err)
-		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
-		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
-		ctx.AbortWithStatusJSON(asset.E().GeneralSystemError.StatusCode, resp)
+		resp.Header.ErrorCode = asset.E().ErrorSpiderDB.ErrorCode
+		resp.Header.Message = asset.E().ErrorSpiderDB.ErrorMessageEN
+		ctx.AbortWithStatusJSON(asset.E().ErrorSpiderDB.StatusCode, resp)
 		return
 	}
 
 	var spiderInfoList []api_model.SpiderInfo
 
-	// for _, spiderList := range spiderInfoListManager {
-	// 	spiderInfo := mapSpiderInfoModel(&spiderList)
+	for _, spiderList := range spiderInfoListManager {
+		spiderInfo := mapSpiderInfoModel(&spiderList, h.imageURLSigner)
 
-	// 	spiderInfoList = append(spiderInfoList, *spiderInfo)
-	// }
+		spiderInfoList = append(spiderInfoList, *spiderInfo)
+	}
 
 	resp.Header.ErrorCode = SUCCESS_CODE
 	resp.Header.Message = ""
@@ -265,7 +425,7 @@ func (h *SpiderInfoHandler) GetSpiderInfoByGeographiesHandler(ctx *gin.Context)
 	var spiderInfoListResp []api_model.SpiderInfo
 
 	for _, spiderInfo := range spiderInfoList {
-		spiderInfoListResp = append(spiderInfoListResp, *mapSpiderInfoModel(&spiderInfo))
+		spiderInfoListResp = append(spiderInfoListResp, *mapSpiderInfoModel(&spiderInfo, h.imageURLSigner))
 	}
 
 	resp.Header.ErrorCode = SUCCESS_CODE
@@ -396,7 +556,7 @@ func (h *SpiderInfoHandler) GetSpiderInfoByLocalityHandler(ctx *gin.Context) {
 	var spiderInfoListResp []api_model.SpiderInfo
 
 	for _, spiderInfo := range spiderInfoList {
-		spiderInfoListResp = append(spiderInfoListResp, *mapSpiderInfoModel(&spiderInfo))
+		spiderInfoListResp = append(spiderInfoListResp, *mapSpiderInfoModel(&spiderInfo, h.imageURLSigner))
 	}
 
 	resp.Data.SpiderInfoList = spiderInfoListResp
@@ -469,7 +629,7 @@ func (h *SpiderInfoHandler) GetSpiderListBySpiderTypeHandler(ctx *gin.Context) {
 	var spiderInfoListResp []api_model.SpiderInfo
 
 	for _, spiderInfo := range spiderInfoList {
-		spiderInfoListResp = append(spiderInfoListResp, *mapSpiderInfoModel(&spiderInfo))
+		spiderInfoListResp = append(spiderInfoListResp, *mapSpiderInfoModel(&spiderInfo, h.imageURLSigner))
 	}
 
 	resp.Data.SpiderInfoList = spiderInfoListResp
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"net/http"
+	"spider-go/api/middleware"
+	api_model "spider-go/api/model"
+	"spider-go/asset"
+	"spider-go/domain"
+	"spider-go/logger"
+	"spider-go/model"
+	"spider-go/usecase"
+	"spider-go/utils/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SpiderRevisionHandler struct {
+	spiderRevisionUsecase domain.SpiderRevisionUsecase
+	imageURLSigner        domain.ImageURLSigner
+	log                   *logger.Logger
+}
+
+func NewSpiderRevisionHandler(spiderRevisionUsecase domain.SpiderRevisionUsecase, imageURLSigner domain.ImageURLSigner) *SpiderRevisionHandler {
+	return &SpiderRevisionHandler{
+		spiderRevisionUsecase: spiderRevisionUsecase,
+		imageURLSigner:        imageURLSigner,
+		log:                   logger.L().Named("SpiderRevisionHandler"),
+	}
+}
+
+// =========================================================
+// spider revision list
+// =========================================================
+func (h *SpiderRevisionHandler) GetSpiderRevisionListHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.GetSpiderRevisionListRequester
+	var resp api_model.GetSpiderRevisionListResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[GetSpiderRevisionListHandler] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[GetSpiderRevisionListHandler] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	revisionList, total, err := h.spiderRevisionUsecase.GetSpiderRevisionList(ctx, req.Data.SpiderUUID, req.Data.Page, req.Data.Size)
+	if err != nil {
+		log.Errorf("[GetSpiderRevisionListHandler] usecase request failed: %+v", err)
+		assetError := h.mapErrorSpiderRevision(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Data.RevisionList = make([]api_model.SpiderRevisionInfo, 0, len(revisionList))
+	for _, revision := range revisionList {
+		resp.Data.RevisionList = append(resp.Data.RevisionList, h.prepareSpiderRevisionInfo(revision))
+	}
+	resp.Data.Total = total
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// =========================================================
+// diff between two revisions
+// =========================================================
+func (h *SpiderRevisionHandler) DiffSpiderRevisionHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.DiffSpiderRevisionRequester
+	var resp api_model.DiffSpiderRevisionResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[DiffSpiderRevisionHandler] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[DiffSpiderRevisionHandler] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	diffList, err := h.spiderRevisionUsecase.DiffSpiderRevision(ctx, req.Data.SpiderUUID, req.Data.FromRevision, req.Data.ToRevision)
+	if err != nil {
+		log.Errorf("[DiffSpiderRevisionHandler] usecase request failed: %+v", err)
+		assetError := h.mapErrorSpiderRevision(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Data.DiffList = make([]api_model.SpiderRevisionDiff, 0, len(diffList))
+	for _, diff := range diffList {
+		resp.Data.DiffList = append(resp.Data.DiffList, api_model.SpiderRevisionDiff{
+			Field: diff.Field,
+			Old:   diff.Old,
+			New:   diff.New,
+		})
+	}
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// =========================================================
+// restore revision
+// =========================================================
+func (h *SpiderRevisionHandler) RestoreSpiderRevisionHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.RestoreSpiderRevisionRequester
+	var resp api_model.RestoreSpiderRevisionResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[RestoreSpiderRevisionHandler] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[RestoreSpiderRevisionHandler] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	loginUser := middleware.GetLoginUser(ctx)
+
+	revision, err := h.spiderRevisionUsecase.RestoreSpiderRevision(ctx, req.Data.SpiderUUID, req.Data.Revision, loginUser.Username)
+	if err != nil {
+		log.Errorf("[RestoreSpiderRevisionHandler] usecase request failed: %+v", err)
+		assetError := h.mapErrorSpiderRevision(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Data = h.prepareSpiderRevisionInfo(*revision)
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// *************************************************
+
+func (h *SpiderRevisionHandler) prepareSpiderRevisionInfo(revision model.SpiderRevision) api_model.SpiderRevisionInfo {
+	return api_model.SpiderRevisionInfo{
+		SpiderUUID:   revision.SpiderUUID,
+		Revision:     revision.Revision,
+		Action:       revision.Action,
+		Snapshot:     *mapSpiderInfoModel(&revision.Snapshot, h.imageURLSigner),
+		RestoredFrom: revision.RestoredFrom,
+		CreatedBy:    revision.CreatedBy,
+		CreatedAt:    revision.CreatedAt,
+	}
+}
+
+func (h *SpiderRevisionHandler) mapErrorSpiderRevision(err error) *asset.ErrorCode {
+	switch err {
+	case usecase.ErrorSpiderRevisionNotFound:
+		return &asset.E().SpiderRevisionNotFound
+	case usecase.ErrorSpiderRevisionVersionConflict:
+		return &asset.E().SpiderVersionConflict
+	case usecase.ErrorSpiderRevisionSpiderInTrash:
+		return &asset.E().SpiderInTrash
+	case usecase.ErrorSpiderRevisionMongoConnection:
+		return &asset.E().ErrorSpiderDB
+	default:
+		return &asset.E().GeneralSystemError
+	}
+}
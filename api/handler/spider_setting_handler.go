@@ -1,13 +1,19 @@
 package handler
 
 import (
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"spider-go/api/middleware"
 	api_model "spider-go/api/model"
 	"spider-go/asset"
 	"spider-go/domain"
 	"spider-go/logger"
+	"spider-go/model"
 	"spider-go/usecase"
 	"spider-go/utils/validator"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,6 +23,7 @@ type SpiderSettingHandler struct {
 	spiderSettingUsecase     domain.DeleteSpiderInfoUsecase
 	updateSpiderInfoUsecase  domain.UpdateSpiderInfoUsecase
 	removeSpiderImageUsecase domain.RemoveSpiderImageUsecase
+	imageURLSigner           domain.ImageURLSigner
 	log                      *logger.Logger
 }
 
@@ -25,12 +32,14 @@ func NewSpiderSettingHandler(
 	spiderSettingUsecase domain.DeleteSpiderInfoUsecase,
 	updateSpiderInfoUsecase domain.UpdateSpiderInfoUsecase,
 	removeSpiderImage domain.RemoveSpiderImageUsecase,
+	imageURLSigner domain.ImageURLSigner,
 ) *SpiderSettingHandler {
 	return &SpiderSettingHandler{
 		uploadImageUsecase:       uploadImageUsecase,
 		spiderSettingUsecase:     spiderSettingUsecase,
 		updateSpiderInfoUsecase:  updateSpiderInfoUsecase,
 		removeSpiderImageUsecase: removeSpiderImage,
+		imageURLSigner:           imageURLSigner,
 		log:                      logger.L().Named("SpiderSettingHandler"),
 	}
 }
@@ -88,6 +97,12 @@ func (h *SpiderSettingHandler) mapUploadImageHandlerErrorCode(err error) *asset.
 		return &asset.E().UserNotLogin
 	case usecase.ErrorUploadImageUsecaseFileTypeNotMatch:
 		return &asset.E().InvalidImageType
+	case usecase.ErrorUploadImageUsecaseFileTooLarge:
+		return &asset.E().ImageFileTooLarge
+	case usecase.ErrorUploadImageUsecaseTooManyFiles:
+		return &asset.E().TooManyImageFiles
+	case usecase.ErrorUploadImageUsecaseNoImageFile, usecase.ErrorUploadImageUsecaseReadRequestFail:
+		return &asset.E().RequestDataFail
 	case usecase.ErrorMongoTechnicalFail:
 		return &asset.E().ErrorSpiderDB
 	default:
@@ -97,6 +112,120 @@ func (h *SpiderSettingHandler) mapUploadImageHandlerErrorCode(err error) *asset.
 
 // *************************************************
 
+// =========================================================
+// upload image file
+// =========================================================
+
+const (
+	UPLOAD_IMAGE_FORM_SPIDER_UUID = "spider_uuid"
+	UPLOAD_IMAGE_FORM_IMAGE       = "image"
+)
+
+// UploadImageFilesHandler read multipart part by part, image file is streamed to storage
+// while it is read so request is never kept in memory
+func (h *SpiderSettingHandler) UploadImageFilesHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var resp api_model.UploadSpiderImageFileResponser
+
+	reader, err := ctx.Request.MultipartReader()
+	if err != nil {
+		log.Errorf("[UploadImageFilesHandler] read multipart request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.AbortWithStatusJSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	spiderUUID, err := h.readUploadSpiderUUID(reader)
+	if err != nil {
+		log.Errorf("[UploadImageFilesHandler] read spider uuid failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.AbortWithStatusJSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	log.Infof("[UploadImageFilesHandler] upload image file of spider `%v` start", spiderUUID)
+
+	// other field after spider uuid is ignored
+	nextFile := func() (*model.UploadImageFile, error) {
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				return nil, err
+			}
+
+			if part.FormName() == UPLOAD_IMAGE_FORM_IMAGE && part.FileName() != "" {
+				return &model.UploadImageFile{FileName: part.FileName(), Content: part}, nil
+			}
+		}
+	}
+
+	results, err := h.uploadImageUsecase.UploadImageFiles(ctx, spiderUUID, nextFile)
+	if err != nil {
+		log.Errorf("[UploadImageFilesHandler] upload image file usecase failed, error: %v", err)
+		assetErr := h.mapUploadImageHandlerErrorCode(err)
+		resp.Header.ErrorCode = assetErr.ErrorCode
+		resp.Header.Message = assetErr.ErrorMessageEN
+		ctx.JSON(assetErr.StatusCode, resp)
+		return
+	}
+
+	for _, result := range results {
+		file := api_model.UploadSpiderImageFileResult{
+			FileName:  result.FileName,
+			ImageName: result.ImageName,
+			ErrorCode: SUCCESS_CODE,
+		}
+
+		if result.Err != nil {
+			assetErr := h.mapUploadImageHandlerErrorCode(result.Err)
+			file.ErrorCode = assetErr.ErrorCode
+			file.Message = assetErr.ErrorMessageEN
+		}
+
+		resp.Data.Files = append(resp.Data.Files, file)
+	}
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = ""
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// readUploadSpiderUUID read spider uuid that must be sent before image file
+func (h *SpiderSettingHandler) readUploadSpiderUUID(reader *multipart.Reader) (string, error) {
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return "", err
+		}
+
+		if part.FileName() != "" {
+			return "", fmt.Errorf("file `%v` is sent before %v", part.FileName(), UPLOAD_IMAGE_FORM_SPIDER_UUID)
+		}
+
+		if part.FormName() != UPLOAD_IMAGE_FORM_SPIDER_UUID {
+			continue
+		}
+
+		// spider uuid is short, longer value is invalid
+		value, err := io.ReadAll(io.LimitReader(part, 128))
+		if err != nil {
+			return "", err
+		}
+
+		spiderUUID := strings.TrimSpace(string(value))
+		if spiderUUID == "" {
+			return "", fmt.Errorf("%v is empty", UPLOAD_IMAGE_FORM_SPIDER_UUID)
+		}
+
+		return spiderUUID, nil
+	}
+}
+
+// *************************************************
+
 // =========================================================
 // delete spider info
 // =========================================================
@@ -123,7 +252,7 @@ func (h *SpiderSettingHandler) DeleteSpiderHandler(ctx *gin.Context) {
 		return
 	}
 
-	err := h.spiderSettingUsecase.DeleteSpiderInfoUsecase(ctx, req.Data.SpiderUUID)
+	err := h.spiderSettingUsecase.DeleteSpiderInfoUsecase(ctx, req.Data.SpiderUUID, middleware.GetLoginUser(ctx).Username)
 	if err != nil {
 		log.Errorf("[DeleteSpiderHandler] delete spider info usecase error: %+v", err)
 		assetErr := h.mapDeleteSpiderHandler(err)
@@ -143,6 +272,8 @@ func (h *SpiderSettingHandler) mapDeleteSpiderHandler(err error) *asset.ErrorCod
 	switch err {
 	case usecase.ErrorMongoTechnicalFail:
 		return &asset.E().SpiderNotFound
+	case usecase.ErrorDeleteSpiderInfoUsecaseSpiderNotFound:
+		return &asset.E().SpiderNotFound
 	case usecase.ErrorDeleteSpiderInfoUsecaseDeleteSpiderInfoFailed:
 		return &asset.E().DeleteSpiderFailed
 	default:
@@ -178,16 +309,21 @@ func (h *SpiderSettingHandler) EditSpiderInfoHandler(ctx *gin.Context) {
 		return
 	}
 
-	err := h.updateSpiderInfoUsecase.UpdateSpiderInfoUsecase(ctx, req.Data)
+	spiderInfo, err := h.updateSpiderInfoUsecase.UpdateSpiderInfoUsecase(ctx, req.Data, middleware.GetLoginUser(ctx))
 	if err != nil {
-		log.Errorf("[DeleteSpiderHandler] delete spider info usecase error: %+v", err)
+		log.Errorf("[EditSpiderInfoHandler] update spider info usecase error: %+v", err)
 		assetErr := h.mapEditSpiderInfoHandler(err)
 		resp.Header.ErrorCode = assetErr.ErrorCode
 		resp.Header.Message = assetErr.ErrorMessageEN
+		// server copy let editor merge own change
+		if err == usecase.ErrorUpdateSpiderInfoUsecaseVersionConflict {
+			resp.Data = mapSpiderInfoModel(spiderInfo, h.imageURLSigner)
+		}
 		ctx.AbortWithStatusJSON(assetErr.StatusCode, resp)
 		return
 	}
 
+	resp.Data = mapSpiderInfoModel(spiderInfo, h.imageURLSigner)
 	resp.Header.ErrorCode = SUCCESS_CODE
 	resp.Header.Message = ""
 
@@ -200,6 +336,14 @@ func (h *SpiderSettingHandler) mapEditSpiderInfoHandler(err error) *asset.ErrorC
 		return &asset.E().SpiderNotFound
 	case usecase.ErrorUpdateSpiderInfoUsecaseSpiderUUIDNotFound:
 		return &asset.E().SpiderNotFound
+	case usecase.ErrorUpdateSpiderInfoUsecaseVersionRequired:
+		return &asset.E().RequestDataFail
+	case usecase.ErrorUpdateSpiderInfoUsecaseVersionConflict:
+		return &asset.E().SpiderVersionConflict
+	case usecase.ErrorUpdateSpiderInfoUsecaseInvalidPatch:
+		return &asset.E().InvalidSpiderPatch
+	case usecase.ErrorUpdateSpiderInfoUsecasePermissionDenied:
+		return &asset.E().InsufficientUserRights
 	default:
 		return &asset.E().GeneralSystemError
 	}
@@ -207,6 +351,53 @@ func (h *SpiderSettingHandler) mapEditSpiderInfoHandler(err error) *asset.ErrorC
 
 // *************************************************
 
+// =========================================================
+// patch spider info
+// =========================================================
+func (h *SpiderSettingHandler) PatchSpiderInfoHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.PatchSpiderInfoRequester
+	var resp api_model.PatchSpiderInfoResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[PatchSpiderInfoHandler] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[PatchSpiderInfoHandler] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	spiderInfo, err := h.updateSpiderInfoUsecase.PatchSpiderInfoUsecase(ctx, req.Data, middleware.GetLoginUser(ctx))
+	if err != nil {
+		log.Errorf("[PatchSpiderInfoHandler] patch spider info usecase error: %+v", err)
+		assetErr := h.mapEditSpiderInfoHandler(err)
+		resp.Header.ErrorCode = assetErr.ErrorCode
+		resp.Header.Message = assetErr.ErrorMessageEN
+		if err == usecase.ErrorUpdateSpiderInfoUsecaseVersionConflict {
+			resp.Data = mapSpiderInfoModel(spiderInfo, h.imageURLSigner)
+		}
+		ctx.AbortWithStatusJSON(assetErr.StatusCode, resp)
+		return
+	}
+
+	resp.Data = mapSpiderInfoModel(spiderInfo, h.imageURLSigner)
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// *************************************************
+
 // =========================================================
 // remove spider image
 // =========================================================
@@ -256,6 +447,12 @@ func (h *SpiderSettingHandler) mapRemoveSpiderImageHandler(err error) *asset.Err
 		return &asset.E().SpiderNotFound
 	case usecase.ErrorUpdateSpiderInfoUsecaseSpiderUUIDNotFound:
 		return &asset.E().SpiderNotFound
+	case usecase.ErrorUpdateSpiderInfoUsecaseVersionRequired:
+		return &asset.E().RequestDataFail
+	case usecase.ErrorUpdateSpiderInfoUsecaseVersionConflict:
+		return &asset.E().SpiderVersionConflict
+	case usecase.ErrorUpdateSpiderInfoUsecaseInvalidPatch:
+		return &asset.E().InvalidSpiderPatch
 	default:
 		return &asset.E().GeneralSystemError
 	}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"net/http"
+	"spider-go/api/middleware"
+	api_model "spider-go/api/model"
+	"spider-go/asset"
+	"spider-go/domain"
+	"spider-go/logger"
+	"spider-go/usecase"
+	"spider-go/utils/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SpiderTrashHandler struct {
+	spiderTrashUsecase domain.SpiderTrashUsecase
+	imageURLSigner     domain.ImageURLSigner
+	log                *logger.Logger
+}
+
+func NewSpiderTrashHandler(spiderTrashUsecase domain.SpiderTrashUsecase, imageURLSigner domain.ImageURLSigner) *SpiderTrashHandler {
+	return &SpiderTrashHandler{
+		spiderTrashUsecase: spiderTrashUsecase,
+		imageURLSigner:     imageURLSigner,
+		log:                logger.L().Named("SpiderTrashHandler"),
+	}
+}
+
+// =========================================================
+// spider trash list
+// =========================================================
+func (h *SpiderTrashHandler) GetSpiderTrashListHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.GetSpiderTrashListRequester
+	var resp api_model.GetSpiderTrashListResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[GetSpiderTrashListHandler] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[GetSpiderTrashListHandler] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	spiderInfoList, total, err := h.spiderTrashUsecase.GetSpiderTrashList(ctx, req.Data.Page, req.Data.Size)
+	if err != nil {
+		log.Errorf("[GetSpiderTrashListHandler] usecase request failed: %+v", err)
+		assetError := h.mapErrorSpiderTrash(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Data.TrashList = make([]api_model.SpiderTrashInfo, 0, len(spiderInfoList))
+	for i := range spiderInfoList {
+		resp.Data.TrashList = append(resp.Data.TrashList, api_model.SpiderTrashInfo{
+			SpiderInfo: *mapSpiderInfoModel(&spiderInfoList[i], h.imageURLSigner),
+			DeletedBy:  spiderInfoList[i].DeletedBy,
+			DeletedAt:  spiderInfoList[i].DeletedAt,
+		})
+	}
+	resp.Data.Total = total
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// =========================================================
+// restore spider info from trash
+// =========================================================
+func (h *SpiderTrashHandler) RestoreSpiderTrashHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.RestoreSpiderTrashRequester
+	var resp api_model.RestoreSpiderTrashResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[RestoreSpiderTrashHandler] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[RestoreSpiderTrashHandler] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	loginUser := middleware.GetLoginUser(ctx)
+
+	if err := h.spiderTrashUsecase.RestoreSpiderInfo(ctx, req.Data.SpiderUUID, loginUser.Username); err != nil {
+		log.Errorf("[RestoreSpiderTrashHandler] usecase request failed: %+v", err)
+		assetError := h.mapErrorSpiderTrash(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// *************************************************
+
+func (h *SpiderTrashHandler) mapErrorSpiderTrash(err error) *asset.ErrorCode {
+	switch err {
+	case usecase.ErrorSpiderTrashNotFound:
+		return &asset.E().SpiderNotInTrash
+	case usecase.ErrorSpiderTrashMongoConnection:
+		return &asset.E().ErrorSpiderDB
+	default:
+		return &asset.E().GeneralSystemError
+	}
+}
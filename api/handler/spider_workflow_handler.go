@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"net/http"
+	"spider-go/api/middleware"
+	api_model "spider-go/api/model"
+	"spider-go/asset"
+	"spider-go/domain"
+	"spider-go/logger"
+	"spider-go/usecase"
+	"spider-go/utils/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SpiderWorkflowHandler struct {
+	spiderWorkflowUsecase domain.SpiderWorkflowUsecase
+	imageURLSigner        domain.ImageURLSigner
+	log                   *logger.Logger
+}
+
+func NewSpiderWorkflowHandler(spiderWorkflowUsecase domain.SpiderWorkflowUsecase, imageURLSigner domain.ImageURLSigner) *SpiderWorkflowHandler {
+	return &SpiderWorkflowHandler{
+		spiderWorkflowUsecase: spiderWorkflowUsecase,
+		imageURLSigner:        imageURLSigner,
+		log:                   logger.L().Named("SpiderWorkflowHandler"),
+	}
+}
+
+// =========================================================
+// submit spider info for review
+// =========================================================
+func (h *SpiderWorkflowHandler) SubmitSpiderInfoHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.SubmitSpiderInfoRequester
+	var resp api_model.SubmitSpiderInfoResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[SubmitSpiderInfoHandler] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[SubmitSpiderInfoHandler] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	loginUser := middleware.GetLoginUser(ctx)
+
+	if err := h.spiderWorkflowUsecase.SubmitSpiderInfo(ctx, req.Data.SpiderUUID, loginUser); err != nil {
+		log.Errorf("[SubmitSpiderInfoHandler] usecase request failed: %+v", err)
+		assetError := h.mapErrorSpiderWorkflow(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// =========================================================
+// review spider info
+// =========================================================
+func (h *SpiderWorkflowHandler) ReviewSpiderInfoHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.ReviewSpiderInfoRequester
+	var resp api_model.ReviewSpiderInfoResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[ReviewSpiderInfoHandler] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[ReviewSpiderInfoHandler] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	loginUser := middleware.GetLoginUser(ctx)
+
+	if err := h.spiderWorkflowUsecase.ReviewSpiderInfo(ctx, req.Data.SpiderUUID, loginUser, req.Data.Approve, req.Data.Comment); err != nil {
+		log.Errorf("[ReviewSpiderInfoHandler] usecase request failed: %+v", err)
+		assetError := h.mapErrorSpiderWorkflow(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// =========================================================
+// review queue
+// =========================================================
+func (h *SpiderWorkflowHandler) GetReviewQueueHandler(ctx *gin.Context) {
+	log := h.log.WithContext(ctx)
+
+	var req api_model.GetReviewQueueRequester
+	var resp api_model.GetReviewQueueResponser
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		log.Errorf("[GetReviewQueueHandler] should bind request failed: %+v", err)
+		resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
+		resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	if err := validator.Struct(req); err != nil {
+		log.Errorf("[GetReviewQueueHandler] validate request data fail, error: %+v", err)
+		resp.Header.ErrorCode = asset.E().RequestDataFail.ErrorCode
+		resp.Header.Message = asset.E().RequestDataFail.ErrorMessageEN
+		ctx.JSON(asset.E().RequestDataFail.StatusCode, resp)
+		return
+	}
+
+	spiderInfoList, total, err := h.spiderWorkflowUsecase.GetReviewQueue(ctx, req.Data.Page, req.Data.Size)
+	if err != nil {
+		log.Errorf("[GetReviewQueueHandler] usecase request failed: %+v", err)
+		assetError := h.mapErrorSpiderWorkflow(err)
+		resp.Header.ErrorCode = assetError.ErrorCode
+		resp.Header.Message = assetError.ErrorMessageEN
+		ctx.JSON(assetError.StatusCode, resp)
+		return
+	}
+
+	resp.Data.SpiderList = make([]api_model.SpiderInfo, 0, len(spiderInfoList))
+	for i := range spiderInfoList {
+		resp.Data.SpiderList = append(resp.Data.SpiderList, *mapSpiderInfoModel(&spiderInfoList[i], h.imageURLSigner))
+	}
+	resp.Data.Total = total
+
+	resp.Header.ErrorCode = SUCCESS_CODE
+	resp.Header.Message = SUCCESS_MESSAGE
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// *************************************************
+
+func (h *SpiderWorkflowHandler) mapErrorSpiderWorkflow(err error) *asset.ErrorCode {
+	switch err {
+	case usecase.ErrorSpiderWorkflowNotFound:
+		return &asset.E().SpiderNotFound
+	case usecase.ErrorSpiderWorkflowInvalidTransition:
+		return &asset.E().InvalidTransition
+	case usecase.ErrorSpiderWorkflowPermissionDenied:
+		return &asset.E().InsufficientUserRights
+	case usecase.ErrorSpiderWorkflowCommentRequired:
+		return &asset.E().ReviewCommentRequired
+	case usecase.ErrorSpiderWorkflowMongoConnection:
+		return &asset.E().ErrorSpiderDB
+	default:
+		return &asset.E().GeneralSystemError
+	}
+}
@@ -8,26 +8,32 @@ import (
 	"spider-go/asset"
 	"spider-go/domain"
 	"spider-go/logger"
+	"spider-go/model"
+	jwt_service "spider-go/utils/jwt"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 )
 
-func Authenticate(jwtService domain.JWTService) gin.HandlerFunc {
+// API_KEY_HEADER is http header of api key, api key can send in body header as `api_key` too
+const API_KEY_HEADER = "X-API-Key"
+
+func Authenticate(jwtService domain.JWTService, authorities domain.Authorities, apiKeyUsecase domain.APIKeyUsecase) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		log := logger.L().Named("Authenticate").WithContext(ctx)
 
+		// machine client send api key instead of jwt, body may not be json
+		if apiKey := ctx.GetHeader(API_KEY_HEADER); apiKey != "" {
+			authenticateAPIKey(ctx, apiKeyUsecase, apiKey)
+			return
+		}
+
 		var req MiddlewareRequest
-		var resp MiddlewareResponse
 
 		// get data in body
 		bodyReq, err := io.ReadAll(ctx.Request.Body)
 		if err != nil {
 			log.Errorf("[authenticate] read all request body failed, error: %+v", err)
-			resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
-			resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
-
-			ctx.AbortWithStatusJSON(asset.E().GeneralSystemError.StatusCode, resp)
+			abortWithError(ctx, &asset.E().GeneralSystemError)
 			return
 		}
 
@@ -39,10 +45,12 @@ func Authenticate(jwtService domain.JWTService) gin.HandlerFunc {
 		// read body request
 		if err := json.Unmarshal(bodyReq, &req); err != nil {
 			log.Errorf("[authenticate] unmarshal request body failed, error: %+v", err)
-			resp.Header.ErrorCode = asset.E().GeneralSystemError.ErrorCode
-			resp.Header.Message = asset.E().GeneralSystemError.ErrorMessageEN
+			abortWithError(ctx, &asset.E().GeneralSystemError)
+			return
+		}
 
-			ctx.AbortWithStatusJSON(asset.E().GeneralSystemError.StatusCode, resp)
+		if apiKey, ok := req.Header["api_key"].(string); ok && apiKey != "" {
+			authenticateAPIKey(ctx, apiKeyUsecase, apiKey)
 			return
 		}
 
@@ -51,30 +59,78 @@ func Authenticate(jwtService domain.JWTService) gin.HandlerFunc {
 		// =========================================================
 
 		token, err := jwtService.ValidateToken(fmt.Sprint(req.Header["token"]))
-		if err != nil {
-			log.Errorf("[authenticate] find user in redis failed, error: %+v", err)
-			resp.Header.ErrorCode = asset.E().UserNotLogin.ErrorCode
-			resp.Header.Message = asset.E().UserNotLogin.ErrorMessageEN
-			ctx.AbortWithStatusJSON(asset.E().UserNotLogin.StatusCode, resp)
+		if err != nil || !token.Valid {
+			log.Errorf("[authenticate] validate token failed, error: %+v", err)
+			abortWithError(ctx, &asset.E().UserNotLogin)
+			return
 		}
+
+		claims, ok := token.Claims.(*jwt_service.AuthCustomClaims)
+		if !ok {
+			log.Errorf("[authenticate] invalid token claims type %T", token.Claims)
+			abortWithError(ctx, &asset.E().UserNotLogin)
+			return
+		}
+
 		// **********************************************************
 
-		// var userInfo interface{}
+		// token issued before iat_ms claim is added only has second, it is compared as start of that second
+		issuedAt := claims.IssuedAtMilli
+		if issuedAt == 0 {
+			issuedAt = claims.IssuedAt * 1000
+		}
 
-		// json.Unmarshal(redisResult, &userInfo)
-		// log.Debugf("[authenticate] user login info: %+v", userInfo)
-		// ctx.Set("user_info", userInfo)
-		log.Infof("[authenticate] token %+v", token.Claims.(jwt.MapClaims))
+		loginUser := &model.LoginUser{
+			Username:  claims.Username,
+			Role:      claims.Role,
+			TokenID:   claims.Id,
+			SessionID: claims.SessionID,
+			IssuedAt:  issuedAt,
+			ExpiresAt: claims.ExpiresAt,
+		}
 
 		// =========================================================
-		//  update data expire
+		// validate token is not revoked
 		// =========================================================
 
-		// jwtService.RefreshToken(token)
+		if err := authorities.ValidateSession(ctx, loginUser); err != nil {
+			log.Errorf("[authenticate] validate session failed, error: %+v", err)
+			if err == domain.ErrorTokenRevoked {
+				abortWithError(ctx, &asset.E().UserNotLogin)
+				return
+			}
+			abortWithError(ctx, &asset.E().ErrorTempDB)
+			return
+		}
 
 		// **********************************************************
 
+		log.Infof("[authenticate] login user: %+v", loginUser)
+
+		ctx.Set(LOGIN_USER_KEY, loginUser)
+
 		ctx.Next()
 
 	}
 }
+
+func authenticateAPIKey(ctx *gin.Context, apiKeyUsecase domain.APIKeyUsecase, apiKey string) {
+	log := logger.L().Named("Authenticate").WithContext(ctx)
+
+	loginUser, err := apiKeyUsecase.AuthenticateAPIKey(ctx, apiKey)
+	if err != nil {
+		log.Errorf("[authenticate] validate api key failed, error: %+v", err)
+		if err == domain.ErrorAPIKeyInvalid {
+			abortWithError(ctx, &asset.E().InvalidAPIKey)
+			return
+		}
+		abortWithError(ctx, &asset.E().ErrorSpiderDB)
+		return
+	}
+
+	log.Infof("[authenticate] api key user: %v, scopes: %v", loginUser.Username, loginUser.Scopes)
+
+	ctx.Set(LOGIN_USER_KEY, loginUser)
+
+	ctx.Next()
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"spider-go/asset"
+	"spider-go/logger"
+	"spider-go/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+const LOGIN_USER_KEY = "login_user"
+
+// GetLoginUser return principal that set by Authenticate, return nil if request not login
+func GetLoginUser(ctx *gin.Context) *model.LoginUser {
+	value, ok := ctx.Get(LOGIN_USER_KEY)
+	if !ok {
+		return nil
+	}
+
+	loginUser, ok := value.(*model.LoginUser)
+	if !ok {
+		return nil
+	}
+
+	return loginUser
+}
+
+// RequireRole allow request when login user have one of roles, must use after Authenticate
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		log := logger.L().Named("RequireRole").WithContext(ctx)
+
+		loginUser := GetLoginUser(ctx)
+		if loginUser == nil {
+			log.Errorf("[require role] login user not found in context")
+			abortWithError(ctx, &asset.E().UserNotLogin)
+			return
+		}
+
+		if !loginUser.HasRole(roles...) {
+			log.Errorf("[require role] user `%v` role `%v` not in %v", loginUser.Username, loginUser.Role, roles)
+			abortWithError(ctx, &asset.E().InsufficientUserRights)
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// RequirePermission allow request when login user have all permissions, must use after Authenticate
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		log := logger.L().Named("RequirePermission").WithContext(ctx)
+
+		loginUser := GetLoginUser(ctx)
+		if loginUser == nil {
+			log.Errorf("[require permission] login user not found in context")
+			abortWithError(ctx, &asset.E().UserNotLogin)
+			return
+		}
+
+		for _, permission := range permissions {
+			if !loginUser.HasPermission(permission) {
+				log.Errorf("[require permission] user `%v` role `%v` not have permission `%v`", loginUser.Username, loginUser.Role, permission)
+				abortWithError(ctx, &asset.E().InsufficientUserRights)
+				return
+			}
+		}
+
+		ctx.Next()
+	}
+}
+
+func abortWithError(ctx *gin.Context, assetErr *asset.ErrorCode) {
+	var resp MiddlewareResponse
+
+	resp.Header.ErrorCode = assetErr.ErrorCode
+	resp.Header.Message = assetErr.ErrorMessageEN
+
+	ctx.AbortWithStatusJSON(assetErr.StatusCode, resp)
+}
@@ -0,0 +1,128 @@
+package model
+
+// ==================================================
+// account list
+// ==================================================
+type GetAccountListRequester struct {
+	Header RequestUserHeader         `json:"header"`
+	Data   GetAccountListRequestData `json:"data"`
+}
+
+type GetAccountListRequestData struct {
+	Keyword string `json:"keyword"`
+	Role    string `json:"role"`
+	Status  string `json:"status"`
+	Page    int32  `json:"page" validate:"min=0"`
+	Size    int32  `json:"size" validate:"min=1,max=100"`
+}
+
+type GetAccountListResponser struct {
+	Header ResponseHeader             `json:"header"`
+	Data   GetAccountListResponseData `json:"data"`
+}
+
+type GetAccountListResponseData struct {
+	AccountList []AccountInfo `json:"account_list"`
+	Total       int64         `json:"total"`
+}
+
+type AccountInfo struct {
+	Username  string `json:"username"`
+	Title     string `json:"title"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Age       int    `json:"age"`
+	MobileNO  string `json:"mobile_no"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	Status    string `json:"status"`
+}
+
+// ==================================================
+// update account status
+// ==================================================
+type UpdateAccountStatusRequester struct {
+	Header RequestUserHeader              `json:"header"`
+	Data   UpdateAccountStatusRequestData `json:"data"`
+}
+
+type UpdateAccountStatusRequestData struct {
+	Username string `json:"username" validate:"required"`
+	Status   string `json:"status" validate:"required"`
+}
+
+type UpdateAccountStatusResponser struct {
+	Header ResponseHeader `json:"header"`
+}
+
+// ==================================================
+// update account role
+// ==================================================
+type UpdateAccountRoleRequester struct {
+	Header RequestUserHeader            `json:"header"`
+	Data   UpdateAccountRoleRequestData `json:"data"`
+}
+
+type UpdateAccountRoleRequestData struct {
+	Username string `json:"username" validate:"required"`
+	Role     string `json:"role" validate:"required"`
+}
+
+type UpdateAccountRoleResponser struct {
+	Header ResponseHeader `json:"header"`
+}
+
+// ==================================================
+// update own profile
+// ==================================================
+type UpdateProfileRequester struct {
+	Header RequestUserHeader        `json:"header"`
+	Data   UpdateProfileRequestData `json:"data"`
+}
+
+type UpdateProfileRequestData struct {
+	Title     string `json:"title"`
+	FirstName string `json:"first_name" validate:"required"`
+	LastName  string `json:"last_name" validate:"required"`
+	Age       int    `json:"age" validate:"min=0"`
+	MobileNO  string `json:"mobile_no"`
+	Email     string `json:"email" validate:"omitempty,email"`
+}
+
+type UpdateProfileResponser struct {
+	Header ResponseHeader `json:"header"`
+}
+
+// ==================================================
+// change own password
+// ==================================================
+type ChangePasswordRequester struct {
+	Header RequestUserHeader         `json:"header"`
+	Data   ChangePasswordRequestData `json:"data"`
+}
+
+type ChangePasswordRequestData struct {
+	OldPassword     string `json:"old_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+	ConfirmPassword string `json:"confirm_password" validate:"required"`
+}
+
+type ChangePasswordResponser struct {
+	Header ResponseHeader `json:"header"`
+}
+
+// ==================================================
+// reset second factor of account
+// ==================================================
+type ResetMFARequester struct {
+	Header RequestUserHeader   `json:"header"`
+	Data   ResetMFARequestData `json:"data"`
+}
+
+type ResetMFARequestData struct {
+	Username string `json:"username" validate:"required"`
+}
+
+type ResetMFAResponser struct {
+	Header ResponseHeader `json:"header"`
+}
@@ -0,0 +1,78 @@
+package model
+
+import "time"
+
+// ==================================================
+// create api key
+// ==================================================
+type CreateAPIKeyRequester struct {
+	Header RequestUserHeader       `json:"header"`
+	Data   CreateAPIKeyRequestData `json:"data"`
+}
+
+type CreateAPIKeyRequestData struct {
+	Name      string    `json:"name" validate:"required"`
+	Scopes    []string  `json:"scopes" validate:"required,min=1"`
+	ExpiresAt time.Time `json:"expires_at" validate:"required"`
+}
+
+type CreateAPIKeyResponser struct {
+	Header ResponseHeader           `json:"header"`
+	Data   CreateAPIKeyResponseData `json:"data"`
+}
+
+type CreateAPIKeyResponseData struct {
+	// api key is shown only once, it cannot be read again
+	APIKey string     `json:"api_key"`
+	Info   APIKeyInfo `json:"info"`
+}
+
+type APIKeyInfo struct {
+	KeyID      string    `json:"key_id"`
+	Name       string    `json:"name"`
+	Scopes     []string  `json:"scopes"`
+	CreatedBy  string    `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	Revoked    bool      `json:"revoked"`
+}
+
+// ==================================================
+// api key list
+// ==================================================
+type GetAPIKeyListRequester struct {
+	Header RequestUserHeader        `json:"header"`
+	Data   GetAPIKeyListRequestData `json:"data"`
+}
+
+type GetAPIKeyListRequestData struct {
+	Page int32 `json:"page" validate:"min=0"`
+	Size int32 `json:"size" validate:"min=1,max=100"`
+}
+
+type GetAPIKeyListResponser struct {
+	Header ResponseHeader            `json:"header"`
+	Data   GetAPIKeyListResponseData `json:"data"`
+}
+
+type GetAPIKeyListResponseData struct {
+	APIKeyList []APIKeyInfo `json:"api_key_list"`
+	Total      int64        `json:"total"`
+}
+
+// ==================================================
+// revoke api key
+// ==================================================
+type RevokeAPIKeyRequester struct {
+	Header RequestUserHeader       `json:"header"`
+	Data   RevokeAPIKeyRequestData `json:"data"`
+}
+
+type RevokeAPIKeyRequestData struct {
+	KeyID string `json:"key_id" validate:"required"`
+}
+
+type RevokeAPIKeyResponser struct {
+	Header ResponseHeader `json:"header"`
+}
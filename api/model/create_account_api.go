@@ -6,15 +6,18 @@ type CreateAccoutReq struct {
 }
 
 type CreateAccountReqData struct {
-	Username        string `json:"username"`
-	Password        string `json:"password"`
-	ConfirmPassword string `json:"confirm_password"`
+	Username string `json:"username"`
+	// password and confirm password are base64 of RSA-OAEP (SHA-256) cipher text
+	// that encrypted by public key of search key
+	Password        string `json:"password" validate:"required"`
+	ConfirmPassword string `json:"confirm_password" validate:"required"`
+	SearchKey       string `json:"search_key" validate:"required"`
 	Title           string `json:"title"`
 	FirstName       string `json:"first_name"`
 	LastName        string `json:"last_name"`
 	Age             int    `json:"age"`
 	MobileNO        string `json:"mobile_no"`
-	Role            string `json:"role"`
+	Email           string `json:"email" validate:"omitempty,email"`
 }
 
 // response
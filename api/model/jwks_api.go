@@ -0,0 +1,6 @@
+package model
+
+// response when jwks is not available, success response is json web key set
+type GetJWKSErrorResponser struct {
+	Header ResponseHeader `json:"header"`
+}
@@ -8,8 +8,10 @@ type LoginRequest struct {
 }
 
 type LoginRequestData struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username string `json:"username" validate:"required"`
+	// base64 of password that encrypted with RSA-OAEP (SHA-256) by public key of search key
+	Password  string `json:"password" validate:"required"`
+	SearchKey string `json:"search_key" validate:"required"`
 }
 
 // response
@@ -21,10 +23,22 @@ type LoginResponse struct {
 type LoginInfo struct {
 	User         User         `json:"user"`
 	BackendToken BackendToken `json:"backendToken"`
+	// set instead of user and token when second factor is required, call verify mfa with mfa token
+	MFA *MFAChallengeInfo `json:"mfa,omitempty"`
+	// set only when second factor is enrolled at this login, shown only once
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+type MFAChallengeInfo struct {
+	MFAToken string `json:"mfa_token"`
+	// set when account must enroll second factor, scan it with authenticator app before verify
+	OtpauthURI string    `json:"otpauth_uri,omitempty"`
+	ExpiresAt  time.Time `json:"expires_at"`
 }
 
 type BackendToken struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
 }
 type User struct {
 	ID        string    `json:"id"`
@@ -34,6 +48,17 @@ type User struct {
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+// verify second factor of login
+type VerifyMFARequester struct {
+	Data VerifyMFARequestData `json:"data"`
+}
+
+type VerifyMFARequestData struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	// totp code or recovery code
+	Code string `json:"code" validate:"required"`
+}
+
 // verify login
 type VerifyLoginRequester struct {
 	Header RequestUserHeader `json:"header"`
@@ -42,3 +67,40 @@ type VerifyLoginRequester struct {
 type VerifyLoginResponser struct {
 	Header ResponseHeader `json:"header"`
 }
+
+// refresh token
+type RefreshTokenRequester struct {
+	Data RefreshTokenRequestData `json:"data"`
+}
+
+type RefreshTokenRequestData struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type RefreshTokenResponser struct {
+	Header ResponseHeader `json:"header"`
+	Data   BackendToken   `json:"data"`
+}
+
+// logout
+type LogoutRequester struct {
+	Header RequestUserHeader `json:"header"`
+}
+
+type LogoutResponser struct {
+	Header ResponseHeader `json:"header"`
+}
+
+// revoke all sessions of user
+type RevokeUserSessionsRequester struct {
+	Header RequestUserHeader             `json:"header"`
+	Data   RevokeUserSessionsRequestData `json:"data"`
+}
+
+type RevokeUserSessionsRequestData struct {
+	Username string `json:"username" validate:"required"`
+}
+
+type RevokeUserSessionsResponser struct {
+	Header ResponseHeader `json:"header"`
+}
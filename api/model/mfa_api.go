@@ -0,0 +1,58 @@
+package model
+
+// ==================================================
+// enroll second factor
+// ==================================================
+type EnrollMFARequester struct {
+	Header RequestUserHeader `json:"header"`
+}
+
+type EnrollMFAResponser struct {
+	Header ResponseHeader        `json:"header"`
+	Data   EnrollMFAResponseData `json:"data"`
+}
+
+type EnrollMFAResponseData struct {
+	OtpauthURI string `json:"otpauth_uri"`
+	// for enter manually when cannot scan qr code
+	Secret string `json:"secret"`
+}
+
+// ==================================================
+// activate second factor
+// ==================================================
+type ActivateMFARequester struct {
+	Header RequestUserHeader      `json:"header"`
+	Data   ActivateMFARequestData `json:"data"`
+}
+
+type ActivateMFARequestData struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type ActivateMFAResponser struct {
+	Header ResponseHeader          `json:"header"`
+	Data   ActivateMFAResponseData `json:"data"`
+}
+
+type ActivateMFAResponseData struct {
+	// shown only once
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// ==================================================
+// disable second factor
+// ==================================================
+type DisableMFARequester struct {
+	Header RequestUserHeader     `json:"header"`
+	Data   DisableMFARequestData `json:"data"`
+}
+
+type DisableMFARequestData struct {
+	// totp code or recovery code
+	Code string `json:"code" validate:"required"`
+}
+
+type DisableMFAResponser struct {
+	Header ResponseHeader `json:"header"`
+}
@@ -0,0 +1,36 @@
+package model
+
+// ==================================================
+// request password reset
+// ==================================================
+type RequestPasswordResetRequester struct {
+	Data RequestPasswordResetRequestData `json:"data"`
+}
+
+type RequestPasswordResetRequestData struct {
+	Username string `json:"username" validate:"required"`
+}
+
+type RequestPasswordResetResponser struct {
+	Header ResponseHeader `json:"header"`
+}
+
+// ==================================================
+// confirm password reset
+// ==================================================
+type ConfirmPasswordResetRequester struct {
+	Data ConfirmPasswordResetRequestData `json:"data"`
+}
+
+type ConfirmPasswordResetRequestData struct {
+	Token string `json:"token" validate:"required"`
+	// new password and confirm password are base64 of RSA-OAEP (SHA-256) cipher text
+	// that encrypted by public key of search key
+	NewPassword     string `json:"new_password" validate:"required"`
+	ConfirmPassword string `json:"confirm_password" validate:"required"`
+	SearchKey       string `json:"search_key" validate:"required"`
+}
+
+type ConfirmPasswordResetResponser struct {
+	Header ResponseHeader `json:"header"`
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type RegisterSpiderInfoRequester struct {
 	Header RequestUserHeader `json:"header"`
 	Data   SpiderInfo        `json:"data"`
@@ -26,7 +28,20 @@ type SpiderInfo struct {
 	Designate      string    `json:"designate"`
 	Address        []Address `json:"address"`
 	Paper          []string  `json:"paper"`
-	Image          []string  `json:"image"`
+	// url of image endpoint, size query select resized image, it is ignored in request
+	Image []string `json:"image"`
+	// name of image that is used to remove image
+	ImageFile []string `json:"image_file,omitempty"`
+	Status    string   `json:"status,omitempty"`
+	Review    *Review  `json:"review,omitempty"`
+	// Version is required on edit, it must be version of spider info that is read
+	Version *int64 `json:"version,omitempty"`
+}
+
+type Review struct {
+	Comment    string    `json:"comment"`
+	ReviewedBy string    `json:"reviewed_by"`
+	ReviewedAt time.Time `json:"reviewed_at"`
 }
 
 type Address struct {
@@ -1,6 +1,9 @@
 package model
 
-import "spider-go/model"
+import (
+	"spider-go/model"
+	"time"
+)
 
 // ==================================================
 // get one spider info
@@ -35,6 +38,7 @@ type GetSpiderImageResponsor struct {
 }
 
 type GetSpiderImageRequestData struct {
+	SpiderUUID      string   `json:"spider_uuid" validate:"required"`
 	SpiderImageList []string `json:"spider_image_list"`
 }
 type GetSpiderImageDate struct {
@@ -47,6 +51,43 @@ type SpiderImageList struct {
 
 // **************************************************
 
+// ==================================================
+// get spider image file
+// ==================================================
+
+// GetSpiderImageFileResponser is sent only when image can not be served
+type GetSpiderImageFileResponser struct {
+	Header ResponseHeader `json:"header"`
+}
+
+// **************************************************
+
+// ==================================================
+// suggest spider position from image exif
+// ==================================================
+type SuggestSpiderPositionRequester struct {
+	Header RequestUserHeader                `json:"header"`
+	Data   SuggestSpiderPositionRequestData `json:"data"`
+}
+
+type SuggestSpiderPositionResponser struct {
+	Header ResponseHeader                    `json:"header"`
+	Data   SuggestSpiderPositionResponseData `json:"data"`
+}
+
+type SuggestSpiderPositionRequestData struct {
+	SpiderUUID string `json:"spider_uuid" validate:"required"`
+}
+
+type SuggestSpiderPositionResponseData struct {
+	Position  []Position        `json:"position"`
+	Altitude  string            `json:"altitude"`
+	TakenAt   *time.Time        `json:"taken_at,omitempty"`
+	ImageExif []model.ImageExif `json:"image_exif"`
+}
+
+// **************************************************
+
 // ==================================================
 // get spider list manager
 // ==================================================
@@ -0,0 +1,84 @@
+package model
+
+import "time"
+
+type SpiderRevisionInfo struct {
+	SpiderUUID   string     `json:"spider_uuid"`
+	Revision     int64      `json:"revision"`
+	Action       string     `json:"action"`
+	Snapshot     SpiderInfo `json:"snapshot"`
+	RestoredFrom int64      `json:"restored_from,omitempty"`
+	CreatedBy    string     `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type SpiderRevisionDiff struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// ==================================================
+// spider revision list
+// ==================================================
+type GetSpiderRevisionListRequester struct {
+	Header RequestUserHeader                `json:"header"`
+	Data   GetSpiderRevisionListRequestData `json:"data"`
+}
+
+type GetSpiderRevisionListRequestData struct {
+	SpiderUUID string `json:"spider_uuid" validate:"required"`
+	Page       int32  `json:"page" validate:"min=0"`
+	Size       int32  `json:"size" validate:"min=1,max=100"`
+}
+
+type GetSpiderRevisionListResponser struct {
+	Header ResponseHeader                    `json:"header"`
+	Data   GetSpiderRevisionListResponseData `json:"data"`
+}
+
+type GetSpiderRevisionListResponseData struct {
+	RevisionList []SpiderRevisionInfo `json:"revision_list"`
+	Total        int64                `json:"total"`
+}
+
+// ==================================================
+// diff between two revisions
+// ==================================================
+type DiffSpiderRevisionRequester struct {
+	Header RequestUserHeader             `json:"header"`
+	Data   DiffSpiderRevisionRequestData `json:"data"`
+}
+
+type DiffSpiderRevisionRequestData struct {
+	SpiderUUID   string `json:"spider_uuid" validate:"required"`
+	FromRevision int64  `json:"from_revision" validate:"min=1"`
+	ToRevision   int64  `json:"to_revision" validate:"min=1"`
+}
+
+type DiffSpiderRevisionResponser struct {
+	Header ResponseHeader                 `json:"header"`
+	Data   DiffSpiderRevisionResponseData `json:"data"`
+}
+
+type DiffSpiderRevisionResponseData struct {
+	DiffList []SpiderRevisionDiff `json:"diff_list"`
+}
+
+// ==================================================
+// restore revision
+// ==================================================
+type RestoreSpiderRevisionRequester struct {
+	Header RequestUserHeader                `json:"header"`
+	Data   RestoreSpiderRevisionRequestData `json:"data"`
+}
+
+type RestoreSpiderRevisionRequestData struct {
+	SpiderUUID string `json:"spider_uuid" validate:"required"`
+	Revision   int64  `json:"revision" validate:"min=1"`
+}
+
+type RestoreSpiderRevisionResponser struct {
+	Header ResponseHeader     `json:"header"`
+	Data   SpiderRevisionInfo `json:"data"`
+}
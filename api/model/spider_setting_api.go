@@ -1,5 +1,7 @@
 package model
 
+import "encoding/json"
+
 // upload spider image
 type SpiderImageSettingRequester struct {
 	Header RequestUserHeader      `json:"header"`
@@ -16,6 +18,24 @@ type SpiderImageSettingData struct {
 	ListImageEncode []string `json:"list_image_encode"`
 }
 
+// upload spider image file, request is multipart form that spider_uuid field
+// is sent before image file fields
+type UploadSpiderImageFileResponser struct {
+	Header ResponseHeader                    `json:"header"`
+	Data   UploadSpiderImageFileResponseData `json:"data"`
+}
+
+type UploadSpiderImageFileResponseData struct {
+	Files []UploadSpiderImageFileResult `json:"files"`
+}
+
+type UploadSpiderImageFileResult struct {
+	FileName  string `json:"file_name"`
+	ImageName string `json:"image_name,omitempty"`
+	ErrorCode string `json:"error_code"`
+	Message   string `json:"message"`
+}
+
 // delete spider info
 type DeleteSpiderRequester struct {
 	Header RequestUserHeader `json:"header"`
@@ -37,9 +57,36 @@ type EditSpiderInfoRequester struct {
 	Data   SpiderInfo        `json:"data"`
 }
 
+// data is updated spider info on success and current server copy on version conflict
 type EditSpiderInfoResponser struct {
 	Header ResponseHeader `json:"header"`
-	Data   struct{}       `json:"data"`
+	Data   *SpiderInfo    `json:"data,omitempty"`
+}
+
+// patch spider info, request must have only one of merge patch (RFC 7386) or json patch (RFC 6902),
+// path of patch is json name of SpiderInfo, e.g. `/address/0/position/-`
+type PatchSpiderInfoRequester struct {
+	Header RequestUserHeader          `json:"header"`
+	Data   PatchSpiderInfoRequestData `json:"data"`
+}
+
+type PatchSpiderInfoRequestData struct {
+	SpiderUUID string           `json:"spider_uuid" validate:"required"`
+	Version    *int64           `json:"version" validate:"required"`
+	MergePatch json.RawMessage  `json:"merge_patch,omitempty"`
+	JSONPatch  []PatchOperation `json:"json_patch,omitempty" validate:"omitempty,dive"`
+}
+
+type PatchOperation struct {
+	Op    string          `json:"op" validate:"required,oneof=add remove replace move copy test"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type PatchSpiderInfoResponser struct {
+	Header ResponseHeader `json:"header"`
+	Data   *SpiderInfo    `json:"data,omitempty"`
 }
 
 // remove spider image
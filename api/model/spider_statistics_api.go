@@ -24,6 +24,8 @@ type GetFamilyListRequester struct {
 type GetFamilyListRequestData struct {
 	Page int32 `json:"page" validate:"min=0"`
 	Size int32 `json:"size" validate:"min=1"`
+	// sort by family name or record count, default is name
+	SortBy string `json:"sort_by" validate:"omitempty,oneof=name count"`
 }
 
 type GetFamilyListResponser struct {
@@ -33,4 +35,25 @@ type GetFamilyListResponser struct {
 
 type GetFamilyListResponseData struct {
 	FamilyList []model.FamilyList `json:"family_list"`
+	Total      int64              `json:"total"`
+}
+
+// **************************************************
+
+// ==================================================
+// rebuild spider statistics
+// ==================================================
+type RebuildSpiderStatisticsRequester struct {
+	Header RequestUserHeader                  `json:"header"`
+	Data   RebuildSpiderStatisticsRequestData `json:"data"`
+}
+
+type RebuildSpiderStatisticsRequestData struct {
+	// statistics is replaced only when apply is true, otherwise only difference is reported
+	Apply bool `json:"apply"`
+}
+
+type RebuildSpiderStatisticsResponser struct {
+	Header ResponseHeader                       `json:"header"`
+	Data   *model.SpiderStatisticsRebuildReport `json:"data"`
 }
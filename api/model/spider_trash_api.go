@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+type SpiderTrashInfo struct {
+	SpiderInfo SpiderInfo `json:"spider_info"`
+	DeletedBy  string     `json:"deleted_by"`
+	DeletedAt  *time.Time `json:"deleted_at"`
+}
+
+// ==================================================
+// spider trash list
+// ==================================================
+type GetSpiderTrashListRequester struct {
+	Header RequestUserHeader             `json:"header"`
+	Data   GetSpiderTrashListRequestData `json:"data"`
+}
+
+type GetSpiderTrashListRequestData struct {
+	Page int32 `json:"page" validate:"min=0"`
+	Size int32 `json:"size" validate:"min=1,max=100"`
+}
+
+type GetSpiderTrashListResponser struct {
+	Header ResponseHeader                 `json:"header"`
+	Data   GetSpiderTrashListResponseData `json:"data"`
+}
+
+type GetSpiderTrashListResponseData struct {
+	TrashList []SpiderTrashInfo `json:"trash_list"`
+	Total     int64             `json:"total"`
+}
+
+// ==================================================
+// restore spider info from trash
+// ==================================================
+type RestoreSpiderTrashRequester struct {
+	Header RequestUserHeader             `json:"header"`
+	Data   RestoreSpiderTrashRequestData `json:"data"`
+}
+
+type RestoreSpiderTrashRequestData struct {
+	SpiderUUID string `json:"spider_uuid" validate:"required"`
+}
+
+type RestoreSpiderTrashResponser struct {
+	Header ResponseHeader `json:"header"`
+}
@@ -0,0 +1,58 @@
+package model
+
+// ==================================================
+// submit spider info for review
+// ==================================================
+type SubmitSpiderInfoRequester struct {
+	Header RequestUserHeader           `json:"header"`
+	Data   SubmitSpiderInfoRequestData `json:"data"`
+}
+
+type SubmitSpiderInfoRequestData struct {
+	SpiderUUID string `json:"spider_uuid" validate:"required"`
+}
+
+type SubmitSpiderInfoResponser struct {
+	Header ResponseHeader `json:"header"`
+}
+
+// ==================================================
+// review spider info
+// ==================================================
+type ReviewSpiderInfoRequester struct {
+	Header RequestUserHeader           `json:"header"`
+	Data   ReviewSpiderInfoRequestData `json:"data"`
+}
+
+type ReviewSpiderInfoRequestData struct {
+	SpiderUUID string `json:"spider_uuid" validate:"required"`
+	Approve    bool   `json:"approve"`
+	Comment    string `json:"comment" validate:"max=1000"`
+}
+
+type ReviewSpiderInfoResponser struct {
+	Header ResponseHeader `json:"header"`
+}
+
+// ==================================================
+// review queue
+// ==================================================
+type GetReviewQueueRequester struct {
+	Header RequestUserHeader         `json:"header"`
+	Data   GetReviewQueueRequestData `json:"data"`
+}
+
+type GetReviewQueueRequestData struct {
+	Page int32 `json:"page" validate:"min=0"`
+	Size int32 `json:"size" validate:"min=1,max=100"`
+}
+
+type GetReviewQueueResponser struct {
+	Header ResponseHeader             `json:"header"`
+	Data   GetReviewQueueResponseData `json:"data"`
+}
+
+type GetReviewQueueResponseData struct {
+	SpiderList []SpiderInfo `json:"spider_list"`
+	Total      int64        `json:"total"`
+}
@@ -1,15 +1,20 @@
 package route
 
 import (
+	"context"
 	"net/http"
 	"spider-go/api/handler"
 	"spider-go/api/middleware"
 	"spider-go/config"
 	"spider-go/database"
 	"spider-go/logger"
+	"spider-go/model"
 	"spider-go/repository"
 	"spider-go/usecase"
+	"spider-go/utils/imaging"
 	jwt_service "spider-go/utils/jwt"
+	"spider-go/utils/notifier"
+	"spider-go/utils/storage"
 
 	limits "github.com/gin-contrib/size"
 	"github.com/gin-gonic/gin"
@@ -21,7 +26,11 @@ func SetupRoutes(log *logger.Logger, conf *config.Root) *gin.Engine {
 	// create service
 	// ==========================================================
 
-	jwtService := jwt_service.NewJWTService(config.C().JWT.Secret, config.C().JWT.ExpireTime, conf.JWT.Issure)
+	jwtService := jwt_service.NewJWTService(config.C().JWT.Secret, config.C().JWT.ExpireTime, conf.JWT.Issure, conf.JWT.Algorithm)
+	notifierService := notifier.NewNotifier(conf.Notifier)
+	imageStorage := storage.NewImageStorage(conf.File)
+	imageURLSigner := storage.NewImageURLSigner(conf.File.ImageURL)
+	derivativeGenerator := imaging.NewDerivativeGenerator(imageStorage, conf.File.Derivative)
 
 	// ==========================================================
 	// create repository
@@ -31,32 +40,71 @@ func SetupRoutes(log *logger.Logger, conf *config.Root) *gin.Engine {
 	spiderStatisticsRepo := repository.NewSpiderStatisticsRepository(database.DB)
 	spiderRepo := repository.NewSpiderRepository(database.DB)
 	thaiGeographiesRepo := repository.NewThaiGeographiesRepository(database.DB)
+	redisRepo := repository.NewRedisRepository(database.RedisClient)
+	signingKeyRepo := repository.NewSigningKeyRepository(database.DB)
+	apiKeyRepo := repository.NewAPIKeyRepository(database.DB)
+	spiderRevisionRepo := repository.NewSpiderRevisionRepository(database.DB)
+	unitOfWork := repository.NewMongoUnitOfWork(database.Client)
 
 	// ==========================================================
 	// create usecase
 	// ==========================================================
 
-	authoritailUsecase := usecase.NewAuthoritiesUsecase(accountRepo, jwtService)
+	authoritailUsecase := usecase.NewAuthoritiesUsecase(accountRepo, redisRepo, jwtService, conf)
+	getRsaKeyUsecase := usecase.NewGetRsaKey(redisRepo, conf)
+	signingKeyUsecase := usecase.NewSigningKeyUsecase(signingKeyRepo, jwtService, conf)
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(apiKeyRepo)
+	accountManagementUsecase := usecase.NewAccountManagementUsecase(accountRepo, authoritailUsecase, conf)
+	mfaUsecase := usecase.NewMFAUsecase(accountRepo, redisRepo, conf)
+	passwordResetUsecase := usecase.NewPasswordResetUsecase(accountRepo, redisRepo, notifierService, authoritailUsecase, conf)
 	spiderStatisticsUsecase := usecase.NewSpiderStatisticsUsecase(spiderStatisticsRepo)
-	registerSpiderUsercase := usecase.NewRegisterSpiderUsecase(spiderRepo, spiderStatisticsRepo)
-	uploadImageusecase := usecase.NewUploadImageUsecase(spiderRepo)
-	spiderInfoUsecase := usecase.NewSpiderInfoUsecase(spiderRepo, accountRepo, conf.File.FileImagePath)
-	deleteSpiderInfoUsecase := usecase.NewDeleteSpiderInfoUsecase(spiderRepo)
-	updateSpiderInfoUsecase := usecase.NewUpdateSpiderInfoUsecase(spiderRepo)
-	removeSpiderImageUsecase := usecase.NewRemoveSpiderImageUsecase(spiderRepo, conf.File.FileImagePath)
+	registerSpiderUsercase := usecase.NewRegisterSpiderUsecase(spiderRepo, spiderStatisticsRepo, spiderRevisionRepo, unitOfWork)
+	uploadImageusecase := usecase.NewUploadImageUsecase(spiderRepo, imageStorage, derivativeGenerator, conf)
+	spiderInfoUsecase := usecase.NewSpiderInfoUsecase(spiderRepo, imageStorage, imageURLSigner, conf)
+	deleteSpiderInfoUsecase := usecase.NewDeleteSpiderInfoUsecase(spiderRepo, spiderStatisticsRepo, spiderRevisionRepo, unitOfWork)
+	updateSpiderInfoUsecase := usecase.NewUpdateSpiderInfoUsecase(spiderRepo, spiderStatisticsRepo, spiderRevisionRepo, unitOfWork)
+	spiderRevisionUsecase := usecase.NewSpiderRevisionUsecase(spiderRepo, spiderStatisticsRepo, spiderRevisionRepo, unitOfWork)
+	spiderTrashUsecase := usecase.NewSpiderTrashUsecase(spiderRepo, spiderStatisticsRepo, spiderRevisionRepo, unitOfWork, imageStorage, conf)
+	spiderWorkflowUsecase := usecase.NewSpiderWorkflowUsecase(spiderRepo, spiderStatisticsRepo, spiderRevisionRepo, unitOfWork)
+	removeSpiderImageUsecase := usecase.NewRemoveSpiderImageUsecase(spiderRepo, imageStorage)
 	thaiGeographiesUsecase := usecase.NewThaiGeographiesUsecase(thaiGeographiesRepo, spiderRepo)
-	getFamilyListUsecase := usecase.NewGetFamilyListUsecase(spiderStatisticsRepo, spiderRepo)
+	getFamilyListUsecase := usecase.NewGetFamilyListUsecase(spiderRepo)
+	spiderStatisticsRebuildUsecase := usecase.NewSpiderStatisticsRebuildUsecase(spiderStatisticsRepo, unitOfWork)
+
+	// ==========================================================
+	// load jwt signing key and rotate in background
+	// ==========================================================
+
+	if err := signingKeyUsecase.RefreshSigningKeys(context.Background()); err != nil {
+		log.Errorf("load jwt signing key error: %+v", err)
+	}
+	go signingKeyUsecase.RunKeyRotation(context.Background(), conf.JWT.ReloadInterval)
+
+	// ==========================================================
+	// purge spider info that stay in trash longer than retention period
+	// ==========================================================
+
+	go spiderTrashUsecase.RunTrashPurge(context.Background(), conf.SpiderTrash.PurgeInterval)
 
 	// ==========================================================
 	// create handler
 	// ==========================================================
 
-	createAccoutHandler := handler.NewCreateAccountHandler(authoritailUsecase)
-	loginHandler := handler.NewLoginHandler(authoritailUsecase)
+	createAccoutHandler := handler.NewCreateAccountHandler(authoritailUsecase, getRsaKeyUsecase)
+	loginHandler := handler.NewLoginHandler(authoritailUsecase, getRsaKeyUsecase)
+	rsaHandler := handler.NewRsaHandler(getRsaKeyUsecase)
+	jwksHandler := handler.NewJWKSHandler(signingKeyUsecase)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyUsecase)
+	accountManagementHandler := handler.NewAccountManagementHandler(accountManagementUsecase)
+	mfaHandler := handler.NewMFAHandler(mfaUsecase)
+	passwordResetHandler := handler.NewPasswordResetHandler(passwordResetUsecase, getRsaKeyUsecase)
 	registerHandler := handler.NewRegisterHandler(registerSpiderUsercase)
-	spiderStatisticsHandler := handler.NewGetSpiderStatisricsHandler(spiderStatisticsUsecase, getFamilyListUsecase)
-	spiderSettingHandler := handler.NewSpiderSettingHandler(uploadImageusecase, deleteSpiderInfoUsecase, updateSpiderInfoUsecase, removeSpiderImageUsecase)
-	spiderInfoHandler := handler.NewSpiderInfoHandler(spiderInfoUsecase, thaiGeographiesUsecase)
+	spiderStatisticsHandler := handler.NewGetSpiderStatisricsHandler(spiderStatisticsUsecase, getFamilyListUsecase, spiderStatisticsRebuildUsecase)
+	spiderSettingHandler := handler.NewSpiderSettingHandler(uploadImageusecase, deleteSpiderInfoUsecase, updateSpiderInfoUsecase, removeSpiderImageUsecase, imageURLSigner)
+	spiderRevisionHandler := handler.NewSpiderRevisionHandler(spiderRevisionUsecase, imageURLSigner)
+	spiderTrashHandler := handler.NewSpiderTrashHandler(spiderTrashUsecase, imageURLSigner)
+	spiderWorkflowHandler := handler.NewSpiderWorkflowHandler(spiderWorkflowUsecase, imageURLSigner)
+	spiderInfoHandler := handler.NewSpiderInfoHandler(spiderInfoUsecase, thaiGeographiesUsecase, imageURLSigner)
 	getGeographiesHandler := handler.NewGetGeographinesHandler(thaiGeographiesUsecase)
 
 	// ==========================================================
@@ -66,8 +114,17 @@ func SetupRoutes(log *logger.Logger, conf *config.Root) *gin.Engine {
 	r := gin.Default()
 	r.Use(gin.Recovery())
 
+	// client ip is used by login protection, so forwarded header is trusted only from configured proxy
+	if err := r.SetTrustedProxies(conf.API.TrustedProxies); err != nil {
+		log.Fatalf("set trusted proxies %v error: %+v", conf.API.TrustedProxies, err)
+	}
+
 	r.Use(middleware.CORSMiddleware())
 
+	// multipart upload is limited by upload config instead of maximum request size of api,
+	// group copy middleware of engine when it is created so it is created before request size limiter
+	g3 := r.Group("")
+
 	r.Use(limits.RequestSizeLimiter(conf.API.MaxRequestSize))
 	r.Use(
 		gin.Recovery(),
@@ -84,6 +141,12 @@ func SetupRoutes(log *logger.Logger, conf *config.Root) *gin.Engine {
 		})
 	})
 
+	// public key for other service verify access token
+	r.GET("/.well-known/jwks.json", jwksHandler.GetJWKSHandler)
+
+	// spider image for img tag, image that is not public is read by signed url in spider info
+	r.GET("/spider/:spider_uuid/image/:image_name", spiderInfoHandler.GetSpiderImageFileHandler)
+
 	// ==========================================================
 	// group 1: no login required
 	// ==========================================================
@@ -91,8 +154,13 @@ func SetupRoutes(log *logger.Logger, conf *config.Root) *gin.Engine {
 	g1 := r.Group("")
 	{
 		// post
+		g1.POST("", rsaHandler.RequestRsaPublicKey)
 		g1.POST("", createAccoutHandler.CreateAccout)
 		g1.POST("", loginHandler.Login)
+		g1.POST("", loginHandler.VerifyMFA)
+		g1.POST("", loginHandler.RefreshToken)
+		g1.POST("", passwordResetHandler.RequestPasswordResetHandler)
+		g1.POST("", passwordResetHandler.ConfirmPasswordResetHandler)
 		g1.POST("", spiderInfoHandler.GetOneSpiderInfoHandler)
 		g1.POST("", spiderInfoHandler.GetSpiderImagesHandler)
 		g1.POST("", getGeographiesHandler.GetProvinceHandler)
@@ -112,16 +180,52 @@ func SetupRoutes(log *logger.Logger, conf *config.Root) *gin.Engine {
 	// ==========================================================
 
 	g2 := r.Group("")
-	g2.Use(middleware.Authenticate(jwtService))
+	g2.Use(middleware.Authenticate(jwtService, authoritailUsecase, apiKeyUsecase))
 	{
 		g2.POST("", loginHandler.VerifyLogin)
-		g2.POST("", registerHandler.RegisterHandler)
-		g2.POST("", spiderSettingHandler.UploadImageSpiderHandler)
+		g2.POST("", loginHandler.Logout)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_ACCOUNT_MANAGE), loginHandler.RevokeUserSessions)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_ACCOUNT_MANAGE), accountManagementHandler.GetAccountListHandler)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_ACCOUNT_MANAGE), accountManagementHandler.UpdateAccountStatusHandler)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_ACCOUNT_MANAGE), accountManagementHandler.UpdateAccountRoleHandler)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_ACCOUNT_MANAGE), accountManagementHandler.ResetMFAHandler)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_API_KEY_MANAGE), apiKeyHandler.CreateAPIKeyHandler)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_API_KEY_MANAGE), apiKeyHandler.GetAPIKeyListHandler)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_API_KEY_MANAGE), apiKeyHandler.RevokeAPIKeyHandler)
+		g2.POST("", accountManagementHandler.UpdateProfileHandler)
+		g2.POST("", accountManagementHandler.ChangePasswordHandler)
+		g2.POST("", mfaHandler.EnrollMFAHandler)
+		g2.POST("", mfaHandler.ActivateMFAHandler)
+		g2.POST("", mfaHandler.DisableMFAHandler)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_SPIDER_CREATE), registerHandler.RegisterHandler)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_IMAGE_WRITE), spiderSettingHandler.UploadImageSpiderHandler)
 		g2.POST("", spiderInfoHandler.GetOneSpiderInfoHandler)
-		g2.POST("", spiderInfoHandler.GetSpiderInfoListManagerHandler)
-		g2.POST("", spiderSettingHandler.DeleteSpiderHandler)
-		g2.POST("", spiderSettingHandler.EditSpiderInfoHandler)
-		g2.POST("", spiderSettingHandler.RemoveSpiderImageHandler)
+		g2.POST("", middleware.RequireRole(model.ACCOUNT_ROLE_MASTER, model.ACCOUNT_ROLE_ADMIN), spiderInfoHandler.GetSpiderInfoListManagerHandler)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_SPIDER_DELETE), spiderSettingHandler.DeleteSpiderHandler)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_SPIDER_CREATE), spiderSettingHandler.EditSpiderInfoHandler)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_SPIDER_CREATE), spiderSettingHandler.PatchSpiderInfoHandler)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_IMAGE_WRITE), spiderSettingHandler.RemoveSpiderImageHandler)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_SPIDER_WRITE), spiderInfoHandler.SuggestSpiderPositionHandler)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_SPIDER_READ), spiderRevisionHandler.GetSpiderRevisionListHandler)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_SPIDER_READ), spiderRevisionHandler.DiffSpiderRevisionHandler)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_SPIDER_WRITE), spiderRevisionHandler.RestoreSpiderRevisionHandler)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_SPIDER_DELETE), spiderTrashHandler.GetSpiderTrashListHandler)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_SPIDER_DELETE), spiderTrashHandler.RestoreSpiderTrashHandler)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_SPIDER_CREATE), spiderWorkflowHandler.SubmitSpiderInfoHandler)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_SPIDER_REVIEW), spiderWorkflowHandler.ReviewSpiderInfoHandler)
+		g2.POST("", middleware.RequirePermission(model.PERMISSION_SPIDER_REVIEW), spiderWorkflowHandler.GetReviewQueueHandler)
+		g2.POST("", middleware.RequireRole(model.ACCOUNT_ROLE_MASTER, model.ACCOUNT_ROLE_ADMIN), spiderStatisticsHandler.RebuildSpiderStatisticsHandler)
+	}
+	// **********************************************************
+
+	// ==========================================================
+	// group 3: login required, multipart upload
+	// ==========================================================
+
+	g3.Use(limits.RequestSizeLimiter(conf.File.Upload.MaxRequestSize))
+	g3.Use(middleware.Authenticate(jwtService, authoritailUsecase, apiKeyUsecase))
+	{
+		g3.POST("", middleware.RequirePermission(model.PERMISSION_IMAGE_WRITE), spiderSettingHandler.UploadImageFilesHandler)
 	}
 	// **********************************************************
 
@@ -19,6 +19,36 @@ type RootError struct {
 	GeographiesNotFound    ErrorCode `mapstructure:"geographies_not_found" json:"geographies_not_found"`
 	RequestDataFail        ErrorCode `mapstructure:"request_data_fail" json:"request_data_fail"`
 	RequestDataNotFound    ErrorCode `mapstructure:"request_data_not_found" json:"request_data_not_found"`
+	InvalidRefreshToken    ErrorCode `mapstructure:"invalid_refresh_token" json:"invalid_refresh_token"`
+	RefreshTokenReused     ErrorCode `mapstructure:"refresh_token_reused" json:"refresh_token_reused"`
+	AccountDisabled        ErrorCode `mapstructure:"account_disabled" json:"account_disabled"`
+	AccountNotFound        ErrorCode `mapstructure:"account_not_found" json:"account_not_found"`
+	AccountLocked          ErrorCode `mapstructure:"account_locked" json:"account_locked"`
+	RSAKeyExpired          ErrorCode `mapstructure:"rsa_key_expired" json:"rsa_key_expired"`
+	RSAKeyReplayed         ErrorCode `mapstructure:"rsa_key_replayed" json:"rsa_key_replayed"`
+	InvalidAPIKey          ErrorCode `mapstructure:"invalid_api_key" json:"invalid_api_key"`
+	APIKeyNotFound         ErrorCode `mapstructure:"api_key_not_found" json:"api_key_not_found"`
+	InvalidResetToken      ErrorCode `mapstructure:"invalid_reset_token" json:"invalid_reset_token"`
+	SendNotificationError  ErrorCode `mapstructure:"send_notification_error" json:"send_notification_error"`
+	InvalidMFAToken        ErrorCode `mapstructure:"invalid_mfa_token" json:"invalid_mfa_token"`
+	InvalidMFACode         ErrorCode `mapstructure:"invalid_mfa_code" json:"invalid_mfa_code"`
+	MFAAlreadyEnabled      ErrorCode `mapstructure:"mfa_already_enabled" json:"mfa_already_enabled"`
+	MFANotEnabled          ErrorCode `mapstructure:"mfa_not_enabled" json:"mfa_not_enabled"`
+	MFARequiredByRole      ErrorCode `mapstructure:"mfa_required_by_role" json:"mfa_required_by_role"`
+	MFAEnrollExpired       ErrorCode `mapstructure:"mfa_enroll_expired" json:"mfa_enroll_expired"`
+	SpiderRevisionNotFound ErrorCode `mapstructure:"spider_revision_not_found" json:"spider_revision_not_found"`
+	SpiderNotInTrash       ErrorCode `mapstructure:"spider_not_in_trash" json:"spider_not_in_trash"`
+	InvalidTransition      ErrorCode `mapstructure:"invalid_spider_status_transition" json:"invalid_spider_status_transition"`
+	ReviewCommentRequired  ErrorCode `mapstructure:"review_comment_required" json:"review_comment_required"`
+	SpiderVersionConflict  ErrorCode `mapstructure:"spider_version_conflict" json:"spider_version_conflict"`
+	InvalidSpiderPatch     ErrorCode `mapstructure:"invalid_spider_patch" json:"invalid_spider_patch"`
+	SpiderImageNotFound    ErrorCode `mapstructure:"spider_image_not_found" json:"spider_image_not_found"`
+	SpiderImageURLInvalid  ErrorCode `mapstructure:"spider_image_url_invalid" json:"spider_image_url_invalid"`
+	ImageFileTooLarge      ErrorCode `mapstructure:"image_file_too_large" json:"image_file_too_large"`
+	TooManyImageFiles      ErrorCode `mapstructure:"too_many_image_files" json:"too_many_image_files"`
+	NoSpiderImageExif      ErrorCode `mapstructure:"no_spider_image_exif" json:"no_spider_image_exif"`
+	SpiderInTrash          ErrorCode `mapstructure:"spider_in_trash" json:"spider_in_trash"`
+	InvalidFamilyListSort  ErrorCode `mapstructure:"invalid_family_list_sort" json:"invalid_family_list_sort"`
 }
 
 type ErrorCode struct {
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"spider-go/config"
+	"spider-go/database"
+	"spider-go/domain"
+	"spider-go/repository"
+	"spider-go/usecase"
+	"spider-go/utils/imaging"
+	"spider-go/utils/storage"
+)
+
+// runCommand run admin command by name, args is argument after command name
+func runCommand(name string, args []string) error {
+	switch name {
+	case "rebuild-statistics":
+		return rebuildStatisticsCommand(args)
+	case "backfill-image-derivatives":
+		return backfillImageDerivativesCommand(args)
+	default:
+		return fmt.Errorf("unknown command `%s`", name)
+	}
+}
+
+// rebuildStatisticsCommand print difference between spider statistics and spider info,
+// statistics is replaced only when -apply is set
+func rebuildStatisticsCommand(args []string) error {
+	flagSet := flag.NewFlagSet("rebuild-statistics", flag.ContinueOnError)
+	apply := flagSet.Bool("apply", false, "replace spider statistics with rebuilt one, default is dry run")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	statisticsRepo := repository.NewSpiderStatisticsRepository(database.DB)
+	unitOfWork := repository.NewMongoUnitOfWork(database.Client)
+	rebuildUsecase := usecase.NewSpiderStatisticsRebuildUsecase(statisticsRepo, unitOfWork)
+
+	report, err := rebuildUsecase.RebuildSpiderStatistics(context.Background(), !*apply)
+	if err != nil {
+		return err
+	}
+
+	return writeCommandReport(os.Stdout, report)
+}
+
+// backfillImageDerivativesCommand generate resized image of image that is uploaded before derivative is added,
+// existing derivative is generated again only when -overwrite is set, e.g. after derivative size is changed
+func backfillImageDerivativesCommand(args []string) error {
+	flagSet := flag.NewFlagSet("backfill-image-derivatives", flag.ContinueOnError)
+	overwrite := flagSet.Bool("overwrite", false, "generate derivative again even it exists")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	imageStorage := storage.NewImageStorage(config.C().File)
+	derivativeGenerator := imaging.NewDerivativeGenerator(imageStorage, config.C().File.Derivative)
+
+	spiderRepo := repository.NewSpiderRepository(database.DB)
+	derivativeUsecase := usecase.NewImageDerivativeUsecase(spiderRepo, derivativeGenerator)
+
+	return backfillImageDerivatives(context.Background(), derivativeUsecase, *overwrite, os.Stdout)
+}
+
+// backfillImageDerivatives write report of backfill to out, image that failed is in report so it does not fail command
+func backfillImageDerivatives(ctx context.Context, derivativeUsecase domain.ImageDerivativeUsecase, overwrite bool, out io.Writer) error {
+	report, err := derivativeUsecase.BackfillImageDerivatives(ctx, overwrite)
+	if err != nil {
+		return err
+	}
+
+	return writeCommandReport(out, report)
+}
+
+// writeCommandReport write report of command as indented json
+func writeCommandReport(out io.Writer, report interface{}) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(report)
+}
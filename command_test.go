@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	mock_domain "spider-go/domain/mock"
+	"spider-go/model"
+	"spider-go/usecase"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+)
+
+// ======================================================================
+// TestRunCommand
+// ======================================================================
+
+// command that is unknown or has invalid flag fail before database is used
+func TestRunCommand(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+	}{
+		{name: "unknown_command", command: "drop-database", args: nil},
+		{name: "rebuild_statistics_unknown_flag", command: "rebuild-statistics", args: []string{"-force"}},
+		{name: "backfill_image_derivatives_unknown_flag", command: "backfill-image-derivatives", args: []string{"-size=large"}},
+		{name: "backfill_image_derivatives_invalid_flag_value", command: "backfill-image-derivatives", args: []string{"-overwrite=maybe"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := runCommand(tt.command, tt.args); err == nil {
+				t.Errorf("runCommand() error = nil, want error")
+			}
+		})
+	}
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestBackfillImageDerivatives
+// ======================================================================
+func TestBackfillImageDerivatives(t *testing.T) {
+
+	report := &model.ImageDerivativeBackfillReport{
+		ImageCount: 3,
+		Generated:  1,
+		Skipped:    1,
+		Failed: []model.ImageDerivativeBackfillFailure{
+			{SpiderUUID: "SPIDER_1", ImageName: "SPIDER_1-a.png", Error: "decode image failed"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		overwrite  bool
+		buildStubs func(*mock_domain.MockImageDerivativeUsecase)
+		want       *model.ImageDerivativeBackfillReport
+		wantErr    error
+	}{
+		{
+			name:      "success_write_report",
+			overwrite: false,
+			buildStubs: func(derivativeUsecase *mock_domain.MockImageDerivativeUsecase) {
+				derivativeUsecase.EXPECT().BackfillImageDerivatives(gomock.Any(), gomock.Eq(false)).Return(report, nil)
+			},
+			want:    report,
+			wantErr: nil,
+		},
+		{
+			name:      "success_overwrite",
+			overwrite: true,
+			buildStubs: func(derivativeUsecase *mock_domain.MockImageDerivativeUsecase) {
+				derivativeUsecase.EXPECT().BackfillImageDerivatives(gomock.Any(), gomock.Eq(true)).Return(report, nil)
+			},
+			want:    report,
+			wantErr: nil,
+		},
+		{
+			name:      "backfill_error",
+			overwrite: false,
+			buildStubs: func(derivativeUsecase *mock_domain.MockImageDerivativeUsecase) {
+				derivativeUsecase.EXPECT().BackfillImageDerivatives(gomock.Any(), gomock.Any()).Return(nil, usecase.ErrorImageDerivativeMongoConnection)
+			},
+			want:    nil,
+			wantErr: usecase.ErrorImageDerivativeMongoConnection,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			derivativeUsecase := mock_domain.NewMockImageDerivativeUsecase(ctrl)
+			tt.buildStubs(derivativeUsecase)
+
+			var out bytes.Buffer
+			err := backfillImageDerivatives(context.TODO(), derivativeUsecase, tt.overwrite, &out)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("backfillImageDerivatives() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.want == nil {
+				if out.Len() != 0 {
+					t.Errorf("backfillImageDerivatives() output = %v, want empty", out.String())
+				}
+				return
+			}
+
+			var got model.ImageDerivativeBackfillReport
+			if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+				t.Errorf("unmarshal output error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(&got, tt.want) {
+				t.Errorf("backfillImageDerivatives() output = %+v, want %+v", got, *tt.want)
+			}
+		})
+	}
+}
+
+// **********************************************************************
@@ -10,17 +10,40 @@ type Root struct {
 	RedisOption RedisOptions `mapstructure:"redis_options"`
 	RSAOption   RSAOption    `mapstructure:"rsa_option"`
 	File        File         `mapstructure:"file"`
+	// policy of username and password when create account or change password
+	CredentialPolicy CredentialPolicy `mapstructure:"credential_policy"`
+	// brute-force protection of login
+	LoginProtection LoginProtection `mapstructure:"login_protection"`
+	// algorithm and parameter of password hash, hash that not match is upgraded when login
+	PasswordHash PasswordHash `mapstructure:"password_hash"`
+	// token of forgot password flow
+	PasswordReset PasswordReset `mapstructure:"password_reset"`
+	// delivery of message to user, e.g. password reset token
+	Notifier Notifier `mapstructure:"notifier"`
+	// TOTP second factor
+	MFA MFA `mapstructure:"mfa"`
+	// deleted spider info that wait for purge
+	SpiderTrash SpiderTrash `mapstructure:"spider_trash"`
 }
 
 type API struct {
 	RunningPort    string `mapstructure:"running_port"`
 	MaxRequestSize int64  `mapstructure:"maximum_request_size"`
+	// ip or cidr of reverse proxy that X-Forwarded-For is trusted from, client ip is remote address when it is empty
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 type JWT struct {
 	Secret     string        `mapstructure:"secret"`
 	ExpireTime time.Duration `mapstructure:"expire_time"`
 	Issure     string        `mapstructure:"issure"`
+	// HS256 (default, sign with secret), RS256 or EdDSA (sign with rotating key pair)
+	Algorithm  string `mapstructure:"algorithm"`
+	RSAKeySize int    `mapstructure:"rsa_key_size"`
+	// create new signing key when active key is older than rotation interval
+	RotationInterval time.Duration `mapstructure:"rotation_interval"`
+	// interval for check rotation and reload key set that may rotated by other instance
+	ReloadInterval time.Duration `mapstructure:"reload_interval"`
 }
 
 type MongoConfig struct {
@@ -38,8 +61,34 @@ type RedisConfig struct {
 }
 
 type RedisOptions struct {
-	RSA   RedisOption `mapstructure:"rsa"`
+	// key format of rsa key pair for encrypt credential, param is search key
+	RSA RedisOption `mapstructure:"rsa"`
+	// key format of marker that rsa key is already used, param is search key
+	// and ttl should not shorter than ttl of rsa key
+	RSAUsed RedisOption `mapstructure:"rsa_used"`
+	// key format of refresh token session, param is session family id
+	// and ttl is lifetime of session family since login
 	Login RedisOption `mapstructure:"login"`
+	// key format of access token denylist, param is jti, ttl is come from exp of token
+	TokenDenylist RedisOption `mapstructure:"token_denylist"`
+	// key format of revoke all sessions of user, param is username,
+	// ttl must longer than lifetime of access token and session family
+	RevokeUser RedisOption `mapstructure:"revoke_user"`
+	// key format of failed login counter, param is username or client ip,
+	// ttl is window of counting failed attempt
+	LoginAttemptUser RedisOption `mapstructure:"login_attempt_user"`
+	LoginAttemptIP   RedisOption `mapstructure:"login_attempt_ip"`
+	// key format of password reset token, param is sha256 of token,
+	// ttl is lifetime of token
+	PasswordReset RedisOption `mapstructure:"password_reset"`
+	// key format of latest password reset token of user, param is username,
+	// ttl should equal ttl of password reset token
+	PasswordResetUser RedisOption `mapstructure:"password_reset_user"`
+	// key format of login that wait for second factor, param is sha256 of mfa token,
+	// ttl is lifetime of mfa token
+	MFAPending RedisOption `mapstructure:"mfa_pending"`
+	// key format of secret that wait for activate, param is username
+	MFAEnroll RedisOption `mapstructure:"mfa_enroll"`
 }
 
 type RedisOption struct {
@@ -55,4 +104,145 @@ type RSAOption struct {
 type File struct {
 	SpiderImage   string `mapstructure:"spider_image"`
 	FileImagePath string `mapstructure:"file_image_path"`
+	// local (default, image is kept in file image path) or s3
+	StorageType string    `mapstructure:"storage_type"`
+	S3          S3Storage `mapstructure:"s3"`
+	// url of image endpoint in spider info
+	ImageURL ImageURL `mapstructure:"image_url"`
+	// multipart image upload
+	Upload ImageUpload `mapstructure:"upload"`
+	// resized image that is generated on upload
+	Derivative ImageDerivative `mapstructure:"derivative"`
+}
+
+// ImageDerivative is longest side in pixel of each size, default size is used when zero
+type ImageDerivative struct {
+	Thumbnail int `mapstructure:"thumbnail"`
+	Medium    int `mapstructure:"medium"`
+	Large     int `mapstructure:"large"`
+	// quality of jpeg derivative, png derivative is lossless
+	JPEGQuality int `mapstructure:"jpeg_quality"`
+}
+
+type ImageUpload struct {
+	// byte size of one image file
+	MaxFileSize int64 `mapstructure:"max_file_size"`
+	// image file in one request, file over limit is reported as failed
+	MaxFiles int `mapstructure:"max_files"`
+	// byte size of multipart request, it is used instead of maximum request size of api
+	MaxRequestSize int64 `mapstructure:"max_request_size"`
+}
+
+type ImageURL struct {
+	// e.g. https://api.example.com, url is relative path when empty
+	BaseURL string `mapstructure:"base_url"`
+	// secret of signed url of image that is not public, image that is not public
+	// can not be read by url when empty
+	SignSecret string `mapstructure:"sign_secret"`
+	// lifetime of signed url
+	SignedURLTTL time.Duration `mapstructure:"signed_url_ttl"`
+	// max-age of cache-control of public image
+	CacheMaxAge time.Duration `mapstructure:"cache_max_age"`
+}
+
+// S3Storage is bucket of S3 or S3-compatible service, e.g. MinIO
+type S3Storage struct {
+	// e.g. https://s3.ap-southeast-1.amazonaws.com or http://localhost:9000
+	Endpoint  string `mapstructure:"endpoint"`
+	Region    string `mapstructure:"region"`
+	Bucket    string `mapstructure:"bucket"`
+	AccessKey string `mapstructure:"access_key"`
+	SecretKey string `mapstructure:"secret_key"`
+	// bucket is in path of url instead of host, most S3-compatible service require it
+	UsePathStyle bool `mapstructure:"use_path_style"`
+	// key prefix of image in bucket
+	Prefix string `mapstructure:"prefix"`
+}
+
+type CredentialPolicy struct {
+	Password PasswordPolicy `mapstructure:"password"`
+	Username UsernamePolicy `mapstructure:"username"`
+}
+
+type PasswordPolicy struct {
+	MinLength      int  `mapstructure:"min_length"`
+	MaxLength      int  `mapstructure:"max_length"`
+	RequireUpper   bool `mapstructure:"require_upper"`
+	RequireLower   bool `mapstructure:"require_lower"`
+	RequireDigit   bool `mapstructure:"require_digit"`
+	RequireSpecial bool `mapstructure:"require_special"`
+	// common password that not allowed, add to built-in list
+	Denylist []string `mapstructure:"denylist"`
+}
+
+type UsernamePolicy struct {
+	MinLength int    `mapstructure:"min_length"`
+	MaxLength int    `mapstructure:"max_length"`
+	Pattern   string `mapstructure:"pattern"`
+}
+
+type LoginProtection struct {
+	// failed attempt of one username before lockout, zero is disable protection
+	MaxUserAttempts int64 `mapstructure:"max_user_attempts"`
+	// failed attempt of one client ip before lockout, zero is disable
+	MaxIPAttempts int64 `mapstructure:"max_ip_attempts"`
+	// failed attempt that not delay response
+	DelayAfter int64         `mapstructure:"delay_after"`
+	BaseDelay  time.Duration `mapstructure:"base_delay"`
+	MaxDelay   time.Duration `mapstructure:"max_delay"`
+	// lockout time after reach max attempts
+	LockoutDuration time.Duration `mapstructure:"lockout_duration"`
+}
+
+type PasswordHash struct {
+	// argon2id (default) or bcrypt
+	Algorithm  string       `mapstructure:"algorithm"`
+	BcryptCost int          `mapstructure:"bcrypt_cost"`
+	Argon2     Argon2Params `mapstructure:"argon2"`
+}
+
+type Argon2Params struct {
+	// memory in KiB
+	Memory      uint32 `mapstructure:"memory"`
+	Iterations  uint32 `mapstructure:"iterations"`
+	Parallelism uint8  `mapstructure:"parallelism"`
+	SaltLength  uint32 `mapstructure:"salt_length"`
+	KeyLength   uint32 `mapstructure:"key_length"`
+}
+
+type PasswordReset struct {
+	// byte size of random token
+	TokenSize int `mapstructure:"token_size"`
+	// link in reset message, param is reset token, message contain only token when empty
+	ResetURLFormat string `mapstructure:"reset_url_format"`
+}
+
+type Notifier struct {
+	// smtp or log (default, write message to application log or file for local development)
+	Type string `mapstructure:"type"`
+	From string `mapstructure:"from"`
+	SMTP SMTP   `mapstructure:"smtp"`
+	// file of log notifier, message is written to application log when empty
+	FilePath string `mapstructure:"file_path"`
+}
+
+type SMTP struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
+
+type MFA struct {
+	// issuer that shown in authenticator app
+	Issuer string `mapstructure:"issuer"`
+	// account of these roles must use second factor, account that not enrolled must enroll at login
+	EnforcedRoles []string `mapstructure:"enforced_roles"`
+}
+
+type SpiderTrash struct {
+	// spider info and image in trash longer than retention period are removed permanently
+	RetentionPeriod time.Duration `mapstructure:"retention_period"`
+	// interval of purge job
+	PurgeInterval time.Duration `mapstructure:"purge_interval"`
 }
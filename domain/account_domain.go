@@ -9,4 +9,21 @@ import (
 type AccountRepository interface {
 	CreateAccout(ctx context.Context, acc model.Account) (err error)
 	FindAccountByUsername(ctx context.Context, username string) (AccountInfo *model.Account, err error)
+	FindAccountList(ctx context.Context, filter model.AccountListFilter) (accountList []model.Account, total int64, err error)
+	UpdateAccountStatus(ctx context.Context, username, status string) (err error)
+	UpdateAccountRole(ctx context.Context, username, role string) (err error)
+	UpdateAccountProfile(ctx context.Context, username string, profile model.AccountProfile) (err error)
+	UpdateAccountPassword(ctx context.Context, username, hashPassword string) (err error)
+	UpdateAccountMFA(ctx context.Context, username string, mfa *model.AccountMFA) (err error)
+	UpdateMFALastUsedStep(ctx context.Context, username string, step int64) (err error)
+	UseMFARecoveryCode(ctx context.Context, username, codeHash string) (err error)
+}
+
+type AccountManagementUsecase interface {
+	GetAccountList(ctx context.Context, filter model.AccountListFilter) ([]model.Account, int64, error)
+	UpdateAccountStatus(ctx context.Context, actor *model.LoginUser, username, status string) error
+	UpdateAccountRole(ctx context.Context, actor *model.LoginUser, username, role string) error
+	UpdateProfile(ctx context.Context, username string, profile model.AccountProfile) error
+	ChangePassword(ctx context.Context, username, oldPassword, newPassword, confirmPassword string) error
+	ResetMFA(ctx context.Context, actor *model.LoginUser, username string) error
 }
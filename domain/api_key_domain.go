@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"spider-go/model"
+	"time"
+)
+
+// error that is checked by middleware, so middleware does not depend on usecase
+var (
+	ErrorAPIKeyInvalid = fmt.Errorf("[API Key Usecase]: api key is invalid, expired or revoked")
+)
+
+//go:generate mockgen -source=api_key_domain.go -destination=./mock/api_key_domain.go
+type APIKeyRepository interface {
+	InsertAPIKey(ctx context.Context, apiKey model.APIKey) (err error)
+	FindAPIKeyByKeyID(ctx context.Context, keyID string) (apiKey *model.APIKey, err error)
+	FindAPIKeyList(ctx context.Context, page, size int32) (apiKeyList []model.APIKey, total int64, err error)
+	RevokeAPIKey(ctx context.Context, keyID, revokedBy string) (err error)
+	UpdateAPIKeyLastUsed(ctx context.Context, keyID string, usedAt time.Time) (err error)
+}
+
+type APIKeyUsecase interface {
+	CreateAPIKey(ctx context.Context, actor *model.LoginUser, name string, scopes []string, expiresAt time.Time) (rawKey string, apiKey *model.APIKey, err error)
+	GetAPIKeyList(ctx context.Context, page, size int32) (apiKeyList []model.APIKey, total int64, err error)
+	RevokeAPIKey(ctx context.Context, actor *model.LoginUser, keyID string) (err error)
+	AuthenticateAPIKey(ctx context.Context, rawKey string) (loginUser *model.LoginUser, err error)
+}
@@ -2,12 +2,25 @@ package domain
 
 import (
 	"context"
+	"fmt"
 	"spider-go/model"
 )
 
+// error that is checked by middleware, so middleware does not depend on usecase
+var (
+	ErrorTokenRevoked = fmt.Errorf("[Authorities Usecase]: access token is revoked")
+)
+
 //go:generate mockgen -source=auth_domain.go -destination=./mock/auth_domain.go
 
 type Authorities interface {
 	CreateAccout(ctx context.Context, data model.Account, password, confirmPassowrd string) (err error)
-	Login(ctx context.Context, username, password string) (accountInfo *model.Account, key string, err error)
+	// Login return challenge instead of token pair when second factor is required
+	Login(ctx context.Context, username, password, clientIP string) (accountInfo *model.Account, tokenPair *model.TokenPair, challenge *model.MFAChallenge, err error)
+	// VerifyMFA complete login of challenge, recovery codes is returned when second factor is enrolled at this login
+	VerifyMFA(ctx context.Context, mfaToken, code, clientIP string) (accountInfo *model.Account, tokenPair *model.TokenPair, recoveryCodes []string, err error)
+	RefreshToken(ctx context.Context, refreshToken string) (tokenPair *model.TokenPair, err error)
+	ValidateSession(ctx context.Context, loginUser *model.LoginUser) (err error)
+	Logout(ctx context.Context, loginUser *model.LoginUser) (err error)
+	RevokeAllSessions(ctx context.Context, username, revokedBy string) (err error)
 }
@@ -6,4 +6,5 @@ import "context"
 
 type GetRsaKeyUsecase interface {
 	GenerateRsaKey(ctx context.Context) (publicKey, searchKey string, err error)
+	DecryptWithRsaKey(ctx context.Context, searchKey string, cipherTexts ...string) (plainTexts []string, err error)
 }
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"spider-go/model"
+	"time"
+)
+
+//go:generate mockgen -source=image_storage_domain.go -destination=./mock/image_storage_domain.go
+type ImageStorage interface {
+	// Save write image by name, image that has same name is replaced, size is -1 when it is unknown
+	Save(ctx context.Context, name string, content io.Reader, size int64, contentType string) (err error)
+	// Open return content of image, caller must close content
+	Open(ctx context.Context, name string) (content io.ReadCloser, object *model.ImageObject, err error)
+	Stat(ctx context.Context, name string) (object *model.ImageObject, err error)
+	// Delete remove image, image that not found is not error
+	Delete(ctx context.Context, name string) (err error)
+}
+
+// ImageURLSigner build url of image endpoint, url of image that is not public is signed and expired
+type ImageURLSigner interface {
+	ImageURL(spiderUUID, imageName string, public bool) (url string)
+	// VerifySignature return expire time of signed url
+	VerifySignature(spiderUUID, imageName, expires, signature string) (expiresAt time.Time, err error)
+}
+
+// ImageDerivativeGenerator create resized image of original image in image storage
+type ImageDerivativeGenerator interface {
+	// Generate save every derivative size of original image, derivative that exists is replaced
+	Generate(ctx context.Context, imageName string) (err error)
+	// Exists is true when every derivative size of image is in storage
+	Exists(ctx context.Context, imageName string) (exists bool, err error)
+}
@@ -1,10 +1,15 @@
 package domain
 
-import "github.com/dgrijalva/jwt-go"
+import (
+	"spider-go/model"
+
+	"github.com/dgrijalva/jwt-go"
+)
 
 //go:generate mockgen -source=jwt_service_domain.go -destination=./mock/jwt_service_domain.go
 type JWTService interface {
-	GenerateNewToken(username, role string) (string, error)
+	GenerateNewToken(username, role, sessionID string) (string, error)
 	ValidateToken(encodedToken string) (*jwt.Token, error)
-	RefreshToken(currenToken *jwt.Token) (string, error)
+	SetSigningKeys(keys []model.SigningKey) error
+	GetJWKS() (model.JWKS, error)
 }
@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+//go:generate mockgen -source=mfa_domain.go -destination=./mock/mfa_domain.go
+type MFAUsecase interface {
+	EnrollMFA(ctx context.Context, username string) (otpauthURI, secret string, err error)
+	ActivateMFA(ctx context.Context, username, code string) (recoveryCodes []string, err error)
+	DisableMFA(ctx context.Context, username, code string) (err error)
+}
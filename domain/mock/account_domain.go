@@ -63,3 +63,226 @@ func (mr *MockAccountRepositoryMockRecorder) FindAccountByUsername(ctx, username
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAccountByUsername", reflect.TypeOf((*MockAccountRepository)(nil).FindAccountByUsername), ctx, username)
 }
+
+// FindAccountList mocks base method.
+func (m *MockAccountRepository) FindAccountList(ctx context.Context, filter model.AccountListFilter) ([]model.Account, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAccountList", ctx, filter)
+	ret0, _ := ret[0].([]model.Account)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindAccountList indicates an expected call of FindAccountList.
+func (mr *MockAccountRepositoryMockRecorder) FindAccountList(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAccountList", reflect.TypeOf((*MockAccountRepository)(nil).FindAccountList), ctx, filter)
+}
+
+// UpdateAccountMFA mocks base method.
+func (m *MockAccountRepository) UpdateAccountMFA(ctx context.Context, username string, mfa *model.AccountMFA) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountMFA", ctx, username, mfa)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAccountMFA indicates an expected call of UpdateAccountMFA.
+func (mr *MockAccountRepositoryMockRecorder) UpdateAccountMFA(ctx, username, mfa interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountMFA", reflect.TypeOf((*MockAccountRepository)(nil).UpdateAccountMFA), ctx, username, mfa)
+}
+
+// UpdateAccountPassword mocks base method.
+func (m *MockAccountRepository) UpdateAccountPassword(ctx context.Context, username, hashPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountPassword", ctx, username, hashPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAccountPassword indicates an expected call of UpdateAccountPassword.
+func (mr *MockAccountRepositoryMockRecorder) UpdateAccountPassword(ctx, username, hashPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountPassword", reflect.TypeOf((*MockAccountRepository)(nil).UpdateAccountPassword), ctx, username, hashPassword)
+}
+
+// UpdateAccountProfile mocks base method.
+func (m *MockAccountRepository) UpdateAccountProfile(ctx context.Context, username string, profile model.AccountProfile) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountProfile", ctx, username, profile)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAccountProfile indicates an expected call of UpdateAccountProfile.
+func (mr *MockAccountRepositoryMockRecorder) UpdateAccountProfile(ctx, username, profile interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountProfile", reflect.TypeOf((*MockAccountRepository)(nil).UpdateAccountProfile), ctx, username, profile)
+}
+
+// UpdateAccountRole mocks base method.
+func (m *MockAccountRepository) UpdateAccountRole(ctx context.Context, username, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountRole", ctx, username, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAccountRole indicates an expected call of UpdateAccountRole.
+func (mr *MockAccountRepositoryMockRecorder) UpdateAccountRole(ctx, username, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountRole", reflect.TypeOf((*MockAccountRepository)(nil).UpdateAccountRole), ctx, username, role)
+}
+
+// UpdateAccountStatus mocks base method.
+func (m *MockAccountRepository) UpdateAccountStatus(ctx context.Context, username, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountStatus", ctx, username, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAccountStatus indicates an expected call of UpdateAccountStatus.
+func (mr *MockAccountRepositoryMockRecorder) UpdateAccountStatus(ctx, username, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountStatus", reflect.TypeOf((*MockAccountRepository)(nil).UpdateAccountStatus), ctx, username, status)
+}
+
+// UpdateMFALastUsedStep mocks base method.
+func (m *MockAccountRepository) UpdateMFALastUsedStep(ctx context.Context, username string, step int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMFALastUsedStep", ctx, username, step)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMFALastUsedStep indicates an expected call of UpdateMFALastUsedStep.
+func (mr *MockAccountRepositoryMockRecorder) UpdateMFALastUsedStep(ctx, username, step interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMFALastUsedStep", reflect.TypeOf((*MockAccountRepository)(nil).UpdateMFALastUsedStep), ctx, username, step)
+}
+
+// UseMFARecoveryCode mocks base method.
+func (m *MockAccountRepository) UseMFARecoveryCode(ctx context.Context, username, codeHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseMFARecoveryCode", ctx, username, codeHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseMFARecoveryCode indicates an expected call of UseMFARecoveryCode.
+func (mr *MockAccountRepositoryMockRecorder) UseMFARecoveryCode(ctx, username, codeHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseMFARecoveryCode", reflect.TypeOf((*MockAccountRepository)(nil).UseMFARecoveryCode), ctx, username, codeHash)
+}
+
+// MockAccountManagementUsecase is a mock of AccountManagementUsecase interface.
+type MockAccountManagementUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountManagementUsecaseMockRecorder
+}
+
+// MockAccountManagementUsecaseMockRecorder is the mock recorder for MockAccountManagementUsecase.
+type MockAccountManagementUsecaseMockRecorder struct {
+	mock *MockAccountManagementUsecase
+}
+
+// NewMockAccountManagementUsecase creates a new mock instance.
+func NewMockAccountManagementUsecase(ctrl *gomock.Controller) *MockAccountManagementUsecase {
+	mock := &MockAccountManagementUsecase{ctrl: ctrl}
+	mock.recorder = &MockAccountManagementUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountManagementUsecase) EXPECT() *MockAccountManagementUsecaseMockRecorder {
+	return m.recorder
+}
+
+// ChangePassword mocks base method.
+func (m *MockAccountManagementUsecase) ChangePassword(ctx context.Context, username, oldPassword, newPassword, confirmPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, username, oldPassword, newPassword, confirmPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockAccountManagementUsecaseMockRecorder) ChangePassword(ctx, username, oldPassword, newPassword, confirmPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockAccountManagementUsecase)(nil).ChangePassword), ctx, username, oldPassword, newPassword, confirmPassword)
+}
+
+// GetAccountList mocks base method.
+func (m *MockAccountManagementUsecase) GetAccountList(ctx context.Context, filter model.AccountListFilter) ([]model.Account, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountList", ctx, filter)
+	ret0, _ := ret[0].([]model.Account)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAccountList indicates an expected call of GetAccountList.
+func (mr *MockAccountManagementUsecaseMockRecorder) GetAccountList(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountList", reflect.TypeOf((*MockAccountManagementUsecase)(nil).GetAccountList), ctx, filter)
+}
+
+// ResetMFA mocks base method.
+func (m *MockAccountManagementUsecase) ResetMFA(ctx context.Context, actor *model.LoginUser, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetMFA", ctx, actor, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetMFA indicates an expected call of ResetMFA.
+func (mr *MockAccountManagementUsecaseMockRecorder) ResetMFA(ctx, actor, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetMFA", reflect.TypeOf((*MockAccountManagementUsecase)(nil).ResetMFA), ctx, actor, username)
+}
+
+// UpdateAccountRole mocks base method.
+func (m *MockAccountManagementUsecase) UpdateAccountRole(ctx context.Context, actor *model.LoginUser, username, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountRole", ctx, actor, username, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAccountRole indicates an expected call of UpdateAccountRole.
+func (mr *MockAccountManagementUsecaseMockRecorder) UpdateAccountRole(ctx, actor, username, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountRole", reflect.TypeOf((*MockAccountManagementUsecase)(nil).UpdateAccountRole), ctx, actor, username, role)
+}
+
+// UpdateAccountStatus mocks base method.
+func (m *MockAccountManagementUsecase) UpdateAccountStatus(ctx context.Context, actor *model.LoginUser, username, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountStatus", ctx, actor, username, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAccountStatus indicates an expected call of UpdateAccountStatus.
+func (mr *MockAccountManagementUsecaseMockRecorder) UpdateAccountStatus(ctx, actor, username, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountStatus", reflect.TypeOf((*MockAccountManagementUsecase)(nil).UpdateAccountStatus), ctx, actor, username, status)
+}
+
+// UpdateProfile mocks base method.
+func (m *MockAccountManagementUsecase) UpdateProfile(ctx context.Context, username string, profile model.AccountProfile) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, username, profile)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockAccountManagementUsecaseMockRecorder) UpdateProfile(ctx, username, profile interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockAccountManagementUsecase)(nil).UpdateProfile), ctx, username, profile)
+}
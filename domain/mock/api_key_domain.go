@@ -0,0 +1,194 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: api_key_domain.go
+
+// Package mock_domain is a generated GoMock package.
+package mock_domain
+
+import (
+	context "context"
+	reflect "reflect"
+	model "spider-go/model"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAPIKeyRepository is a mock of APIKeyRepository interface.
+type MockAPIKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyRepositoryMockRecorder
+}
+
+// MockAPIKeyRepositoryMockRecorder is the mock recorder for MockAPIKeyRepository.
+type MockAPIKeyRepositoryMockRecorder struct {
+	mock *MockAPIKeyRepository
+}
+
+// NewMockAPIKeyRepository creates a new mock instance.
+func NewMockAPIKeyRepository(ctrl *gomock.Controller) *MockAPIKeyRepository {
+	mock := &MockAPIKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyRepository) EXPECT() *MockAPIKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindAPIKeyByKeyID mocks base method.
+func (m *MockAPIKeyRepository) FindAPIKeyByKeyID(ctx context.Context, keyID string) (*model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAPIKeyByKeyID", ctx, keyID)
+	ret0, _ := ret[0].(*model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAPIKeyByKeyID indicates an expected call of FindAPIKeyByKeyID.
+func (mr *MockAPIKeyRepositoryMockRecorder) FindAPIKeyByKeyID(ctx, keyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAPIKeyByKeyID", reflect.TypeOf((*MockAPIKeyRepository)(nil).FindAPIKeyByKeyID), ctx, keyID)
+}
+
+// FindAPIKeyList mocks base method.
+func (m *MockAPIKeyRepository) FindAPIKeyList(ctx context.Context, page, size int32) ([]model.APIKey, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAPIKeyList", ctx, page, size)
+	ret0, _ := ret[0].([]model.APIKey)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindAPIKeyList indicates an expected call of FindAPIKeyList.
+func (mr *MockAPIKeyRepositoryMockRecorder) FindAPIKeyList(ctx, page, size interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAPIKeyList", reflect.TypeOf((*MockAPIKeyRepository)(nil).FindAPIKeyList), ctx, page, size)
+}
+
+// InsertAPIKey mocks base method.
+func (m *MockAPIKeyRepository) InsertAPIKey(ctx context.Context, apiKey model.APIKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAPIKey", ctx, apiKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertAPIKey indicates an expected call of InsertAPIKey.
+func (mr *MockAPIKeyRepositoryMockRecorder) InsertAPIKey(ctx, apiKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAPIKey", reflect.TypeOf((*MockAPIKeyRepository)(nil).InsertAPIKey), ctx, apiKey)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockAPIKeyRepository) RevokeAPIKey(ctx context.Context, keyID, revokedBy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, keyID, revokedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockAPIKeyRepositoryMockRecorder) RevokeAPIKey(ctx, keyID, revokedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockAPIKeyRepository)(nil).RevokeAPIKey), ctx, keyID, revokedBy)
+}
+
+// UpdateAPIKeyLastUsed mocks base method.
+func (m *MockAPIKeyRepository) UpdateAPIKeyLastUsed(ctx context.Context, keyID string, usedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAPIKeyLastUsed", ctx, keyID, usedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAPIKeyLastUsed indicates an expected call of UpdateAPIKeyLastUsed.
+func (mr *MockAPIKeyRepositoryMockRecorder) UpdateAPIKeyLastUsed(ctx, keyID, usedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAPIKeyLastUsed", reflect.TypeOf((*MockAPIKeyRepository)(nil).UpdateAPIKeyLastUsed), ctx, keyID, usedAt)
+}
+
+// MockAPIKeyUsecase is a mock of APIKeyUsecase interface.
+type MockAPIKeyUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyUsecaseMockRecorder
+}
+
+// MockAPIKeyUsecaseMockRecorder is the mock recorder for MockAPIKeyUsecase.
+type MockAPIKeyUsecaseMockRecorder struct {
+	mock *MockAPIKeyUsecase
+}
+
+// NewMockAPIKeyUsecase creates a new mock instance.
+func NewMockAPIKeyUsecase(ctrl *gomock.Controller) *MockAPIKeyUsecase {
+	mock := &MockAPIKeyUsecase{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyUsecase) EXPECT() *MockAPIKeyUsecaseMockRecorder {
+	return m.recorder
+}
+
+// AuthenticateAPIKey mocks base method.
+func (m *MockAPIKeyUsecase) AuthenticateAPIKey(ctx context.Context, rawKey string) (*model.LoginUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticateAPIKey", ctx, rawKey)
+	ret0, _ := ret[0].(*model.LoginUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthenticateAPIKey indicates an expected call of AuthenticateAPIKey.
+func (mr *MockAPIKeyUsecaseMockRecorder) AuthenticateAPIKey(ctx, rawKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateAPIKey", reflect.TypeOf((*MockAPIKeyUsecase)(nil).AuthenticateAPIKey), ctx, rawKey)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockAPIKeyUsecase) CreateAPIKey(ctx context.Context, actor *model.LoginUser, name string, scopes []string, expiresAt time.Time) (string, *model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, actor, name, scopes, expiresAt)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(*model.APIKey)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockAPIKeyUsecaseMockRecorder) CreateAPIKey(ctx, actor, name, scopes, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockAPIKeyUsecase)(nil).CreateAPIKey), ctx, actor, name, scopes, expiresAt)
+}
+
+// GetAPIKeyList mocks base method.
+func (m *MockAPIKeyUsecase) GetAPIKeyList(ctx context.Context, page, size int32) ([]model.APIKey, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyList", ctx, page, size)
+	ret0, _ := ret[0].([]model.APIKey)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAPIKeyList indicates an expected call of GetAPIKeyList.
+func (mr *MockAPIKeyUsecaseMockRecorder) GetAPIKeyList(ctx, page, size interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyList", reflect.TypeOf((*MockAPIKeyUsecase)(nil).GetAPIKeyList), ctx, page, size)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockAPIKeyUsecase) RevokeAPIKey(ctx context.Context, actor *model.LoginUser, keyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, actor, keyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockAPIKeyUsecaseMockRecorder) RevokeAPIKey(ctx, actor, keyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockAPIKeyUsecase)(nil).RevokeAPIKey), ctx, actor, keyID)
+}
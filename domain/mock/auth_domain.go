@@ -50,17 +50,92 @@ func (mr *MockAuthoritiesMockRecorder) CreateAccout(ctx, data, password, confirm
 }
 
 // Login mocks base method.
-func (m *MockAuthorities) Login(ctx context.Context, username, password string) (*model.Account, string, error) {
+func (m *MockAuthorities) Login(ctx context.Context, username, password, clientIP string) (*model.Account, *model.TokenPair, *model.MFAChallenge, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", ctx, username, password)
+	ret := m.ctrl.Call(m, "Login", ctx, username, password, clientIP)
 	ret0, _ := ret[0].(*model.Account)
-	ret1, _ := ret[1].(string)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret1, _ := ret[1].(*model.TokenPair)
+	ret2, _ := ret[2].(*model.MFAChallenge)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
 }
 
 // Login indicates an expected call of Login.
-func (mr *MockAuthoritiesMockRecorder) Login(ctx, username, password interface{}) *gomock.Call {
+func (mr *MockAuthoritiesMockRecorder) Login(ctx, username, password, clientIP interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthorities)(nil).Login), ctx, username, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthorities)(nil).Login), ctx, username, password, clientIP)
+}
+
+// Logout mocks base method.
+func (m *MockAuthorities) Logout(ctx context.Context, loginUser *model.LoginUser) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, loginUser)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockAuthoritiesMockRecorder) Logout(ctx, loginUser interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthorities)(nil).Logout), ctx, loginUser)
+}
+
+// RefreshToken mocks base method.
+func (m *MockAuthorities) RefreshToken(ctx context.Context, refreshToken string) (*model.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshToken", ctx, refreshToken)
+	ret0, _ := ret[0].(*model.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshToken indicates an expected call of RefreshToken.
+func (mr *MockAuthoritiesMockRecorder) RefreshToken(ctx, refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockAuthorities)(nil).RefreshToken), ctx, refreshToken)
+}
+
+// RevokeAllSessions mocks base method.
+func (m *MockAuthorities) RevokeAllSessions(ctx context.Context, username, revokedBy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAllSessions", ctx, username, revokedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAllSessions indicates an expected call of RevokeAllSessions.
+func (mr *MockAuthoritiesMockRecorder) RevokeAllSessions(ctx, username, revokedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAllSessions", reflect.TypeOf((*MockAuthorities)(nil).RevokeAllSessions), ctx, username, revokedBy)
+}
+
+// ValidateSession mocks base method.
+func (m *MockAuthorities) ValidateSession(ctx context.Context, loginUser *model.LoginUser) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateSession", ctx, loginUser)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateSession indicates an expected call of ValidateSession.
+func (mr *MockAuthoritiesMockRecorder) ValidateSession(ctx, loginUser interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateSession", reflect.TypeOf((*MockAuthorities)(nil).ValidateSession), ctx, loginUser)
+}
+
+// VerifyMFA mocks base method.
+func (m *MockAuthorities) VerifyMFA(ctx context.Context, mfaToken, code, clientIP string) (*model.Account, *model.TokenPair, []string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyMFA", ctx, mfaToken, code, clientIP)
+	ret0, _ := ret[0].(*model.Account)
+	ret1, _ := ret[1].(*model.TokenPair)
+	ret2, _ := ret[2].([]string)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// VerifyMFA indicates an expected call of VerifyMFA.
+func (mr *MockAuthoritiesMockRecorder) VerifyMFA(ctx, mfaToken, code, clientIP interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyMFA", reflect.TypeOf((*MockAuthorities)(nil).VerifyMFA), ctx, mfaToken, code, clientIP)
 }
@@ -34,6 +34,26 @@ func (m *MockGetRsaKeyUsecase) EXPECT() *MockGetRsaKeyUsecaseMockRecorder {
 	return m.recorder
 }
 
+// DecryptWithRsaKey mocks base method.
+func (m *MockGetRsaKeyUsecase) DecryptWithRsaKey(ctx context.Context, searchKey string, cipherTexts ...string) ([]string, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, searchKey}
+	for _, a := range cipherTexts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DecryptWithRsaKey", varargs...)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecryptWithRsaKey indicates an expected call of DecryptWithRsaKey.
+func (mr *MockGetRsaKeyUsecaseMockRecorder) DecryptWithRsaKey(ctx, searchKey interface{}, cipherTexts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, searchKey}, cipherTexts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecryptWithRsaKey", reflect.TypeOf((*MockGetRsaKeyUsecase)(nil).DecryptWithRsaKey), varargs...)
+}
+
 // GenerateRsaKey mocks base method.
 func (m *MockGetRsaKeyUsecase) GenerateRsaKey(ctx context.Context) (string, string, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,201 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: image_storage_domain.go
+
+// Package mock_domain is a generated GoMock package.
+package mock_domain
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+	model "spider-go/model"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockImageStorage is a mock of ImageStorage interface.
+type MockImageStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockImageStorageMockRecorder
+}
+
+// MockImageStorageMockRecorder is the mock recorder for MockImageStorage.
+type MockImageStorageMockRecorder struct {
+	mock *MockImageStorage
+}
+
+// NewMockImageStorage creates a new mock instance.
+func NewMockImageStorage(ctrl *gomock.Controller) *MockImageStorage {
+	mock := &MockImageStorage{ctrl: ctrl}
+	mock.recorder = &MockImageStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImageStorage) EXPECT() *MockImageStorageMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockImageStorage) Delete(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockImageStorageMockRecorder) Delete(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockImageStorage)(nil).Delete), ctx, name)
+}
+
+// Open mocks base method.
+func (m *MockImageStorage) Open(ctx context.Context, name string) (io.ReadCloser, *model.ImageObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", ctx, name)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(*model.ImageObject)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockImageStorageMockRecorder) Open(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockImageStorage)(nil).Open), ctx, name)
+}
+
+// Save mocks base method.
+func (m *MockImageStorage) Save(ctx context.Context, name string, content io.Reader, size int64, contentType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, name, content, size, contentType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockImageStorageMockRecorder) Save(ctx, name, content, size, contentType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockImageStorage)(nil).Save), ctx, name, content, size, contentType)
+}
+
+// Stat mocks base method.
+func (m *MockImageStorage) Stat(ctx context.Context, name string) (*model.ImageObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stat", ctx, name)
+	ret0, _ := ret[0].(*model.ImageObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stat indicates an expected call of Stat.
+func (mr *MockImageStorageMockRecorder) Stat(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stat", reflect.TypeOf((*MockImageStorage)(nil).Stat), ctx, name)
+}
+
+// MockImageURLSigner is a mock of ImageURLSigner interface.
+type MockImageURLSigner struct {
+	ctrl     *gomock.Controller
+	recorder *MockImageURLSignerMockRecorder
+}
+
+// MockImageURLSignerMockRecorder is the mock recorder for MockImageURLSigner.
+type MockImageURLSignerMockRecorder struct {
+	mock *MockImageURLSigner
+}
+
+// NewMockImageURLSigner creates a new mock instance.
+func NewMockImageURLSigner(ctrl *gomock.Controller) *MockImageURLSigner {
+	mock := &MockImageURLSigner{ctrl: ctrl}
+	mock.recorder = &MockImageURLSignerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImageURLSigner) EXPECT() *MockImageURLSignerMockRecorder {
+	return m.recorder
+}
+
+// ImageURL mocks base method.
+func (m *MockImageURLSigner) ImageURL(spiderUUID, imageName string, public bool) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImageURL", spiderUUID, imageName, public)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ImageURL indicates an expected call of ImageURL.
+func (mr *MockImageURLSignerMockRecorder) ImageURL(spiderUUID, imageName, public interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImageURL", reflect.TypeOf((*MockImageURLSigner)(nil).ImageURL), spiderUUID, imageName, public)
+}
+
+// VerifySignature mocks base method.
+func (m *MockImageURLSigner) VerifySignature(spiderUUID, imageName, expires, signature string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifySignature", spiderUUID, imageName, expires, signature)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifySignature indicates an expected call of VerifySignature.
+func (mr *MockImageURLSignerMockRecorder) VerifySignature(spiderUUID, imageName, expires, signature interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifySignature", reflect.TypeOf((*MockImageURLSigner)(nil).VerifySignature), spiderUUID, imageName, expires, signature)
+}
+
+// MockImageDerivativeGenerator is a mock of ImageDerivativeGenerator interface.
+type MockImageDerivativeGenerator struct {
+	ctrl     *gomock.Controller
+	recorder *MockImageDerivativeGeneratorMockRecorder
+}
+
+// MockImageDerivativeGeneratorMockRecorder is the mock recorder for MockImageDerivativeGenerator.
+type MockImageDerivativeGeneratorMockRecorder struct {
+	mock *MockImageDerivativeGenerator
+}
+
+// NewMockImageDerivativeGenerator creates a new mock instance.
+func NewMockImageDerivativeGenerator(ctrl *gomock.Controller) *MockImageDerivativeGenerator {
+	mock := &MockImageDerivativeGenerator{ctrl: ctrl}
+	mock.recorder = &MockImageDerivativeGeneratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImageDerivativeGenerator) EXPECT() *MockImageDerivativeGeneratorMockRecorder {
+	return m.recorder
+}
+
+// Exists mocks base method.
+func (m *MockImageDerivativeGenerator) Exists(ctx context.Context, imageName string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", ctx, imageName)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockImageDerivativeGeneratorMockRecorder) Exists(ctx, imageName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockImageDerivativeGenerator)(nil).Exists), ctx, imageName)
+}
+
+// Generate mocks base method.
+func (m *MockImageDerivativeGenerator) Generate(ctx context.Context, imageName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Generate", ctx, imageName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Generate indicates an expected call of Generate.
+func (mr *MockImageDerivativeGeneratorMockRecorder) Generate(ctx, imageName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockImageDerivativeGenerator)(nil).Generate), ctx, imageName)
+}
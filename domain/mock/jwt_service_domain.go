@@ -6,6 +6,7 @@ package mock_domain
 
 import (
 	reflect "reflect"
+	model "spider-go/model"
 
 	jwt "github.com/dgrijalva/jwt-go"
 	gomock "github.com/golang/mock/gomock"
@@ -35,33 +36,47 @@ func (m *MockJWTService) EXPECT() *MockJWTServiceMockRecorder {
 }
 
 // GenerateNewToken mocks base method.
-func (m *MockJWTService) GenerateNewToken(username, role string) (string, error) {
+func (m *MockJWTService) GenerateNewToken(username, role, sessionID string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateNewToken", username, role)
+	ret := m.ctrl.Call(m, "GenerateNewToken", username, role, sessionID)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GenerateNewToken indicates an expected call of GenerateNewToken.
-func (mr *MockJWTServiceMockRecorder) GenerateNewToken(username, role interface{}) *gomock.Call {
+func (mr *MockJWTServiceMockRecorder) GenerateNewToken(username, role, sessionID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateNewToken", reflect.TypeOf((*MockJWTService)(nil).GenerateNewToken), username, role)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateNewToken", reflect.TypeOf((*MockJWTService)(nil).GenerateNewToken), username, role, sessionID)
 }
 
-// RefreshToken mocks base method.
-func (m *MockJWTService) RefreshToken(currenToken *jwt.Token) (string, error) {
+// GetJWKS mocks base method.
+func (m *MockJWTService) GetJWKS() (model.JWKS, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RefreshToken", currenToken)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetJWKS")
+	ret0, _ := ret[0].(model.JWKS)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// RefreshToken indicates an expected call of RefreshToken.
-func (mr *MockJWTServiceMockRecorder) RefreshToken(currenToken interface{}) *gomock.Call {
+// GetJWKS indicates an expected call of GetJWKS.
+func (mr *MockJWTServiceMockRecorder) GetJWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJWKS", reflect.TypeOf((*MockJWTService)(nil).GetJWKS))
+}
+
+// SetSigningKeys mocks base method.
+func (m *MockJWTService) SetSigningKeys(keys []model.SigningKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSigningKeys", keys)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSigningKeys indicates an expected call of SetSigningKeys.
+func (mr *MockJWTServiceMockRecorder) SetSigningKeys(keys interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockJWTService)(nil).RefreshToken), currenToken)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSigningKeys", reflect.TypeOf((*MockJWTService)(nil).SetSigningKeys), keys)
 }
 
 // ValidateToken mocks base method.
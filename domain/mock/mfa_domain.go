@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: mfa_domain.go
+
+// Package mock_domain is a generated GoMock package.
+package mock_domain
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockMFAUsecase is a mock of MFAUsecase interface.
+type MockMFAUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockMFAUsecaseMockRecorder
+}
+
+// MockMFAUsecaseMockRecorder is the mock recorder for MockMFAUsecase.
+type MockMFAUsecaseMockRecorder struct {
+	mock *MockMFAUsecase
+}
+
+// NewMockMFAUsecase creates a new mock instance.
+func NewMockMFAUsecase(ctrl *gomock.Controller) *MockMFAUsecase {
+	mock := &MockMFAUsecase{ctrl: ctrl}
+	mock.recorder = &MockMFAUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMFAUsecase) EXPECT() *MockMFAUsecaseMockRecorder {
+	return m.recorder
+}
+
+// ActivateMFA mocks base method.
+func (m *MockMFAUsecase) ActivateMFA(ctx context.Context, username, code string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActivateMFA", ctx, username, code)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActivateMFA indicates an expected call of ActivateMFA.
+func (mr *MockMFAUsecaseMockRecorder) ActivateMFA(ctx, username, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivateMFA", reflect.TypeOf((*MockMFAUsecase)(nil).ActivateMFA), ctx, username, code)
+}
+
+// DisableMFA mocks base method.
+func (m *MockMFAUsecase) DisableMFA(ctx context.Context, username, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableMFA", ctx, username, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableMFA indicates an expected call of DisableMFA.
+func (mr *MockMFAUsecaseMockRecorder) DisableMFA(ctx, username, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableMFA", reflect.TypeOf((*MockMFAUsecase)(nil).DisableMFA), ctx, username, code)
+}
+
+// EnrollMFA mocks base method.
+func (m *MockMFAUsecase) EnrollMFA(ctx context.Context, username string) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollMFA", ctx, username)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EnrollMFA indicates an expected call of EnrollMFA.
+func (mr *MockMFAUsecaseMockRecorder) EnrollMFA(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollMFA", reflect.TypeOf((*MockMFAUsecase)(nil).EnrollMFA), ctx, username)
+}
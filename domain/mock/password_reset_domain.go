@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: password_reset_domain.go
+
+// Package mock_domain is a generated GoMock package.
+package mock_domain
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPasswordResetUsecase is a mock of PasswordResetUsecase interface.
+type MockPasswordResetUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordResetUsecaseMockRecorder
+}
+
+// MockPasswordResetUsecaseMockRecorder is the mock recorder for MockPasswordResetUsecase.
+type MockPasswordResetUsecaseMockRecorder struct {
+	mock *MockPasswordResetUsecase
+}
+
+// NewMockPasswordResetUsecase creates a new mock instance.
+func NewMockPasswordResetUsecase(ctrl *gomock.Controller) *MockPasswordResetUsecase {
+	mock := &MockPasswordResetUsecase{ctrl: ctrl}
+	mock.recorder = &MockPasswordResetUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordResetUsecase) EXPECT() *MockPasswordResetUsecaseMockRecorder {
+	return m.recorder
+}
+
+// ConfirmPasswordReset mocks base method.
+func (m *MockPasswordResetUsecase) ConfirmPasswordReset(ctx context.Context, token, newPassword, confirmPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmPasswordReset", ctx, token, newPassword, confirmPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmPasswordReset indicates an expected call of ConfirmPasswordReset.
+func (mr *MockPasswordResetUsecaseMockRecorder) ConfirmPasswordReset(ctx, token, newPassword, confirmPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmPasswordReset", reflect.TypeOf((*MockPasswordResetUsecase)(nil).ConfirmPasswordReset), ctx, token, newPassword, confirmPassword)
+}
+
+// RequestPasswordReset mocks base method.
+func (m *MockPasswordResetUsecase) RequestPasswordReset(ctx context.Context, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestPasswordReset", ctx, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestPasswordReset indicates an expected call of RequestPasswordReset.
+func (mr *MockPasswordResetUsecaseMockRecorder) RequestPasswordReset(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestPasswordReset", reflect.TypeOf((*MockPasswordResetUsecase)(nil).RequestPasswordReset), ctx, username)
+}
@@ -35,6 +35,63 @@ func (m *MockRedisRepository) EXPECT() *MockRedisRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CompareAndSetDataToRedis mocks base method.
+func (m *MockRedisRepository) CompareAndSetDataToRedis(ctx context.Context, key, field, expected string, data interface{}, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareAndSetDataToRedis", ctx, key, field, expected, data, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompareAndSetDataToRedis indicates an expected call of CompareAndSetDataToRedis.
+func (mr *MockRedisRepositoryMockRecorder) CompareAndSetDataToRedis(ctx, key, field, expected, data, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareAndSetDataToRedis", reflect.TypeOf((*MockRedisRepository)(nil).CompareAndSetDataToRedis), ctx, key, field, expected, data, ttl)
+}
+
+// DeleteDataFromRedis mocks base method.
+func (m *MockRedisRepository) DeleteDataFromRedis(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDataFromRedis", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDataFromRedis indicates an expected call of DeleteDataFromRedis.
+func (mr *MockRedisRepositoryMockRecorder) DeleteDataFromRedis(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDataFromRedis", reflect.TypeOf((*MockRedisRepository)(nil).DeleteDataFromRedis), ctx, key)
+}
+
+// GetAndDeleteDataFromRedis mocks base method.
+func (m *MockRedisRepository) GetAndDeleteDataFromRedis(ctx context.Context, key string, data interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAndDeleteDataFromRedis", ctx, key, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetAndDeleteDataFromRedis indicates an expected call of GetAndDeleteDataFromRedis.
+func (mr *MockRedisRepositoryMockRecorder) GetAndDeleteDataFromRedis(ctx, key, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAndDeleteDataFromRedis", reflect.TypeOf((*MockRedisRepository)(nil).GetAndDeleteDataFromRedis), ctx, key, data)
+}
+
+// GetCounter mocks base method.
+func (m *MockRedisRepository) GetCounter(ctx context.Context, key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCounter", ctx, key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCounter indicates an expected call of GetCounter.
+func (mr *MockRedisRepositoryMockRecorder) GetCounter(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCounter", reflect.TypeOf((*MockRedisRepository)(nil).GetCounter), ctx, key)
+}
+
 // GetDataFromRedis mocks base method.
 func (m *MockRedisRepository) GetDataFromRedis(ctx context.Context, key string, data interface{}) error {
 	m.ctrl.T.Helper()
@@ -49,6 +106,21 @@ func (mr *MockRedisRepositoryMockRecorder) GetDataFromRedis(ctx, key, data inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDataFromRedis", reflect.TypeOf((*MockRedisRepository)(nil).GetDataFromRedis), ctx, key, data)
 }
 
+// IncreaseCounter mocks base method.
+func (m *MockRedisRepository) IncreaseCounter(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncreaseCounter", ctx, key, ttl)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncreaseCounter indicates an expected call of IncreaseCounter.
+func (mr *MockRedisRepositoryMockRecorder) IncreaseCounter(ctx, key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncreaseCounter", reflect.TypeOf((*MockRedisRepository)(nil).IncreaseCounter), ctx, key, ttl)
+}
+
 // SetDataToRedisWithTTL mocks base method.
 func (m *MockRedisRepository) SetDataToRedisWithTTL(ctx context.Context, key string, data interface{}, ttl time.Duration) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,158 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: signing_key_domain.go
+
+// Package mock_domain is a generated GoMock package.
+package mock_domain
+
+import (
+	context "context"
+	reflect "reflect"
+	model "spider-go/model"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSigningKeyRepository is a mock of SigningKeyRepository interface.
+type MockSigningKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSigningKeyRepositoryMockRecorder
+}
+
+// MockSigningKeyRepositoryMockRecorder is the mock recorder for MockSigningKeyRepository.
+type MockSigningKeyRepositoryMockRecorder struct {
+	mock *MockSigningKeyRepository
+}
+
+// NewMockSigningKeyRepository creates a new mock instance.
+func NewMockSigningKeyRepository(ctrl *gomock.Controller) *MockSigningKeyRepository {
+	mock := &MockSigningKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockSigningKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSigningKeyRepository) EXPECT() *MockSigningKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpiredSigningKeys mocks base method.
+func (m *MockSigningKeyRepository) DeleteExpiredSigningKeys(ctx context.Context, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredSigningKeys", ctx, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredSigningKeys indicates an expected call of DeleteExpiredSigningKeys.
+func (mr *MockSigningKeyRepositoryMockRecorder) DeleteExpiredSigningKeys(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredSigningKeys", reflect.TypeOf((*MockSigningKeyRepository)(nil).DeleteExpiredSigningKeys), ctx, now)
+}
+
+// FindVerifiableSigningKeys mocks base method.
+func (m *MockSigningKeyRepository) FindVerifiableSigningKeys(ctx context.Context, now time.Time) ([]model.SigningKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindVerifiableSigningKeys", ctx, now)
+	ret0, _ := ret[0].([]model.SigningKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindVerifiableSigningKeys indicates an expected call of FindVerifiableSigningKeys.
+func (mr *MockSigningKeyRepositoryMockRecorder) FindVerifiableSigningKeys(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindVerifiableSigningKeys", reflect.TypeOf((*MockSigningKeyRepository)(nil).FindVerifiableSigningKeys), ctx, now)
+}
+
+// InsertSigningKey mocks base method.
+func (m *MockSigningKeyRepository) InsertSigningKey(ctx context.Context, key model.SigningKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertSigningKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertSigningKey indicates an expected call of InsertSigningKey.
+func (mr *MockSigningKeyRepositoryMockRecorder) InsertSigningKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSigningKey", reflect.TypeOf((*MockSigningKeyRepository)(nil).InsertSigningKey), ctx, key)
+}
+
+// RetireSigningKeys mocks base method.
+func (m *MockSigningKeyRepository) RetireSigningKeys(ctx context.Context, exceptKID string, verifyUntil time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetireSigningKeys", ctx, exceptKID, verifyUntil)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetireSigningKeys indicates an expected call of RetireSigningKeys.
+func (mr *MockSigningKeyRepositoryMockRecorder) RetireSigningKeys(ctx, exceptKID, verifyUntil interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetireSigningKeys", reflect.TypeOf((*MockSigningKeyRepository)(nil).RetireSigningKeys), ctx, exceptKID, verifyUntil)
+}
+
+// MockSigningKeyUsecase is a mock of SigningKeyUsecase interface.
+type MockSigningKeyUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockSigningKeyUsecaseMockRecorder
+}
+
+// MockSigningKeyUsecaseMockRecorder is the mock recorder for MockSigningKeyUsecase.
+type MockSigningKeyUsecaseMockRecorder struct {
+	mock *MockSigningKeyUsecase
+}
+
+// NewMockSigningKeyUsecase creates a new mock instance.
+func NewMockSigningKeyUsecase(ctrl *gomock.Controller) *MockSigningKeyUsecase {
+	mock := &MockSigningKeyUsecase{ctrl: ctrl}
+	mock.recorder = &MockSigningKeyUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSigningKeyUsecase) EXPECT() *MockSigningKeyUsecaseMockRecorder {
+	return m.recorder
+}
+
+// GetJWKS mocks base method.
+func (m *MockSigningKeyUsecase) GetJWKS(ctx context.Context) (model.JWKS, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJWKS", ctx)
+	ret0, _ := ret[0].(model.JWKS)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJWKS indicates an expected call of GetJWKS.
+func (mr *MockSigningKeyUsecaseMockRecorder) GetJWKS(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJWKS", reflect.TypeOf((*MockSigningKeyUsecase)(nil).GetJWKS), ctx)
+}
+
+// RefreshSigningKeys mocks base method.
+func (m *MockSigningKeyUsecase) RefreshSigningKeys(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshSigningKeys", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshSigningKeys indicates an expected call of RefreshSigningKeys.
+func (mr *MockSigningKeyUsecaseMockRecorder) RefreshSigningKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshSigningKeys", reflect.TypeOf((*MockSigningKeyUsecase)(nil).RefreshSigningKeys), ctx)
+}
+
+// RunKeyRotation mocks base method.
+func (m *MockSigningKeyUsecase) RunKeyRotation(ctx context.Context, interval time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunKeyRotation", ctx, interval)
+}
+
+// RunKeyRotation indicates an expected call of RunKeyRotation.
+func (mr *MockSigningKeyUsecaseMockRecorder) RunKeyRotation(ctx, interval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunKeyRotation", reflect.TypeOf((*MockSigningKeyUsecase)(nil).RunKeyRotation), ctx, interval)
+}
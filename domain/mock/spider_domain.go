@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 	model "spider-go/api/model"
 	model0 "spider-go/model"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -36,20 +37,6 @@ func (m *MockSpiderRepository) EXPECT() *MockSpiderRepositoryMockRecorder {
 	return m.recorder
 }
 
-// DeleteSpiderInfoWithSpiderUUID mocks base method.
-func (m *MockSpiderRepository) DeleteSpiderInfoWithSpiderUUID(ctx context.Context, spiderUUID string) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSpiderInfoWithSpiderUUID", ctx, spiderUUID)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// DeleteSpiderInfoWithSpiderUUID indicates an expected call of DeleteSpiderInfoWithSpiderUUID.
-func (mr *MockSpiderRepositoryMockRecorder) DeleteSpiderInfoWithSpiderUUID(ctx, spiderUUID interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSpiderInfoWithSpiderUUID", reflect.TypeOf((*MockSpiderRepository)(nil).DeleteSpiderInfoWithSpiderUUID), ctx, spiderUUID)
-}
-
 // FindAllSpiderListManager mocks base method.
 func (m *MockSpiderRepository) FindAllSpiderListManager(ctx context.Context, page, limit int) ([]model0.SpiderInfo, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +67,37 @@ func (mr *MockSpiderRepositoryMockRecorder) FindAllSpiderListWithActive(ctx inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllSpiderListWithActive", reflect.TypeOf((*MockSpiderRepository)(nil).FindAllSpiderListWithActive), ctx)
 }
 
+// FindExpiredSpiderTrash mocks base method.
+func (m *MockSpiderRepository) FindExpiredSpiderTrash(ctx context.Context, deletedBefore time.Time) ([]model0.SpiderInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExpiredSpiderTrash", ctx, deletedBefore)
+	ret0, _ := ret[0].([]model0.SpiderInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindExpiredSpiderTrash indicates an expected call of FindExpiredSpiderTrash.
+func (mr *MockSpiderRepositoryMockRecorder) FindExpiredSpiderTrash(ctx, deletedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExpiredSpiderTrash", reflect.TypeOf((*MockSpiderRepository)(nil).FindExpiredSpiderTrash), ctx, deletedBefore)
+}
+
+// FindFamilyList mocks base method.
+func (m *MockSpiderRepository) FindFamilyList(ctx context.Context, sortBy string, page, size int32) ([]model0.FamilyList, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFamilyList", ctx, sortBy, page, size)
+	ret0, _ := ret[0].([]model0.FamilyList)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindFamilyList indicates an expected call of FindFamilyList.
+func (mr *MockSpiderRepositoryMockRecorder) FindFamilyList(ctx, sortBy, page, size interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFamilyList", reflect.TypeOf((*MockSpiderRepository)(nil).FindFamilyList), ctx, sortBy, page, size)
+}
+
 // FindSpiderByUUID mocks base method.
 func (m *MockSpiderRepository) FindSpiderByUUID(ctx context.Context, spiderUUID string) (*model0.SpiderInfo, error) {
 	m.ctrl.T.Helper()
@@ -110,6 +128,21 @@ func (mr *MockSpiderRepositoryMockRecorder) FindSpiderByUUIDAndStatus(ctx, spide
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSpiderByUUIDAndStatus", reflect.TypeOf((*MockSpiderRepository)(nil).FindSpiderByUUIDAndStatus), ctx, spiderUUID, isStatusActive)
 }
 
+// FindSpiderImageFileList mocks base method.
+func (m *MockSpiderRepository) FindSpiderImageFileList(ctx context.Context) ([]model0.SpiderInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSpiderImageFileList", ctx)
+	ret0, _ := ret[0].([]model0.SpiderInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSpiderImageFileList indicates an expected call of FindSpiderImageFileList.
+func (mr *MockSpiderRepositoryMockRecorder) FindSpiderImageFileList(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSpiderImageFileList", reflect.TypeOf((*MockSpiderRepository)(nil).FindSpiderImageFileList), ctx)
+}
+
 // FindSpiderInfoByFirstFamilyOrGenus mocks base method.
 func (m *MockSpiderRepository) FindSpiderInfoByFirstFamilyOrGenus(ctx context.Context, field, value string) ([]model0.SpiderInfo, error) {
 	m.ctrl.T.Helper()
@@ -170,6 +203,38 @@ func (mr *MockSpiderRepositoryMockRecorder) FindSpiderInfoListByGeographies(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSpiderInfoListByGeographies", reflect.TypeOf((*MockSpiderRepository)(nil).FindSpiderInfoListByGeographies), ctx, province, district, position)
 }
 
+// FindSpiderInfoListByStatus mocks base method.
+func (m *MockSpiderRepository) FindSpiderInfoListByStatus(ctx context.Context, status string, page, size int32) ([]model0.SpiderInfo, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSpiderInfoListByStatus", ctx, status, page, size)
+	ret0, _ := ret[0].([]model0.SpiderInfo)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindSpiderInfoListByStatus indicates an expected call of FindSpiderInfoListByStatus.
+func (mr *MockSpiderRepositoryMockRecorder) FindSpiderInfoListByStatus(ctx, status, page, size interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSpiderInfoListByStatus", reflect.TypeOf((*MockSpiderRepository)(nil).FindSpiderInfoListByStatus), ctx, status, page, size)
+}
+
+// FindSpiderTrashList mocks base method.
+func (m *MockSpiderRepository) FindSpiderTrashList(ctx context.Context, page, size int32) ([]model0.SpiderInfo, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSpiderTrashList", ctx, page, size)
+	ret0, _ := ret[0].([]model0.SpiderInfo)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindSpiderTrashList indicates an expected call of FindSpiderTrashList.
+func (mr *MockSpiderRepositoryMockRecorder) FindSpiderTrashList(ctx, page, size interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSpiderTrashList", reflect.TypeOf((*MockSpiderRepository)(nil).FindSpiderTrashList), ctx, page, size)
+}
+
 // InsertNewSpider mocks base method.
 func (m *MockSpiderRepository) InsertNewSpider(ctx context.Context, data model0.SpiderInfo) error {
 	m.ctrl.T.Helper()
@@ -184,6 +249,77 @@ func (mr *MockSpiderRepositoryMockRecorder) InsertNewSpider(ctx, data interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertNewSpider", reflect.TypeOf((*MockSpiderRepository)(nil).InsertNewSpider), ctx, data)
 }
 
+// MoveSpiderInfoToTrash mocks base method.
+func (m *MockSpiderRepository) MoveSpiderInfoToTrash(ctx context.Context, spiderUUID, previousStatus, deletedBy string, deletedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveSpiderInfoToTrash", ctx, spiderUUID, previousStatus, deletedBy, deletedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveSpiderInfoToTrash indicates an expected call of MoveSpiderInfoToTrash.
+func (mr *MockSpiderRepositoryMockRecorder) MoveSpiderInfoToTrash(ctx, spiderUUID, previousStatus, deletedBy, deletedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveSpiderInfoToTrash", reflect.TypeOf((*MockSpiderRepository)(nil).MoveSpiderInfoToTrash), ctx, spiderUUID, previousStatus, deletedBy, deletedAt)
+}
+
+// PatchSpiderInfo mocks base method.
+func (m *MockSpiderRepository) PatchSpiderInfo(ctx context.Context, spiderUUID string, expectedVersion int64, spiderInfo model0.SpiderInfo, fields []string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchSpiderInfo", ctx, spiderUUID, expectedVersion, spiderInfo, fields)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchSpiderInfo indicates an expected call of PatchSpiderInfo.
+func (mr *MockSpiderRepositoryMockRecorder) PatchSpiderInfo(ctx, spiderUUID, expectedVersion, spiderInfo, fields interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchSpiderInfo", reflect.TypeOf((*MockSpiderRepository)(nil).PatchSpiderInfo), ctx, spiderUUID, expectedVersion, spiderInfo, fields)
+}
+
+// PurgeSpiderInfo mocks base method.
+func (m *MockSpiderRepository) PurgeSpiderInfo(ctx context.Context, spiderUUID string, deletedBefore time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeSpiderInfo", ctx, spiderUUID, deletedBefore)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeSpiderInfo indicates an expected call of PurgeSpiderInfo.
+func (mr *MockSpiderRepositoryMockRecorder) PurgeSpiderInfo(ctx, spiderUUID, deletedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeSpiderInfo", reflect.TypeOf((*MockSpiderRepository)(nil).PurgeSpiderInfo), ctx, spiderUUID, deletedBefore)
+}
+
+// PushSpiderImageExif mocks base method.
+func (m *MockSpiderRepository) PushSpiderImageExif(ctx context.Context, spiderUUID string, imageExif []model0.ImageExif) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PushSpiderImageExif", ctx, spiderUUID, imageExif)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PushSpiderImageExif indicates an expected call of PushSpiderImageExif.
+func (mr *MockSpiderRepositoryMockRecorder) PushSpiderImageExif(ctx, spiderUUID, imageExif interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushSpiderImageExif", reflect.TypeOf((*MockSpiderRepository)(nil).PushSpiderImageExif), ctx, spiderUUID, imageExif)
+}
+
+// RestoreSpiderInfoFromTrash mocks base method.
+func (m *MockSpiderRepository) RestoreSpiderInfoFromTrash(ctx context.Context, spiderUUID, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSpiderInfoFromTrash", ctx, spiderUUID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreSpiderInfoFromTrash indicates an expected call of RestoreSpiderInfoFromTrash.
+func (mr *MockSpiderRepositoryMockRecorder) RestoreSpiderInfoFromTrash(ctx, spiderUUID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSpiderInfoFromTrash", reflect.TypeOf((*MockSpiderRepository)(nil).RestoreSpiderInfoFromTrash), ctx, spiderUUID, status)
+}
+
 // UpdateImageFileToSpiderInfo mocks base method.
 func (m *MockSpiderRepository) UpdateImageFileToSpiderInfo(ctx context.Context, filesName []string, spiderUUID string) error {
 	m.ctrl.T.Helper()
@@ -199,18 +335,32 @@ func (mr *MockSpiderRepositoryMockRecorder) UpdateImageFileToSpiderInfo(ctx, fil
 }
 
 // UpdateSpiderInfo mocks base method.
-func (m *MockSpiderRepository) UpdateSpiderInfo(ctx context.Context, spiderUUID string, spiderInfo model0.SpiderInfo) (bool, error) {
+func (m *MockSpiderRepository) UpdateSpiderInfo(ctx context.Context, spiderUUID string, expectedVersion int64, spiderInfo model0.SpiderInfo) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateSpiderInfo", ctx, spiderUUID, spiderInfo)
+	ret := m.ctrl.Call(m, "UpdateSpiderInfo", ctx, spiderUUID, expectedVersion, spiderInfo)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateSpiderInfo indicates an expected call of UpdateSpiderInfo.
-func (mr *MockSpiderRepositoryMockRecorder) UpdateSpiderInfo(ctx, spiderUUID, spiderInfo interface{}) *gomock.Call {
+func (mr *MockSpiderRepositoryMockRecorder) UpdateSpiderInfo(ctx, spiderUUID, expectedVersion, spiderInfo interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSpiderInfo", reflect.TypeOf((*MockSpiderRepository)(nil).UpdateSpiderInfo), ctx, spiderUUID, spiderInfo)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSpiderInfo", reflect.TypeOf((*MockSpiderRepository)(nil).UpdateSpiderInfo), ctx, spiderUUID, expectedVersion, spiderInfo)
+}
+
+// UpdateSpiderStatus mocks base method.
+func (m *MockSpiderRepository) UpdateSpiderStatus(ctx context.Context, spiderUUID, fromStatus, toStatus string, review *model0.SpiderReview) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSpiderStatus", ctx, spiderUUID, fromStatus, toStatus, review)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSpiderStatus indicates an expected call of UpdateSpiderStatus.
+func (mr *MockSpiderRepositoryMockRecorder) UpdateSpiderStatus(ctx, spiderUUID, fromStatus, toStatus, review interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSpiderStatus", reflect.TypeOf((*MockSpiderRepository)(nil).UpdateSpiderStatus), ctx, spiderUUID, fromStatus, toStatus, review)
 }
 
 // MockRegisterSpiderUsecase is a mock of RegisterSpiderUsecase interface.
@@ -237,18 +387,86 @@ func (m *MockRegisterSpiderUsecase) EXPECT() *MockRegisterSpiderUsecaseMockRecor
 }
 
 // Register mocks base method.
-func (m *MockRegisterSpiderUsecase) Register(ctx context.Context, req model.SpiderInfo, username string) (string, error) {
+func (m *MockRegisterSpiderUsecase) Register(ctx context.Context, req model.SpiderInfo, loginUser *model0.LoginUser) (string, string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Register", ctx, req, username)
+	ret := m.ctrl.Call(m, "Register", ctx, req, loginUser)
 	ret0, _ := ret[0].(string)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // Register indicates an expected call of Register.
-func (mr *MockRegisterSpiderUsecaseMockRecorder) Register(ctx, req, username interface{}) *gomock.Call {
+func (mr *MockRegisterSpiderUsecaseMockRecorder) Register(ctx, req, loginUser interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockRegisterSpiderUsecase)(nil).Register), ctx, req, loginUser)
+}
+
+// MockSpiderWorkflowUsecase is a mock of SpiderWorkflowUsecase interface.
+type MockSpiderWorkflowUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpiderWorkflowUsecaseMockRecorder
+}
+
+// MockSpiderWorkflowUsecaseMockRecorder is the mock recorder for MockSpiderWorkflowUsecase.
+type MockSpiderWorkflowUsecaseMockRecorder struct {
+	mock *MockSpiderWorkflowUsecase
+}
+
+// NewMockSpiderWorkflowUsecase creates a new mock instance.
+func NewMockSpiderWorkflowUsecase(ctrl *gomock.Controller) *MockSpiderWorkflowUsecase {
+	mock := &MockSpiderWorkflowUsecase{ctrl: ctrl}
+	mock.recorder = &MockSpiderWorkflowUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSpiderWorkflowUsecase) EXPECT() *MockSpiderWorkflowUsecaseMockRecorder {
+	return m.recorder
+}
+
+// GetReviewQueue mocks base method.
+func (m *MockSpiderWorkflowUsecase) GetReviewQueue(ctx context.Context, page, size int32) ([]model0.SpiderInfo, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewQueue", ctx, page, size)
+	ret0, _ := ret[0].([]model0.SpiderInfo)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetReviewQueue indicates an expected call of GetReviewQueue.
+func (mr *MockSpiderWorkflowUsecaseMockRecorder) GetReviewQueue(ctx, page, size interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockRegisterSpiderUsecase)(nil).Register), ctx, req, username)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewQueue", reflect.TypeOf((*MockSpiderWorkflowUsecase)(nil).GetReviewQueue), ctx, page, size)
+}
+
+// ReviewSpiderInfo mocks base method.
+func (m *MockSpiderWorkflowUsecase) ReviewSpiderInfo(ctx context.Context, spiderUUID string, actor *model0.LoginUser, approve bool, comment string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReviewSpiderInfo", ctx, spiderUUID, actor, approve, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReviewSpiderInfo indicates an expected call of ReviewSpiderInfo.
+func (mr *MockSpiderWorkflowUsecaseMockRecorder) ReviewSpiderInfo(ctx, spiderUUID, actor, approve, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReviewSpiderInfo", reflect.TypeOf((*MockSpiderWorkflowUsecase)(nil).ReviewSpiderInfo), ctx, spiderUUID, actor, approve, comment)
+}
+
+// SubmitSpiderInfo mocks base method.
+func (m *MockSpiderWorkflowUsecase) SubmitSpiderInfo(ctx context.Context, spiderUUID string, actor *model0.LoginUser) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitSpiderInfo", ctx, spiderUUID, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubmitSpiderInfo indicates an expected call of SubmitSpiderInfo.
+func (mr *MockSpiderWorkflowUsecaseMockRecorder) SubmitSpiderInfo(ctx, spiderUUID, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitSpiderInfo", reflect.TypeOf((*MockSpiderWorkflowUsecase)(nil).SubmitSpiderInfo), ctx, spiderUUID, actor)
 }
 
 // MockSpiderInfoUsecase is a mock of SpiderInfoUsecase interface.
@@ -275,18 +493,18 @@ func (m *MockSpiderInfoUsecase) EXPECT() *MockSpiderInfoUsecaseMockRecorder {
 }
 
 // GetSpiderImagesUsecase mocks base method.
-func (m *MockSpiderInfoUsecase) GetSpiderImagesUsecase(ctx context.Context, fileImages []string) ([]model0.SpiderImageList, error) {
+func (m *MockSpiderInfoUsecase) GetSpiderImagesUsecase(ctx context.Context, spiderUUID string, fileImages []string) ([]model0.SpiderImageList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSpiderImagesUsecase", ctx, fileImages)
+	ret := m.ctrl.Call(m, "GetSpiderImagesUsecase", ctx, spiderUUID, fileImages)
 	ret0, _ := ret[0].([]model0.SpiderImageList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSpiderImagesUsecase indicates an expected call of GetSpiderImagesUsecase.
-func (mr *MockSpiderInfoUsecaseMockRecorder) GetSpiderImagesUsecase(ctx, fileImages interface{}) *gomock.Call {
+func (mr *MockSpiderInfoUsecaseMockRecorder) GetSpiderImagesUsecase(ctx, spiderUUID, fileImages interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpiderImagesUsecase", reflect.TypeOf((*MockSpiderInfoUsecase)(nil).GetSpiderImagesUsecase), ctx, fileImages)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpiderImagesUsecase", reflect.TypeOf((*MockSpiderInfoUsecase)(nil).GetSpiderImagesUsecase), ctx, spiderUUID, fileImages)
 }
 
 // GetSpiderInfoListByGeographies mocks base method.
@@ -320,33 +538,33 @@ func (mr *MockSpiderInfoUsecaseMockRecorder) GetSpiderInfoListByLocality(ctx, lo
 }
 
 // GetSpiderInfoListManager mocks base method.
-func (m *MockSpiderInfoUsecase) GetSpiderInfoListManager(ctx context.Context, usecase string, page, limit int) ([]model0.SpiderInfo, error) {
+func (m *MockSpiderInfoUsecase) GetSpiderInfoListManager(ctx context.Context, page, limit int) ([]model0.SpiderInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSpiderInfoListManager", ctx, usecase, page, limit)
+	ret := m.ctrl.Call(m, "GetSpiderInfoListManager", ctx, page, limit)
 	ret0, _ := ret[0].([]model0.SpiderInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSpiderInfoListManager indicates an expected call of GetSpiderInfoListManager.
-func (mr *MockSpiderInfoUsecaseMockRecorder) GetSpiderInfoListManager(ctx, usecase, page, limit interface{}) *gomock.Call {
+func (mr *MockSpiderInfoUsecaseMockRecorder) GetSpiderInfoListManager(ctx, page, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpiderInfoListManager", reflect.TypeOf((*MockSpiderInfoUsecase)(nil).GetSpiderInfoListManager), ctx, usecase, page, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpiderInfoListManager", reflect.TypeOf((*MockSpiderInfoUsecase)(nil).GetSpiderInfoListManager), ctx, page, limit)
 }
 
 // GetSpiderInfoUsecase mocks base method.
-func (m *MockSpiderInfoUsecase) GetSpiderInfoUsecase(ctx context.Context, spiderUUID, username string, isValidateLogin bool) (*model0.SpiderInfo, error) {
+func (m *MockSpiderInfoUsecase) GetSpiderInfoUsecase(ctx context.Context, spiderUUID string, loginUser *model0.LoginUser) (*model0.SpiderInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSpiderInfoUsecase", ctx, spiderUUID, username, isValidateLogin)
+	ret := m.ctrl.Call(m, "GetSpiderInfoUsecase", ctx, spiderUUID, loginUser)
 	ret0, _ := ret[0].(*model0.SpiderInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSpiderInfoUsecase indicates an expected call of GetSpiderInfoUsecase.
-func (mr *MockSpiderInfoUsecaseMockRecorder) GetSpiderInfoUsecase(ctx, spiderUUID, username, isValidateLogin interface{}) *gomock.Call {
+func (mr *MockSpiderInfoUsecaseMockRecorder) GetSpiderInfoUsecase(ctx, spiderUUID, loginUser interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpiderInfoUsecase", reflect.TypeOf((*MockSpiderInfoUsecase)(nil).GetSpiderInfoUsecase), ctx, spiderUUID, username, isValidateLogin)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpiderInfoUsecase", reflect.TypeOf((*MockSpiderInfoUsecase)(nil).GetSpiderInfoUsecase), ctx, spiderUUID, loginUser)
 }
 
 // GetSpiderListBySpiderTypeUsecase mocks base method.
@@ -363,3 +581,33 @@ func (mr *MockSpiderInfoUsecaseMockRecorder) GetSpiderListBySpiderTypeUsecase(ct
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpiderListBySpiderTypeUsecase", reflect.TypeOf((*MockSpiderInfoUsecase)(nil).GetSpiderListBySpiderTypeUsecase), ctx, param)
 }
+
+// OpenSpiderImage mocks base method.
+func (m *MockSpiderInfoUsecase) OpenSpiderImage(ctx context.Context, param model0.OpenSpiderImageParam) (*model0.SpiderImageFile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenSpiderImage", ctx, param)
+	ret0, _ := ret[0].(*model0.SpiderImageFile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenSpiderImage indicates an expected call of OpenSpiderImage.
+func (mr *MockSpiderInfoUsecaseMockRecorder) OpenSpiderImage(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenSpiderImage", reflect.TypeOf((*MockSpiderInfoUsecase)(nil).OpenSpiderImage), ctx, param)
+}
+
+// SuggestSpiderPosition mocks base method.
+func (m *MockSpiderInfoUsecase) SuggestSpiderPosition(ctx context.Context, spiderUUID string) (*model0.SpiderPositionSuggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestSpiderPosition", ctx, spiderUUID)
+	ret0, _ := ret[0].(*model0.SpiderPositionSuggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestSpiderPosition indicates an expected call of SuggestSpiderPosition.
+func (mr *MockSpiderInfoUsecaseMockRecorder) SuggestSpiderPosition(ctx, spiderUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestSpiderPosition", reflect.TypeOf((*MockSpiderInfoUsecase)(nil).SuggestSpiderPosition), ctx, spiderUUID)
+}
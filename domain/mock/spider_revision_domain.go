@@ -0,0 +1,151 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: spider_revision_domain.go
+
+// Package mock_domain is a generated GoMock package.
+package mock_domain
+
+import (
+	context "context"
+	reflect "reflect"
+	model "spider-go/model"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSpiderRevisionRepository is a mock of SpiderRevisionRepository interface.
+type MockSpiderRevisionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpiderRevisionRepositoryMockRecorder
+}
+
+// MockSpiderRevisionRepositoryMockRecorder is the mock recorder for MockSpiderRevisionRepository.
+type MockSpiderRevisionRepositoryMockRecorder struct {
+	mock *MockSpiderRevisionRepository
+}
+
+// NewMockSpiderRevisionRepository creates a new mock instance.
+func NewMockSpiderRevisionRepository(ctrl *gomock.Controller) *MockSpiderRevisionRepository {
+	mock := &MockSpiderRevisionRepository{ctrl: ctrl}
+	mock.recorder = &MockSpiderRevisionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSpiderRevisionRepository) EXPECT() *MockSpiderRevisionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindSpiderRevision mocks base method.
+func (m *MockSpiderRevisionRepository) FindSpiderRevision(ctx context.Context, spiderUUID string, revisionNumber int64) (*model.SpiderRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSpiderRevision", ctx, spiderUUID, revisionNumber)
+	ret0, _ := ret[0].(*model.SpiderRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSpiderRevision indicates an expected call of FindSpiderRevision.
+func (mr *MockSpiderRevisionRepositoryMockRecorder) FindSpiderRevision(ctx, spiderUUID, revisionNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSpiderRevision", reflect.TypeOf((*MockSpiderRevisionRepository)(nil).FindSpiderRevision), ctx, spiderUUID, revisionNumber)
+}
+
+// FindSpiderRevisionList mocks base method.
+func (m *MockSpiderRevisionRepository) FindSpiderRevisionList(ctx context.Context, spiderUUID string, page, size int32) ([]model.SpiderRevision, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSpiderRevisionList", ctx, spiderUUID, page, size)
+	ret0, _ := ret[0].([]model.SpiderRevision)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindSpiderRevisionList indicates an expected call of FindSpiderRevisionList.
+func (mr *MockSpiderRevisionRepositoryMockRecorder) FindSpiderRevisionList(ctx, spiderUUID, page, size interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSpiderRevisionList", reflect.TypeOf((*MockSpiderRevisionRepository)(nil).FindSpiderRevisionList), ctx, spiderUUID, page, size)
+}
+
+// InsertSpiderRevision mocks base method.
+func (m *MockSpiderRevisionRepository) InsertSpiderRevision(ctx context.Context, revision model.SpiderRevision) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertSpiderRevision", ctx, revision)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertSpiderRevision indicates an expected call of InsertSpiderRevision.
+func (mr *MockSpiderRevisionRepositoryMockRecorder) InsertSpiderRevision(ctx, revision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSpiderRevision", reflect.TypeOf((*MockSpiderRevisionRepository)(nil).InsertSpiderRevision), ctx, revision)
+}
+
+// MockSpiderRevisionUsecase is a mock of SpiderRevisionUsecase interface.
+type MockSpiderRevisionUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpiderRevisionUsecaseMockRecorder
+}
+
+// MockSpiderRevisionUsecaseMockRecorder is the mock recorder for MockSpiderRevisionUsecase.
+type MockSpiderRevisionUsecaseMockRecorder struct {
+	mock *MockSpiderRevisionUsecase
+}
+
+// NewMockSpiderRevisionUsecase creates a new mock instance.
+func NewMockSpiderRevisionUsecase(ctrl *gomock.Controller) *MockSpiderRevisionUsecase {
+	mock := &MockSpiderRevisionUsecase{ctrl: ctrl}
+	mock.recorder = &MockSpiderRevisionUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSpiderRevisionUsecase) EXPECT() *MockSpiderRevisionUsecaseMockRecorder {
+	return m.recorder
+}
+
+// DiffSpiderRevision mocks base method.
+func (m *MockSpiderRevisionUsecase) DiffSpiderRevision(ctx context.Context, spiderUUID string, fromRevision, toRevision int64) ([]model.SpiderRevisionDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffSpiderRevision", ctx, spiderUUID, fromRevision, toRevision)
+	ret0, _ := ret[0].([]model.SpiderRevisionDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffSpiderRevision indicates an expected call of DiffSpiderRevision.
+func (mr *MockSpiderRevisionUsecaseMockRecorder) DiffSpiderRevision(ctx, spiderUUID, fromRevision, toRevision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffSpiderRevision", reflect.TypeOf((*MockSpiderRevisionUsecase)(nil).DiffSpiderRevision), ctx, spiderUUID, fromRevision, toRevision)
+}
+
+// GetSpiderRevisionList mocks base method.
+func (m *MockSpiderRevisionUsecase) GetSpiderRevisionList(ctx context.Context, spiderUUID string, page, size int32) ([]model.SpiderRevision, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSpiderRevisionList", ctx, spiderUUID, page, size)
+	ret0, _ := ret[0].([]model.SpiderRevision)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSpiderRevisionList indicates an expected call of GetSpiderRevisionList.
+func (mr *MockSpiderRevisionUsecaseMockRecorder) GetSpiderRevisionList(ctx, spiderUUID, page, size interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpiderRevisionList", reflect.TypeOf((*MockSpiderRevisionUsecase)(nil).GetSpiderRevisionList), ctx, spiderUUID, page, size)
+}
+
+// RestoreSpiderRevision mocks base method.
+func (m *MockSpiderRevisionUsecase) RestoreSpiderRevision(ctx context.Context, spiderUUID string, revisionNumber int64, username string) (*model.SpiderRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSpiderRevision", ctx, spiderUUID, revisionNumber, username)
+	ret0, _ := ret[0].(*model.SpiderRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreSpiderRevision indicates an expected call of RestoreSpiderRevision.
+func (mr *MockSpiderRevisionUsecaseMockRecorder) RestoreSpiderRevision(ctx, spiderUUID, revisionNumber, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSpiderRevision", reflect.TypeOf((*MockSpiderRevisionUsecase)(nil).RestoreSpiderRevision), ctx, spiderUUID, revisionNumber, username)
+}
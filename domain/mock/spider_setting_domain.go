@@ -8,6 +8,8 @@ import (
 	context "context"
 	reflect "reflect"
 	model "spider-go/api/model"
+	model0 "spider-go/model"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,6 +37,21 @@ func (m *MockUploadImageUsecase) EXPECT() *MockUploadImageUsecaseMockRecorder {
 	return m.recorder
 }
 
+// UploadImageFiles mocks base method.
+func (m *MockUploadImageUsecase) UploadImageFiles(ctx context.Context, spiderUUID string, nextFile func() (*model0.UploadImageFile, error)) ([]model0.UploadImageResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadImageFiles", ctx, spiderUUID, nextFile)
+	ret0, _ := ret[0].([]model0.UploadImageResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadImageFiles indicates an expected call of UploadImageFiles.
+func (mr *MockUploadImageUsecaseMockRecorder) UploadImageFiles(ctx, spiderUUID, nextFile interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadImageFiles", reflect.TypeOf((*MockUploadImageUsecase)(nil).UploadImageFiles), ctx, spiderUUID, nextFile)
+}
+
 // UploadImageSpiderUsecase mocks base method.
 func (m *MockUploadImageUsecase) UploadImageSpiderUsecase(ctx context.Context, spiderUUID string, listImageEncode64 []string) error {
 	m.ctrl.T.Helper()
@@ -49,6 +66,44 @@ func (mr *MockUploadImageUsecaseMockRecorder) UploadImageSpiderUsecase(ctx, spid
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadImageSpiderUsecase", reflect.TypeOf((*MockUploadImageUsecase)(nil).UploadImageSpiderUsecase), ctx, spiderUUID, listImageEncode64)
 }
 
+// MockImageDerivativeUsecase is a mock of ImageDerivativeUsecase interface.
+type MockImageDerivativeUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockImageDerivativeUsecaseMockRecorder
+}
+
+// MockImageDerivativeUsecaseMockRecorder is the mock recorder for MockImageDerivativeUsecase.
+type MockImageDerivativeUsecaseMockRecorder struct {
+	mock *MockImageDerivativeUsecase
+}
+
+// NewMockImageDerivativeUsecase creates a new mock instance.
+func NewMockImageDerivativeUsecase(ctrl *gomock.Controller) *MockImageDerivativeUsecase {
+	mock := &MockImageDerivativeUsecase{ctrl: ctrl}
+	mock.recorder = &MockImageDerivativeUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImageDerivativeUsecase) EXPECT() *MockImageDerivativeUsecaseMockRecorder {
+	return m.recorder
+}
+
+// BackfillImageDerivatives mocks base method.
+func (m *MockImageDerivativeUsecase) BackfillImageDerivatives(ctx context.Context, overwrite bool) (*model0.ImageDerivativeBackfillReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackfillImageDerivatives", ctx, overwrite)
+	ret0, _ := ret[0].(*model0.ImageDerivativeBackfillReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BackfillImageDerivatives indicates an expected call of BackfillImageDerivatives.
+func (mr *MockImageDerivativeUsecaseMockRecorder) BackfillImageDerivatives(ctx, overwrite interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackfillImageDerivatives", reflect.TypeOf((*MockImageDerivativeUsecase)(nil).BackfillImageDerivatives), ctx, overwrite)
+}
+
 // MockDeleteSpiderInfoUsecase is a mock of DeleteSpiderInfoUsecase interface.
 type MockDeleteSpiderInfoUsecase struct {
 	ctrl     *gomock.Controller
@@ -73,17 +128,17 @@ func (m *MockDeleteSpiderInfoUsecase) EXPECT() *MockDeleteSpiderInfoUsecaseMockR
 }
 
 // DeleteSpiderInfoUsecase mocks base method.
-func (m *MockDeleteSpiderInfoUsecase) DeleteSpiderInfoUsecase(ctx context.Context, spider_uuid string) error {
+func (m *MockDeleteSpiderInfoUsecase) DeleteSpiderInfoUsecase(ctx context.Context, spider_uuid, username string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSpiderInfoUsecase", ctx, spider_uuid)
+	ret := m.ctrl.Call(m, "DeleteSpiderInfoUsecase", ctx, spider_uuid, username)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteSpiderInfoUsecase indicates an expected call of DeleteSpiderInfoUsecase.
-func (mr *MockDeleteSpiderInfoUsecaseMockRecorder) DeleteSpiderInfoUsecase(ctx, spider_uuid interface{}) *gomock.Call {
+func (mr *MockDeleteSpiderInfoUsecaseMockRecorder) DeleteSpiderInfoUsecase(ctx, spider_uuid, username interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSpiderInfoUsecase", reflect.TypeOf((*MockDeleteSpiderInfoUsecase)(nil).DeleteSpiderInfoUsecase), ctx, spider_uuid)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSpiderInfoUsecase", reflect.TypeOf((*MockDeleteSpiderInfoUsecase)(nil).DeleteSpiderInfoUsecase), ctx, spider_uuid, username)
 }
 
 // MockUpdateSpiderInfoUsecase is a mock of UpdateSpiderInfoUsecase interface.
@@ -109,18 +164,34 @@ func (m *MockUpdateSpiderInfoUsecase) EXPECT() *MockUpdateSpiderInfoUsecaseMockR
 	return m.recorder
 }
 
+// PatchSpiderInfoUsecase mocks base method.
+func (m *MockUpdateSpiderInfoUsecase) PatchSpiderInfoUsecase(ctx context.Context, req model.PatchSpiderInfoRequestData, loginUser *model0.LoginUser) (*model0.SpiderInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchSpiderInfoUsecase", ctx, req, loginUser)
+	ret0, _ := ret[0].(*model0.SpiderInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchSpiderInfoUsecase indicates an expected call of PatchSpiderInfoUsecase.
+func (mr *MockUpdateSpiderInfoUsecaseMockRecorder) PatchSpiderInfoUsecase(ctx, req, loginUser interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchSpiderInfoUsecase", reflect.TypeOf((*MockUpdateSpiderInfoUsecase)(nil).PatchSpiderInfoUsecase), ctx, req, loginUser)
+}
+
 // UpdateSpiderInfoUsecase mocks base method.
-func (m *MockUpdateSpiderInfoUsecase) UpdateSpiderInfoUsecase(ctx context.Context, spiderInfo model.SpiderInfo) error {
+func (m *MockUpdateSpiderInfoUsecase) UpdateSpiderInfoUsecase(ctx context.Context, spiderInfo model.SpiderInfo, loginUser *model0.LoginUser) (*model0.SpiderInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateSpiderInfoUsecase", ctx, spiderInfo)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "UpdateSpiderInfoUsecase", ctx, spiderInfo, loginUser)
+	ret0, _ := ret[0].(*model0.SpiderInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // UpdateSpiderInfoUsecase indicates an expected call of UpdateSpiderInfoUsecase.
-func (mr *MockUpdateSpiderInfoUsecaseMockRecorder) UpdateSpiderInfoUsecase(ctx, spiderInfo interface{}) *gomock.Call {
+func (mr *MockUpdateSpiderInfoUsecaseMockRecorder) UpdateSpiderInfoUsecase(ctx, spiderInfo, loginUser interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSpiderInfoUsecase", reflect.TypeOf((*MockUpdateSpiderInfoUsecase)(nil).UpdateSpiderInfoUsecase), ctx, spiderInfo)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSpiderInfoUsecase", reflect.TypeOf((*MockUpdateSpiderInfoUsecase)(nil).UpdateSpiderInfoUsecase), ctx, spiderInfo, loginUser)
 }
 
 // MockRemoveSpiderImageUsecase is a mock of RemoveSpiderImageUsecase interface.
@@ -159,3 +230,83 @@ func (mr *MockRemoveSpiderImageUsecaseMockRecorder) RemoveSpiderImageBySpiderIma
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSpiderImageBySpiderImageNameList", reflect.TypeOf((*MockRemoveSpiderImageUsecase)(nil).RemoveSpiderImageBySpiderImageNameList), ctx, spiderUUID, spiderImageList)
 }
+
+// MockSpiderTrashUsecase is a mock of SpiderTrashUsecase interface.
+type MockSpiderTrashUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpiderTrashUsecaseMockRecorder
+}
+
+// MockSpiderTrashUsecaseMockRecorder is the mock recorder for MockSpiderTrashUsecase.
+type MockSpiderTrashUsecaseMockRecorder struct {
+	mock *MockSpiderTrashUsecase
+}
+
+// NewMockSpiderTrashUsecase creates a new mock instance.
+func NewMockSpiderTrashUsecase(ctrl *gomock.Controller) *MockSpiderTrashUsecase {
+	mock := &MockSpiderTrashUsecase{ctrl: ctrl}
+	mock.recorder = &MockSpiderTrashUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSpiderTrashUsecase) EXPECT() *MockSpiderTrashUsecaseMockRecorder {
+	return m.recorder
+}
+
+// GetSpiderTrashList mocks base method.
+func (m *MockSpiderTrashUsecase) GetSpiderTrashList(ctx context.Context, page, size int32) ([]model0.SpiderInfo, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSpiderTrashList", ctx, page, size)
+	ret0, _ := ret[0].([]model0.SpiderInfo)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSpiderTrashList indicates an expected call of GetSpiderTrashList.
+func (mr *MockSpiderTrashUsecaseMockRecorder) GetSpiderTrashList(ctx, page, size interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpiderTrashList", reflect.TypeOf((*MockSpiderTrashUsecase)(nil).GetSpiderTrashList), ctx, page, size)
+}
+
+// PurgeExpiredSpiderTrash mocks base method.
+func (m *MockSpiderTrashUsecase) PurgeExpiredSpiderTrash(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpiredSpiderTrash", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpiredSpiderTrash indicates an expected call of PurgeExpiredSpiderTrash.
+func (mr *MockSpiderTrashUsecaseMockRecorder) PurgeExpiredSpiderTrash(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpiredSpiderTrash", reflect.TypeOf((*MockSpiderTrashUsecase)(nil).PurgeExpiredSpiderTrash), ctx)
+}
+
+// RestoreSpiderInfo mocks base method.
+func (m *MockSpiderTrashUsecase) RestoreSpiderInfo(ctx context.Context, spiderUUID, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSpiderInfo", ctx, spiderUUID, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreSpiderInfo indicates an expected call of RestoreSpiderInfo.
+func (mr *MockSpiderTrashUsecaseMockRecorder) RestoreSpiderInfo(ctx, spiderUUID, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSpiderInfo", reflect.TypeOf((*MockSpiderTrashUsecase)(nil).RestoreSpiderInfo), ctx, spiderUUID, username)
+}
+
+// RunTrashPurge mocks base method.
+func (m *MockSpiderTrashUsecase) RunTrashPurge(ctx context.Context, interval time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunTrashPurge", ctx, interval)
+}
+
+// RunTrashPurge indicates an expected call of RunTrashPurge.
+func (mr *MockSpiderTrashUsecaseMockRecorder) RunTrashPurge(ctx, interval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunTrashPurge", reflect.TypeOf((*MockSpiderTrashUsecase)(nil).RunTrashPurge), ctx, interval)
+}
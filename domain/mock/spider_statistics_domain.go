@@ -35,63 +35,62 @@ func (m *MockStatisticsRepository) EXPECT() *MockStatisticsRepositoryMockRecorde
 	return m.recorder
 }
 
-// FindAllSpiderStatistics mocks base method.
-func (m *MockStatisticsRepository) FindAllSpiderStatistics(ctx context.Context) ([]model.SpiderStatistics, error) {
+// AggregateSpiderStatistics mocks base method.
+func (m *MockStatisticsRepository) AggregateSpiderStatistics(ctx context.Context) ([]model.SpiderStatistics, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAllSpiderStatistics", ctx)
+	ret := m.ctrl.Call(m, "AggregateSpiderStatistics", ctx)
 	ret0, _ := ret[0].([]model.SpiderStatistics)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindAllSpiderStatistics indicates an expected call of FindAllSpiderStatistics.
-func (mr *MockStatisticsRepositoryMockRecorder) FindAllSpiderStatistics(ctx interface{}) *gomock.Call {
+// AggregateSpiderStatistics indicates an expected call of AggregateSpiderStatistics.
+func (mr *MockStatisticsRepositoryMockRecorder) AggregateSpiderStatistics(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllSpiderStatistics", reflect.TypeOf((*MockStatisticsRepository)(nil).FindAllSpiderStatistics), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregateSpiderStatistics", reflect.TypeOf((*MockStatisticsRepository)(nil).AggregateSpiderStatistics), ctx)
 }
 
-// FindFamilyListWithLimitSizePage mocks base method.
-func (m *MockStatisticsRepository) FindFamilyListWithLimitSizePage(ctx context.Context, page, limit int32) ([]model.SpiderStatistics, error) {
+// FindAllSpiderStatistics mocks base method.
+func (m *MockStatisticsRepository) FindAllSpiderStatistics(ctx context.Context) ([]model.SpiderStatistics, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindFamilyListWithLimitSizePage", ctx, page, limit)
+	ret := m.ctrl.Call(m, "FindAllSpiderStatistics", ctx)
 	ret0, _ := ret[0].([]model.SpiderStatistics)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindFamilyListWithLimitSizePage indicates an expected call of FindFamilyListWithLimitSizePage.
-func (mr *MockStatisticsRepositoryMockRecorder) FindFamilyListWithLimitSizePage(ctx, page, limit interface{}) *gomock.Call {
+// FindAllSpiderStatistics indicates an expected call of FindAllSpiderStatistics.
+func (mr *MockStatisticsRepositoryMockRecorder) FindAllSpiderStatistics(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFamilyListWithLimitSizePage", reflect.TypeOf((*MockStatisticsRepository)(nil).FindFamilyListWithLimitSizePage), ctx, page, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllSpiderStatistics", reflect.TypeOf((*MockStatisticsRepository)(nil).FindAllSpiderStatistics), ctx)
 }
 
-// FindSpiderStatisticsByFamily mocks base method.
-func (m *MockStatisticsRepository) FindSpiderStatisticsByFamily(ctx context.Context, family string) (*model.SpiderStatistics, error) {
+// IncreaseSpiderStatistics mocks base method.
+func (m *MockStatisticsRepository) IncreaseSpiderStatistics(ctx context.Context, familyName, genusName, speciesName string, delta int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindSpiderStatisticsByFamily", ctx, family)
-	ret0, _ := ret[0].(*model.SpiderStatistics)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "IncreaseSpiderStatistics", ctx, familyName, genusName, speciesName, delta)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// FindSpiderStatisticsByFamily indicates an expected call of FindSpiderStatisticsByFamily.
-func (mr *MockStatisticsRepositoryMockRecorder) FindSpiderStatisticsByFamily(ctx, family interface{}) *gomock.Call {
+// IncreaseSpiderStatistics indicates an expected call of IncreaseSpiderStatistics.
+func (mr *MockStatisticsRepositoryMockRecorder) IncreaseSpiderStatistics(ctx, familyName, genusName, speciesName, delta interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSpiderStatisticsByFamily", reflect.TypeOf((*MockStatisticsRepository)(nil).FindSpiderStatisticsByFamily), ctx, family)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncreaseSpiderStatistics", reflect.TypeOf((*MockStatisticsRepository)(nil).IncreaseSpiderStatistics), ctx, familyName, genusName, speciesName, delta)
 }
 
-// UpsertSpiderStatistics mocks base method.
-func (m *MockStatisticsRepository) UpsertSpiderStatistics(ctx context.Context, familyName string, data model.SpiderStatistics) error {
+// ReplaceAllSpiderStatistics mocks base method.
+func (m *MockStatisticsRepository) ReplaceAllSpiderStatistics(ctx context.Context, data []model.SpiderStatistics) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpsertSpiderStatistics", ctx, familyName, data)
+	ret := m.ctrl.Call(m, "ReplaceAllSpiderStatistics", ctx, data)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpsertSpiderStatistics indicates an expected call of UpsertSpiderStatistics.
-func (mr *MockStatisticsRepositoryMockRecorder) UpsertSpiderStatistics(ctx, familyName, data interface{}) *gomock.Call {
+// ReplaceAllSpiderStatistics indicates an expected call of ReplaceAllSpiderStatistics.
+func (mr *MockStatisticsRepositoryMockRecorder) ReplaceAllSpiderStatistics(ctx, data interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertSpiderStatistics", reflect.TypeOf((*MockStatisticsRepository)(nil).UpsertSpiderStatistics), ctx, familyName, data)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceAllSpiderStatistics", reflect.TypeOf((*MockStatisticsRepository)(nil).ReplaceAllSpiderStatistics), ctx, data)
 }
 
 // MockStatisticsUsecase is a mock of StatisticsUsecase interface.
@@ -132,6 +131,44 @@ func (mr *MockStatisticsUsecaseMockRecorder) GetSpiderStatisticsList(ctx interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpiderStatisticsList", reflect.TypeOf((*MockStatisticsUsecase)(nil).GetSpiderStatisticsList), ctx)
 }
 
+// MockSpiderStatisticsRebuildUsecase is a mock of SpiderStatisticsRebuildUsecase interface.
+type MockSpiderStatisticsRebuildUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpiderStatisticsRebuildUsecaseMockRecorder
+}
+
+// MockSpiderStatisticsRebuildUsecaseMockRecorder is the mock recorder for MockSpiderStatisticsRebuildUsecase.
+type MockSpiderStatisticsRebuildUsecaseMockRecorder struct {
+	mock *MockSpiderStatisticsRebuildUsecase
+}
+
+// NewMockSpiderStatisticsRebuildUsecase creates a new mock instance.
+func NewMockSpiderStatisticsRebuildUsecase(ctrl *gomock.Controller) *MockSpiderStatisticsRebuildUsecase {
+	mock := &MockSpiderStatisticsRebuildUsecase{ctrl: ctrl}
+	mock.recorder = &MockSpiderStatisticsRebuildUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSpiderStatisticsRebuildUsecase) EXPECT() *MockSpiderStatisticsRebuildUsecaseMockRecorder {
+	return m.recorder
+}
+
+// RebuildSpiderStatistics mocks base method.
+func (m *MockSpiderStatisticsRebuildUsecase) RebuildSpiderStatistics(ctx context.Context, dryRun bool) (*model.SpiderStatisticsRebuildReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebuildSpiderStatistics", ctx, dryRun)
+	ret0, _ := ret[0].(*model.SpiderStatisticsRebuildReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RebuildSpiderStatistics indicates an expected call of RebuildSpiderStatistics.
+func (mr *MockSpiderStatisticsRebuildUsecaseMockRecorder) RebuildSpiderStatistics(ctx, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildSpiderStatistics", reflect.TypeOf((*MockSpiderStatisticsRebuildUsecase)(nil).RebuildSpiderStatistics), ctx, dryRun)
+}
+
 // MockGetFamilyListUsecase is a mock of GetFamilyListUsecase interface.
 type MockGetFamilyListUsecase struct {
 	ctrl     *gomock.Controller
@@ -156,16 +193,17 @@ func (m *MockGetFamilyListUsecase) EXPECT() *MockGetFamilyListUsecaseMockRecorde
 }
 
 // Execute mocks base method.
-func (m *MockGetFamilyListUsecase) Execute(ctx context.Context, page, size int32) ([]model.FamilyList, error) {
+func (m *MockGetFamilyListUsecase) Execute(ctx context.Context, sortBy string, page, size int32) ([]model.FamilyList, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Execute", ctx, page, size)
+	ret := m.ctrl.Call(m, "Execute", ctx, sortBy, page, size)
 	ret0, _ := ret[0].([]model.FamilyList)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // Execute indicates an expected call of Execute.
-func (mr *MockGetFamilyListUsecaseMockRecorder) Execute(ctx, page, size interface{}) *gomock.Call {
+func (mr *MockGetFamilyListUsecaseMockRecorder) Execute(ctx, sortBy, page, size interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockGetFamilyListUsecase)(nil).Execute), ctx, page, size)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockGetFamilyListUsecase)(nil).Execute), ctx, sortBy, page, size)
 }
@@ -0,0 +1,11 @@
+package domain
+
+import (
+	"context"
+	"spider-go/model"
+)
+
+//go:generate mockgen -source=notifier_domain.go -destination=./mock/notifier_domain.go
+type Notifier interface {
+	Send(ctx context.Context, notification model.Notification) (err error)
+}
@@ -0,0 +1,9 @@
+package domain
+
+import "context"
+
+//go:generate mockgen -source=password_reset_domain.go -destination=./mock/password_reset_domain.go
+type PasswordResetUsecase interface {
+	RequestPasswordReset(ctx context.Context, username string) (err error)
+	ConfirmPasswordReset(ctx context.Context, token, newPassword, confirmPassword string) (err error)
+}
@@ -10,4 +10,9 @@ import (
 type RedisRepository interface {
 	SetDataToRedisWithTTL(ctx context.Context, key string, data interface{}, ttl time.Duration) (err error)
 	GetDataFromRedis(ctx context.Context, key string, data interface{}) (err error)
+	DeleteDataFromRedis(ctx context.Context, key string) (err error)
+	GetAndDeleteDataFromRedis(ctx context.Context, key string, data interface{}) (err error)
+	CompareAndSetDataToRedis(ctx context.Context, key, field, expected string, data interface{}, ttl time.Duration) (err error)
+	IncreaseCounter(ctx context.Context, key string, ttl time.Duration) (count int64, err error)
+	GetCounter(ctx context.Context, key string) (count int64, err error)
 }
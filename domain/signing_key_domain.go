@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"context"
+	"spider-go/model"
+	"time"
+)
+
+//go:generate mockgen -source=signing_key_domain.go -destination=./mock/signing_key_domain.go
+type SigningKeyRepository interface {
+	InsertSigningKey(ctx context.Context, key model.SigningKey) (err error)
+	FindVerifiableSigningKeys(ctx context.Context, now time.Time) (keys []model.SigningKey, err error)
+	RetireSigningKeys(ctx context.Context, exceptKID string, verifyUntil time.Time) (err error)
+	DeleteExpiredSigningKeys(ctx context.Context, now time.Time) (err error)
+}
+
+type SigningKeyUsecase interface {
+	RefreshSigningKeys(ctx context.Context) (err error)
+	RunKeyRotation(ctx context.Context, interval time.Duration)
+	GetJWKS(ctx context.Context) (jwks model.JWKS, err error)
+}
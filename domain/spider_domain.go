@@ -4,6 +4,7 @@ import (
 	"context"
 	api_model "spider-go/api/model"
 	"spider-go/model"
+	"time"
 )
 
 //go:generate mockgen -source=spider_domain.go -destination=./mock/spider_domain.go
@@ -12,25 +13,46 @@ type SpiderRepository interface {
 	InsertNewSpider(ctx context.Context, data model.SpiderInfo) error
 	FindSpiderByUUID(ctx context.Context, spiderUUID string) (*model.SpiderInfo, error)
 	UpdateImageFileToSpiderInfo(ctx context.Context, filesName []string, spiderUUID string) error
+	PushSpiderImageExif(ctx context.Context, spiderUUID string, imageExif []model.ImageExif) error
 	FindSpiderByUUIDAndStatus(ctx context.Context, spiderUUID string, isStatusActive bool) (*model.SpiderInfo, error)
 	FindAllSpiderListWithActive(ctx context.Context) ([]model.SpiderInfo, error)
 	FindAllSpiderListManager(ctx context.Context, page, limit int) ([]model.SpiderInfo, error)
-	DeleteSpiderInfoWithSpiderUUID(ctx context.Context, spiderUUID string) error
-	UpdateSpiderInfo(ctx context.Context, spiderUUID string, spiderInfo model.SpiderInfo) (bool, error)
+	MoveSpiderInfoToTrash(ctx context.Context, spiderUUID, previousStatus, deletedBy string, deletedAt time.Time) error
+	RestoreSpiderInfoFromTrash(ctx context.Context, spiderUUID, status string) error
+	FindSpiderTrashList(ctx context.Context, page, size int32) ([]model.SpiderInfo, int64, error)
+	FindExpiredSpiderTrash(ctx context.Context, deletedBefore time.Time) ([]model.SpiderInfo, error)
+	PurgeSpiderInfo(ctx context.Context, spiderUUID string, deletedBefore time.Time) error
+	UpdateSpiderStatus(ctx context.Context, spiderUUID, fromStatus, toStatus string, review *model.SpiderReview) error
+	FindSpiderInfoListByStatus(ctx context.Context, status string, page, size int32) ([]model.SpiderInfo, int64, error)
+	UpdateSpiderInfo(ctx context.Context, spiderUUID string, expectedVersion int64, spiderInfo model.SpiderInfo) (bool, error)
+	PatchSpiderInfo(ctx context.Context, spiderUUID string, expectedVersion int64, spiderInfo model.SpiderInfo, fields []string) (bool, error)
 	FindSpiderInfoListByGeographies(ctx context.Context, province, district, position string) ([]model.SpiderInfo, error)
 	FindSpiderInfoBySpiderType(ctx context.Context, family, genus, species string, isLimitPage bool, page, limit int32) ([]model.SpiderInfo, error)
 	FindSpiderInfoByLocality(ctx context.Context, locality string, page, limit int32) ([]model.SpiderInfo, error)
 	FindSpiderInfoByFirstFamilyOrGenus(ctx context.Context, field, value string) ([]model.SpiderInfo, error)
+	FindFamilyList(ctx context.Context, sortBy string, page, size int32) ([]model.FamilyList, int64, error)
+	FindSpiderImageFileList(ctx context.Context) ([]model.SpiderInfo, error)
 }
 
 type RegisterSpiderUsecase interface {
-	Register(ctx context.Context, req api_model.SpiderInfo, username string) (string, error)
+	// Register create draft when login user can not review, record of reviewer is published immediately
+	Register(ctx context.Context, req api_model.SpiderInfo, loginUser *model.LoginUser) (spiderUUID, status string, err error)
+}
+
+type SpiderWorkflowUsecase interface {
+	SubmitSpiderInfo(ctx context.Context, spiderUUID string, actor *model.LoginUser) (err error)
+	ReviewSpiderInfo(ctx context.Context, spiderUUID string, actor *model.LoginUser, approve bool, comment string) (err error)
+	GetReviewQueue(ctx context.Context, page, size int32) (spiderInfoList []model.SpiderInfo, total int64, err error)
 }
 
 type SpiderInfoUsecase interface {
-	GetSpiderInfoUsecase(ctx context.Context, spiderUUID, username string) (*model.SpiderInfo, error)
-	GetSpiderImagesUsecase(ctx context.Context, fileImages []string) ([]model.SpiderImageList, error)
-	GetSpiderInfoListManager(ctx context.Context, usecase string, page, limit int) ([]model.SpiderInfo, error)
+	GetSpiderInfoUsecase(ctx context.Context, spiderUUID string, loginUser *model.LoginUser) (*model.SpiderInfo, error)
+	GetSpiderImagesUsecase(ctx context.Context, spiderUUID string, fileImages []string) ([]model.SpiderImageList, error)
+	// OpenSpiderImage return image of spider info, image of spider info that is not public require signed url
+	OpenSpiderImage(ctx context.Context, param model.OpenSpiderImageParam) (*model.SpiderImageFile, error)
+	// SuggestSpiderPosition suggest position and altitude of spider info from gps and taken time of its image
+	SuggestSpiderPosition(ctx context.Context, spiderUUID string) (*model.SpiderPositionSuggestion, error)
+	GetSpiderInfoListManager(ctx context.Context, page, limit int) ([]model.SpiderInfo, error)
 	GetSpiderInfoListByGeographies(ctx context.Context, province, district, position string) ([]model.SpiderInfo, error)
 	GetSpiderInfoListByLocality(ctx context.Context, locality string, page, size int32) ([]model.SpiderInfo, error)
 	GetSpiderListBySpiderTypeUsecase(ctx context.Context, param model.GetSpiderListBySpiderTypeParam) ([]model.SpiderInfo, error)
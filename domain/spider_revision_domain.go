@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"context"
+	"spider-go/model"
+)
+
+//go:generate mockgen -source=spider_revision_domain.go -destination=./mock/spider_revision_domain.go
+
+type SpiderRevisionRepository interface {
+	// InsertSpiderRevision assign next revision number of spider_uuid and save revision
+	InsertSpiderRevision(ctx context.Context, revision model.SpiderRevision) (revisionNumber int64, err error)
+	FindSpiderRevisionList(ctx context.Context, spiderUUID string, page, size int32) (revisionList []model.SpiderRevision, total int64, err error)
+	FindSpiderRevision(ctx context.Context, spiderUUID string, revisionNumber int64) (revision *model.SpiderRevision, err error)
+}
+
+type SpiderRevisionUsecase interface {
+	GetSpiderRevisionList(ctx context.Context, spiderUUID string, page, size int32) (revisionList []model.SpiderRevision, total int64, err error)
+	DiffSpiderRevision(ctx context.Context, spiderUUID string, fromRevision, toRevision int64) (diffList []model.SpiderRevisionDiff, err error)
+	RestoreSpiderRevision(ctx context.Context, spiderUUID string, revisionNumber int64, username string) (revision *model.SpiderRevision, err error)
+}
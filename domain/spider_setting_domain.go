@@ -3,22 +3,41 @@ package domain
 import (
 	"context"
 	api_model "spider-go/api/model"
+	"spider-go/model"
+	"time"
 )
 
 //go:generate mockgen -source=spider_setting_domain.go -destination=./mock/spider_setting_domain.go
 
 type UploadImageUsecase interface {
 	UploadImageSpiderUsecase(ctx context.Context, spiderUUID string, listImageEncode64 []string) error
+	// UploadImageFiles stream file from nextFile to storage until nextFile return io.EOF,
+	// file that is invalid is reported in result and other file is still saved
+	UploadImageFiles(ctx context.Context, spiderUUID string, nextFile func() (*model.UploadImageFile, error)) ([]model.UploadImageResult, error)
+}
+
+type ImageDerivativeUsecase interface {
+	// BackfillImageDerivatives generate derivative of existing image, image that has every derivative
+	// is skipped unless overwrite is set
+	BackfillImageDerivatives(ctx context.Context, overwrite bool) (*model.ImageDerivativeBackfillReport, error)
 }
 
 type DeleteSpiderInfoUsecase interface {
-	DeleteSpiderInfoUsecase(ctx context.Context, spider_uuid string) error
+	DeleteSpiderInfoUsecase(ctx context.Context, spider_uuid string, username string) error
 }
 
 type UpdateSpiderInfoUsecase interface {
-	UpdateSpiderInfoUsecase(ctx context.Context, spiderInfo api_model.SpiderInfo) error
+	UpdateSpiderInfoUsecase(ctx context.Context, spiderInfo api_model.SpiderInfo, loginUser *model.LoginUser) (*model.SpiderInfo, error)
+	PatchSpiderInfoUsecase(ctx context.Context, req api_model.PatchSpiderInfoRequestData, loginUser *model.LoginUser) (*model.SpiderInfo, error)
 }
 
 type RemoveSpiderImageUsecase interface {
 	RemoveSpiderImageBySpiderImageNameList(ctx context.Context, spiderUUID string, spiderImageList []string) error
 }
+
+type SpiderTrashUsecase interface {
+	GetSpiderTrashList(ctx context.Context, page, size int32) (spiderInfoList []model.SpiderInfo, total int64, err error)
+	RestoreSpiderInfo(ctx context.Context, spiderUUID string, username string) (err error)
+	PurgeExpiredSpiderTrash(ctx context.Context) (purgedCount int, err error)
+	RunTrashPurge(ctx context.Context, interval time.Duration)
+}
@@ -8,15 +8,19 @@ import (
 //go:generate mockgen -source=spider_statistics_domain.go -destination=./mock/spider_statistics_domain.go
 type StatisticsRepository interface {
 	FindAllSpiderStatistics(ctx context.Context) ([]model.SpiderStatistics, error)
-	FindSpiderStatisticsByFamily(ctx context.Context, family string) (*model.SpiderStatistics, error)
-	UpsertSpiderStatistics(ctx context.Context, familyName string, data model.SpiderStatistics) error
-	FindFamilyListWithLimitSizePage(ctx context.Context, page, limit int32) ([]model.SpiderStatistics, error)
+	IncreaseSpiderStatistics(ctx context.Context, familyName, genusName, speciesName string, delta int64) error
+	AggregateSpiderStatistics(ctx context.Context) ([]model.SpiderStatistics, error)
+	ReplaceAllSpiderStatistics(ctx context.Context, data []model.SpiderStatistics) error
 }
 
 type StatisticsUsecase interface {
 	GetSpiderStatisticsList(ctx context.Context) ([]model.SpiderStatistics, error)
 }
 
+type SpiderStatisticsRebuildUsecase interface {
+	RebuildSpiderStatistics(ctx context.Context, dryRun bool) (*model.SpiderStatisticsRebuildReport, error)
+}
+
 type GetFamilyListUsecase interface {
-	Execute(ctx context.Context, page, size int32) ([]model.FamilyList, error)
+	Execute(ctx context.Context, sortBy string, page, size int32) ([]model.FamilyList, int64, error)
 }
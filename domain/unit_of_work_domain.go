@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+//go:generate mockgen -source=unit_of_work_domain.go -destination=./mock/unit_of_work_domain.go
+type UnitOfWork interface {
+	// Do run fn in one transaction, repository call in fn must use ctx of fn to join the transaction.
+	// transaction is committed when fn return nil, otherwise aborted and error of fn is returned
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
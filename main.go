@@ -5,6 +5,7 @@ import (
 	"flag"
 	"log"
 	"net/http"
+	"os"
 	"os/signal"
 	"spider-go/api/route"
 	"spider-go/asset"
@@ -25,20 +26,38 @@ func main() {
 	// load config
 	config.LoadConfig("config", "config")
 	stage := flag.String("stage", "localhost", "set working environment")
+	flag.Parse()
 	mainLog.Infof("start service with ennvironmant %s", *stage)
 
 	// load asset
 	asset.LoadErrorCode("asset", "error")
 
+	// os.Exit skip deferred function, so failed command exit after connection is closed
+	exitCode := 0
+	defer func() {
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	}()
+
 	//connect db
 	mongoDB := database.NewMongoDB(&config.C().Mongo)
 	mongoDB.Connect()
 	mongoDB.SetDB()
 	defer mongoDB.Close()
 
+	// run admin command instead of server, e.g. `go-spider-be rebuild-statistics -apply`
+	if flag.NArg() > 0 {
+		if err := runCommand(flag.Arg(0), flag.Args()[1:]); err != nil {
+			mainLog.Errorf("command `%s` failed, error: %+v", flag.Arg(0), err)
+			exitCode = 1
+		}
+		return
+	}
+
 	// connect redis client
-	// database.NewRedisClient(&config.C().Redis)
-	// defer database.RedisClient.Close()
+	database.NewRedisClient(&config.C().Redis)
+	defer database.RedisClient.Close()
 
 	// call router
 	r := route.SetupRoutes(mainLog, config.C())
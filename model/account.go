@@ -1,11 +1,66 @@
 package model
 
+import "time"
+
 var (
 	ACCOUNT_ROLE_MASTER  = "master"
 	ACCOUNT_ROLE_ADMIN   = "admin"
 	ACCOUNT_ROLE_GENERAL = "general"
 )
 
+var (
+	ACCOUNT_STATUS_ACTIVE   = "active"
+	ACCOUNT_STATUS_DISABLED = "disabled"
+)
+
+// permission is action that route required, role have permission follow ROLE_PERMISSIONS
+var (
+	PERMISSION_SPIDER_READ    = "spider:read"
+	PERMISSION_SPIDER_CREATE  = "spider:create"
+	PERMISSION_SPIDER_WRITE   = "spider:write"
+	PERMISSION_SPIDER_DELETE  = "spider:delete"
+	PERMISSION_SPIDER_REVIEW  = "spider:review"
+	PERMISSION_IMAGE_WRITE    = "image:write"
+	PERMISSION_ACCOUNT_MANAGE = "account:manage"
+	PERMISSION_API_KEY_MANAGE = "api_key:manage"
+)
+
+// API_KEY_SCOPES is permission that can grant to api key, manage permission is for human account only
+var API_KEY_SCOPES = []string{
+	PERMISSION_SPIDER_READ,
+	PERMISSION_SPIDER_CREATE,
+	PERMISSION_SPIDER_WRITE,
+	PERMISSION_SPIDER_DELETE,
+	PERMISSION_SPIDER_REVIEW,
+	PERMISSION_IMAGE_WRITE,
+}
+
+var ROLE_PERMISSIONS = map[string][]string{
+	ACCOUNT_ROLE_MASTER: {
+		PERMISSION_SPIDER_READ,
+		PERMISSION_SPIDER_CREATE,
+		PERMISSION_SPIDER_WRITE,
+		PERMISSION_SPIDER_DELETE,
+		PERMISSION_SPIDER_REVIEW,
+		PERMISSION_IMAGE_WRITE,
+		PERMISSION_ACCOUNT_MANAGE,
+		PERMISSION_API_KEY_MANAGE,
+	},
+	ACCOUNT_ROLE_ADMIN: {
+		PERMISSION_SPIDER_READ,
+		PERMISSION_SPIDER_CREATE,
+		PERMISSION_SPIDER_WRITE,
+		PERMISSION_SPIDER_DELETE,
+		PERMISSION_SPIDER_REVIEW,
+		PERMISSION_IMAGE_WRITE,
+		PERMISSION_ACCOUNT_MANAGE,
+		PERMISSION_API_KEY_MANAGE,
+	},
+	ACCOUNT_ROLE_GENERAL: {
+		PERMISSION_SPIDER_CREATE,
+	},
+}
+
 type Account struct {
 	Username     string `json:"username" bson:"username"`
 	HashPassword string `json:"hash_password" bson:"hash_password"`
@@ -14,10 +69,101 @@ type Account struct {
 	LastName     string `json:"last_name" bson:"last_name"`
 	Age          int    `json:"age" bson:"age"`
 	MobileNO     string `json:"mobile_no" bson:"mobile_no"`
-	Role         string `json:"role" bson:"role"`
+	// email is used for deliver password reset token
+	Email  string `json:"email" bson:"email"`
+	Role   string `json:"role" bson:"role"`
+	Status string `json:"status" bson:"status"`
+	// second factor, nil when not enrolled
+	MFA       *AccountMFA `json:"mfa,omitempty" bson:"mfa,omitempty"`
+	CreatedAt time.Time   `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at" bson:"updated_at"`
+}
+
+// account that created before status field is active
+func (a *Account) IsDisabled() bool {
+	return a.Status == ACCOUNT_STATUS_DISABLED
+}
+
+func (a *Account) MFAEnabled() bool {
+	return a.MFA != nil && a.MFA.Enabled
+}
+
+// AccountMFA is TOTP second factor, secret is base32 and recovery code is kept as sha256
+type AccountMFA struct {
+	Enabled       bool     `json:"enabled" bson:"enabled"`
+	Secret        string   `json:"-" bson:"secret"`
+	RecoveryCodes []string `json:"-" bson:"recovery_codes"`
+	// time step of latest totp code that used, for reject replay code
+	LastUsedStep int64     `json:"-" bson:"last_used_step"`
+	EnabledAt    time.Time `json:"enabled_at" bson:"enabled_at"`
+}
+
+// MFAChallenge is result of login that password is correct but second factor is required
+type MFAChallenge struct {
+	Token string
+	// account of role that enforce second factor but not enrolled yet must scan enroll uri before verify
+	EnrollURI string
+	ExpiresAt time.Time
+}
+
+type AccountProfile struct {
+	Title     string `json:"title" bson:"title"`
+	FirstName string `json:"first_name" bson:"first_name"`
+	LastName  string `json:"last_name" bson:"last_name"`
+	Age       int    `json:"age" bson:"age"`
+	MobileNO  string `json:"mobile_no" bson:"mobile_no"`
+	Email     string `json:"email" bson:"email"`
+}
+
+type AccountListFilter struct {
+	Keyword string
+	Role    string
+	Status  string
+	Page    int32
+	Size    int32
+}
+
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
 }
 
+// LoginUser is principal of request, middleware set it to gin context after authenticate success
 type LoginUser struct {
-	Username string `json:"username" bson:"username"`
-	Role     string `json:"role" bson:"role"`
+	Username  string `json:"username" bson:"username"`
+	Role      string `json:"role" bson:"role"`
+	TokenID   string `json:"token_id" bson:"token_id"`
+	SessionID string `json:"session_id" bson:"session_id"`
+	IssuedAt  int64  `json:"issued_at" bson:"issued_at"` // unix millisecond
+	ExpiresAt int64  `json:"expires_at" bson:"expires_at"`
+	// APIKeyID is set when request authenticate by api key, permission come from Scopes instead of Role
+	APIKeyID string   `json:"api_key_id" bson:"api_key_id"`
+	Scopes   []string `json:"scopes" bson:"scopes"`
+}
+
+func (u *LoginUser) IsAPIKey() bool {
+	return u.APIKeyID != ""
+}
+
+func (u *LoginUser) HasRole(roles ...string) bool {
+	for _, role := range roles {
+		if u.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *LoginUser) HasPermission(permission string) bool {
+	permissions := ROLE_PERMISSIONS[u.Role]
+	if u.IsAPIKey() {
+		permissions = u.Scopes
+	}
+
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
 }
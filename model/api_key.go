@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// APIKey is credential of machine client, raw key is shown once when create and only hash is stored
+type APIKey struct {
+	KeyID      string    `json:"key_id" bson:"key_id"`
+	Name       string    `json:"name" bson:"name"`
+	KeyHash    string    `json:"key_hash" bson:"key_hash"`
+	Scopes     []string  `json:"scopes" bson:"scopes"`
+	CreatedBy  string    `json:"created_by" bson:"created_by"`
+	CreatedAt  time.Time `json:"created_at" bson:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at" bson:"expires_at"`
+	LastUsedAt time.Time `json:"last_used_at" bson:"last_used_at"`
+	Revoked    bool      `json:"revoked" bson:"revoked"`
+	RevokedBy  string    `json:"revoked_by" bson:"revoked_by"`
+	RevokedAt  time.Time `json:"revoked_at" bson:"revoked_at"`
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"io"
+	"time"
+)
+
+// size of image that is requested from image endpoint, original is full resolution image that is uploaded
+const (
+	IMAGE_SIZE_ORIGINAL  = "original"
+	IMAGE_SIZE_THUMBNAIL = "thumbnail"
+	IMAGE_SIZE_MEDIUM    = "medium"
+	IMAGE_SIZE_LARGE     = "large"
+)
+
+// IMAGE_DERIVATIVE_SIZES is size of resized image that is stored alongside original
+var IMAGE_DERIVATIVE_SIZES = []string{IMAGE_SIZE_THUMBNAIL, IMAGE_SIZE_MEDIUM, IMAGE_SIZE_LARGE}
+
+// ImageObject is metadata of image file in image storage
+type ImageObject struct {
+	Name        string
+	ContentType string
+	Size        int64
+	ModTime     time.Time
+	// ETag is quoted entity tag of image content, it is changed when image is replaced
+	ETag string
+}
+
+// OpenSpiderImageParam is image that is requested by url, expires and signature
+// are come from signed url of image that is not public
+type OpenSpiderImageParam struct {
+	SpiderUUID string
+	ImageName  string
+	// one of image size, original when empty
+	Size      string
+	Expires   string
+	Signature string
+}
+
+// SpiderImageFile is image that is served by image endpoint, caller must close content
+type SpiderImageFile struct {
+	Content io.ReadCloser
+	Object  ImageObject
+	Public  bool
+	// time that image can be cached, signed url image is cached until url expire
+	MaxAge time.Duration
+}
+
+// UploadImageFile is file part of multipart upload, content can be read only until next file is requested
+type UploadImageFile struct {
+	FileName string
+	Content  io.Reader
+}
+
+// UploadImageResult is result of one file of multipart upload
+type UploadImageResult struct {
+	FileName string
+	// name of saved image, empty when file is failed
+	ImageName string
+	Err       error
+}
+
+// ImageDerivativeBackfillReport is result of generate derivative of existing image
+type ImageDerivativeBackfillReport struct {
+	ImageCount int `json:"image_count"`
+	Generated  int `json:"generated"`
+	// image that already has every derivative
+	Skipped int                              `json:"skipped"`
+	Failed  []ImageDerivativeBackfillFailure `json:"failed"`
+}
+
+type ImageDerivativeBackfillFailure struct {
+	SpiderUUID string `json:"spider_uuid"`
+	ImageName  string `json:"image_name"`
+	Error      string `json:"error"`
+}
+
+// ImageExif is metadata of uploaded jpeg that is read from exif, field that is not recorded by camera is empty
+type ImageExif struct {
+	ImageName string `json:"image_name" bson:"image_name"`
+	// time that photo is taken
+	TakenAt *time.Time `json:"taken_at,omitempty" bson:"taken_at,omitempty"`
+	// decimal degrees, south and west are negative
+	Latitude  *float64 `json:"latitude,omitempty" bson:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty" bson:"longitude,omitempty"`
+	// meter from sea level, below sea level is negative
+	Altitude    *float64 `json:"altitude,omitempty" bson:"altitude,omitempty"`
+	CameraMake  string   `json:"camera_make,omitempty" bson:"camera_make,omitempty"`
+	CameraModel string   `json:"camera_model,omitempty" bson:"camera_model,omitempty"`
+}
+
+func (e ImageExif) HasPosition() bool {
+	return e.Latitude != nil && e.Longitude != nil
+}
+
+// SpiderPositionSuggestion is position and altitude of spider info that is suggested from exif of its image
+type SpiderPositionSuggestion struct {
+	// one position for each image that has gps
+	Position []Position
+	// altitude in format of spider info, e.g. `1000 m` or range `1000, 1750 m`, empty when no image has altitude
+	Altitude string
+	// earliest time that photo is taken, nil when no image has taken time
+	TakenAt *time.Time
+	// exif that suggestion is made from
+	Images []ImageExif
+}
@@ -0,0 +1,7 @@
+package model
+
+type Notification struct {
+	To      string
+	Subject string
+	Body    string
+}
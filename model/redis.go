@@ -7,8 +7,53 @@ type RedisRsaKey struct {
 	PublicKey  string `json:"public_key" bson:"public_key"`
 }
 
+// RedisRsaKeyUsed is marker of rsa key that already used, for tell replayed key from expired key
+type RedisRsaKeyUsed struct {
+	UsedAt time.Time `json:"used_at"`
+}
+
+// Login is refresh token session, one session family is created per login and
+// TokenHash is rotated every time refresh token is used
 type Login struct {
 	Username  string    `json:"username"`
 	Role      string    `json:"role"`
+	FamilyID  string    `json:"family_id"`
+	TokenHash string    `json:"token_hash"`
+	CreatedAt time.Time `json:"created_at"`
+	// hash of refresh token that already rotated out, one of them is used again only when token is stolen
+	RotatedTokenHashes []string `json:"rotated_token_hashes,omitempty"`
+}
+
+// RevokedToken is denylist of access token, key is jti and expire at exp of token
+type RevokedToken struct {
+	Username  string    `json:"username"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+// RevokedUser make every token and session of user that issued before RevokedAt invalid
+type RevokedUser struct {
+	Username  string    `json:"username"`
+	RevokedBy string    `json:"revoked_by"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+// PasswordReset is one-time token of forgot password, key is sha256 of token
+type PasswordReset struct {
+	Username  string    `json:"username"`
+	TokenHash string    `json:"token_hash"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MFAPending is login that wait for second factor, key is sha256 of mfa token,
+// EnrollSecret is set when account must enroll second factor at this login
+type MFAPending struct {
+	Username     string    `json:"username"`
+	EnrollSecret string    `json:"enroll_secret"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// MFAEnroll is secret that wait for activate by first code, key is username
+type MFAEnroll struct {
+	Secret    string    `json:"secret"`
 	CreatedAt time.Time `json:"created_at"`
 }
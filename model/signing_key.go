@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+var (
+	SIGNING_ALGORITHM_HS256 = "HS256"
+	SIGNING_ALGORITHM_RS256 = "RS256"
+	SIGNING_ALGORITHM_EDDSA = "EdDSA"
+)
+
+// SigningKey is asymmetric key for sign access token, only one key is active for signing
+// and retired key is kept for verify token until VerifyUntil
+type SigningKey struct {
+	KID        string `json:"kid" bson:"kid"`
+	Algorithm  string `json:"algorithm" bson:"algorithm"`
+	PrivateKey string `json:"private_key" bson:"private_key"`
+	PublicKey  string `json:"public_key" bson:"public_key"`
+	Active     bool   `json:"active" bson:"active"`
+	// verify until is set when key is retired, it is time that last token signed by this key expire
+	VerifyUntil time.Time `json:"verify_until" bson:"verify_until"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	RetiredAt   time.Time `json:"retired_at" bson:"retired_at"`
+}
+
+// JWKS is json web key set (RFC 7517) of public key for verify access token
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
@@ -9,6 +9,15 @@ import (
 var (
 	SPIDER_INFO_STATUS_ACTIVE   = "active"
 	SPIDER_INFO_STATUS_INACTIVE = "inactive"
+	// spider info in trash, it is hidden from every listing and purged after retention period
+	SPIDER_INFO_STATUS_DELETED = "deleted"
+
+	// editorial workflow, draft -> submitted -> published or rejected,
+	// published record use status active so record that created before workflow stay public
+	SPIDER_INFO_STATUS_DRAFT     = "draft"
+	SPIDER_INFO_STATUS_SUBMITTED = "submitted"
+	SPIDER_INFO_STATUS_REJECTED  = "rejected"
+	SPIDER_INFO_STATUS_PUBLISHED = SPIDER_INFO_STATUS_ACTIVE
 )
 
 type SpiderInfo struct {
@@ -33,6 +42,32 @@ type SpiderInfo struct {
 	UpdatedAt    time.Time          `json:"updated_at,omitempty" bson:"updated_at"`
 	ImageFile    []string           `json:"image_file" bson:"image_file,omitempty"`
 	CreatedBy    string             `json:"created_by" bson:"created_by,omitempty"`
+	// exif of uploaded jpeg, image without exif has no entry
+	ImageExif []ImageExif `json:"image_exif,omitempty" bson:"image_exif,omitempty"`
+	// set when spider info is moved to trash, status before delete is used on restore
+	DeletedBy          string     `json:"deleted_by,omitempty" bson:"deleted_by,omitempty"`
+	DeletedAt          *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	StatusBeforeDelete string     `json:"status_before_delete,omitempty" bson:"status_before_delete,omitempty"`
+	// latest review of editorial workflow, nil until submitted record is reviewed
+	Review *SpiderReview `json:"review,omitempty" bson:"review,omitempty"`
+	// Version is increased on every write, editor send version that is read so concurrent edit is detected,
+	// record that created before versioning has no field and is treated as version 0
+	Version int64 `json:"version" bson:"version"`
+}
+
+type SpiderReview struct {
+	Comment    string    `json:"comment" bson:"comment"`
+	ReviewedBy string    `json:"reviewed_by" bson:"reviewed_by"`
+	ReviewedAt time.Time `json:"reviewed_at" bson:"reviewed_at"`
+}
+
+func (s SpiderInfo) IsDeleted() bool {
+	return s.Status == SPIDER_INFO_STATUS_DELETED
+}
+
+// IsPublic is true when spider info and its image can be read without login
+func (s SpiderInfo) IsPublic() bool {
+	return s.Status == SPIDER_INFO_STATUS_PUBLISHED
 }
 
 type Address struct {
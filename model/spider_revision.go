@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	SPIDER_REVISION_ACTION_CREATE  = "create"
+	SPIDER_REVISION_ACTION_UPDATE  = "update"
+	SPIDER_REVISION_ACTION_DELETE  = "delete"
+	SPIDER_REVISION_ACTION_RESTORE = "restore"
+)
+
+// SpiderRevision is immutable full snapshot of spider info after each change,
+// snapshot of delete revision is the last state before the record was deleted
+type SpiderRevision struct {
+	ID           primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	SpiderUUID   string             `json:"spider_uuid" bson:"spider_uuid"`
+	Revision     int64              `json:"revision" bson:"revision"`
+	Action       string             `json:"action" bson:"action"`
+	Snapshot     SpiderInfo         `json:"snapshot" bson:"snapshot"`
+	RestoredFrom int64              `json:"restored_from,omitempty" bson:"restored_from,omitempty"`
+	CreatedBy    string             `json:"created_by" bson:"created_by"`
+	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// SpiderRevisionDiff is value of one field of spider info that differ between two revisions
+type SpiderRevisionDiff struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
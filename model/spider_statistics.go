@@ -2,24 +2,66 @@ package model
 
 import "time"
 
+// SpiderStatistics is family -> genus -> species tree of published spider info,
+// record count of every node is number of spider info under that node
 type SpiderStatistics struct {
-	FamilyName string       `json:"family_name" bson:"family_name"`
-	Genus      []GenusGroup `json:"genus" bson:"genus"`
-	CreatedAt  time.Time    `json:"created_at" bson:"created_at"`
-	UpdatedAt  time.Time    `json:"updated_at" bson:"updated_at"`
+	FamilyName  string       `json:"family_name" bson:"family_name"`
+	Genus       []GenusGroup `json:"genus" bson:"genus"`
+	RecordCount int64        `json:"record_count" bson:"record_count"`
+	CreatedAt   time.Time    `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at" bson:"updated_at"`
 }
 
 type GenusGroup struct {
-	GenusName string         `json:"genus_name" bson:"genus_name"`
-	Species   []SpeciesGroup `json:"species" bson:"species"`
+	GenusName   string         `json:"genus_name" bson:"genus_name"`
+	Species     []SpeciesGroup `json:"species" bson:"species"`
+	RecordCount int64          `json:"record_count" bson:"record_count"`
 }
 
 type SpeciesGroup struct {
 	SpeciesName string `json:"species_name" bson:"species_name"`
+	RecordCount int64  `json:"record_count" bson:"record_count"`
 }
 
+// family list is sorted by family name or by record count, most recorded family first
+const (
+	FAMILY_LIST_SORT_NAME  = "name"
+	FAMILY_LIST_SORT_COUNT = "count"
+)
+
+// FamilyList is summary of published spider info of one family, author is author of first recorded spider info
 type FamilyList struct {
-	Family   string `json:"family"`
-	Author   string `json:"author"`
-	Quantity int32  `json:"quantity"`
+	Family       string `json:"family" bson:"family"`
+	Author       string `json:"author" bson:"author"`
+	Quantity     int32  `json:"quantity" bson:"quantity"`
+	GenusCount   int32  `json:"genus_count" bson:"genus_count"`
+	SpeciesCount int32  `json:"species_count" bson:"species_count"`
+}
+
+// ==================================================
+// statistics rebuild
+// ==================================================
+const (
+	SPIDER_STATISTICS_CHANGE_ADDED   = "added"
+	SPIDER_STATISTICS_CHANGE_REMOVED = "removed"
+	SPIDER_STATISTICS_CHANGE_CHANGED = "changed"
+)
+
+// SpiderStatisticsChange is one node of statistics tree that rebuild add, remove or recount,
+// genus and species name are empty when node is family or genus
+type SpiderStatisticsChange struct {
+	FamilyName  string `json:"family_name"`
+	GenusName   string `json:"genus_name,omitempty"`
+	SpeciesName string `json:"species_name,omitempty"`
+	Action      string `json:"action"`
+	Before      int64  `json:"before"`
+	After       int64  `json:"after"`
+}
+
+// SpiderStatisticsRebuildReport is result of rebuild, statistics is not changed when it is dry run
+type SpiderStatisticsRebuildReport struct {
+	DryRun      bool                     `json:"dry_run"`
+	FamilyCount int                      `json:"family_count"`
+	RecordCount int64                    `json:"record_count"`
+	Changes     []SpiderStatisticsChange `json:"changes"`
 }
@@ -0,0 +1,45 @@
+package model
+
+// SPIDER_WORKFLOW_TRANSITIONS is permission that required for move spider info from status to next status,
+// contributor with create permission can submit only own record
+var SPIDER_WORKFLOW_TRANSITIONS = map[string]map[string]string{
+	SPIDER_INFO_STATUS_DRAFT: {
+		SPIDER_INFO_STATUS_SUBMITTED: PERMISSION_SPIDER_CREATE,
+	},
+	// record that hidden before workflow is treated as draft
+	SPIDER_INFO_STATUS_INACTIVE: {
+		SPIDER_INFO_STATUS_SUBMITTED: PERMISSION_SPIDER_CREATE,
+	},
+	SPIDER_INFO_STATUS_REJECTED: {
+		SPIDER_INFO_STATUS_SUBMITTED: PERMISSION_SPIDER_CREATE,
+	},
+	SPIDER_INFO_STATUS_SUBMITTED: {
+		SPIDER_INFO_STATUS_PUBLISHED: PERMISSION_SPIDER_REVIEW,
+		SPIDER_INFO_STATUS_REJECTED:  PERMISSION_SPIDER_REVIEW,
+	},
+}
+
+// SpiderWorkflowPermission return permission of transition, ok is false when transition is not allowed
+func SpiderWorkflowPermission(from, to string) (permission string, ok bool) {
+	permission, ok = SPIDER_WORKFLOW_TRANSITIONS[from][to]
+	return permission, ok
+}
+
+// CanEditSpiderInfo is true when user can edit spider info, user with write permission can edit any record
+// and contributor can edit own record until it is submitted or after it is rejected
+func CanEditSpiderInfo(spiderInfo SpiderInfo, loginUser *LoginUser) bool {
+	if loginUser.HasPermission(PERMISSION_SPIDER_WRITE) {
+		return true
+	}
+
+	if !loginUser.HasPermission(PERMISSION_SPIDER_CREATE) || spiderInfo.CreatedBy != loginUser.Username {
+		return false
+	}
+
+	switch spiderInfo.Status {
+	case SPIDER_INFO_STATUS_DRAFT, SPIDER_INFO_STATUS_REJECTED, SPIDER_INFO_STATUS_INACTIVE:
+		return true
+	default:
+		return false
+	}
+}
@@ -2,12 +2,15 @@ package repository
 
 import (
 	"context"
+	"regexp"
 	"spider-go/domain"
 	"spider-go/logger"
 	"spider-go/model"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type Account struct {
@@ -58,3 +61,175 @@ func (r *Account) FindAccountByUsername(ctx context.Context, username string) (A
 
 	return &accountInfo, nil
 }
+
+func (r *Account) FindAccountList(ctx context.Context, filter model.AccountListFilter) ([]model.Account, int64, error) {
+	log := r.log.WithContext(ctx)
+
+	coll := r.database.Collection(r.collectionName)
+
+	selector := bson.M{}
+
+	if filter.Keyword != "" {
+		keyword := bson.M{
+			"$regex":   regexp.QuoteMeta(filter.Keyword),
+			"$options": "i",
+		}
+		selector["$or"] = bson.A{
+			bson.M{"username": keyword},
+			bson.M{"first_name": keyword},
+			bson.M{"last_name": keyword},
+		}
+	}
+
+	if filter.Role != "" {
+		selector["role"] = filter.Role
+	}
+
+	// account that created before status field is active
+	switch filter.Status {
+	case model.ACCOUNT_STATUS_DISABLED:
+		selector["status"] = model.ACCOUNT_STATUS_DISABLED
+	case model.ACCOUNT_STATUS_ACTIVE:
+		selector["status"] = bson.M{"$ne": model.ACCOUNT_STATUS_DISABLED}
+	}
+
+	total, err := coll.CountDocuments(ctx, selector)
+	if err != nil {
+		log.Errorf("[FindAccountList] count account with selector %+v error: %+v", selector, err)
+		return nil, 0, err
+	}
+
+	opts := options.Find()
+
+	opts.SetSort(bson.M{
+		"username": 1,
+	})
+	opts.SetSkip(int64(filter.Page * filter.Size))
+	opts.SetLimit(int64(filter.Size))
+
+	// never return hash password and secret of second factor
+	opts.SetProjection(bson.M{
+		"hash_password":      0,
+		"mfa.secret":         0,
+		"mfa.recovery_codes": 0,
+	})
+
+	cursor, err := coll.Find(ctx, selector, opts)
+	if err != nil {
+		log.Errorf("[FindAccountList] find account with selector %+v error: %+v", selector, err)
+		return nil, 0, err
+	}
+
+	var accountList []model.Account
+
+	if err := cursor.All(ctx, &accountList); err != nil {
+		log.Errorf("[FindAccountList] decode account list error: %+v", err)
+		return nil, 0, err
+	}
+
+	return accountList, total, nil
+}
+
+func (r *Account) UpdateAccountStatus(ctx context.Context, username, status string) error {
+	return r.updateAccount(ctx, username, bson.M{
+		"status": status,
+	})
+}
+
+func (r *Account) UpdateAccountRole(ctx context.Context, username, role string) error {
+	return r.updateAccount(ctx, username, bson.M{
+		"role": role,
+	})
+}
+
+func (r *Account) UpdateAccountProfile(ctx context.Context, username string, profile model.AccountProfile) error {
+	return r.updateAccount(ctx, username, bson.M{
+		"title":      profile.Title,
+		"first_name": profile.FirstName,
+		"last_name":  profile.LastName,
+		"age":        profile.Age,
+		"mobile_no":  profile.MobileNO,
+		"email":      profile.Email,
+	})
+}
+
+func (r *Account) UpdateAccountPassword(ctx context.Context, username, hashPassword string) error {
+	return r.updateAccount(ctx, username, bson.M{
+		"hash_password": hashPassword,
+	})
+}
+
+// UpdateAccountMFA replace second factor of account, nil remove second factor
+func (r *Account) UpdateAccountMFA(ctx context.Context, username string, mfa *model.AccountMFA) error {
+	if mfa != nil {
+		return r.updateAccount(ctx, username, bson.M{
+			"mfa": mfa,
+		})
+	}
+
+	return r.updateOne(ctx, bson.M{"username": username}, bson.M{
+		"$set":   bson.M{"updated_at": time.Now()},
+		"$unset": bson.M{"mfa": ""},
+	})
+}
+
+// UpdateMFALastUsedStep save time step of totp code that used, code of the same or older step is replay
+// and return ErrorMongoNotFound
+func (r *Account) UpdateMFALastUsedStep(ctx context.Context, username string, step int64) error {
+	selector := bson.M{
+		"username":           username,
+		"mfa.enabled":        true,
+		"mfa.last_used_step": bson.M{"$lt": step},
+	}
+
+	return r.updateOne(ctx, selector, bson.M{
+		"$set": bson.M{"mfa.last_used_step": step},
+	})
+}
+
+// UseMFARecoveryCode remove recovery code after use, code that not exist return ErrorMongoNotFound
+func (r *Account) UseMFARecoveryCode(ctx context.Context, username, codeHash string) error {
+	selector := bson.M{
+		"username":           username,
+		"mfa.enabled":        true,
+		"mfa.recovery_codes": codeHash,
+	}
+
+	return r.updateOne(ctx, selector, bson.M{
+		"$pull": bson.M{"mfa.recovery_codes": codeHash},
+		"$set":  bson.M{"updated_at": time.Now()},
+	})
+}
+
+func (r *Account) updateAccount(ctx context.Context, username string, fields bson.M) error {
+	fields["updated_at"] = time.Now()
+
+	selector := bson.M{
+		"username": username,
+	}
+
+	updater := bson.M{
+		"$set": fields,
+	}
+
+	return r.updateOne(ctx, selector, updater)
+}
+
+func (r *Account) updateOne(ctx context.Context, selector, updater bson.M) error {
+	log := r.log.WithContext(ctx)
+
+	coll := r.database.Collection(r.collectionName)
+
+	result, err := coll.UpdateOne(ctx, selector, updater)
+	if err != nil {
+		log.Errorf("[updateOne] update account with selector %+v error: %+v", selector, err)
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		log.Warnf("[updateOne] account with selector %+v not found", selector)
+		return ErrorMongoNotFound
+	}
+
+	return nil
+}
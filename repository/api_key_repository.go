@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"spider-go/domain"
+	"spider-go/logger"
+	"spider-go/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type APIKeyRepository struct {
+	database       *mongo.Database
+	log            *logger.Logger
+	collectionName string
+}
+
+func NewAPIKeyRepository(db *mongo.Database) domain.APIKeyRepository {
+	return &APIKeyRepository{
+		database:       db,
+		log:            logger.L().Named("APIKeyRepository"),
+		collectionName: "api_key",
+	}
+}
+
+func (r *APIKeyRepository) InsertAPIKey(ctx context.Context, apiKey model.APIKey) error {
+	log := r.log.WithContext(ctx)
+
+	coll := r.database.Collection(r.collectionName)
+
+	if _, err := coll.InsertOne(ctx, apiKey); err != nil {
+		log.Errorf("[InsertAPIKey] insert api key `%v` error: %+v", apiKey.KeyID, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepository) FindAPIKeyByKeyID(ctx context.Context, keyID string) (*model.APIKey, error) {
+	coll := r.database.Collection(r.collectionName)
+
+	selector := bson.M{
+		"key_id": keyID,
+	}
+
+	var apiKey model.APIKey
+
+	if err := coll.FindOne(ctx, selector).Decode(&apiKey); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrorMongoNotFound
+		}
+		return nil, err
+	}
+
+	return &apiKey, nil
+}
+
+func (r *APIKeyRepository) FindAPIKeyList(ctx context.Context, page, size int32) ([]model.APIKey, int64, error) {
+	log := r.log.WithContext(ctx)
+
+	coll := r.database.Collection(r.collectionName)
+
+	selector := bson.M{}
+
+	total, err := coll.CountDocuments(ctx, selector)
+	if err != nil {
+		log.Errorf("[FindAPIKeyList] count api key error: %+v", err)
+		return nil, 0, err
+	}
+
+	opts := options.Find()
+
+	opts.SetSort(bson.M{
+		"created_at": -1,
+	})
+	opts.SetSkip(int64(page * size))
+	opts.SetLimit(int64(size))
+
+	// never return hash of key
+	opts.SetProjection(bson.M{
+		"key_hash": 0,
+	})
+
+	cursor, err := coll.Find(ctx, selector, opts)
+	if err != nil {
+		log.Errorf("[FindAPIKeyList] find api key error: %+v", err)
+		return nil, 0, err
+	}
+
+	var apiKeyList []model.APIKey
+
+	if err := cursor.All(ctx, &apiKeyList); err != nil {
+		log.Errorf("[FindAPIKeyList] decode api key list error: %+v", err)
+		return nil, 0, err
+	}
+
+	return apiKeyList, total, nil
+}
+
+func (r *APIKeyRepository) RevokeAPIKey(ctx context.Context, keyID, revokedBy string) error {
+	log := r.log.WithContext(ctx)
+
+	coll := r.database.Collection(r.collectionName)
+
+	selector := bson.M{
+		"key_id": keyID,
+	}
+
+	updater := bson.M{
+		"$set": bson.M{
+			"revoked":    true,
+			"revoked_by": revokedBy,
+			"revoked_at": time.Now(),
+		},
+	}
+
+	result, err := coll.UpdateOne(ctx, selector, updater)
+	if err != nil {
+		log.Errorf("[RevokeAPIKey] revoke api key `%v` error: %+v", keyID, err)
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrorMongoNotFound
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepository) UpdateAPIKeyLastUsed(ctx context.Context, keyID string, usedAt time.Time) error {
+	log := r.log.WithContext(ctx)
+
+	coll := r.database.Collection(r.collectionName)
+
+	selector := bson.M{
+		"key_id": keyID,
+	}
+
+	updater := bson.M{
+		"$set": bson.M{
+			"last_used_at": usedAt,
+		},
+	}
+
+	if _, err := coll.UpdateOne(ctx, selector, updater); err != nil {
+		log.Errorf("[UpdateAPIKeyLastUsed] update last used of api key `%v` error: %+v", keyID, err)
+		return err
+	}
+
+	return nil
+}
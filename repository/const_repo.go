@@ -5,3 +5,4 @@ import "fmt"
 // error
 var ErrorMongoNotFound = fmt.Errorf("mongo not found")
 var ErrorRedisNotFound = fmt.Errorf("redis not found")
+var ErrorRedisConflict = fmt.Errorf("redis data is changed")
@@ -45,3 +45,85 @@ func (r *RedisRepository) GetDataFromRedis(ctx context.Context, key string, data
 	json.Unmarshal(result, data)
 	return nil
 }
+
+func (r *RedisRepository) DeleteDataFromRedis(ctx context.Context, key string) (err error) {
+	return r.client.Del(ctx, key).Err()
+}
+
+// GetAndDeleteDataFromRedis get data and delete key in one command, data can be read only once
+func (r *RedisRepository) GetAndDeleteDataFromRedis(ctx context.Context, key string, data interface{}) (err error) {
+
+	result, err := r.client.GetDel(ctx, key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return ErrorRedisNotFound
+		}
+		return err
+	}
+
+	return json.Unmarshal(result, data)
+}
+
+// compareAndSetScript set new data only when string field of current json data equal expected value
+var compareAndSetScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if not current then
+	return 0
+end
+if cjson.decode(current)[ARGV[1]] ~= ARGV[2] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[3], 'PX', ARGV[4])
+return 1
+`)
+
+// CompareAndSetDataToRedis replace data only when field of current data is still expected value,
+// compare and set run in one script so concurrent writer can not both succeed,
+// ErrorRedisConflict is returned when key not found or field is changed
+func (r *RedisRepository) CompareAndSetDataToRedis(ctx context.Context, key, field, expected string, data interface{}, ttl time.Duration) (err error) {
+
+	prepareJsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	// ttl less than one millisecond is rejected by redis
+	ttlMilli := ttl.Milliseconds()
+	if ttlMilli < 1 {
+		ttlMilli = 1
+	}
+
+	swapped, err := compareAndSetScript.Run(ctx, r.client, []string{key}, field, expected, prepareJsonData, ttlMilli).Int()
+	if err != nil {
+		return err
+	}
+	if swapped == 0 {
+		return ErrorRedisConflict
+	}
+
+	return nil
+}
+
+// IncreaseCounter increase counter by one and set ttl of counter in the same transaction
+func (r *RedisRepository) IncreaseCounter(ctx context.Context, key string, ttl time.Duration) (count int64, err error) {
+	pipe := r.client.TxPipeline()
+
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, ttl)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
+
+// GetCounter return zero when counter not found
+func (r *RedisRepository) GetCounter(ctx context.Context, key string) (count int64, err error) {
+	count, err = r.client.Get(ctx, key).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+
+	return count, err
+}
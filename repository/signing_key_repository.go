@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"spider-go/domain"
+	"spider-go/logger"
+	"spider-go/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SigningKeyRepository struct {
+	database       *mongo.Database
+	log            *logger.Logger
+	collectionName string
+}
+
+func NewSigningKeyRepository(db *mongo.Database) domain.SigningKeyRepository {
+	return &SigningKeyRepository{
+		database:       db,
+		log:            logger.L().Named("SigningKeyRepository"),
+		collectionName: "jwt_signing_key",
+	}
+}
+
+func (r *SigningKeyRepository) InsertSigningKey(ctx context.Context, key model.SigningKey) error {
+	log := r.log.WithContext(ctx)
+
+	coll := r.database.Collection(r.collectionName)
+
+	if _, err := coll.InsertOne(ctx, key); err != nil {
+		log.Errorf("[InsertSigningKey] insert signing key `%v` error: %+v", key.KID, err)
+		return err
+	}
+
+	return nil
+}
+
+// FindVerifiableSigningKeys find active key and retired key that token signed by it is not expire, newest first
+func (r *SigningKeyRepository) FindVerifiableSigningKeys(ctx context.Context, now time.Time) ([]model.SigningKey, error) {
+	log := r.log.WithContext(ctx)
+
+	coll := r.database.Collection(r.collectionName)
+
+	selector := bson.M{
+		"$or": bson.A{
+			bson.M{"active": true},
+			bson.M{"verify_until": bson.M{"$gt": now}},
+		},
+	}
+
+	opts := options.Find()
+	opts.SetSort(bson.M{
+		"created_at": -1,
+	})
+
+	cursor, err := coll.Find(ctx, selector, opts)
+	if err != nil {
+		log.Errorf("[FindVerifiableSigningKeys] find signing key error: %+v", err)
+		return nil, err
+	}
+
+	var keys []model.SigningKey
+
+	if err := cursor.All(ctx, &keys); err != nil {
+		log.Errorf("[FindVerifiableSigningKeys] decode signing key error: %+v", err)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// RetireSigningKeys make every active key except exceptKID retired, retired key is used for verify only
+func (r *SigningKeyRepository) RetireSigningKeys(ctx context.Context, exceptKID string, verifyUntil time.Time) error {
+	log := r.log.WithContext(ctx)
+
+	coll := r.database.Collection(r.collectionName)
+
+	selector := bson.M{
+		"active": true,
+		"kid":    bson.M{"$ne": exceptKID},
+	}
+
+	updater := bson.M{
+		"$set": bson.M{
+			"active":       false,
+			"verify_until": verifyUntil,
+			"retired_at":   time.Now(),
+		},
+	}
+
+	result, err := coll.UpdateMany(ctx, selector, updater)
+	if err != nil {
+		log.Errorf("[RetireSigningKeys] retire signing key error: %+v", err)
+		return err
+	}
+
+	log.Infof("[RetireSigningKeys] retired %v signing key", result.ModifiedCount)
+
+	return nil
+}
+
+func (r *SigningKeyRepository) DeleteExpiredSigningKeys(ctx context.Context, now time.Time) error {
+	log := r.log.WithContext(ctx)
+
+	coll := r.database.Collection(r.collectionName)
+
+	selector := bson.M{
+		"active":       false,
+		"verify_until": bson.M{"$lte": now},
+	}
+
+	if _, err := coll.DeleteMany(ctx, selector); err != nil {
+		log.Errorf("[DeleteExpiredSigningKeys] delete signing key error: %+v", err)
+		return err
+	}
+
+	return nil
+}
@@ -62,6 +62,9 @@ func (r *SpiderRepository) FindSpiderByUUID(ctx context.Context, spiderUUID stri
 	var resultSpiderInfo model.SpiderInfo
 
 	if err := coll.FindOne(ctx, selector).Decode(&resultSpiderInfo); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrorMongoNotFound
+		}
 		return nil, err
 	}
 
@@ -79,11 +82,26 @@ func (r *SpiderRepository) UpdateImageFileToSpiderInfo(ctx context.Context, file
 		"spider_uuid": spiderUUID,
 	}
 
+	// $nin require array, nil is encoded as null
+	keepImageExif := filesName
+	if keepImageExif == nil {
+		keepImageExif = []string{}
+	}
+
 	updater := bson.M{
 		"$set": bson.M{
 			"image_file": filesName,
 			"updated_at": tn,
 		},
+		// exif of removed image is removed with it
+		"$pull": bson.M{
+			"image_exif": bson.M{
+				"image_name": bson.M{"$nin": keepImageExif},
+			},
+		},
+		"$inc": bson.M{
+			"version": 1,
+		},
 	}
 
 	result, err := coll.UpdateOne(ctx, selector, updater)
@@ -96,6 +114,39 @@ func (r *SpiderRepository) UpdateImageFileToSpiderInfo(ctx context.Context, file
 	return nil
 }
 
+// PushSpiderImageExif add exif of uploaded image, version is increased so update that read spider info before exif is added
+// fail with version conflict instead of overwrite image exif with old list
+func (r *SpiderRepository) PushSpiderImageExif(ctx context.Context, spiderUUID string, imageExif []model.ImageExif) error {
+	log := r.log.WithContext(ctx)
+
+	coll := r.database.Collection(r.collectionName)
+
+	selector := bson.M{
+		"spider_uuid": spiderUUID,
+	}
+
+	updater := bson.M{
+		"$push": bson.M{
+			"image_exif": bson.M{"$each": imageExif},
+		},
+		"$inc": bson.M{
+			"version": 1,
+		},
+	}
+
+	result, err := coll.UpdateOne(ctx, selector, updater)
+	if err != nil {
+		log.Errorf("[PushSpiderImageExif] update mongo failed, error: %v", err)
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrorMongoNotFound
+	}
+
+	return nil
+}
+
 func (r *SpiderRepository) FindSpiderByUUIDAndStatus(ctx context.Context, spiderUUID string, isStatusActive bool) (*model.SpiderInfo, error) {
 
 	log := r.log.WithContext(ctx)
@@ -106,13 +157,15 @@ func (r *SpiderRepository) FindSpiderByUUIDAndStatus(ctx context.Context, spider
 
 	if isStatusActive {
 		selector["status"] = model.SPIDER_INFO_STATUS_ACTIVE
+	} else {
+		excludeDeletedSpider(selector)
 	}
 
 	log.Infof("[find spider by uuid and status] find spider status active `%v` with selector: %v", isStatusActive, selector)
 
 	var resultSpiderInfo model.SpiderInfo
 
-	if err := coll.FindOne(ctx, bson.M{"spider_uuid": spiderUUID}).Decode(&resultSpiderInfo); err != nil {
+	if err := coll.FindOne(ctx, selector).Decode(&resultSpiderInfo); err != nil {
 		log.Errorf("[find spider by uuid and status] error find one in mongo, error: %v", err)
 		if err == mongo.ErrNoDocuments {
 			return nil, ErrorMongoNotFound
@@ -123,10 +176,13 @@ func (r *SpiderRepository) FindSpiderByUUIDAndStatus(ctx context.Context, spider
 	return &resultSpiderInfo, nil
 }
 
+// findManySpiderWithCondition never return spider info in trash unless filter has condition of status
 func (r *SpiderRepository) findManySpiderWithCondition(ctx context.Context, filter bson.M, opts *options.FindOptions) (*mongo.Cursor, error) {
 
 	coll := r.database.Collection(r.collectionName)
 
+	filter = excludeDeletedSpider(filter)
+
 	cursor, err := coll.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
@@ -186,42 +242,228 @@ func (r *SpiderRepository) FindAllSpiderListManager(ctx context.Context, page, l
 	return spiderInfoList, nil
 }
 
-func (r *SpiderRepository) DeleteSpiderInfoWithSpiderUUID(ctx context.Context, spiderUUID string) error {
+func (r *SpiderRepository) MoveSpiderInfoToTrash(ctx context.Context, spiderUUID, previousStatus, deletedBy string, deletedAt time.Time) error {
 	log := r.log.WithContext(ctx)
 
-	log.Infof("[DeleteSpiderInfoWithSpiderUUID] spider_uuid: %v", spiderUUID)
+	log.Infof("[MoveSpiderInfoToTrash] spider_uuid: %v", spiderUUID)
 
+	// status in selector prevent concurrent change of status between find and delete
 	selector := bson.M{
 		"spider_uuid": spiderUUID,
+		"status":      previousStatus,
+	}
+
+	updater := bson.M{
+		"$set": bson.M{
+			"status":               model.SPIDER_INFO_STATUS_DELETED,
+			"status_before_delete": previousStatus,
+			"deleted_by":           deletedBy,
+			"deleted_at":           deletedAt,
+			"updated_at":           deletedAt,
+		},
+		"$inc": bson.M{
+			"version": 1,
+		},
 	}
 
 	coll := r.database.Collection(r.collectionName)
 
-	result, err := coll.DeleteOne(ctx, selector)
+	result, err := coll.UpdateOne(ctx, selector, updater)
 	if err != nil {
-		log.Errorf("[DeleteSpiderInfoWithSpiderUUID] delete one at spider_uuid `%v` failed, result: %+v, error: %+v", spiderUUID, result, err)
+		log.Errorf("[MoveSpiderInfoToTrash] move spider_uuid `%v` to trash failed, error: %+v", spiderUUID, err)
 		return err
 	}
 
-	if result.DeletedCount == 0 {
-		log.Errorf("[DeleteSpiderInfoWithSpiderUUID] delete spider result is `%v`", result.DeletedCount)
-		return ErrorSpiderRepositoryDeleteSpiderIsZero
+	if result.MatchedCount == 0 {
+		return ErrorMongoNotFound
+	}
+
+	return nil
+}
+
+func (r *SpiderRepository) RestoreSpiderInfoFromTrash(ctx context.Context, spiderUUID, status string) error {
+	log := r.log.WithContext(ctx)
+
+	log.Infof("[RestoreSpiderInfoFromTrash] spider_uuid: %v", spiderUUID)
+
+	selector := bson.M{
+		"spider_uuid": spiderUUID,
+		"status":      model.SPIDER_INFO_STATUS_DELETED,
+	}
+
+	updater := bson.M{
+		"$set": bson.M{
+			"status":     status,
+			"updated_at": time.Now(),
+		},
+		"$unset": bson.M{
+			"status_before_delete": "",
+			"deleted_by":           "",
+			"deleted_at":           "",
+		},
+		"$inc": bson.M{
+			"version": 1,
+		},
+	}
+
+	coll := r.database.Collection(r.collectionName)
+
+	result, err := coll.UpdateOne(ctx, selector, updater)
+	if err != nil {
+		log.Errorf("[RestoreSpiderInfoFromTrash] restore spider_uuid `%v` failed, error: %+v", spiderUUID, err)
+		return err
 	}
 
-	log.Infof("[DeleteSpiderInfoWithSpiderUUID] delete one at spider_uuid `%v` successfull, result: %+v", spiderUUID, result)
+	if result.MatchedCount == 0 {
+		return ErrorMongoNotFound
+	}
 
 	return nil
 }
 
-func (r *SpiderRepository) UpdateSpiderInfo(ctx context.Context, spiderUUID string, spiderInfo model.SpiderInfo) (bool, error) {
+func (r *SpiderRepository) FindSpiderTrashList(ctx context.Context, page, size int32) ([]model.SpiderInfo, int64, error) {
 	log := r.log.WithContext(ctx)
 
-	log.Infof("[UpdateSpiderInfo] update spider where spider_uuid: %v", spiderUUID)
+	coll := r.database.Collection(r.collectionName)
+
+	selector := bson.M{
+		"status": model.SPIDER_INFO_STATUS_DELETED,
+	}
+
+	total, err := coll.CountDocuments(ctx, selector)
+	if err != nil {
+		log.Errorf("[FindSpiderTrashList] count spider info in trash error: %+v", err)
+		return nil, 0, err
+	}
+
+	opts := options.Find()
+
+	opts.SetSort(bson.M{
+		"deleted_at": -1,
+	})
+	opts.SetSkip(int64(page * size))
+	opts.SetLimit(int64(size))
+
+	cursor, err := r.findManySpiderWithCondition(ctx, selector, opts)
+	if err != nil {
+		log.Errorf("[FindSpiderTrashList] find spider info in trash error: %+v", err)
+		return nil, 0, err
+	}
+
+	var spiderInfoList []model.SpiderInfo
+
+	if err := cursor.All(ctx, &spiderInfoList); err != nil {
+		log.Errorf("[FindSpiderTrashList] get spider info data from cursor error: %+v", err)
+		return nil, 0, err
+	}
+
+	return spiderInfoList, total, nil
+}
+
+func (r *SpiderRepository) FindExpiredSpiderTrash(ctx context.Context, deletedBefore time.Time) ([]model.SpiderInfo, error) {
+	log := r.log.WithContext(ctx)
+
+	selector := bson.M{
+		"status": model.SPIDER_INFO_STATUS_DELETED,
+		"deleted_at": bson.M{
+			"$lt": deletedBefore,
+		},
+	}
+
+	cursor, err := r.findManySpiderWithCondition(ctx, selector, nil)
+	if err != nil {
+		log.Errorf("[FindExpiredSpiderTrash] find spider info deleted before `%v` error: %+v", deletedBefore, err)
+		return nil, err
+	}
+
+	var spiderInfoList []model.SpiderInfo
+
+	if err := cursor.All(ctx, &spiderInfoList); err != nil {
+		log.Errorf("[FindExpiredSpiderTrash] get spider info data from cursor error: %+v", err)
+		return nil, err
+	}
+
+	return spiderInfoList, nil
+}
+
+// FindSpiderImageFileList return uuid and image file of spider info that has image, spider info in trash is included
+// because it can be restored
+func (r *SpiderRepository) FindSpiderImageFileList(ctx context.Context) ([]model.SpiderInfo, error) {
+	log := r.log.WithContext(ctx)
+
+	coll := r.database.Collection(r.collectionName)
+
+	selector := bson.M{
+		"image_file.0": bson.M{
+			"$exists": true,
+		},
+	}
+
+	opts := options.Find()
+	opts.SetProjection(bson.M{
+		"spider_uuid": 1,
+		"image_file":  1,
+	})
+
+	cursor, err := coll.Find(ctx, selector, opts)
+	if err != nil {
+		log.Errorf("[FindSpiderImageFileList] find spider image file error: %+v", err)
+		return nil, err
+	}
+
+	var spiderInfoList []model.SpiderInfo
+
+	if err := cursor.All(ctx, &spiderInfoList); err != nil {
+		log.Errorf("[FindSpiderImageFileList] get spider info data from cursor error: %+v", err)
+		return nil, err
+	}
+
+	return spiderInfoList, nil
+}
+
+// PurgeSpiderInfo remove spider info permanently, only spider info that still in trash since before `deletedBefore` is removed
+func (r *SpiderRepository) PurgeSpiderInfo(ctx context.Context, spiderUUID string, deletedBefore time.Time) error {
+	log := r.log.WithContext(ctx)
+
+	log.Infof("[PurgeSpiderInfo] spider_uuid: %v", spiderUUID)
 
 	selector := bson.M{
 		"spider_uuid": spiderUUID,
+		"status":      model.SPIDER_INFO_STATUS_DELETED,
+		"deleted_at": bson.M{
+			"$lt": deletedBefore,
+		},
 	}
 
+	coll := r.database.Collection(r.collectionName)
+
+	result, err := coll.DeleteOne(ctx, selector)
+	if err != nil {
+		log.Errorf("[PurgeSpiderInfo] delete one at spider_uuid `%v` failed, result: %+v, error: %+v", spiderUUID, result, err)
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		log.Errorf("[PurgeSpiderInfo] delete spider result is `%v`", result.DeletedCount)
+		return ErrorSpiderRepositoryDeleteSpiderIsZero
+	}
+
+	log.Infof("[PurgeSpiderInfo] delete one at spider_uuid `%v` successfull, result: %+v", spiderUUID, result)
+
+	return nil
+}
+
+// UpdateSpiderInfo replace spider info when version of record is still expectedVersion,
+// false is returned when record is not found or changed by other request
+func (r *SpiderRepository) UpdateSpiderInfo(ctx context.Context, spiderUUID string, expectedVersion int64, spiderInfo model.SpiderInfo) (bool, error) {
+	log := r.log.WithContext(ctx)
+
+	log.Infof("[UpdateSpiderInfo] update spider where spider_uuid: %v, version: %v", spiderUUID, expectedVersion)
+
+	selector := spiderVersionSelector(spiderUUID, expectedVersion)
+
+	spiderInfo.Version = expectedVersion + 1
+
 	updater := bson.M{
 		"$set": spiderInfo,
 	}
@@ -242,6 +484,80 @@ func (r *SpiderRepository) UpdateSpiderInfo(ctx context.Context, spiderUUID stri
 	return true, nil
 }
 
+// PatchSpiderInfo write only fields of spiderInfo that named in fields (bson name) when version of record is still expectedVersion,
+// zero value field is unset so patched document keep same shape as inserted document
+func (r *SpiderRepository) PatchSpiderInfo(ctx context.Context, spiderUUID string, expectedVersion int64, spiderInfo model.SpiderInfo, fields []string) (bool, error) {
+	log := r.log.WithContext(ctx)
+
+	log.Infof("[PatchSpiderInfo] patch spider where spider_uuid: %v, version: %v, fields: %v", spiderUUID, expectedVersion, fields)
+
+	raw, err := bson.Marshal(spiderInfo)
+	if err != nil {
+		log.Errorf("[PatchSpiderInfo] marshal spider info failed, error: %+v", err)
+		return false, err
+	}
+
+	var document bson.M
+	if err := bson.Unmarshal(raw, &document); err != nil {
+		log.Errorf("[PatchSpiderInfo] unmarshal spider info failed, error: %+v", err)
+		return false, err
+	}
+
+	set := bson.M{
+		"updated_at": spiderInfo.UpdatedAt,
+		"version":    expectedVersion + 1,
+	}
+	unset := bson.M{}
+
+	for _, field := range fields {
+		if value, ok := document[field]; ok {
+			set[field] = value
+		} else {
+			unset[field] = ""
+		}
+	}
+
+	updater := bson.M{
+		"$set": set,
+	}
+
+	if len(unset) > 0 {
+		updater["$unset"] = unset
+	}
+
+	coll := r.database.Collection(r.collectionName)
+
+	result, err := coll.UpdateOne(ctx, spiderVersionSelector(spiderUUID, expectedVersion), updater)
+	if err != nil {
+		log.Errorf("[PatchSpiderInfo] patch spider info failed, error: %+v", err)
+		return false, err
+	}
+
+	if result.MatchedCount == 0 {
+		log.Warnf("[PatchSpiderInfo] patch spider info is zero update")
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// spiderVersionSelector match spider info that is still at expectedVersion
+func spiderVersionSelector(spiderUUID string, expectedVersion int64) bson.M {
+	selector := bson.M{
+		"spider_uuid": spiderUUID,
+		"version":     expectedVersion,
+	}
+
+	// null match record that created before versioning
+	if expectedVersion == 0 {
+		selector["version"] = bson.M{
+			"$in": bson.A{0, nil},
+		}
+	}
+
+	return selector
+}
+
 func (r *SpiderRepository) FindSpiderInfoListByGeographies(ctx context.Context, province, district, position string) ([]model.SpiderInfo, error) {
 	log := r.log.WithContext(ctx)
 	var spiderInfoList []model.SpiderInfo
@@ -279,6 +595,7 @@ func (r *SpiderRepository) FindSpiderInfoListByGeographies(ctx context.Context,
 		"address": bson.M{
 			"$elemMatch": geographiesCondition,
 		},
+		"status": model.SPIDER_INFO_STATUS_PUBLISHED,
 	}
 
 	log.Infof("[FindSpiderInfoListByGeographies] find spider info with condition: %+v", selector)
@@ -313,7 +630,9 @@ func (r *SpiderRepository) FindSpiderInfoBySpiderType(ctx context.Context, famil
 		opts.SetLimit(int64(limit))
 	}
 
-	selector := bson.M{}
+	selector := bson.M{
+		"status": model.SPIDER_INFO_STATUS_PUBLISHED,
+	}
 
 	if family != "" {
 		selector["family"] = family
@@ -370,6 +689,7 @@ func (r *SpiderRepository) FindSpiderInfoByLocality(ctx context.Context, localit
 				},
 			},
 		},
+		"status": model.SPIDER_INFO_STATUS_PUBLISHED,
 	}
 
 	log.Infof("[FindSpiderInfoByLocality] find spider with skip `%v` and limit `%v`", int64(page), int64(limit))
@@ -401,7 +721,8 @@ func (r *SpiderRepository) FindSpiderInfoByFirstFamilyOrGenus(ctx context.Contex
 	opts := options.Find()
 
 	selecter := bson.M{
-		field: value,
+		field:    value,
+		"status": model.SPIDER_INFO_STATUS_PUBLISHED,
 	}
 
 	var spiderInfoList []model.SpiderInfo
@@ -423,3 +744,182 @@ func (r *SpiderRepository) FindSpiderInfoByFirstFamilyOrGenus(ctx context.Contex
 	return spiderInfoList, nil
 
 }
+
+// FindFamilyList summarize published spider info of every family in one aggregation,
+// total is number of family for pagination
+func (r *SpiderRepository) FindFamilyList(ctx context.Context, sortBy string, page, size int32) ([]model.FamilyList, int64, error) {
+	log := r.log.WithContext(ctx)
+
+	var sorter bson.D
+	switch sortBy {
+	case model.FAMILY_LIST_SORT_NAME:
+		sorter = bson.D{{Key: "family", Value: 1}}
+	case model.FAMILY_LIST_SORT_COUNT:
+		sorter = bson.D{{Key: "quantity", Value: -1}, {Key: "family", Value: 1}}
+	default:
+		return nil, 0, fmt.Errorf("unknown sort of family list `%v`", sortBy)
+	}
+
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{"status": model.SPIDER_INFO_STATUS_PUBLISHED}},
+		// author of first recorded spider info represent family
+		bson.M{"$sort": bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}},
+		bson.M{"$group": bson.M{
+			"_id":      "$family",
+			"author":   bson.M{"$first": "$author"},
+			"quantity": bson.M{"$sum": 1},
+			"genus":    bson.M{"$addToSet": "$genus"},
+			"species":  bson.M{"$addToSet": bson.M{"genus": "$genus", "species": "$species"}},
+		}},
+		bson.M{"$project": bson.M{
+			"_id":           0,
+			"family":        "$_id",
+			"author":        1,
+			"quantity":      1,
+			"genus_count":   bson.M{"$size": "$genus"},
+			"species_count": bson.M{"$size": "$species"},
+		}},
+		bson.M{"$facet": bson.M{
+			"family_list": bson.A{
+				bson.M{"$sort": sorter},
+				bson.M{"$skip": int64(page) * int64(size)},
+				bson.M{"$limit": int64(size)},
+			},
+			"total": bson.A{
+				bson.M{"$count": "count"},
+			},
+		}},
+	}
+
+	coll := r.database.Collection(r.collectionName)
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		log.Errorf("[FindFamilyList] aggregate family list error: %+v", err)
+		return nil, 0, err
+	}
+
+	var result []struct {
+		FamilyList []model.FamilyList `bson:"family_list"`
+		Total      []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+	}
+
+	if err := cursor.All(ctx, &result); err != nil {
+		log.Errorf("[FindFamilyList] decode to struct failed, error: %+v", err)
+		return nil, 0, err
+	}
+
+	familyList := []model.FamilyList{}
+	var total int64
+
+	if len(result) > 0 {
+		if result[0].FamilyList != nil {
+			familyList = result[0].FamilyList
+		}
+		if len(result[0].Total) > 0 {
+			total = result[0].Total[0].Count
+		}
+	}
+
+	return familyList, total, nil
+}
+
+// UpdateSpiderStatus move spider info from status to next status of editorial workflow,
+// review is kept when nil so contributor can read comment of previous review
+func (r *SpiderRepository) UpdateSpiderStatus(ctx context.Context, spiderUUID, fromStatus, toStatus string, review *model.SpiderReview) error {
+	log := r.log.WithContext(ctx)
+
+	log.Infof("[UpdateSpiderStatus] spider_uuid `%v` from `%v` to `%v`", spiderUUID, fromStatus, toStatus)
+
+	selector := bson.M{
+		"spider_uuid": spiderUUID,
+		"status":      fromStatus,
+	}
+
+	set := bson.M{
+		"status":     toStatus,
+		"updated_at": time.Now(),
+	}
+
+	if review != nil {
+		set["review"] = review
+	}
+
+	updater := bson.M{
+		"$set": set,
+		"$inc": bson.M{
+			"version": 1,
+		},
+	}
+
+	coll := r.database.Collection(r.collectionName)
+
+	result, err := coll.UpdateOne(ctx, selector, updater)
+	if err != nil {
+		log.Errorf("[UpdateSpiderStatus] update status of spider_uuid `%v` failed, error: %+v", spiderUUID, err)
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrorMongoNotFound
+	}
+
+	return nil
+}
+
+// FindSpiderInfoListByStatus return spider info of status, oldest change first
+func (r *SpiderRepository) FindSpiderInfoListByStatus(ctx context.Context, status string, page, size int32) ([]model.SpiderInfo, int64, error) {
+	log := r.log.WithContext(ctx)
+
+	coll := r.database.Collection(r.collectionName)
+
+	selector := bson.M{
+		"status": status,
+	}
+
+	total, err := coll.CountDocuments(ctx, selector)
+	if err != nil {
+		log.Errorf("[FindSpiderInfoListByStatus] count spider info of status `%v` error: %+v", status, err)
+		return nil, 0, err
+	}
+
+	opts := options.Find()
+
+	opts.SetSort(bson.M{
+		"updated_at": 1,
+	})
+	opts.SetSkip(int64(page * size))
+	opts.SetLimit(int64(size))
+
+	cursor, err := r.findManySpiderWithCondition(ctx, selector, opts)
+	if err != nil {
+		log.Errorf("[FindSpiderInfoListByStatus] find spider info of status `%v` error: %+v", status, err)
+		return nil, 0, err
+	}
+
+	var spiderInfoList []model.SpiderInfo
+
+	if err := cursor.All(ctx, &spiderInfoList); err != nil {
+		log.Errorf("[FindSpiderInfoListByStatus] get spider info data from cursor error: %+v", err)
+		return nil, 0, err
+	}
+
+	return spiderInfoList, total, nil
+}
+
+// excludeDeletedSpider add condition that hide spider info in trash when selector has no condition of status
+func excludeDeletedSpider(selector bson.M) bson.M {
+	if selector == nil {
+		selector = bson.M{}
+	}
+
+	if _, ok := selector["status"]; !ok {
+		selector["status"] = bson.M{
+			"$ne": model.SPIDER_INFO_STATUS_DELETED,
+		}
+	}
+
+	return selector
+}
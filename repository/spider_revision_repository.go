@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"spider-go/domain"
+	"spider-go/logger"
+	"spider-go/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SpiderRevisionRepository struct {
+	database              *mongo.Database
+	log                   *logger.Logger
+	collectionName        string
+	counterCollectionName string
+}
+
+func NewSpiderRevisionRepository(db *mongo.Database) domain.SpiderRevisionRepository {
+	return &SpiderRevisionRepository{
+		database:              db,
+		log:                   logger.L().Named("SpiderRevisionRepository"),
+		collectionName:        "spider_revision",
+		counterCollectionName: "spider_revision_counter",
+	}
+}
+
+func (r *SpiderRevisionRepository) InsertSpiderRevision(ctx context.Context, revision model.SpiderRevision) (int64, error) {
+	log := r.log.WithContext(ctx)
+
+	revisionNumber, err := r.nextRevisionNumber(ctx, revision.SpiderUUID)
+	if err != nil {
+		log.Errorf("[InsertSpiderRevision] get next revision number of `%v` error: %+v", revision.SpiderUUID, err)
+		return 0, err
+	}
+
+	revision.Revision = revisionNumber
+
+	coll := r.database.Collection(r.collectionName)
+
+	if _, err := coll.InsertOne(ctx, revision); err != nil {
+		log.Errorf("[InsertSpiderRevision] insert revision `%v` of `%v` error: %+v", revisionNumber, revision.SpiderUUID, err)
+		return 0, err
+	}
+
+	return revisionNumber, nil
+}
+
+// nextRevisionNumber increase counter of spider_uuid atomically, so concurrent change never get the same number
+func (r *SpiderRevisionRepository) nextRevisionNumber(ctx context.Context, spiderUUID string) (int64, error) {
+	coll := r.database.Collection(r.counterCollectionName)
+
+	selector := bson.M{
+		"_id": spiderUUID,
+	}
+
+	updater := bson.M{
+		"$inc": bson.M{
+			"seq": int64(1),
+		},
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After)
+
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+
+	if err := coll.FindOneAndUpdate(ctx, selector, updater, opts).Decode(&counter); err != nil {
+		return 0, err
+	}
+
+	return counter.Seq, nil
+}
+
+func (r *SpiderRevisionRepository) FindSpiderRevisionList(ctx context.Context, spiderUUID string, page, size int32) ([]model.SpiderRevision, int64, error) {
+	log := r.log.WithContext(ctx)
+
+	coll := r.database.Collection(r.collectionName)
+
+	selector := bson.M{
+		"spider_uuid": spiderUUID,
+	}
+
+	total, err := coll.CountDocuments(ctx, selector)
+	if err != nil {
+		log.Errorf("[FindSpiderRevisionList] count revision of `%v` error: %+v", spiderUUID, err)
+		return nil, 0, err
+	}
+
+	opts := options.Find()
+
+	opts.SetSort(bson.M{
+		"revision": -1,
+	})
+	opts.SetSkip(int64(page * size))
+	opts.SetLimit(int64(size))
+
+	cursor, err := coll.Find(ctx, selector, opts)
+	if err != nil {
+		log.Errorf("[FindSpiderRevisionList] find revision of `%v` error: %+v", spiderUUID, err)
+		return nil, 0, err
+	}
+
+	var revisionList []model.SpiderRevision
+
+	if err := cursor.All(ctx, &revisionList); err != nil {
+		log.Errorf("[FindSpiderRevisionList] decode revision list of `%v` error: %+v", spiderUUID, err)
+		return nil, 0, err
+	}
+
+	return revisionList, total, nil
+}
+
+func (r *SpiderRevisionRepository) FindSpiderRevision(ctx context.Context, spiderUUID string, revisionNumber int64) (*model.SpiderRevision, error) {
+	coll := r.database.Collection(r.collectionName)
+
+	selector := bson.M{
+		"spider_uuid": spiderUUID,
+		"revision":    revisionNumber,
+	}
+
+	var revision model.SpiderRevision
+
+	if err := coll.FindOne(ctx, selector).Decode(&revision); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrorMongoNotFound
+		}
+		return nil, err
+	}
+
+	return &revision, nil
+}
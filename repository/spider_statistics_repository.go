@@ -5,6 +5,7 @@ import (
 	"spider-go/domain"
 	"spider-go/logger"
 	"spider-go/model"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -15,15 +16,16 @@ type StatisticsRepository struct {
 	database       *mongo.Database
 	log            *logger.Logger
 	collectionName string
+	// statistics is rebuilt from spider collection
+	spiderCollectionName string
 }
 
-// FindSpiderStatisticsByFamily implements domain.StatisticsRepository
-
 func NewSpiderStatisticsRepository(db *mongo.Database) domain.StatisticsRepository {
 	return &StatisticsRepository{
-		database:       db,
-		log:            logger.L().Named("StatisticsRepository"),
-		collectionName: "spider_statistics",
+		database:             db,
+		log:                  logger.L().Named("StatisticsRepository"),
+		collectionName:       "spider_statistics",
+		spiderCollectionName: "spider",
 	}
 }
 
@@ -45,78 +47,230 @@ func (r *StatisticsRepository) FindAllSpiderStatistics(ctx context.Context) ([]m
 	return result, nil
 }
 
-func (r *StatisticsRepository) FindSpiderStatisticsByFamily(ctx context.Context, family string) (*model.SpiderStatistics, error) {
+// IncreaseSpiderStatistics change record count of species and its genus and family by delta with update operators,
+// so concurrent write never overwrite each other. missing node is added on positive delta, count of species that
+// has no record is not decreased, and species, genus or family that has no record left is removed
+func (r *StatisticsRepository) IncreaseSpiderStatistics(ctx context.Context, familyName, genusName, speciesName string, delta int64) error {
 	log := r.log.WithContext(ctx)
 
 	coll := r.database.Collection(r.collectionName)
+	tn := time.Now()
 
-	selector := bson.M{
-		"family_name": family,
-	}
-
-	var result model.SpiderStatistics
-
-	if err := coll.FindOne(ctx, selector).Decode(&result); err != nil {
-		log.Errorf("[FindSpiderStatisticsByFamily], find one spider statistics error %+v", err)
-		if err == mongo.ErrNoDocuments {
-			return nil, ErrorMongoNotFound
+	if delta > 0 {
+		if err := r.addSpiderStatisticsNode(ctx, coll, familyName, genusName, speciesName, tn); err != nil {
+			log.Errorf("[IncreaseSpiderStatistics] add node `%v/%v/%v` error: %+v", familyName, genusName, speciesName, err)
+			return err
 		}
-		return nil, err
 	}
 
-	return &result, nil
-
-}
-
-func (r *StatisticsRepository) UpsertSpiderStatistics(ctx context.Context, familyName string, data model.SpiderStatistics) error {
-	log := r.log.WithContext(ctx)
-
-	opts := options.Update().SetUpsert(true)
+	speciesMatch := bson.M{"species_name": speciesName}
+	speciesFilter := bson.M{"s.species_name": speciesName}
+	if delta < 0 {
+		speciesMatch["record_count"] = bson.M{"$gte": -delta}
+		speciesFilter["s.record_count"] = bson.M{"$gte": -delta}
+	}
 
 	selector := bson.M{
 		"family_name": familyName,
+		"genus": bson.M{"$elemMatch": bson.M{
+			"genus_name": genusName,
+			"species":    bson.M{"$elemMatch": speciesMatch},
+		}},
 	}
 
 	updater := bson.M{
-		"$set": data,
+		"$inc": bson.M{
+			"record_count":                         delta,
+			"genus.$[g].record_count":              delta,
+			"genus.$[g].species.$[s].record_count": delta,
+		},
+		"$set": bson.M{"updated_at": tn},
 	}
 
-	coll := r.database.Collection(r.collectionName)
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{
+		Filters: []interface{}{
+			bson.M{"g.genus_name": genusName},
+			speciesFilter,
+		},
+	})
 
 	result, err := coll.UpdateOne(ctx, selector, updater, opts)
-
 	if err != nil {
-		log.Errorf("[upsert spider statistics] upsert data error: %+v, result: %+v", err, result)
+		log.Errorf("[IncreaseSpiderStatistics] increase `%v/%v/%v` by %v error: %+v", familyName, genusName, speciesName, delta, err)
 		return err
 	}
 
-	log.Infof("[upsert spider statistics] upsert data result: %+v", result)
+	if result.MatchedCount == 0 {
+		// statistics that drift from spider info is fixed by statistics rebuild
+		log.Warnf("[IncreaseSpiderStatistics] `%v/%v/%v` has no record to decrease", familyName, genusName, speciesName)
+		return nil
+	}
+
+	if delta < 0 {
+		if err := r.removeEmptySpiderStatisticsNode(ctx, coll, familyName, genusName, speciesName); err != nil {
+			log.Errorf("[IncreaseSpiderStatistics] remove empty node `%v/%v/%v` error: %+v", familyName, genusName, speciesName, err)
+			return err
+		}
+	}
 
 	return nil
 }
 
-func (r *StatisticsRepository) FindFamilyListWithLimitSizePage(ctx context.Context, page, limit int32) ([]model.SpiderStatistics, error) {
+// addSpiderStatisticsNode create family, genus and species node that is not found, every step is no-op when node exist
+func (r *StatisticsRepository) addSpiderStatisticsNode(ctx context.Context, coll *mongo.Collection, familyName, genusName, speciesName string, tn time.Time) error {
+	_, err := coll.UpdateOne(ctx,
+		bson.M{"family_name": familyName},
+		bson.M{
+			"$setOnInsert": bson.M{
+				"genus":        bson.A{},
+				"record_count": 0,
+				"created_at":   tn,
+			},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return err
+	}
 
-	log := r.log.WithContext(ctx)
+	_, err = coll.UpdateOne(ctx,
+		bson.M{"family_name": familyName, "genus.genus_name": bson.M{"$ne": genusName}},
+		bson.M{"$push": bson.M{"genus": model.GenusGroup{
+			GenusName: genusName,
+			Species:   []model.SpeciesGroup{},
+		}}},
+	)
+	if err != nil {
+		return err
+	}
 
-	coll := r.database.Collection(r.collectionName)
+	_, err = coll.UpdateOne(ctx,
+		bson.M{
+			"family_name": familyName,
+			"genus": bson.M{"$elemMatch": bson.M{
+				"genus_name":           genusName,
+				"species.species_name": bson.M{"$ne": speciesName},
+			}},
+		},
+		bson.M{"$push": bson.M{"genus.$.species": model.SpeciesGroup{
+			SpeciesName: speciesName,
+		}}},
+	)
+
+	return err
+}
 
-	var result []model.SpiderStatistics
-	opts := options.Find()
+// removeEmptySpiderStatisticsNode remove species and genus that has no record left, then family that has no genus left
+func (r *StatisticsRepository) removeEmptySpiderStatisticsNode(ctx context.Context, coll *mongo.Collection, familyName, genusName, speciesName string) error {
+	_, err := coll.UpdateOne(ctx,
+		bson.M{"family_name": familyName},
+		bson.M{"$pull": bson.M{"genus.$[g].species": bson.M{
+			"species_name": speciesName,
+			"record_count": bson.M{"$lte": 0},
+		}}},
+		options.Update().SetArrayFilters(options.ArrayFilters{
+			Filters: []interface{}{bson.M{"g.genus_name": genusName}},
+		}),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = coll.UpdateOne(ctx,
+		bson.M{"family_name": familyName},
+		bson.M{"$pull": bson.M{"genus": bson.M{
+			"genus_name": genusName,
+			"species":    bson.M{"$size": 0},
+		}}},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = coll.DeleteOne(ctx, bson.M{"family_name": familyName, "genus": bson.M{"$size": 0}})
 
-	opts.SetSkip(int64(page * limit))
-	opts.SetLimit(int64(limit))
+	return err
+}
 
-	cursor, err := coll.Find(ctx, bson.M{}, opts)
+// AggregateSpiderStatistics count published spider info to family -> genus -> species tree,
+// family, genus and species are sorted by name
+func (r *StatisticsRepository) AggregateSpiderStatistics(ctx context.Context) ([]model.SpiderStatistics, error) {
+	log := r.log.WithContext(ctx)
+
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{"status": model.SPIDER_INFO_STATUS_PUBLISHED}},
+		bson.M{"$group": bson.M{
+			"_id":          bson.M{"family": "$family", "genus": "$genus", "species": "$species"},
+			"record_count": bson.M{"$sum": 1},
+		}},
+		bson.M{"$sort": bson.D{
+			{Key: "_id.family", Value: 1},
+			{Key: "_id.genus", Value: 1},
+			{Key: "_id.species", Value: 1},
+		}},
+		bson.M{"$group": bson.M{
+			"_id":          bson.M{"family": "$_id.family", "genus": "$_id.genus"},
+			"species":      bson.M{"$push": bson.M{"species_name": "$_id.species", "record_count": "$record_count"}},
+			"record_count": bson.M{"$sum": "$record_count"},
+		}},
+		bson.M{"$sort": bson.D{
+			{Key: "_id.family", Value: 1},
+			{Key: "_id.genus", Value: 1},
+		}},
+		bson.M{"$group": bson.M{
+			"_id":          "$_id.family",
+			"genus":        bson.M{"$push": bson.M{"genus_name": "$_id.genus", "species": "$species", "record_count": "$record_count"}},
+			"record_count": bson.M{"$sum": "$record_count"},
+		}},
+		bson.M{"$sort": bson.M{"_id": 1}},
+		bson.M{"$project": bson.M{"_id": 0, "family_name": "$_id", "genus": 1, "record_count": 1}},
+	}
+
+	coll := r.database.Collection(r.spiderCollectionName)
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
 	if err != nil {
-		log.Errorf("[FindFamilyListWithLimitSizePage] mongo error: %+v", err)
+		log.Errorf("[AggregateSpiderStatistics] aggregate spider info error: %+v", err)
 		return nil, err
 	}
 
-	if err = cursor.All(context.TODO(), &result); err != nil {
-		log.Errorf("[FindFamilyListWithLimitSizePage] decode to struct failed, error: %+v", err)
+	result := []model.SpiderStatistics{}
+	if err := cursor.All(ctx, &result); err != nil {
+		log.Errorf("[AggregateSpiderStatistics] decode to struct failed, error: %+v", err)
 		return nil, err
 	}
 
 	return result, nil
 }
+
+// ReplaceAllSpiderStatistics replace every statistics document with data in place, so index of collection is kept.
+// it must be called in unit of work, reader see either old or new statistics and increase of statistics that run at
+// same time conflict with it instead of write to replaced document. it is not built in temp collection and renamed,
+// so size and time of rebuild is limited by transaction, see README
+func (r *StatisticsRepository) ReplaceAllSpiderStatistics(ctx context.Context, data []model.SpiderStatistics) error {
+	log := r.log.WithContext(ctx)
+
+	coll := r.database.Collection(r.collectionName)
+
+	deleted, err := coll.DeleteMany(ctx, bson.M{})
+	if err != nil {
+		log.Errorf("[ReplaceAllSpiderStatistics] delete spider statistics error: %+v", err)
+		return err
+	}
+
+	if len(data) > 0 {
+		documents := make([]interface{}, 0, len(data))
+		for _, statistics := range data {
+			documents = append(documents, statistics)
+		}
+
+		if _, err := coll.InsertMany(ctx, documents); err != nil {
+			log.Errorf("[ReplaceAllSpiderStatistics] insert spider statistics error: %+v", err)
+			return err
+		}
+	}
+
+	log.Infof("[ReplaceAllSpiderStatistics] replace %v family with %v family", deleted.DeletedCount, len(data))
+
+	return nil
+}
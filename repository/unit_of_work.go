@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"spider-go/domain"
+	"spider-go/logger"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoUnitOfWork run work in transaction of mongo session, transaction require mongo replica set or sharded cluster
+type MongoUnitOfWork struct {
+	client *mongo.Client
+	log    *logger.Logger
+}
+
+func NewMongoUnitOfWork(client *mongo.Client) domain.UnitOfWork {
+	return &MongoUnitOfWork{
+		client: client,
+		log:    logger.L().Named("MongoUnitOfWork"),
+	}
+}
+
+func (u *MongoUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	log := u.log.WithContext(ctx)
+
+	session, err := u.client.StartSession()
+	if err != nil {
+		log.Errorf("[Do] start mongo session error: %+v", err)
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	// transient error is retried by driver, so fn must not keep state outside of transaction
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessionCtx)
+	})
+
+	return err
+}
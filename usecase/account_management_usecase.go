@@ -0,0 +1,252 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"spider-go/config"
+	"spider-go/domain"
+	"spider-go/logger"
+	"spider-go/model"
+	"spider-go/repository"
+	"spider-go/utils/cryptography"
+	"spider-go/utils/policy"
+)
+
+var (
+	ErrorAccountManagementInvalidData             = fmt.Errorf("[Account Management Usecase]: invalid request data")
+	ErrorAccountManagementAccountNotFound         = fmt.Errorf("[Account Management Usecase]: account not found")
+	ErrorAccountManagementInsufficientRights      = fmt.Errorf("[Account Management Usecase]: insufficient rights to manage this account")
+	ErrorAccountManagementConfirmPasswordNotMatch = fmt.Errorf("[Account Management Usecase]: password and confirm password not match")
+	ErrorAccountManagementInvalidPassword         = fmt.Errorf("[Account Management Usecase]: invalid password")
+	ErrorAccountManagementHashPasswordFail        = fmt.Errorf("[Account Management Usecase]: hashing password failed")
+	ErrorAccountManagementPasswordNotQualify      = fmt.Errorf("[Account Management Usecase]: password does not qualify")
+	ErrorAccountManagementMongoConnection         = fmt.Errorf("[Account Management Usecase]: mongo error")
+	ErrorAccountManagementTempDataConnection      = fmt.Errorf("[Account Management Usecase]: redis error")
+)
+
+type AccountManagementUsecase struct {
+	accRepo          domain.AccountRepository
+	authorities      domain.Authorities
+	credentialPolicy *policy.CredentialPolicy
+	passwordHasher   cryptography.PasswordHasher
+	log              *logger.Logger
+}
+
+func NewAccountManagementUsecase(accRepo domain.AccountRepository, authorities domain.Authorities, conf *config.Root) domain.AccountManagementUsecase {
+	return &AccountManagementUsecase{
+		accRepo:          accRepo,
+		authorities:      authorities,
+		credentialPolicy: policy.NewCredentialPolicy(conf.CredentialPolicy),
+		passwordHasher:   cryptography.NewPasswordHasher(conf.PasswordHash),
+		log:              logger.L().Named("AccountManagementUsecase"),
+	}
+}
+
+// ========================================================
+// manage account by master or admin
+// ========================================================
+
+func (u *AccountManagementUsecase) GetAccountList(ctx context.Context, filter model.AccountListFilter) ([]model.Account, int64, error) {
+	log := u.log.WithContext(ctx)
+
+	accountList, total, err := u.accRepo.FindAccountList(ctx, filter)
+	if err != nil {
+		log.Errorf("[GetAccountList] find account list with filter %+v error: %+v", filter, err)
+		return nil, 0, ErrorAccountManagementMongoConnection
+	}
+
+	return accountList, total, nil
+}
+
+func (u *AccountManagementUsecase) UpdateAccountStatus(ctx context.Context, actor *model.LoginUser, username, status string) error {
+	log := u.log.WithContext(ctx)
+
+	if status != model.ACCOUNT_STATUS_ACTIVE && status != model.ACCOUNT_STATUS_DISABLED {
+		log.Errorf("[UpdateAccountStatus] invalid status `%v`", status)
+		return ErrorAccountManagementInvalidData
+	}
+
+	accountInfo, err := u.findManagedAccount(ctx, actor, username)
+	if err != nil {
+		return err
+	}
+
+	if err := u.accRepo.UpdateAccountStatus(ctx, accountInfo.Username, status); err != nil {
+		log.Errorf("[UpdateAccountStatus] update status of account `%v` error: %+v", username, err)
+		return u.mapRepositoryError(err)
+	}
+
+	// disabled account must not use session that still alive
+	if status == model.ACCOUNT_STATUS_DISABLED {
+		if err := u.authorities.RevokeAllSessions(ctx, accountInfo.Username, actor.Username); err != nil {
+			log.Errorf("[UpdateAccountStatus] revoke sessions of account `%v` error: %+v", username, err)
+			return ErrorAccountManagementTempDataConnection
+		}
+	}
+
+	log.Infof("[UpdateAccountStatus] account `%v` status is changed to `%v` by `%v`", username, status, actor.Username)
+
+	return nil
+}
+
+func (u *AccountManagementUsecase) UpdateAccountRole(ctx context.Context, actor *model.LoginUser, username, role string) error {
+	log := u.log.WithContext(ctx)
+
+	if _, ok := model.ROLE_PERMISSIONS[role]; !ok {
+		log.Errorf("[UpdateAccountRole] invalid role `%v`", role)
+		return ErrorAccountManagementInvalidData
+	}
+
+	// only master can promote account to admin or master
+	if !u.canManageRole(actor, role) {
+		log.Warnf("[UpdateAccountRole] user `%v` cannot grant role `%v`", actor.Username, role)
+		return ErrorAccountManagementInsufficientRights
+	}
+
+	accountInfo, err := u.findManagedAccount(ctx, actor, username)
+	if err != nil {
+		return err
+	}
+
+	if err := u.accRepo.UpdateAccountRole(ctx, accountInfo.Username, role); err != nil {
+		log.Errorf("[UpdateAccountRole] update role of account `%v` error: %+v", username, err)
+		return u.mapRepositoryError(err)
+	}
+
+	// role is in access token claims, force login again for get token with new role
+	if err := u.authorities.RevokeAllSessions(ctx, accountInfo.Username, actor.Username); err != nil {
+		log.Errorf("[UpdateAccountRole] revoke sessions of account `%v` error: %+v", username, err)
+		return ErrorAccountManagementTempDataConnection
+	}
+
+	log.Infof("[UpdateAccountRole] account `%v` role is changed from `%v` to `%v` by `%v`", username, accountInfo.Role, role, actor.Username)
+
+	return nil
+}
+
+// ResetMFA remove second factor of account that lost authenticator and recovery codes,
+// account must enroll again at next login when role enforce second factor
+func (u *AccountManagementUsecase) ResetMFA(ctx context.Context, actor *model.LoginUser, username string) error {
+	log := u.log.WithContext(ctx)
+
+	accountInfo, err := u.findManagedAccount(ctx, actor, username)
+	if err != nil {
+		return err
+	}
+
+	if err := u.accRepo.UpdateAccountMFA(ctx, accountInfo.Username, nil); err != nil {
+		log.Errorf("[ResetMFA] remove second factor of account `%v` error: %+v", username, err)
+		return u.mapRepositoryError(err)
+	}
+
+	// session that still alive may belong to someone that take over the account
+	if err := u.authorities.RevokeAllSessions(ctx, accountInfo.Username, actor.Username); err != nil {
+		log.Errorf("[ResetMFA] revoke sessions of account `%v` error: %+v", username, err)
+		return ErrorAccountManagementTempDataConnection
+	}
+
+	log.Infof("[ResetMFA] second factor of account `%v` is reset by `%v`", username, actor.Username)
+
+	return nil
+}
+
+// findManagedAccount find target account and check actor can manage it
+func (u *AccountManagementUsecase) findManagedAccount(ctx context.Context, actor *model.LoginUser, username string) (*model.Account, error) {
+	log := u.log.WithContext(ctx)
+
+	if actor.Username == username {
+		log.Warnf("[findManagedAccount] user `%v` cannot manage own account", actor.Username)
+		return nil, ErrorAccountManagementInsufficientRights
+	}
+
+	accountInfo, err := u.accRepo.FindAccountByUsername(ctx, username)
+	if err != nil {
+		log.Errorf("[findManagedAccount] find account `%v` error: %+v", username, err)
+		return nil, u.mapRepositoryError(err)
+	}
+
+	if !u.canManageRole(actor, accountInfo.Role) {
+		log.Warnf("[findManagedAccount] user `%v` cannot manage account `%v` with role `%v`", actor.Username, username, accountInfo.Role)
+		return nil, ErrorAccountManagementInsufficientRights
+	}
+
+	return accountInfo, nil
+}
+
+// canManageRole master can manage every role, admin can manage only general user
+func (u *AccountManagementUsecase) canManageRole(actor *model.LoginUser, role string) bool {
+	if actor.HasRole(model.ACCOUNT_ROLE_MASTER) {
+		return true
+	}
+
+	return actor.HasRole(model.ACCOUNT_ROLE_ADMIN) && role == model.ACCOUNT_ROLE_GENERAL
+}
+
+// ********************************************************
+
+// ========================================================
+// self-service
+// ========================================================
+
+func (u *AccountManagementUsecase) UpdateProfile(ctx context.Context, username string, profile model.AccountProfile) error {
+	log := u.log.WithContext(ctx)
+
+	if err := u.accRepo.UpdateAccountProfile(ctx, username, profile); err != nil {
+		log.Errorf("[UpdateProfile] update profile of account `%v` error: %+v", username, err)
+		return u.mapRepositoryError(err)
+	}
+
+	return nil
+}
+
+func (u *AccountManagementUsecase) ChangePassword(ctx context.Context, username, oldPassword, newPassword, confirmPassword string) error {
+	log := u.log.WithContext(ctx)
+
+	if newPassword != confirmPassword {
+		return ErrorAccountManagementConfirmPasswordNotMatch
+	}
+
+	if err := u.credentialPolicy.ValidatePassword(username, newPassword); err != nil {
+		log.Errorf("[ChangePassword] new password of account `%v` does not qualify: %+v", username, err)
+		return ErrorAccountManagementPasswordNotQualify
+	}
+
+	accountInfo, err := u.accRepo.FindAccountByUsername(ctx, username)
+	if err != nil {
+		log.Errorf("[ChangePassword] find account `%v` error: %+v", username, err)
+		return u.mapRepositoryError(err)
+	}
+
+	match, err := u.passwordHasher.Verify(oldPassword, accountInfo.HashPassword)
+	if err != nil {
+		log.Errorf("[ChangePassword] verify password hash of account `%v` error: %+v", username, err)
+	}
+
+	if !match {
+		return ErrorAccountManagementInvalidPassword
+	}
+
+	hashPass, err := u.passwordHasher.Hash(newPassword)
+	if err != nil {
+		log.Errorf("[ChangePassword] hashing password error: %+v", err)
+		return ErrorAccountManagementHashPasswordFail
+	}
+
+	if err := u.accRepo.UpdateAccountPassword(ctx, username, hashPass); err != nil {
+		log.Errorf("[ChangePassword] update password of account `%v` error: %+v", username, err)
+		return u.mapRepositoryError(err)
+	}
+
+	log.Infof("[ChangePassword] account `%v` change password successful", username)
+
+	return nil
+}
+
+// ********************************************************
+
+func (u *AccountManagementUsecase) mapRepositoryError(err error) error {
+	if err == repository.ErrorMongoNotFound {
+		return ErrorAccountManagementAccountNotFound
+	}
+	return ErrorAccountManagementMongoConnection
+}
@@ -0,0 +1,345 @@
+package usecase
+
+import (
+	"context"
+	"spider-go/config"
+	mock_domain "spider-go/domain/mock"
+	"spider-go/model"
+	"spider-go/repository"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+)
+
+type commonStubsAccountManagement struct {
+	mockAccRepo     *mock_domain.MockAccountRepository
+	mockAuthorities *mock_domain.MockAuthorities
+}
+
+var (
+	unittestMaster = &model.LoginUser{Username: "master", Role: model.ACCOUNT_ROLE_MASTER}
+	unittestAdmin  = &model.LoginUser{Username: "admin", Role: model.ACCOUNT_ROLE_ADMIN}
+)
+
+// ======================================================================
+// TestAccountManagementUsecase_UpdateAccountRole
+// ======================================================================
+func TestAccountManagementUsecase_UpdateAccountRole(t *testing.T) {
+
+	type args struct {
+		actor    *model.LoginUser
+		username string
+		role     string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		buildStubs func(*commonStubsAccountManagement)
+		wantErr    error
+	}{
+		{
+			name: "master_promote_general_to_admin",
+			args: args{
+				actor:    unittestMaster,
+				username: "unittest",
+				role:     model.ACCOUNT_ROLE_ADMIN,
+			},
+			buildStubs: success_update_account_role,
+			wantErr:    nil,
+		},
+		{
+			name: "admin_cannot_promote_to_admin",
+			args: args{
+				actor:    unittestAdmin,
+				username: "unittest",
+				role:     model.ACCOUNT_ROLE_ADMIN,
+			},
+			buildStubs: func(stubs *commonStubsAccountManagement) {},
+			wantErr:    ErrorAccountManagementInsufficientRights,
+		},
+		{
+			name: "admin_cannot_demote_admin",
+			args: args{
+				actor:    unittestAdmin,
+				username: "other_admin",
+				role:     model.ACCOUNT_ROLE_GENERAL,
+			},
+			buildStubs: find_admin_account,
+			wantErr:    ErrorAccountManagementInsufficientRights,
+		},
+		{
+			name: "cannot_change_own_role",
+			args: args{
+				actor:    unittestMaster,
+				username: "master",
+				role:     model.ACCOUNT_ROLE_GENERAL,
+			},
+			buildStubs: func(stubs *commonStubsAccountManagement) {},
+			wantErr:    ErrorAccountManagementInsufficientRights,
+		},
+		{
+			name: "invalid_role",
+			args: args{
+				actor:    unittestMaster,
+				username: "unittest",
+				role:     "superuser",
+			},
+			buildStubs: func(stubs *commonStubsAccountManagement) {},
+			wantErr:    ErrorAccountManagementInvalidData,
+		},
+		{
+			name: "account_not_found",
+			args: args{
+				actor:    unittestMaster,
+				username: "unittest",
+				role:     model.ACCOUNT_ROLE_ADMIN,
+			},
+			buildStubs: account_management_not_found,
+			wantErr:    ErrorAccountManagementAccountNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := commonStubsAccountManagement{
+				mockAccRepo:     mock_domain.NewMockAccountRepository(ctrl),
+				mockAuthorities: mock_domain.NewMockAuthorities(ctrl),
+			}
+
+			tt.buildStubs(&stubs)
+
+			u := NewAccountManagementUsecase(stubs.mockAccRepo, stubs.mockAuthorities, &config.Root{})
+			if err := u.UpdateAccountRole(context.TODO(), tt.args.actor, tt.args.username, tt.args.role); err != tt.wantErr {
+				t.Errorf("AccountManagementUsecase.UpdateAccountRole() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func success_update_account_role(stubs *commonStubsAccountManagement) {
+	stubs.mockAccRepo.EXPECT().FindAccountByUsername(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+	).Return(&model.Account{Username: "unittest", Role: model.ACCOUNT_ROLE_GENERAL}, nil)
+
+	stubs.mockAccRepo.EXPECT().UpdateAccountRole(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+		gomock.Eq(model.ACCOUNT_ROLE_ADMIN),
+	).Return(nil)
+
+	stubs.mockAuthorities.EXPECT().RevokeAllSessions(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+		gomock.Eq("master"),
+	).Return(nil)
+}
+
+func find_admin_account(stubs *commonStubsAccountManagement) {
+	stubs.mockAccRepo.EXPECT().FindAccountByUsername(
+		gomock.Any(),
+		gomock.Eq("other_admin"),
+	).Return(&model.Account{Username: "other_admin", Role: model.ACCOUNT_ROLE_ADMIN}, nil)
+}
+
+func account_management_not_found(stubs *commonStubsAccountManagement) {
+	stubs.mockAccRepo.EXPECT().FindAccountByUsername(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+	).Return(nil, repository.ErrorMongoNotFound)
+}
+
+// ======================================================================
+// TestAccountManagementUsecase_UpdateAccountStatus
+// ======================================================================
+func TestAccountManagementUsecase_UpdateAccountStatus(t *testing.T) {
+
+	type args struct {
+		actor    *model.LoginUser
+		username string
+		status   string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		buildStubs func(*commonStubsAccountManagement)
+		wantErr    error
+	}{
+		{
+			name: "admin_disable_general_revoke_sessions",
+			args: args{
+				actor:    unittestAdmin,
+				username: "unittest",
+				status:   model.ACCOUNT_STATUS_DISABLED,
+			},
+			buildStubs: success_disable_account,
+			wantErr:    nil,
+		},
+		{
+			name: "enable_account_not_revoke_sessions",
+			args: args{
+				actor:    unittestAdmin,
+				username: "unittest",
+				status:   model.ACCOUNT_STATUS_ACTIVE,
+			},
+			buildStubs: success_enable_account,
+			wantErr:    nil,
+		},
+		{
+			name: "invalid_status",
+			args: args{
+				actor:    unittestAdmin,
+				username: "unittest",
+				status:   "deleted",
+			},
+			buildStubs: func(stubs *commonStubsAccountManagement) {},
+			wantErr:    ErrorAccountManagementInvalidData,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := commonStubsAccountManagement{
+				mockAccRepo:     mock_domain.NewMockAccountRepository(ctrl),
+				mockAuthorities: mock_domain.NewMockAuthorities(ctrl),
+			}
+
+			tt.buildStubs(&stubs)
+
+			u := NewAccountManagementUsecase(stubs.mockAccRepo, stubs.mockAuthorities, &config.Root{})
+			if err := u.UpdateAccountStatus(context.TODO(), tt.args.actor, tt.args.username, tt.args.status); err != tt.wantErr {
+				t.Errorf("AccountManagementUsecase.UpdateAccountStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func success_disable_account(stubs *commonStubsAccountManagement) {
+	stubs.mockAccRepo.EXPECT().FindAccountByUsername(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+	).Return(&model.Account{Username: "unittest", Role: model.ACCOUNT_ROLE_GENERAL}, nil)
+
+	stubs.mockAccRepo.EXPECT().UpdateAccountStatus(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+		gomock.Eq(model.ACCOUNT_STATUS_DISABLED),
+	).Return(nil)
+
+	stubs.mockAuthorities.EXPECT().RevokeAllSessions(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+		gomock.Eq("admin"),
+	).Return(nil)
+}
+
+func success_enable_account(stubs *commonStubsAccountManagement) {
+	stubs.mockAccRepo.EXPECT().FindAccountByUsername(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+	).Return(&model.Account{Username: "unittest", Role: model.ACCOUNT_ROLE_GENERAL, Status: model.ACCOUNT_STATUS_DISABLED}, nil)
+
+	stubs.mockAccRepo.EXPECT().UpdateAccountStatus(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+		gomock.Eq(model.ACCOUNT_STATUS_ACTIVE),
+	).Return(nil)
+}
+
+// ======================================================================
+// TestAccountManagementUsecase_ChangePassword
+// ======================================================================
+func TestAccountManagementUsecase_ChangePassword(t *testing.T) {
+
+	type args struct {
+		oldPassword     string
+		newPassword     string
+		confirmPassword string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		buildStubs func(*commonStubsAccountManagement)
+		wantErr    error
+	}{
+		{
+			name: "success",
+			args: args{
+				oldPassword:     "unittestsuccess",
+				newPassword:     "newpassword",
+				confirmPassword: "newpassword",
+			},
+			buildStubs: success_change_password,
+			wantErr:    nil,
+		},
+		{
+			name: "confirm_password_not_match",
+			args: args{
+				oldPassword:     "unittestsuccess",
+				newPassword:     "newpassword",
+				confirmPassword: "otherpassword",
+			},
+			buildStubs: func(stubs *commonStubsAccountManagement) {},
+			wantErr:    ErrorAccountManagementConfirmPasswordNotMatch,
+		},
+		{
+			name: "password_not_qualify",
+			args: args{
+				oldPassword:     "unittestsuccess",
+				newPassword:     "password123",
+				confirmPassword: "password123",
+			},
+			buildStubs: func(stubs *commonStubsAccountManagement) {},
+			wantErr:    ErrorAccountManagementPasswordNotQualify,
+		},
+		{
+			name: "invalid_old_password",
+			args: args{
+				oldPassword:     "wrongpassword",
+				newPassword:     "newpassword",
+				confirmPassword: "newpassword",
+			},
+			buildStubs: find_account_for_change_password,
+			wantErr:    ErrorAccountManagementInvalidPassword,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := commonStubsAccountManagement{
+				mockAccRepo:     mock_domain.NewMockAccountRepository(ctrl),
+				mockAuthorities: mock_domain.NewMockAuthorities(ctrl),
+			}
+
+			tt.buildStubs(&stubs)
+
+			u := NewAccountManagementUsecase(stubs.mockAccRepo, stubs.mockAuthorities, &config.Root{})
+			if err := u.ChangePassword(context.TODO(), "unittest", tt.args.oldPassword, tt.args.newPassword, tt.args.confirmPassword); err != tt.wantErr {
+				t.Errorf("AccountManagementUsecase.ChangePassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func find_account_for_change_password(stubs *commonStubsAccountManagement) {
+	stubs.mockAccRepo.EXPECT().FindAccountByUsername(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+	).Return(&model.Account{Username: "unittest", HashPassword: unittestHashPassword}, nil)
+}
+
+func success_change_password(stubs *commonStubsAccountManagement) {
+	find_account_for_change_password(stubs)
+
+	stubs.mockAccRepo.EXPECT().UpdateAccountPassword(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+		gomock.Any(),
+	).Return(nil)
+}
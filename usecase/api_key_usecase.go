@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"spider-go/domain"
+	"spider-go/logger"
+	"spider-go/model"
+	"spider-go/repository"
+	"spider-go/utils/cryptography"
+	"spider-go/utils/random"
+	"spider-go/utils/uuid"
+	"strings"
+	"time"
+)
+
+var (
+	ErrorAPIKeyInvalidData        = fmt.Errorf("[API Key Usecase]: invalid request data")
+	ErrorAPIKeyInsufficientRights = fmt.Errorf("[API Key Usecase]: cannot grant scope that actor does not have")
+	ErrorAPIKeyNotFound           = fmt.Errorf("[API Key Usecase]: api key not found")
+	ErrorAPIKeyInvalid            = domain.ErrorAPIKeyInvalid
+	ErrorAPIKeyGenerateFail       = fmt.Errorf("[API Key Usecase]: generate api key failed")
+	ErrorAPIKeyMongoConnection    = fmt.Errorf("[API Key Usecase]: mongo error")
+)
+
+const (
+	// structure of api key is spk_<key_id>.<secret>
+	API_KEY_PREFIX    = "spk_"
+	API_KEY_SEPARATOR = "."
+	API_KEY_SIZE      = 32
+
+	// last used time is not update every request for reduce write to mongo
+	API_KEY_LAST_USED_INTERVAL = time.Minute
+
+	// username of principal that authenticate by api key is api_key:<key_id>
+	API_KEY_USERNAME_FORMAT = "api_key:%s"
+)
+
+type APIKeyUsecase struct {
+	apiKeyRepo domain.APIKeyRepository
+	log        *logger.Logger
+}
+
+func NewAPIKeyUsecase(apiKeyRepo domain.APIKeyRepository) domain.APIKeyUsecase {
+	return &APIKeyUsecase{
+		apiKeyRepo: apiKeyRepo,
+		log:        logger.L().Named("APIKeyUsecase"),
+	}
+}
+
+// ========================================================
+// manage api key
+// ========================================================
+
+// CreateAPIKey return raw key that must show to user once, only hash of secret is saved
+func (u *APIKeyUsecase) CreateAPIKey(ctx context.Context, actor *model.LoginUser, name string, scopes []string, expiresAt time.Time) (string, *model.APIKey, error) {
+	log := u.log.WithContext(ctx)
+
+	if name == "" || len(scopes) == 0 || !expiresAt.After(time.Now()) {
+		log.Errorf("[CreateAPIKey] invalid name `%v`, scopes %v or expires at %v", name, scopes, expiresAt)
+		return "", nil, ErrorAPIKeyInvalidData
+	}
+
+	for _, scope := range scopes {
+		if !isAPIKeyScope(scope) {
+			log.Errorf("[CreateAPIKey] scope `%v` cannot grant to api key", scope)
+			return "", nil, ErrorAPIKeyInvalidData
+		}
+		if !actor.HasPermission(scope) {
+			log.Warnf("[CreateAPIKey] user `%v` does not have scope `%v`", actor.Username, scope)
+			return "", nil, ErrorAPIKeyInsufficientRights
+		}
+	}
+
+	secret, err := random.NewRandom().RandomToken(API_KEY_SIZE)
+	if err != nil {
+		log.Errorf("[CreateAPIKey] generate secret error: %+v", err)
+		return "", nil, ErrorAPIKeyGenerateFail
+	}
+
+	keyID := uuid.GernerateUUID32()
+
+	apiKey := model.APIKey{
+		KeyID:     keyID,
+		Name:      name,
+		KeyHash:   cryptography.NewCrypto().HashSHA256(secret),
+		Scopes:    scopes,
+		CreatedBy: actor.Username,
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}
+
+	if err := u.apiKeyRepo.InsertAPIKey(ctx, apiKey); err != nil {
+		log.Errorf("[CreateAPIKey] insert api key error: %+v", err)
+		return "", nil, ErrorAPIKeyMongoConnection
+	}
+
+	log.Infof("[CreateAPIKey] api key `%v` (%v) with scopes %v is created by `%v`", keyID, name, scopes, actor.Username)
+
+	apiKey.KeyHash = ""
+
+	return API_KEY_PREFIX + keyID + API_KEY_SEPARATOR + secret, &apiKey, nil
+}
+
+func (u *APIKeyUsecase) GetAPIKeyList(ctx context.Context, page, size int32) ([]model.APIKey, int64, error) {
+	log := u.log.WithContext(ctx)
+
+	apiKeyList, total, err := u.apiKeyRepo.FindAPIKeyList(ctx, page, size)
+	if err != nil {
+		log.Errorf("[GetAPIKeyList] find api key list error: %+v", err)
+		return nil, 0, ErrorAPIKeyMongoConnection
+	}
+
+	return apiKeyList, total, nil
+}
+
+func (u *APIKeyUsecase) RevokeAPIKey(ctx context.Context, actor *model.LoginUser, keyID string) error {
+	log := u.log.WithContext(ctx)
+
+	if err := u.apiKeyRepo.RevokeAPIKey(ctx, keyID, actor.Username); err != nil {
+		log.Errorf("[RevokeAPIKey] revoke api key `%v` error: %+v", keyID, err)
+		if err == repository.ErrorMongoNotFound {
+			return ErrorAPIKeyNotFound
+		}
+		return ErrorAPIKeyMongoConnection
+	}
+
+	log.Infof("[RevokeAPIKey] api key `%v` is revoked by `%v`", keyID, actor.Username)
+
+	return nil
+}
+
+func isAPIKeyScope(scope string) bool {
+	for _, s := range model.API_KEY_SCOPES {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ********************************************************
+
+// ========================================================
+// authenticate by api key
+// ========================================================
+
+func (u *APIKeyUsecase) AuthenticateAPIKey(ctx context.Context, rawKey string) (*model.LoginUser, error) {
+	log := u.log.WithContext(ctx)
+
+	keyID, secret, found := strings.Cut(strings.TrimPrefix(rawKey, API_KEY_PREFIX), API_KEY_SEPARATOR)
+	if !strings.HasPrefix(rawKey, API_KEY_PREFIX) || !found || keyID == "" || secret == "" {
+		log.Errorf("[AuthenticateAPIKey] invalid api key format")
+		return nil, ErrorAPIKeyInvalid
+	}
+
+	apiKey, err := u.apiKeyRepo.FindAPIKeyByKeyID(ctx, keyID)
+	if err != nil {
+		log.Errorf("[AuthenticateAPIKey] find api key `%v` error: %+v", keyID, err)
+		if err == repository.ErrorMongoNotFound {
+			return nil, ErrorAPIKeyInvalid
+		}
+		return nil, ErrorAPIKeyMongoConnection
+	}
+
+	secretHash := cryptography.NewCrypto().HashSHA256(secret)
+
+	if subtle.ConstantTimeCompare([]byte(secretHash), []byte(apiKey.KeyHash)) != 1 {
+		log.Warnf("[AuthenticateAPIKey] secret of api key `%v` not match", keyID)
+		return nil, ErrorAPIKeyInvalid
+	}
+
+	now := time.Now()
+
+	if apiKey.Revoked || !now.Before(apiKey.ExpiresAt) {
+		log.Warnf("[AuthenticateAPIKey] api key `%v` is revoked or expired at %v", keyID, apiKey.ExpiresAt)
+		return nil, ErrorAPIKeyInvalid
+	}
+
+	if now.Sub(apiKey.LastUsedAt) >= API_KEY_LAST_USED_INTERVAL {
+		if err := u.apiKeyRepo.UpdateAPIKeyLastUsed(ctx, keyID, now); err != nil {
+			log.Errorf("[AuthenticateAPIKey] update last used of api key `%v` error: %+v", keyID, err)
+		}
+	}
+
+	return &model.LoginUser{
+		Username:  fmt.Sprintf(API_KEY_USERNAME_FORMAT, apiKey.KeyID),
+		APIKeyID:  apiKey.KeyID,
+		Scopes:    apiKey.Scopes,
+		ExpiresAt: apiKey.ExpiresAt.Unix(),
+	}, nil
+}
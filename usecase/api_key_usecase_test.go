@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"context"
+	mock_domain "spider-go/domain/mock"
+	"spider-go/model"
+	"spider-go/repository"
+	"spider-go/utils/cryptography"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+type commonStubsAPIKey struct {
+	mockAPIKeyRepo *mock_domain.MockAPIKeyRepository
+}
+
+const (
+	unittestAPIKeyID     = "7d1c0b0e-3b8f-4a7e-9e57-0c1f2a3b4c5d"
+	unittestAPIKeySecret = "secret"
+	unittestAPIKey       = API_KEY_PREFIX + unittestAPIKeyID + API_KEY_SEPARATOR + unittestAPIKeySecret
+)
+
+// ======================================================================
+// TestAPIKeyUsecase_CreateAPIKey
+// ======================================================================
+func TestAPIKeyUsecase_CreateAPIKey(t *testing.T) {
+
+	type args struct {
+		actor     *model.LoginUser
+		scopes    []string
+		expiresAt time.Time
+	}
+	tests := []struct {
+		name       string
+		args       args
+		buildStubs func(*commonStubsAPIKey)
+		wantErr    error
+	}{
+		{
+			name: "success",
+			args: args{
+				actor:     unittestAdmin,
+				scopes:    []string{model.PERMISSION_SPIDER_READ, model.PERMISSION_IMAGE_WRITE},
+				expiresAt: time.Now().Add(24 * time.Hour),
+			},
+			buildStubs: success_insert_api_key,
+			wantErr:    nil,
+		},
+		{
+			name: "manage_scope_cannot_grant",
+			args: args{
+				actor:     unittestAdmin,
+				scopes:    []string{model.PERMISSION_ACCOUNT_MANAGE},
+				expiresAt: time.Now().Add(24 * time.Hour),
+			},
+			buildStubs: func(stubs *commonStubsAPIKey) {},
+			wantErr:    ErrorAPIKeyInvalidData,
+		},
+		{
+			name: "actor_does_not_have_scope",
+			args: args{
+				actor:     &model.LoginUser{Username: "unittest", Role: model.ACCOUNT_ROLE_GENERAL},
+				scopes:    []string{model.PERMISSION_SPIDER_DELETE},
+				expiresAt: time.Now().Add(24 * time.Hour),
+			},
+			buildStubs: func(stubs *commonStubsAPIKey) {},
+			wantErr:    ErrorAPIKeyInsufficientRights,
+		},
+		{
+			name: "expires_at_in_the_past",
+			args: args{
+				actor:     unittestAdmin,
+				scopes:    []string{model.PERMISSION_SPIDER_READ},
+				expiresAt: time.Now().Add(-time.Hour),
+			},
+			buildStubs: func(stubs *commonStubsAPIKey) {},
+			wantErr:    ErrorAPIKeyInvalidData,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := commonStubsAPIKey{
+				mockAPIKeyRepo: mock_domain.NewMockAPIKeyRepository(ctrl),
+			}
+
+			tt.buildStubs(&stubs)
+
+			u := NewAPIKeyUsecase(stubs.mockAPIKeyRepo)
+			rawKey, apiKey, err := u.CreateAPIKey(context.TODO(), tt.args.actor, "import script", tt.args.scopes, tt.args.expiresAt)
+			if err != tt.wantErr {
+				t.Errorf("APIKeyUsecase.CreateAPIKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && (rawKey == "" || apiKey.KeyHash != "") {
+				t.Errorf("APIKeyUsecase.CreateAPIKey() raw key is empty or hash is returned, %+v", apiKey)
+			}
+		})
+	}
+}
+
+func success_insert_api_key(stubs *commonStubsAPIKey) {
+	stubs.mockAPIKeyRepo.EXPECT().InsertAPIKey(
+		gomock.Any(),
+		gomock.Any(),
+	).DoAndReturn(func(_ context.Context, apiKey model.APIKey) error {
+		if apiKey.KeyHash == "" || apiKey.CreatedBy != "admin" {
+			return ErrorAPIKeyMongoConnection
+		}
+		return nil
+	})
+}
+
+// ======================================================================
+// TestAPIKeyUsecase_AuthenticateAPIKey
+// ======================================================================
+func TestAPIKeyUsecase_AuthenticateAPIKey(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		rawKey     string
+		buildStubs func(*commonStubsAPIKey)
+		wantErr    error
+	}{
+		{
+			name:   "success_update_last_used",
+			rawKey: unittestAPIKey,
+			buildStubs: func(stubs *commonStubsAPIKey) {
+				stubAPIKey(stubs, model.APIKey{ExpiresAt: time.Now().Add(time.Hour)})
+
+				stubs.mockAPIKeyRepo.EXPECT().UpdateAPIKeyLastUsed(
+					gomock.Any(),
+					gomock.Eq(unittestAPIKeyID),
+					gomock.Any(),
+				).Return(nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name:   "success_recently_used_not_update",
+			rawKey: unittestAPIKey,
+			buildStubs: func(stubs *commonStubsAPIKey) {
+				stubAPIKey(stubs, model.APIKey{ExpiresAt: time.Now().Add(time.Hour), LastUsedAt: time.Now()})
+			},
+			wantErr: nil,
+		},
+		{
+			name:   "wrong_secret",
+			rawKey: API_KEY_PREFIX + unittestAPIKeyID + API_KEY_SEPARATOR + "wrong",
+			buildStubs: func(stubs *commonStubsAPIKey) {
+				stubAPIKey(stubs, model.APIKey{ExpiresAt: time.Now().Add(time.Hour)})
+			},
+			wantErr: ErrorAPIKeyInvalid,
+		},
+		{
+			name:   "expired",
+			rawKey: unittestAPIKey,
+			buildStubs: func(stubs *commonStubsAPIKey) {
+				stubAPIKey(stubs, model.APIKey{ExpiresAt: time.Now().Add(-time.Hour)})
+			},
+			wantErr: ErrorAPIKeyInvalid,
+		},
+		{
+			name:   "revoked",
+			rawKey: unittestAPIKey,
+			buildStubs: func(stubs *commonStubsAPIKey) {
+				stubAPIKey(stubs, model.APIKey{ExpiresAt: time.Now().Add(time.Hour), Revoked: true})
+			},
+			wantErr: ErrorAPIKeyInvalid,
+		},
+		{
+			name:   "key_not_found",
+			rawKey: unittestAPIKey,
+			buildStubs: func(stubs *commonStubsAPIKey) {
+				stubs.mockAPIKeyRepo.EXPECT().FindAPIKeyByKeyID(
+					gomock.Any(),
+					gomock.Eq(unittestAPIKeyID),
+				).Return(nil, repository.ErrorMongoNotFound)
+			},
+			wantErr: ErrorAPIKeyInvalid,
+		},
+		{
+			name:       "invalid_format",
+			rawKey:     unittestAPIKeyID,
+			buildStubs: func(stubs *commonStubsAPIKey) {},
+			wantErr:    ErrorAPIKeyInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := commonStubsAPIKey{
+				mockAPIKeyRepo: mock_domain.NewMockAPIKeyRepository(ctrl),
+			}
+
+			tt.buildStubs(&stubs)
+
+			u := NewAPIKeyUsecase(stubs.mockAPIKeyRepo)
+			loginUser, err := u.AuthenticateAPIKey(context.TODO(), tt.rawKey)
+			if err != tt.wantErr {
+				t.Errorf("APIKeyUsecase.AuthenticateAPIKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !loginUser.IsAPIKey() || !loginUser.HasPermission(model.PERMISSION_SPIDER_READ) || loginUser.HasPermission(model.PERMISSION_SPIDER_DELETE) {
+				t.Errorf("APIKeyUsecase.AuthenticateAPIKey() invalid principal %+v", loginUser)
+			}
+		})
+	}
+}
+
+// stubAPIKey return api key of unittestAPIKey with spider:read scope
+func stubAPIKey(stubs *commonStubsAPIKey, apiKey model.APIKey) {
+	apiKey.KeyID = unittestAPIKeyID
+	apiKey.KeyHash = cryptography.NewCrypto().HashSHA256(unittestAPIKeySecret)
+	apiKey.Scopes = []string{model.PERMISSION_SPIDER_READ}
+
+	stubs.mockAPIKeyRepo.EXPECT().FindAPIKeyByKeyID(
+		gomock.Any(),
+		gomock.Eq(unittestAPIKeyID),
+	).Return(&apiKey, nil)
+}
@@ -3,10 +3,20 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"spider-go/config"
 	"spider-go/domain"
 	"spider-go/logger"
 	"spider-go/model"
+	"spider-go/repository"
 	"spider-go/utils/cryptography"
+	"spider-go/utils/policy"
+	"spider-go/utils/random"
+	"spider-go/utils/totp"
+	"spider-go/utils/uuid"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slices"
 )
 
 var (
@@ -18,19 +28,51 @@ var (
 	ErrorAuthoritiesInvalidPassword          = fmt.Errorf("[Authorities Usecase]: invalid password")
 	ErrorAuthoritiesTempDataConnection       = fmt.Errorf("[Authorities Usecase]: redis error")
 	ErrorAuthoritiesGenerateTokenFail        = fmt.Errorf("[Authorities Usecase]: generate jwt token failed")
+	ErrorAuthoritiesInvalidRefreshToken      = fmt.Errorf("[Authorities Usecase]: refresh token is invalid or expired")
+	ErrorAuthoritiesRefreshTokenReused       = fmt.Errorf("[Authorities Usecase]: refresh token is reused, session family is revoked")
+	ErrorAuthoritiesTokenRevoked             = domain.ErrorTokenRevoked
+	ErrorAuthoritiesAccountDisabled          = fmt.Errorf("[Authorities Usecase]: account is disabled")
+	ErrorAuthoritiesAccountLocked            = fmt.Errorf("[Authorities Usecase]: too many failed login, account is temporary locked")
+	ErrorAuthoritiesPasswordNotQualify       = fmt.Errorf("[Authorities Usecase]: password does not qualify")
+	ErrorAuthoritiesUsernameNotQualify       = fmt.Errorf("[Authorities Usecase]: username does not qualify")
+	ErrorAuthoritiesInvalidMFAToken          = fmt.Errorf("[Authorities Usecase]: mfa token is invalid or expired, please login")
+	ErrorAuthoritiesInvalidMFACode           = fmt.Errorf("[Authorities Usecase]: invalid second factor code")
+	ErrorAuthoritiesGenerateMFAFail          = fmt.Errorf("[Authorities Usecase]: generate mfa challenge failed")
+)
+
+const (
+	REFRESH_TOKEN_SIZE      = 32
+	REFRESH_TOKEN_SEPARATOR = "."
+	MFA_TOKEN_SIZE          = 32
+
+	// latest rotated out refresh token that is kept for reuse detection
+	REFRESH_TOKEN_ROTATED_LIMIT = 100
 )
 
 type Authorities struct {
-	accRepo    domain.AccountRepository
-	JWTService domain.JWTService
-	log        *logger.Logger
+	accRepo          domain.AccountRepository
+	redisRepo        domain.RedisRepository
+	JWTService       domain.JWTService
+	credentialPolicy *policy.CredentialPolicy
+	passwordHasher   cryptography.PasswordHasher
+	config           *config.Root
+	log              *logger.Logger
 }
 
-func NewAuthoritiesUsecase(accRepo domain.AccountRepository, JWTService domain.JWTService) domain.Authorities {
+func NewAuthoritiesUsecase(
+	accRepo domain.AccountRepository,
+	redisRepo domain.RedisRepository,
+	JWTService domain.JWTService,
+	conf *config.Root,
+) domain.Authorities {
 	return &Authorities{
-		accRepo:    accRepo,
-		JWTService: JWTService,
-		log:        logger.L().Named("CreateAccountUsecase"),
+		accRepo:          accRepo,
+		redisRepo:        redisRepo,
+		JWTService:       JWTService,
+		credentialPolicy: policy.NewCredentialPolicy(conf.CredentialPolicy),
+		passwordHasher:   cryptography.NewPasswordHasher(conf.PasswordHash),
+		config:           conf,
+		log:              logger.L().Named("CreateAccountUsecase"),
 	}
 }
 
@@ -44,12 +86,20 @@ func (u *Authorities) CreateAccout(ctx context.Context, data model.Account, pass
 		return ErrorAuthoritiesConfirmPasswordNotMatch
 	}
 
+	if err := u.credentialPolicy.ValidateUsername(data.Username); err != nil {
+		log.Errorf("username `%v` does not qualify: %+v", data.Username, err)
+		return ErrorAuthoritiesUsernameNotQualify
+	}
+
+	if err := u.credentialPolicy.ValidatePassword(data.Username, password); err != nil {
+		log.Errorf("password of username `%v` does not qualify: %+v", data.Username, err)
+		return ErrorAuthoritiesPasswordNotQualify
+	}
+
 	// ==========================================================
 	// hashing password
 	// ==========================================================
-	cryptoFunc := cryptography.NewCrypto()
-
-	hashPass, err := cryptoFunc.HashPassword(ctx, password)
+	hashPass, err := u.passwordHasher.Hash(password)
 	if err != nil {
 		log.Errorf("hashing password error: %+v", err)
 		return ErrorAuthoritiesHashPasswordFail
@@ -57,6 +107,13 @@ func (u *Authorities) CreateAccout(ctx context.Context, data model.Account, pass
 
 	data.HashPassword = hashPass
 
+	// new account always start as general user, higher role is granted by account management
+	now := time.Now()
+	data.Role = model.ACCOUNT_ROLE_GENERAL
+	data.Status = model.ACCOUNT_STATUS_ACTIVE
+	data.CreatedAt = now
+	data.UpdatedAt = now
+
 	// ==========================================================
 	// save data to mongo
 	// ==========================================================
@@ -68,29 +125,623 @@ func (u *Authorities) CreateAccout(ctx context.Context, data model.Account, pass
 	return nil
 }
 
-func (u *Authorities) Login(ctx context.Context, username, password string) (accountInfo *model.Account, token string, err error) {
+func (u *Authorities) Login(ctx context.Context, username, password, clientIP string) (accountInfo *model.Account, tokenPair *model.TokenPair, challenge *model.MFAChallenge, err error) {
 	log := u.log.WithContext(ctx)
 
+	// =======================================================
+	// brute-force protection
+	// =======================================================
+	attempt, err := u.getLoginAttempt(ctx, username, clientIP)
+	if err != nil {
+		return nil, nil, nil, ErrorAuthoritiesTempDataConnection
+	}
+
+	if attempt.isLocked(u.config.LoginProtection) {
+		log.Warnf("login of username `%v` from ip `%v` is locked, attempt: %+v", username, clientIP, attempt)
+		return nil, nil, nil, ErrorAuthoritiesAccountLocked
+	}
+
 	accountInfo, err = u.accRepo.FindAccountByUsername(ctx, username)
 	if err != nil {
-		if err.Error() == MONGO_NOT_FOUND {
+		if err == repository.ErrorMongoNotFound {
 			log.Errorf("find account by username %v, error not found", username)
-			return nil, "", ErrorAuthoritiesFindAccountNotFound
+			u.recordFailedLogin(ctx, attempt)
+			return nil, nil, nil, ErrorAuthoritiesFindAccountNotFound
 		}
 		log.Errorf("find account by username %v, but error: %+v", username, err)
-		return nil, "", ErrorAuthoritiesMongoConnection
+		return nil, nil, nil, ErrorAuthoritiesMongoConnection
+	}
+
+	match, err := u.passwordHasher.Verify(password, accountInfo.HashPassword)
+	if err != nil {
+		log.Errorf("verify password hash of username %v error: %+v", username, err)
+	}
+
+	if !match {
+		u.recordFailedLogin(ctx, attempt)
+		return nil, nil, nil, ErrorAuthoritiesInvalidPassword
+	}
+
+	u.resetFailedLogin(ctx, attempt)
+	u.rehashPassword(ctx, accountInfo, password)
+
+	if accountInfo.IsDisabled() {
+		log.Warnf("account `%v` is disabled", username)
+		return nil, nil, nil, ErrorAuthoritiesAccountDisabled
+	}
+
+	// =======================================================
+	// second factor is verified by VerifyMFA with mfa token
+	// =======================================================
+	if accountInfo.MFAEnabled() || mfaEnforced(u.config.MFA, accountInfo.Role) {
+		challenge, err = u.createMFAChallenge(ctx, accountInfo)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return accountInfo, nil, challenge, nil
+	}
+
+	// =======================================================
+	// start new session family
+	// =======================================================
+	familyID := uuid.GernerateUUID32()
+
+	session := model.Login{
+		Username:  accountInfo.Username,
+		Role:      accountInfo.Role,
+		FamilyID:  familyID,
+		CreatedAt: time.Now(),
+	}
+
+	tokenPair, err = u.issueTokenPair(ctx, session)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return accountInfo, tokenPair, nil, nil
+}
+
+// VerifyMFA complete login that wait for second factor, failed code is counted as failed login.
+// account that enroll at this login is activated by first valid code and get recovery codes
+func (u *Authorities) VerifyMFA(ctx context.Context, mfaToken, code, clientIP string) (accountInfo *model.Account, tokenPair *model.TokenPair, recoveryCodes []string, err error) {
+	log := u.log.WithContext(ctx)
+
+	pendingKey := fmt.Sprintf(u.config.RedisOption.MFAPending.KeyFormat, cryptography.NewCrypto().HashSHA256(mfaToken))
+
+	var pending model.MFAPending
+	if err := u.redisRepo.GetDataFromRedis(ctx, pendingKey, &pending); err != nil {
+		if err == repository.ErrorRedisNotFound {
+			log.Warnf("[VerifyMFA] mfa token is invalid or expired")
+			return nil, nil, nil, ErrorAuthoritiesInvalidMFAToken
+		}
+		log.Errorf("[VerifyMFA] get mfa pending error: %+v", err)
+		return nil, nil, nil, ErrorAuthoritiesTempDataConnection
+	}
+
+	attempt, err := u.getLoginAttempt(ctx, pending.Username, clientIP)
+	if err != nil {
+		return nil, nil, nil, ErrorAuthoritiesTempDataConnection
+	}
+
+	if attempt.isLocked(u.config.LoginProtection) {
+		log.Warnf("[VerifyMFA] login of username `%v` from ip `%v` is locked, attempt: %+v", pending.Username, clientIP, attempt)
+		return nil, nil, nil, ErrorAuthoritiesAccountLocked
+	}
+
+	accountInfo, err = u.accRepo.FindAccountByUsername(ctx, pending.Username)
+	if err != nil {
+		log.Errorf("[VerifyMFA] find account by username %v, but error: %+v", pending.Username, err)
+		if err == repository.ErrorMongoNotFound {
+			return nil, nil, nil, ErrorAuthoritiesInvalidMFAToken
+		}
+		return nil, nil, nil, ErrorAuthoritiesMongoConnection
+	}
+
+	if accountInfo.IsDisabled() {
+		log.Warnf("[VerifyMFA] account `%v` is disabled", pending.Username)
+		return nil, nil, nil, ErrorAuthoritiesAccountDisabled
+	}
+
+	if pending.EnrollSecret != "" {
+		step, ok := totp.Validate(pending.EnrollSecret, code, time.Now())
+		if !ok {
+			u.recordFailedLogin(ctx, attempt)
+			return nil, nil, nil, ErrorAuthoritiesInvalidMFACode
+		}
+
+		recoveryCodes, err = enableMFA(ctx, u.accRepo, accountInfo.Username, pending.EnrollSecret, step)
+		if err != nil {
+			log.Errorf("[VerifyMFA] enable second factor of `%v` error: %+v", pending.Username, err)
+			return nil, nil, nil, ErrorAuthoritiesMongoConnection
+		}
+	} else if err := verifySecondFactor(ctx, u.accRepo, accountInfo, code); err != nil {
+		log.Warnf("[VerifyMFA] verify second factor of `%v` error: %+v", pending.Username, err)
+		if err == ErrorMFAMongoConnection {
+			return nil, nil, nil, ErrorAuthoritiesMongoConnection
+		}
+		u.recordFailedLogin(ctx, attempt)
+		return nil, nil, nil, ErrorAuthoritiesInvalidMFACode
+	}
+
+	// mfa token is single use
+	if err := u.redisRepo.GetAndDeleteDataFromRedis(ctx, pendingKey, &pending); err != nil {
+		log.Warnf("[VerifyMFA] mfa token of `%v` is already used, error: %+v", pending.Username, err)
+		return nil, nil, nil, ErrorAuthoritiesInvalidMFAToken
+	}
+
+	u.resetFailedLogin(ctx, attempt)
+
+	session := model.Login{
+		Username:  accountInfo.Username,
+		Role:      accountInfo.Role,
+		FamilyID:  uuid.GernerateUUID32(),
+		CreatedAt: time.Now(),
+	}
+
+	tokenPair, err = u.issueTokenPair(ctx, session)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return accountInfo, tokenPair, recoveryCodes, nil
+}
+
+// createMFAChallenge save login that wait for second factor, account of enforced role that not enrolled
+// get new secret for enroll at this login
+func (u *Authorities) createMFAChallenge(ctx context.Context, accountInfo *model.Account) (*model.MFAChallenge, error) {
+	log := u.log.WithContext(ctx)
+
+	mfaToken, err := random.NewRandom().RandomToken(MFA_TOKEN_SIZE)
+	if err != nil {
+		log.Errorf("[createMFAChallenge] generate mfa token error: %+v", err)
+		return nil, ErrorAuthoritiesGenerateMFAFail
+	}
+
+	now := time.Now()
+
+	pending := model.MFAPending{
+		Username:  accountInfo.Username,
+		CreatedAt: now,
+	}
+
+	challenge := &model.MFAChallenge{
+		Token:     mfaToken,
+		ExpiresAt: now.Add(u.config.RedisOption.MFAPending.TTL),
+	}
+
+	if !accountInfo.MFAEnabled() {
+		pending.EnrollSecret, err = totp.GenerateSecret()
+		if err != nil {
+			log.Errorf("[createMFAChallenge] generate secret error: %+v", err)
+			return nil, ErrorAuthoritiesGenerateMFAFail
+		}
+		challenge.EnrollURI = totp.URI(mfaIssuer(u.config.MFA), accountInfo.Username, pending.EnrollSecret)
+	}
+
+	pendingKey := fmt.Sprintf(u.config.RedisOption.MFAPending.KeyFormat, cryptography.NewCrypto().HashSHA256(mfaToken))
+
+	if err := u.redisRepo.SetDataToRedisWithTTL(ctx, pendingKey, pending, u.config.RedisOption.MFAPending.TTL); err != nil {
+		log.Errorf("[createMFAChallenge] save mfa pending of `%v` error: %+v", accountInfo.Username, err)
+		return nil, ErrorAuthoritiesTempDataConnection
+	}
+
+	return challenge, nil
+}
+
+// rehashPassword upgrade hash that created by old algorithm or parameter,
+// plain password is known only after verify success so upgrade can do only at login
+func (u *Authorities) rehashPassword(ctx context.Context, accountInfo *model.Account, password string) {
+	log := u.log.WithContext(ctx)
+
+	if !u.passwordHasher.NeedsRehash(accountInfo.HashPassword) {
+		return
+	}
+
+	hashPass, err := u.passwordHasher.Hash(password)
+	if err != nil {
+		log.Errorf("[rehashPassword] hashing password of username %v error: %+v", accountInfo.Username, err)
+		return
+	}
+
+	// login still success when upgrade fail, it will retry at next login
+	if err := u.accRepo.UpdateAccountPassword(ctx, accountInfo.Username, hashPass); err != nil {
+		log.Errorf("[rehashPassword] update password hash of username %v error: %+v", accountInfo.Username, err)
+		return
+	}
+
+	accountInfo.HashPassword = hashPass
+	log.Infof("[rehashPassword] password hash of username %v is upgraded", accountInfo.Username)
+}
+
+// ========================================================
+// brute-force protection of login
+// ========================================================
+
+type loginAttempt struct {
+	userKey   string
+	ipKey     string
+	userCount int64
+	ipCount   int64
+}
+
+func (a loginAttempt) isLocked(conf config.LoginProtection) bool {
+	if conf.MaxUserAttempts > 0 && a.userCount >= conf.MaxUserAttempts {
+		return true
+	}
+	return conf.MaxIPAttempts > 0 && a.ipCount >= conf.MaxIPAttempts
+}
+
+// getLoginAttempt read failed login counter of username and client ip, counter that disabled is zero
+func (u *Authorities) getLoginAttempt(ctx context.Context, username, clientIP string) (attempt loginAttempt, err error) {
+	log := u.log.WithContext(ctx)
+
+	conf := u.config.LoginProtection
+
+	if conf.MaxUserAttempts > 0 {
+		attempt.userKey = fmt.Sprintf(u.config.RedisOption.LoginAttemptUser.KeyFormat, username)
+		if attempt.userCount, err = u.redisRepo.GetCounter(ctx, attempt.userKey); err != nil {
+			log.Errorf("[getLoginAttempt] get failed login counter of username `%v` error: %+v", username, err)
+			return attempt, err
+		}
+	}
+
+	if conf.MaxIPAttempts > 0 && clientIP != "" {
+		attempt.ipKey = fmt.Sprintf(u.config.RedisOption.LoginAttemptIP.KeyFormat, clientIP)
+		if attempt.ipCount, err = u.redisRepo.GetCounter(ctx, attempt.ipKey); err != nil {
+			log.Errorf("[getLoginAttempt] get failed login counter of ip `%v` error: %+v", clientIP, err)
+			return attempt, err
+		}
+	}
+
+	return attempt, nil
+}
+
+// recordFailedLogin increase failed counter, lock when reach max attempts and delay response progressively
+func (u *Authorities) recordFailedLogin(ctx context.Context, attempt loginAttempt) {
+	log := u.log.WithContext(ctx)
+
+	conf := u.config.LoginProtection
+
+	if attempt.userKey != "" {
+		ttl := u.config.RedisOption.LoginAttemptUser.TTL
+		if attempt.userCount+1 >= conf.MaxUserAttempts {
+			ttl = conf.LockoutDuration
+		}
+
+		count, err := u.redisRepo.IncreaseCounter(ctx, attempt.userKey, ttl)
+		if err != nil {
+			log.Errorf("[recordFailedLogin] increase counter `%v` error: %+v", attempt.userKey, err)
+		} else {
+			attempt.userCount = count
+		}
+	}
+
+	if attempt.ipKey != "" {
+		ttl := u.config.RedisOption.LoginAttemptIP.TTL
+		if attempt.ipCount+1 >= conf.MaxIPAttempts {
+			ttl = conf.LockoutDuration
+		}
+
+		count, err := u.redisRepo.IncreaseCounter(ctx, attempt.ipKey, ttl)
+		if err != nil {
+			log.Errorf("[recordFailedLogin] increase counter `%v` error: %+v", attempt.ipKey, err)
+		} else {
+			attempt.ipCount = count
+		}
+	}
+
+	failedCount := attempt.userCount
+	if attempt.ipCount > failedCount {
+		failedCount = attempt.ipCount
+	}
+
+	delay := loginDelay(conf, failedCount)
+	if delay <= 0 {
+		return
+	}
+
+	log.Warnf("[recordFailedLogin] failed login %v times, delay response %v", failedCount, delay)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// resetFailedLogin clear failed counter of username, counter of ip is keep for protect credential stuffing
+func (u *Authorities) resetFailedLogin(ctx context.Context, attempt loginAttempt) {
+	if attempt.userKey == "" || attempt.userCount == 0 {
+		return
+	}
+
+	if err := u.redisRepo.DeleteDataFromRedis(ctx, attempt.userKey); err != nil {
+		u.log.WithContext(ctx).Errorf("[resetFailedLogin] remove counter `%v` error: %+v", attempt.userKey, err)
+	}
+}
+
+// loginDelay double delay for every failed attempt after DelayAfter and not over MaxDelay
+func loginDelay(conf config.LoginProtection, failedCount int64) time.Duration {
+	if conf.BaseDelay <= 0 || failedCount <= conf.DelayAfter {
+		return 0
+	}
+
+	delay := conf.BaseDelay
+	for i := conf.DelayAfter + 1; i < failedCount; i++ {
+		delay *= 2
+		if conf.MaxDelay > 0 && delay >= conf.MaxDelay {
+			return conf.MaxDelay
+		}
+	}
+
+	if conf.MaxDelay > 0 && delay > conf.MaxDelay {
+		return conf.MaxDelay
+	}
+
+	return delay
+}
+
+// ********************************************************
+
+// ========================================================
+// refresh token with rotation
+// ========================================================
+
+func (u *Authorities) RefreshToken(ctx context.Context, refreshToken string) (tokenPair *model.TokenPair, err error) {
+	log := u.log.WithContext(ctx)
+
+	// structure refresh token
+	// <family_id>.<secret>
+	familyID, _, found := strings.Cut(refreshToken, REFRESH_TOKEN_SEPARATOR)
+	if !found || familyID == "" {
+		log.Errorf("[RefreshToken] invalid refresh token format")
+		return nil, ErrorAuthoritiesInvalidRefreshToken
+	}
+
+	sessionKey := u.sessionKey(familyID)
+
+	var session model.Login
+
+	if err := u.redisRepo.GetDataFromRedis(ctx, sessionKey, &session); err != nil {
+		log.Errorf("[RefreshToken] get session family `%v` from redis error: %+v", familyID, err)
+		if err == repository.ErrorRedisNotFound {
+			return nil, ErrorAuthoritiesInvalidRefreshToken
+		}
+		return nil, ErrorAuthoritiesTempDataConnection
+	}
+
+	tokenHash := cryptography.NewCrypto().HashSHA256(refreshToken)
+
+	if tokenHash != session.TokenHash {
+		// token of this family that already rotated out is used again, someone may steal it
+		if slices.Contains(session.RotatedTokenHashes, tokenHash) {
+			log.Warnf("[RefreshToken] refresh token reused, revoke session family `%v` of user `%v`", familyID, session.Username)
+			if err := u.redisRepo.DeleteDataFromRedis(ctx, sessionKey); err != nil {
+				log.Errorf("[RefreshToken] revoke session family `%v` error: %+v", familyID, err)
+				return nil, ErrorAuthoritiesTempDataConnection
+			}
+			return nil, ErrorAuthoritiesRefreshTokenReused
+		}
+
+		// secret that never issued does not prove that token is stolen, family is kept
+		log.Errorf("[RefreshToken] unknown refresh token of session family `%v`", familyID)
+		return nil, ErrorAuthoritiesInvalidRefreshToken
+	}
+
+	isRevoked, err := u.isRevokedUser(ctx, session.Username, session.CreatedAt)
+	if err != nil {
+		return nil, ErrorAuthoritiesTempDataConnection
+	}
+
+	if isRevoked {
+		log.Warnf("[RefreshToken] all sessions of user `%v` is revoked, remove session family `%v`", session.Username, familyID)
+		if err := u.redisRepo.DeleteDataFromRedis(ctx, sessionKey); err != nil {
+			log.Errorf("[RefreshToken] remove session family `%v` error: %+v", familyID, err)
+		}
+		return nil, ErrorAuthoritiesInvalidRefreshToken
+	}
+
+	// read account again for get current role
+	accountInfo, err := u.accRepo.FindAccountByUsername(ctx, session.Username)
+	if err != nil {
+		log.Errorf("[RefreshToken] find account by username %v, error: %+v", session.Username, err)
+		if err == repository.ErrorMongoNotFound {
+			return nil, ErrorAuthoritiesInvalidRefreshToken
+		}
+		return nil, ErrorAuthoritiesMongoConnection
 	}
 
-	crypto := cryptography.NewCrypto()
+	if accountInfo.IsDisabled() {
+		log.Warnf("[RefreshToken] account `%v` is disabled, remove session family `%v`", session.Username, familyID)
+		if err := u.redisRepo.DeleteDataFromRedis(ctx, sessionKey); err != nil {
+			log.Errorf("[RefreshToken] remove session family `%v` error: %+v", familyID, err)
+		}
+		return nil, ErrorAuthoritiesAccountDisabled
+	}
+
+	session.Role = accountInfo.Role
+
+	return u.issueTokenPair(ctx, session)
+}
+
+// issueTokenPair create new access token and rotate refresh token of session family
+func (u *Authorities) issueTokenPair(ctx context.Context, session model.Login) (*model.TokenPair, error) {
+	log := u.log.WithContext(ctx)
+
+	// session family is not extend when rotate
+	ttl := time.Until(session.CreatedAt.Add(u.config.RedisOption.Login.TTL))
+	if ttl <= 0 {
+		log.Errorf("[issueTokenPair] session family `%v` is expired", session.FamilyID)
+		return nil, ErrorAuthoritiesInvalidRefreshToken
+	}
 
-	if !crypto.CheckPasswordHash(password, accountInfo.HashPassword) {
-		return nil, "", ErrorAuthoritiesInvalidPassword
+	accessToken, err := u.JWTService.GenerateNewToken(session.Username, session.Role, session.FamilyID)
+	if err != nil {
+		log.Errorf("[issueTokenPair] generate access token error: %+v", err)
+		return nil, ErrorAuthoritiesGenerateTokenFail
 	}
 
-	token, err = u.JWTService.GenerateNewToken(accountInfo.Username, accountInfo.Role)
+	secret, err := random.NewRandom().RandomToken(REFRESH_TOKEN_SIZE)
 	if err != nil {
-		return nil, "", ErrorAuthoritiesGenerateTokenFail
+		log.Errorf("[issueTokenPair] generate refresh token error: %+v", err)
+		return nil, ErrorAuthoritiesGenerateTokenFail
+	}
+
+	refreshToken := session.FamilyID + REFRESH_TOKEN_SEPARATOR + secret
+	previousTokenHash := session.TokenHash
+
+	// first token of family has no previous token
+	if previousTokenHash != "" {
+		session.RotatedTokenHashes = append(session.RotatedTokenHashes, previousTokenHash)
+		if len(session.RotatedTokenHashes) > REFRESH_TOKEN_ROTATED_LIMIT {
+			session.RotatedTokenHashes = session.RotatedTokenHashes[len(session.RotatedTokenHashes)-REFRESH_TOKEN_ROTATED_LIMIT:]
+		}
+	}
+
+	session.TokenHash = cryptography.NewCrypto().HashSHA256(refreshToken)
+
+	sessionKey := u.sessionKey(session.FamilyID)
+
+	if previousTokenHash == "" {
+		err = u.redisRepo.SetDataToRedisWithTTL(ctx, sessionKey, session, ttl)
+	} else {
+		// rotate only when presented token is still current token of family
+		err = u.redisRepo.CompareAndSetDataToRedis(ctx, sessionKey, "token_hash", previousTokenHash, session, ttl)
+	}
+
+	if err == repository.ErrorRedisConflict {
+		// same refresh token is rotated by another request first, one of them may use stolen token
+		log.Warnf("[issueTokenPair] refresh token is already rotated, revoke session family `%v` of user `%v`", session.FamilyID, session.Username)
+		if err := u.redisRepo.DeleteDataFromRedis(ctx, sessionKey); err != nil {
+			log.Errorf("[issueTokenPair] revoke session family `%v` error: %+v", session.FamilyID, err)
+			return nil, ErrorAuthoritiesTempDataConnection
+		}
+		return nil, ErrorAuthoritiesRefreshTokenReused
+	}
+	if err != nil {
+		log.Errorf("[issueTokenPair] save session family `%v` to redis error: %+v", session.FamilyID, err)
+		return nil, ErrorAuthoritiesTempDataConnection
+	}
+
+	return &model.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (u *Authorities) sessionKey(familyID string) string {
+	return fmt.Sprintf(u.config.RedisOption.Login.KeyFormat, familyID)
+}
+
+// ********************************************************
+
+// ========================================================
+// session revocation
+// ========================================================
+
+// ValidateSession check access token is not in denylist and user is not revoke all sessions after token issued
+func (u *Authorities) ValidateSession(ctx context.Context, loginUser *model.LoginUser) error {
+	log := u.log.WithContext(ctx)
+
+	var revokedToken model.RevokedToken
+
+	denylistKey := fmt.Sprintf(u.config.RedisOption.TokenDenylist.KeyFormat, loginUser.TokenID)
+
+	err := u.redisRepo.GetDataFromRedis(ctx, denylistKey, &revokedToken)
+	if err == nil {
+		log.Warnf("[ValidateSession] token `%v` of user `%v` is revoked", loginUser.TokenID, loginUser.Username)
+		return ErrorAuthoritiesTokenRevoked
+	}
+
+	if err != repository.ErrorRedisNotFound {
+		log.Errorf("[ValidateSession] get token denylist error: %+v", err)
+		return ErrorAuthoritiesTempDataConnection
+	}
+
+	isRevoked, err := u.isRevokedUser(ctx, loginUser.Username, time.UnixMilli(loginUser.IssuedAt))
+	if err != nil {
+		return ErrorAuthoritiesTempDataConnection
+	}
+
+	if isRevoked {
+		log.Warnf("[ValidateSession] all sessions of user `%v` is revoked", loginUser.Username)
+		return ErrorAuthoritiesTokenRevoked
+	}
+
+	return nil
+}
+
+// Logout add access token to denylist until it expire and remove refresh token session family
+func (u *Authorities) Logout(ctx context.Context, loginUser *model.LoginUser) error {
+	log := u.log.WithContext(ctx)
+
+	ttl := time.Until(time.Unix(loginUser.ExpiresAt, 0))
+
+	if ttl > 0 {
+		revokedToken := model.RevokedToken{
+			Username:  loginUser.Username,
+			RevokedAt: time.Now(),
+		}
+
+		denylistKey := fmt.Sprintf(u.config.RedisOption.TokenDenylist.KeyFormat, loginUser.TokenID)
+
+		if err := u.redisRepo.SetDataToRedisWithTTL(ctx, denylistKey, revokedToken, ttl); err != nil {
+			log.Errorf("[Logout] add token `%v` to denylist error: %+v", loginUser.TokenID, err)
+			return ErrorAuthoritiesTempDataConnection
+		}
+	}
+
+	if loginUser.SessionID != "" {
+		if err := u.redisRepo.DeleteDataFromRedis(ctx, u.sessionKey(loginUser.SessionID)); err != nil {
+			log.Errorf("[Logout] remove session family `%v` error: %+v", loginUser.SessionID, err)
+			return ErrorAuthoritiesTempDataConnection
+		}
+	}
+
+	log.Infof("[Logout] user `%v` logout successful", loginUser.Username)
+
+	return nil
+}
+
+// RevokeAllSessions make every access token and refresh token of user that issued before now invalid
+func (u *Authorities) RevokeAllSessions(ctx context.Context, username, revokedBy string) error {
+	log := u.log.WithContext(ctx)
+
+	revokedUser := model.RevokedUser{
+		Username:  username,
+		RevokedBy: revokedBy,
+		RevokedAt: time.Now(),
+	}
+
+	revokeKey := fmt.Sprintf(u.config.RedisOption.RevokeUser.KeyFormat, username)
+
+	if err := u.redisRepo.SetDataToRedisWithTTL(ctx, revokeKey, revokedUser, u.config.RedisOption.RevokeUser.TTL); err != nil {
+		log.Errorf("[RevokeAllSessions] save revoke user `%v` error: %+v", username, err)
+		return ErrorAuthoritiesTempDataConnection
+	}
+
+	log.Infof("[RevokeAllSessions] all sessions of user `%v` is revoked by `%v`", username, revokedBy)
+
+	return nil
+}
+
+// isRevokedUser return true when user revoke all sessions at or after issuedAt
+func (u *Authorities) isRevokedUser(ctx context.Context, username string, issuedAt time.Time) (bool, error) {
+	log := u.log.WithContext(ctx)
+
+	var revokedUser model.RevokedUser
+
+	revokeKey := fmt.Sprintf(u.config.RedisOption.RevokeUser.KeyFormat, username)
+
+	if err := u.redisRepo.GetDataFromRedis(ctx, revokeKey, &revokedUser); err != nil {
+		if err == repository.ErrorRedisNotFound {
+			return false, nil
+		}
+		log.Errorf("[isRevokedUser] get revoke user `%v` error: %+v", username, err)
+		return false, err
 	}
 
-	return accountInfo, token, nil
+	return !issuedAt.After(revokedUser.RevokedAt), nil
 }
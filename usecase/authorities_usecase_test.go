@@ -1,105 +1,1110 @@
 package usecase
 
-// import (
-// 	"context"
-// 	"reflect"
-// 	mock_domain "spider-go/domain/mock"
-// 	"spider-go/model"
-// 	"testing"
-
-// 	"github.com/golang/mock/gomock"
-// )
-
-// func TestAuthorities_Login(t *testing.T) {
-
-// 	type args struct {
-// 		username string
-// 		password string
-// 	}
-// 	tests := []struct {
-// 		name            string
-// 		args            args
-// 		buildStubs      func(mock_domain.MockAccountRepository, mock_domain.MockRedisRepository)
-// 		wantAccountInfo *model.Account
-// 		wantErr         error
-// 	}{
-// 		// TODO: Add test cases.
-// 		{
-// 			name: "success",
-// 			args: args{
-// 				username: "unittest",
-// 				password: "unittestsuccess",
-// 			},
-// 			buildStubs: successLogin,
-// 			wantAccountInfo: &model.Account{
-// 				Username:     "unittest",
-// 				HashPassword: "$2a$10$jfEYdAp0ZjH4LSyBl1NMYuH97s4sbuEgiv1kRBxNqzBsBnuenRD8i",
-// 			},
-// 			wantErr: nil,
-// 		},
-// 		{
-// 			name: "invalid password",
-// 			args: args{
-// 				username: "unittest",
-// 				password: "nopassword",
-// 			},
-// 			buildStubs:      invalidPassword,
-// 			wantAccountInfo: nil,
-// 			wantErr:         ErrorAuthoritiesInvalidPassword,
-// 		},
-// 	}
-// 	for _, tt := range tests {
-// 		t.Run(tt.name, func(t *testing.T) {
-// 			ctrl := gomock.NewController(t)
-// 			defer ctrl.Finish()
-
-// 			mockAccountRepository := mock_domain.NewMockAccountRepository(ctrl)
-// 			mockRedisRepository := mock_domain.NewMockRedisRepository(ctrl)
-
-// 			tt.buildStubs(*mockAccountRepository, *mockRedisRepository)
-
-// 			usecase := NewAuthoritiesUsecase(mockAccountRepository, mockRedisRepository)
-// 			gotAccountInfo, _, err := usecase.Login(context.TODO(), tt.args.username, tt.args.password)
-// 			if err != tt.wantErr {
-// 				t.Errorf("Authorities.Login() error = %v, wantErr %v", err, tt.wantErr)
-// 				return
-// 			}
-// 			if !reflect.DeepEqual(gotAccountInfo, tt.wantAccountInfo) {
-// 				t.Errorf("Authorities.Login() = %v, want %v", gotAccountInfo, tt.wantAccountInfo)
-// 			}
-// 		})
-// 	}
-// }
-
-// func successLogin(mockAccountRepo mock_domain.MockAccountRepository, mockRedisRepo mock_domain.MockRedisRepository) {
-// 	mockAccountRepo.EXPECT().FindAccountByUsername(
-// 		gomock.Any(),
-// 		gomock.Eq("unittest"),
-// 	).Return(
-// 		&model.Account{
-// 			Username:     "unittest",
-// 			HashPassword: "$2a$10$jfEYdAp0ZjH4LSyBl1NMYuH97s4sbuEgiv1kRBxNqzBsBnuenRD8i",
-// 		},
-// 		nil,
-// 	)
-
-// 	mockRedisRepo.EXPECT().SetDataToRedisWithTTL(
-// 		gomock.Any(),
-// 		gomock.Any(),
-// 		gomock.Eq(model.Login{Username: "unittest"}),
-// 		gomock.Any(),
-// 	).Return(nil)
-// }
-
-// func invalidPassword(mockAccountRepo mock_domain.MockAccountRepository, mockRedisRepo mock_domain.MockRedisRepository) {
-// 	mockAccountRepo.EXPECT().FindAccountByUsername(
-// 		gomock.Any(),
-// 		gomock.Eq("unittest"),
-// 	).Return(
-// 		&model.Account{
-// 			Username:     "unittest",
-// 			HashPassword: "$2a$10$jfEYdAp0ZjH4LSyBl1NMYuH97s4sbuEgiv1kRBxNqzBsBnuenRD8i",
-// 		},
-// 		nil,
-// 	)
-// }
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"spider-go/config"
+	mock_domain "spider-go/domain/mock"
+	"spider-go/model"
+	"spider-go/repository"
+	"spider-go/utils/cryptography"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+type commonStubsAuthorities struct {
+	mockAccRepo    *mock_domain.MockAccountRepository
+	mockRedisRepo  *mock_domain.MockRedisRepository
+	mockJWTService *mock_domain.MockJWTService
+}
+
+// policy match unittestHashPassword so login does not rehash
+var unittestPasswordHash = config.PasswordHash{
+	Algorithm:  cryptography.PASSWORD_HASH_ALGORITHM_BCRYPT,
+	BcryptCost: 10,
+}
+
+var authoritiesConfig = &config.Root{
+	PasswordHash: unittestPasswordHash,
+	RedisOption: config.RedisOptions{
+		Login: config.RedisOption{
+			KeyFormat: "login_%s",
+			TTL:       time.Hour,
+		},
+		TokenDenylist: config.RedisOption{
+			KeyFormat: "token_denylist_%s",
+		},
+		RevokeUser: config.RedisOption{
+			KeyFormat: "revoke_user_%s",
+			TTL:       time.Hour,
+		},
+	},
+}
+
+const (
+	unittestHashPassword = "$2a$10$jfEYdAp0ZjH4LSyBl1NMYuH97s4sbuEgiv1kRBxNqzBsBnuenRD8i"
+	unittestFamilyID     = "b3a1f1d8-0c4e-4a4f-9d6c-2f1e0f7a9c11"
+	unittestRefreshToken = unittestFamilyID + ".secret"
+)
+
+// ======================================================================
+// TestAuthorities_Login
+// ======================================================================
+func TestAuthorities_Login(t *testing.T) {
+
+	type args struct {
+		username string
+		password string
+	}
+	tests := []struct {
+		name            string
+		args            args
+		buildStubs      func(*commonStubsAuthorities)
+		wantAccountInfo *model.Account
+		wantErr         error
+	}{
+		{
+			name: "success",
+			args: args{
+				username: "unittest",
+				password: "unittestsuccess",
+			},
+			buildStubs: successLogin,
+			wantAccountInfo: &model.Account{
+				Username:     "unittest",
+				HashPassword: unittestHashPassword,
+				Role:         model.ACCOUNT_ROLE_ADMIN,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "invalid password",
+			args: args{
+				username: "unittest",
+				password: "nopassword",
+			},
+			buildStubs:      invalidPassword,
+			wantAccountInfo: nil,
+			wantErr:         ErrorAuthoritiesInvalidPassword,
+		},
+		{
+			name: "account disabled",
+			args: args{
+				username: "unittest",
+				password: "unittestsuccess",
+			},
+			buildStubs:      disabledAccountLogin,
+			wantAccountInfo: nil,
+			wantErr:         ErrorAuthoritiesAccountDisabled,
+		},
+		{
+			name: "account not found",
+			args: args{
+				username: "unittest",
+				password: "unittestsuccess",
+			},
+			buildStubs:      accountNotFoundLogin,
+			wantAccountInfo: nil,
+			wantErr:         ErrorAuthoritiesFindAccountNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := commonStubsAuthorities{
+				mockAccRepo:    mock_domain.NewMockAccountRepository(ctrl),
+				mockRedisRepo:  mock_domain.NewMockRedisRepository(ctrl),
+				mockJWTService: mock_domain.NewMockJWTService(ctrl),
+			}
+
+			tt.buildStubs(&stubs)
+
+			usecase := NewAuthoritiesUsecase(stubs.mockAccRepo, stubs.mockRedisRepo, stubs.mockJWTService, authoritiesConfig)
+			gotAccountInfo, gotTokenPair, _, err := usecase.Login(context.TODO(), tt.args.username, tt.args.password, "127.0.0.1")
+			if err != tt.wantErr {
+				t.Errorf("Authorities.Login() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotAccountInfo, tt.wantAccountInfo) {
+				t.Errorf("Authorities.Login() = %v, want %v", gotAccountInfo, tt.wantAccountInfo)
+			}
+			if err == nil && (gotTokenPair.AccessToken != "access-token" || gotTokenPair.RefreshToken == "") {
+				t.Errorf("Authorities.Login() invalid token pair %+v", gotTokenPair)
+			}
+		})
+	}
+}
+
+func disabledAccountLogin(stubs *commonStubsAuthorities) {
+	stubs.mockAccRepo.EXPECT().FindAccountByUsername(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+	).Return(
+		&model.Account{
+			Username:     "unittest",
+			HashPassword: unittestHashPassword,
+			Role:         model.ACCOUNT_ROLE_GENERAL,
+			Status:       model.ACCOUNT_STATUS_DISABLED,
+		},
+		nil,
+	)
+}
+
+func successLogin(stubs *commonStubsAuthorities) {
+	stubs.mockAccRepo.EXPECT().FindAccountByUsername(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+	).Return(
+		&model.Account{
+			Username:     "unittest",
+			HashPassword: unittestHashPassword,
+			Role:         model.ACCOUNT_ROLE_ADMIN,
+		},
+		nil,
+	)
+
+	stubs.mockJWTService.EXPECT().GenerateNewToken(
+		gomock.Eq("unittest"),
+		gomock.Eq(model.ACCOUNT_ROLE_ADMIN),
+		gomock.Any(),
+	).Return("access-token", nil)
+
+	stubs.mockRedisRepo.EXPECT().SetDataToRedisWithTTL(
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+	).Return(nil)
+}
+
+func accountNotFoundLogin(stubs *commonStubsAuthorities) {
+	stubs.mockAccRepo.EXPECT().FindAccountByUsername(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+	).Return(nil, repository.ErrorMongoNotFound)
+}
+
+func invalidPassword(stubs *commonStubsAuthorities) {
+	stubs.mockAccRepo.EXPECT().FindAccountByUsername(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+	).Return(
+		&model.Account{
+			Username:     "unittest",
+			HashPassword: unittestHashPassword,
+		},
+		nil,
+	)
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestAuthorities_LoginProtection
+// ======================================================================
+var loginProtectionConfig = &config.Root{
+	RedisOption: config.RedisOptions{
+		Login: authoritiesConfig.RedisOption.Login,
+		LoginAttemptUser: config.RedisOption{
+			KeyFormat: "login_attempt_user_%s",
+			TTL:       15 * time.Minute,
+		},
+		LoginAttemptIP: config.RedisOption{
+			KeyFormat: "login_attempt_ip_%s",
+			TTL:       15 * time.Minute,
+		},
+	},
+	PasswordHash: unittestPasswordHash,
+	LoginProtection: config.LoginProtection{
+		MaxUserAttempts: 5,
+		MaxIPAttempts:   20,
+		LockoutDuration: 30 * time.Minute,
+	},
+}
+
+func TestAuthorities_LoginProtection(t *testing.T) {
+
+	type args struct {
+		username string
+		password string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		buildStubs func(*commonStubsAuthorities)
+		wantErr    error
+	}{
+		{
+			name: "locked_by_username",
+			args: args{
+				username: "unittest",
+				password: "unittestsuccess",
+			},
+			buildStubs: func(stubs *commonStubsAuthorities) {
+				stubLoginAttempt(stubs, 5, 0)
+			},
+			wantErr: ErrorAuthoritiesAccountLocked,
+		},
+		{
+			name: "locked_by_ip",
+			args: args{
+				username: "unittest",
+				password: "unittestsuccess",
+			},
+			buildStubs: func(stubs *commonStubsAuthorities) {
+				stubLoginAttempt(stubs, 0, 20)
+			},
+			wantErr: ErrorAuthoritiesAccountLocked,
+		},
+		{
+			name: "last_failed_attempt_start_lockout",
+			args: args{
+				username: "unittest",
+				password: "nopassword",
+			},
+			buildStubs: last_failed_attempt_start_lockout,
+			wantErr:    ErrorAuthoritiesInvalidPassword,
+		},
+		{
+			name: "success_reset_username_counter",
+			args: args{
+				username: "unittest",
+				password: "unittestsuccess",
+			},
+			buildStubs: success_reset_username_counter,
+			wantErr:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := commonStubsAuthorities{
+				mockAccRepo:    mock_domain.NewMockAccountRepository(ctrl),
+				mockRedisRepo:  mock_domain.NewMockRedisRepository(ctrl),
+				mockJWTService: mock_domain.NewMockJWTService(ctrl),
+			}
+
+			tt.buildStubs(&stubs)
+
+			usecase := NewAuthoritiesUsecase(stubs.mockAccRepo, stubs.mockRedisRepo, stubs.mockJWTService, loginProtectionConfig)
+			if _, _, _, err := usecase.Login(context.TODO(), tt.args.username, tt.args.password, "127.0.0.1"); err != tt.wantErr {
+				t.Errorf("Authorities.Login() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func stubLoginAttempt(stubs *commonStubsAuthorities, userCount, ipCount int64) {
+	stubs.mockRedisRepo.EXPECT().GetCounter(
+		gomock.Any(),
+		gomock.Eq("login_attempt_user_unittest"),
+	).Return(userCount, nil)
+
+	stubs.mockRedisRepo.EXPECT().GetCounter(
+		gomock.Any(),
+		gomock.Eq("login_attempt_ip_127.0.0.1"),
+	).Return(ipCount, nil)
+}
+
+func last_failed_attempt_start_lockout(stubs *commonStubsAuthorities) {
+	stubLoginAttempt(stubs, 4, 4)
+	invalidPassword(stubs)
+
+	stubs.mockRedisRepo.EXPECT().IncreaseCounter(
+		gomock.Any(),
+		gomock.Eq("login_attempt_user_unittest"),
+		gomock.Eq(30*time.Minute),
+	).Return(int64(5), nil)
+
+	stubs.mockRedisRepo.EXPECT().IncreaseCounter(
+		gomock.Any(),
+		gomock.Eq("login_attempt_ip_127.0.0.1"),
+		gomock.Eq(15*time.Minute),
+	).Return(int64(5), nil)
+}
+
+func success_reset_username_counter(stubs *commonStubsAuthorities) {
+	stubLoginAttempt(stubs, 2, 2)
+
+	stubs.mockRedisRepo.EXPECT().DeleteDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("login_attempt_user_unittest"),
+	).Return(nil)
+
+	successLogin(stubs)
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestAuthorities_LoginRehash
+// ======================================================================
+var rehashConfig = &config.Root{
+	RedisOption: authoritiesConfig.RedisOption,
+	PasswordHash: config.PasswordHash{
+		Algorithm: cryptography.PASSWORD_HASH_ALGORITHM_ARGON2ID,
+		Argon2: config.Argon2Params{
+			Memory:      64,
+			Iterations:  1,
+			Parallelism: 1,
+		},
+	},
+}
+
+func TestAuthorities_LoginRehash(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		buildStubs   func(*commonStubsAuthorities)
+		wantUpgraded bool
+		wantErr      error
+	}{
+		{
+			name:         "upgrade_bcrypt_hash_to_argon2id",
+			buildStubs:   upgrade_bcrypt_hash_to_argon2id,
+			wantUpgraded: true,
+			wantErr:      nil,
+		},
+		{
+			name:         "update_hash_fail_still_login",
+			buildStubs:   update_hash_fail_still_login,
+			wantUpgraded: false,
+			wantErr:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := commonStubsAuthorities{
+				mockAccRepo:    mock_domain.NewMockAccountRepository(ctrl),
+				mockRedisRepo:  mock_domain.NewMockRedisRepository(ctrl),
+				mockJWTService: mock_domain.NewMockJWTService(ctrl),
+			}
+
+			tt.buildStubs(&stubs)
+
+			usecase := NewAuthoritiesUsecase(stubs.mockAccRepo, stubs.mockRedisRepo, stubs.mockJWTService, rehashConfig)
+			gotAccountInfo, _, _, err := usecase.Login(context.TODO(), "unittest", "unittestsuccess", "127.0.0.1")
+			if err != tt.wantErr {
+				t.Errorf("Authorities.Login() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if upgraded := gotAccountInfo.HashPassword != unittestHashPassword; upgraded != tt.wantUpgraded {
+				t.Errorf("Authorities.Login() upgraded = %v, want %v", upgraded, tt.wantUpgraded)
+			}
+		})
+	}
+}
+
+func stubRehashPassword(stubs *commonStubsAuthorities, updateErr error) {
+	stubs.mockAccRepo.EXPECT().UpdateAccountPassword(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+		gomock.Any(),
+	).DoAndReturn(func(_ context.Context, _ string, hashPassword string) error {
+		hasher := cryptography.NewPasswordHasher(rehashConfig.PasswordHash)
+		if match, err := hasher.Verify("unittestsuccess", hashPassword); !match || err != nil {
+			return fmt.Errorf("rehash password is not verifiable")
+		}
+		if hasher.NeedsRehash(hashPassword) {
+			return fmt.Errorf("rehash password does not match current policy")
+		}
+		return updateErr
+	})
+}
+
+func upgrade_bcrypt_hash_to_argon2id(stubs *commonStubsAuthorities) {
+	successLogin(stubs)
+	stubRehashPassword(stubs, nil)
+}
+
+func update_hash_fail_still_login(stubs *commonStubsAuthorities) {
+	successLogin(stubs)
+	stubRehashPassword(stubs, repository.ErrorMongoNotFound)
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestAuthorities_MFA
+// ======================================================================
+var authoritiesMFAConfig = &config.Root{
+	PasswordHash: unittestPasswordHash,
+	RedisOption: config.RedisOptions{
+		Login:      authoritiesConfig.RedisOption.Login,
+		MFAPending: mfaConfig.RedisOption.MFAPending,
+	},
+	MFA: mfaConfig.MFA,
+}
+
+func TestAuthorities_LoginMFA(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		account       *model.Account
+		wantEnrollURI bool
+	}{
+		{
+			name:          "enabled_mfa_return_challenge",
+			account:       unittestMFAAccount(model.ACCOUNT_ROLE_ADMIN),
+			wantEnrollURI: false,
+		},
+		{
+			name:          "enforced_role_not_enrolled_return_enroll_uri",
+			account:       &model.Account{Username: "unittest", Role: model.ACCOUNT_ROLE_MASTER},
+			wantEnrollURI: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := commonStubsAuthorities{
+				mockAccRepo:    mock_domain.NewMockAccountRepository(ctrl),
+				mockRedisRepo:  mock_domain.NewMockRedisRepository(ctrl),
+				mockJWTService: mock_domain.NewMockJWTService(ctrl),
+			}
+
+			tt.account.HashPassword = unittestHashPassword
+
+			stubs.mockAccRepo.EXPECT().FindAccountByUsername(
+				gomock.Any(),
+				gomock.Eq("unittest"),
+			).Return(tt.account, nil)
+
+			stubs.mockRedisRepo.EXPECT().SetDataToRedisWithTTL(
+				gomock.Any(),
+				gomock.Any(),
+				gomock.Any(),
+				gomock.Eq(5*time.Minute),
+			).Return(nil)
+
+			usecase := NewAuthoritiesUsecase(stubs.mockAccRepo, stubs.mockRedisRepo, stubs.mockJWTService, authoritiesMFAConfig)
+			_, gotTokenPair, gotChallenge, err := usecase.Login(context.TODO(), "unittest", "unittestsuccess", "127.0.0.1")
+			if err != nil {
+				t.Errorf("Authorities.Login() error = %v", err)
+				return
+			}
+			if gotTokenPair != nil || gotChallenge == nil || gotChallenge.Token == "" {
+				t.Errorf("Authorities.Login() token pair = %+v, challenge = %+v, want only challenge", gotTokenPair, gotChallenge)
+				return
+			}
+			if (gotChallenge.EnrollURI != "") != tt.wantEnrollURI {
+				t.Errorf("Authorities.Login() enroll uri = %v, want enroll uri %v", gotChallenge.EnrollURI, tt.wantEnrollURI)
+			}
+		})
+	}
+}
+
+func TestAuthorities_VerifyMFA(t *testing.T) {
+
+	tests := []struct {
+		name              string
+		code              string
+		buildStubs        func(*commonStubsAuthorities)
+		wantRecoveryCodes bool
+		wantErr           error
+	}{
+		{
+			name:              "success_verify_totp_code",
+			code:              unittestMFACode(),
+			buildStubs:        success_verify_totp_code,
+			wantRecoveryCodes: false,
+			wantErr:           nil,
+		},
+		{
+			name:              "success_enroll_at_login",
+			code:              unittestMFACode(),
+			buildStubs:        success_enroll_at_login,
+			wantRecoveryCodes: true,
+			wantErr:           nil,
+		},
+		{
+			name:       "invalid_code",
+			code:       "000000",
+			buildStubs: verify_mfa_invalid_code,
+			wantErr:    ErrorAuthoritiesInvalidMFACode,
+		},
+		{
+			name: "mfa_token_expired",
+			code: unittestMFACode(),
+			buildStubs: func(stubs *commonStubsAuthorities) {
+				stubs.mockRedisRepo.EXPECT().GetDataFromRedis(
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+				).Return(repository.ErrorRedisNotFound)
+			},
+			wantErr: ErrorAuthoritiesInvalidMFAToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := commonStubsAuthorities{
+				mockAccRepo:    mock_domain.NewMockAccountRepository(ctrl),
+				mockRedisRepo:  mock_domain.NewMockRedisRepository(ctrl),
+				mockJWTService: mock_domain.NewMockJWTService(ctrl),
+			}
+
+			tt.buildStubs(&stubs)
+
+			usecase := NewAuthoritiesUsecase(stubs.mockAccRepo, stubs.mockRedisRepo, stubs.mockJWTService, authoritiesMFAConfig)
+			_, gotTokenPair, gotRecoveryCodes, err := usecase.VerifyMFA(context.TODO(), "mfa-token", tt.code, "127.0.0.1")
+			if err != tt.wantErr {
+				t.Errorf("Authorities.VerifyMFA() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && gotTokenPair.AccessToken != "access-token" {
+				t.Errorf("Authorities.VerifyMFA() invalid token pair %+v", gotTokenPair)
+			}
+			if (len(gotRecoveryCodes) > 0) != tt.wantRecoveryCodes {
+				t.Errorf("Authorities.VerifyMFA() recovery codes = %v, want recovery codes %v", gotRecoveryCodes, tt.wantRecoveryCodes)
+			}
+		})
+	}
+}
+
+func stubMFAPending(stubs *commonStubsAuthorities, enrollSecret string, account *model.Account) {
+	pendingKey := "mfa_pending_" + cryptography.NewCrypto().HashSHA256("mfa-token")
+
+	stubs.mockRedisRepo.EXPECT().GetDataFromRedis(
+		gomock.Any(),
+		gomock.Eq(pendingKey),
+		gomock.Any(),
+	).DoAndReturn(func(_ context.Context, _ string, data interface{}) error {
+		*data.(*model.MFAPending) = model.MFAPending{Username: "unittest", EnrollSecret: enrollSecret}
+		return nil
+	})
+
+	stubs.mockAccRepo.EXPECT().FindAccountByUsername(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+	).Return(account, nil)
+}
+
+func stubIssueTokenAfterMFA(stubs *commonStubsAuthorities, role string) {
+	stubs.mockRedisRepo.EXPECT().GetAndDeleteDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("mfa_pending_"+cryptography.NewCrypto().HashSHA256("mfa-token")),
+		gomock.Any(),
+	).Return(nil)
+
+	stubs.mockJWTService.EXPECT().GenerateNewToken(
+		gomock.Eq("unittest"),
+		gomock.Eq(role),
+		gomock.Any(),
+	).Return("access-token", nil)
+
+	stubs.mockRedisRepo.EXPECT().SetDataToRedisWithTTL(
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+	).Return(nil)
+}
+
+func success_verify_totp_code(stubs *commonStubsAuthorities) {
+	stubMFAPending(stubs, "", unittestMFAAccount(model.ACCOUNT_ROLE_ADMIN))
+
+	stubs.mockAccRepo.EXPECT().UpdateMFALastUsedStep(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+		gomock.Any(),
+	).Return(nil)
+
+	stubIssueTokenAfterMFA(stubs, model.ACCOUNT_ROLE_ADMIN)
+}
+
+func success_enroll_at_login(stubs *commonStubsAuthorities) {
+	stubMFAPending(stubs, unittestMFASecret, &model.Account{Username: "unittest", Role: model.ACCOUNT_ROLE_MASTER})
+
+	stubs.mockAccRepo.EXPECT().UpdateAccountMFA(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+		gomock.Any(),
+	).Return(nil)
+
+	stubIssueTokenAfterMFA(stubs, model.ACCOUNT_ROLE_MASTER)
+}
+
+func verify_mfa_invalid_code(stubs *commonStubsAuthorities) {
+	stubMFAPending(stubs, "", unittestMFAAccount(model.ACCOUNT_ROLE_ADMIN))
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestAuthorities_CreateAccout
+// ======================================================================
+func TestAuthorities_CreateAccout(t *testing.T) {
+
+	type args struct {
+		username        string
+		password        string
+		confirmPassword string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "confirm_password_not_match",
+			args: args{
+				username:        "unittest",
+				password:        "Spider-Hunter-2023",
+				confirmPassword: "Spider-Hunter-2024",
+			},
+			wantErr: ErrorAuthoritiesConfirmPasswordNotMatch,
+		},
+		{
+			name: "username_invalid_character",
+			args: args{
+				username:        "unit test",
+				password:        "Spider-Hunter-2023",
+				confirmPassword: "Spider-Hunter-2023",
+			},
+			wantErr: ErrorAuthoritiesUsernameNotQualify,
+		},
+		{
+			name: "password_too_short",
+			args: args{
+				username:        "unittest",
+				password:        "short",
+				confirmPassword: "short",
+			},
+			wantErr: ErrorAuthoritiesPasswordNotQualify,
+		},
+		{
+			name: "password_is_common",
+			args: args{
+				username:        "unittest",
+				password:        "Password123",
+				confirmPassword: "Password123",
+			},
+			wantErr: ErrorAuthoritiesPasswordNotQualify,
+		},
+		{
+			name: "password_contain_username",
+			args: args{
+				username:        "unittest",
+				password:        "my-UnitTest-password",
+				confirmPassword: "my-UnitTest-password",
+			},
+			wantErr: ErrorAuthoritiesPasswordNotQualify,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			usecase := NewAuthoritiesUsecase(
+				mock_domain.NewMockAccountRepository(ctrl),
+				mock_domain.NewMockRedisRepository(ctrl),
+				mock_domain.NewMockJWTService(ctrl),
+				authoritiesConfig,
+			)
+
+			data := model.Account{Username: tt.args.username}
+			if err := usecase.CreateAccout(context.TODO(), data, tt.args.password, tt.args.confirmPassword); err != tt.wantErr {
+				t.Errorf("Authorities.CreateAccout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestAuthorities_RefreshToken
+// ======================================================================
+func TestAuthorities_RefreshToken(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		refreshToken string
+		buildStubs   func(*commonStubsAuthorities)
+		wantErr      error
+	}{
+		{
+			name:         "success_rotate_refresh_token",
+			refreshToken: unittestRefreshToken,
+			buildStubs:   success_rotate_refresh_token,
+			wantErr:      nil,
+		},
+		{
+			name:         "invalid_refresh_token_format",
+			refreshToken: "no-separator",
+			buildStubs:   func(*commonStubsAuthorities) {},
+			wantErr:      ErrorAuthoritiesInvalidRefreshToken,
+		},
+		{
+			name:         "session_family_not_found",
+			refreshToken: unittestRefreshToken,
+			buildStubs:   session_family_not_found,
+			wantErr:      ErrorAuthoritiesInvalidRefreshToken,
+		},
+		{
+			name:         "reused_refresh_token_revoke_family",
+			refreshToken: unittestRefreshToken,
+			buildStubs:   reused_refresh_token_revoke_family,
+			wantErr:      ErrorAuthoritiesRefreshTokenReused,
+		},
+		{
+			name:         "unknown_secret_of_family_is_invalid",
+			refreshToken: unittestFamilyID + ".guessed",
+			buildStubs:   unknown_secret_of_family_is_invalid,
+			wantErr:      ErrorAuthoritiesInvalidRefreshToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := commonStubsAuthorities{
+				mockAccRepo:    mock_domain.NewMockAccountRepository(ctrl),
+				mockRedisRepo:  mock_domain.NewMockRedisRepository(ctrl),
+				mockJWTService: mock_domain.NewMockJWTService(ctrl),
+			}
+
+			tt.buildStubs(&stubs)
+
+			usecase := NewAuthoritiesUsecase(stubs.mockAccRepo, stubs.mockRedisRepo, stubs.mockJWTService, authoritiesConfig)
+			gotTokenPair, err := usecase.RefreshToken(context.TODO(), tt.refreshToken)
+			if err != tt.wantErr {
+				t.Errorf("Authorities.RefreshToken() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && gotTokenPair.RefreshToken == tt.refreshToken {
+				t.Errorf("Authorities.RefreshToken() refresh token is not rotated")
+			}
+		})
+	}
+}
+
+func stubSessionFamily(stubs *commonStubsAuthorities, tokenHash string, rotatedTokenHashes ...string) {
+	stubs.mockRedisRepo.EXPECT().GetDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("login_"+unittestFamilyID),
+		gomock.Any(),
+	).DoAndReturn(func(_ context.Context, _ string, data interface{}) error {
+		*data.(*model.Login) = model.Login{
+			Username:  "unittest",
+			Role:      model.ACCOUNT_ROLE_GENERAL,
+			FamilyID:  unittestFamilyID,
+			TokenHash: tokenHash,
+			CreatedAt: time.Now(),
+			// rotated token of earlier refresh
+			RotatedTokenHashes: rotatedTokenHashes,
+		}
+		return nil
+	})
+}
+
+func success_rotate_refresh_token(stubs *commonStubsAuthorities) {
+	stubSessionFamily(stubs, cryptography.NewCrypto().HashSHA256(unittestRefreshToken))
+
+	stubs.mockRedisRepo.EXPECT().GetDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("revoke_user_unittest"),
+		gomock.Any(),
+	).Return(repository.ErrorRedisNotFound)
+
+	stubs.mockAccRepo.EXPECT().FindAccountByUsername(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+	).Return(&model.Account{Username: "unittest", Role: model.ACCOUNT_ROLE_ADMIN}, nil)
+
+	// role is read again from account
+	stubs.mockJWTService.EXPECT().GenerateNewToken(
+		gomock.Eq("unittest"),
+		gomock.Eq(model.ACCOUNT_ROLE_ADMIN),
+		gomock.Eq(unittestFamilyID),
+	).Return("access-token", nil)
+
+	// presented token is kept as rotated out token, and family is rotated only when presented token is still current
+	stubs.mockRedisRepo.EXPECT().CompareAndSetDataToRedis(
+		gomock.Any(),
+		gomock.Eq("login_"+unittestFamilyID),
+		gomock.Eq("token_hash"),
+		gomock.Eq(cryptography.NewCrypto().HashSHA256(unittestRefreshToken)),
+		gomock.Any(),
+		gomock.Any(),
+	).DoAndReturn(func(_ context.Context, _, _, _ string, data interface{}, _ time.Duration) error {
+		session := data.(model.Login)
+		if len(session.RotatedTokenHashes) != 1 || session.RotatedTokenHashes[0] != cryptography.NewCrypto().HashSHA256(unittestRefreshToken) {
+			return repository.ErrorRedisNotFound
+		}
+		return nil
+	})
+}
+
+func session_family_not_found(stubs *commonStubsAuthorities) {
+	stubs.mockRedisRepo.EXPECT().GetDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("login_"+unittestFamilyID),
+		gomock.Any(),
+	).Return(repository.ErrorRedisNotFound)
+}
+
+func reused_refresh_token_revoke_family(stubs *commonStubsAuthorities) {
+	crypto := cryptography.NewCrypto()
+	stubSessionFamily(stubs, crypto.HashSHA256(unittestFamilyID+".rotated"), crypto.HashSHA256(unittestRefreshToken))
+
+	stubs.mockRedisRepo.EXPECT().DeleteDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("login_"+unittestFamilyID),
+	).Return(nil)
+}
+
+// family is not revoked by token that never issued, revoke is not expected
+func unknown_secret_of_family_is_invalid(stubs *commonStubsAuthorities) {
+	crypto := cryptography.NewCrypto()
+	stubSessionFamily(stubs, crypto.HashSHA256(unittestFamilyID+".rotated"), crypto.HashSHA256(unittestRefreshToken))
+}
+
+// ======================================================================
+// TestAuthorities_RefreshTokenConcurrentRotation
+// ======================================================================
+
+// two requests read session family before either of them rotate it, only first rotation succeed
+// and second rotation of the same token revoke family
+func TestAuthorities_RefreshTokenConcurrentRotation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	stubs := commonStubsAuthorities{
+		mockAccRepo:    mock_domain.NewMockAccountRepository(ctrl),
+		mockRedisRepo:  mock_domain.NewMockRedisRepository(ctrl),
+		mockJWTService: mock_domain.NewMockJWTService(ctrl),
+	}
+
+	tokenHash := cryptography.NewCrypto().HashSHA256(unittestRefreshToken)
+
+	// current token hash of family that is kept in redis
+	currentTokenHash := tokenHash
+	familyExists := true
+
+	stubSessionFamily(&stubs, tokenHash)
+	stubSessionFamily(&stubs, tokenHash)
+
+	stubs.mockRedisRepo.EXPECT().GetDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("revoke_user_unittest"),
+		gomock.Any(),
+	).Return(repository.ErrorRedisNotFound).Times(2)
+
+	stubs.mockAccRepo.EXPECT().FindAccountByUsername(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+	).Return(&model.Account{Username: "unittest", Role: model.ACCOUNT_ROLE_GENERAL}, nil).Times(2)
+
+	stubs.mockJWTService.EXPECT().GenerateNewToken(
+		gomock.Eq("unittest"),
+		gomock.Eq(model.ACCOUNT_ROLE_GENERAL),
+		gomock.Eq(unittestFamilyID),
+	).Return("access-token", nil).Times(2)
+
+	stubs.mockRedisRepo.EXPECT().CompareAndSetDataToRedis(
+		gomock.Any(),
+		gomock.Eq("login_"+unittestFamilyID),
+		gomock.Eq("token_hash"),
+		gomock.Eq(tokenHash),
+		gomock.Any(),
+		gomock.Any(),
+	).DoAndReturn(func(_ context.Context, _, _, expected string, data interface{}, _ time.Duration) error {
+		if !familyExists || currentTokenHash != expected {
+			return repository.ErrorRedisConflict
+		}
+		currentTokenHash = data.(model.Login).TokenHash
+		return nil
+	}).Times(2)
+
+	stubs.mockRedisRepo.EXPECT().DeleteDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("login_"+unittestFamilyID),
+	).DoAndReturn(func(_ context.Context, _ string) error {
+		familyExists = false
+		return nil
+	})
+
+	usecase := NewAuthoritiesUsecase(stubs.mockAccRepo, stubs.mockRedisRepo, stubs.mockJWTService, authoritiesConfig)
+
+	firstTokenPair, err := usecase.RefreshToken(context.TODO(), unittestRefreshToken)
+	if err != nil {
+		t.Fatalf("Authorities.RefreshToken() first rotation error = %v", err)
+	}
+	if firstTokenPair.RefreshToken == unittestRefreshToken {
+		t.Errorf("Authorities.RefreshToken() refresh token is not rotated")
+	}
+
+	if _, err := usecase.RefreshToken(context.TODO(), unittestRefreshToken); err != ErrorAuthoritiesRefreshTokenReused {
+		t.Errorf("Authorities.RefreshToken() second rotation error = %v, wantErr %v", err, ErrorAuthoritiesRefreshTokenReused)
+	}
+
+	if familyExists {
+		t.Errorf("Authorities.RefreshToken() session family is not revoked")
+	}
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestAuthorities_ValidateSession
+// ======================================================================
+func TestAuthorities_ValidateSession(t *testing.T) {
+
+	issuedAt := time.Now().UnixMilli()
+
+	stubRevokeUser := func(stubs *commonStubsAuthorities, revokedAt time.Time) {
+		stubDenylist(stubs, repository.ErrorRedisNotFound)
+		stubs.mockRedisRepo.EXPECT().GetDataFromRedis(
+			gomock.Any(),
+			gomock.Eq("revoke_user_unittest"),
+			gomock.Any(),
+		).DoAndReturn(func(_ context.Context, _ string, data interface{}) error {
+			*data.(*model.RevokedUser) = model.RevokedUser{
+				Username:  "unittest",
+				RevokedAt: revokedAt,
+			}
+			return nil
+		})
+	}
+
+	tests := []struct {
+		name       string
+		buildStubs func(*commonStubsAuthorities)
+		wantErr    error
+	}{
+		{
+			name: "valid_session",
+			buildStubs: func(stubs *commonStubsAuthorities) {
+				stubDenylist(stubs, repository.ErrorRedisNotFound)
+				stubs.mockRedisRepo.EXPECT().GetDataFromRedis(
+					gomock.Any(),
+					gomock.Eq("revoke_user_unittest"),
+					gomock.Any(),
+				).Return(repository.ErrorRedisNotFound)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "token_in_denylist",
+			buildStubs: func(stubs *commonStubsAuthorities) {
+				stubDenylist(stubs, nil)
+			},
+			wantErr: ErrorAuthoritiesTokenRevoked,
+		},
+		{
+			name: "user_revoke_all_sessions_after_token_issued",
+			buildStubs: func(stubs *commonStubsAuthorities) {
+				stubRevokeUser(stubs, time.UnixMilli(issuedAt).Add(time.Minute))
+			},
+			wantErr: ErrorAuthoritiesTokenRevoked,
+		},
+		{
+			name: "user_revoke_all_sessions_less_than_second_after_token_issued",
+			buildStubs: func(stubs *commonStubsAuthorities) {
+				stubRevokeUser(stubs, time.UnixMilli(issuedAt).Add(300*time.Millisecond))
+			},
+			wantErr: ErrorAuthoritiesTokenRevoked,
+		},
+		{
+			name: "token_issued_less_than_second_after_user_revoke_all_sessions",
+			buildStubs: func(stubs *commonStubsAuthorities) {
+				stubRevokeUser(stubs, time.UnixMilli(issuedAt).Add(-300*time.Millisecond))
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := commonStubsAuthorities{
+				mockAccRepo:    mock_domain.NewMockAccountRepository(ctrl),
+				mockRedisRepo:  mock_domain.NewMockRedisRepository(ctrl),
+				mockJWTService: mock_domain.NewMockJWTService(ctrl),
+			}
+
+			tt.buildStubs(&stubs)
+
+			loginUser := model.LoginUser{
+				Username: "unittest",
+				TokenID:  "jti",
+				IssuedAt: issuedAt,
+			}
+
+			usecase := NewAuthoritiesUsecase(stubs.mockAccRepo, stubs.mockRedisRepo, stubs.mockJWTService, authoritiesConfig)
+			if err := usecase.ValidateSession(context.TODO(), &loginUser); err != tt.wantErr {
+				t.Errorf("Authorities.ValidateSession() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func stubDenylist(stubs *commonStubsAuthorities, err error) {
+	stubs.mockRedisRepo.EXPECT().GetDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("token_denylist_jti"),
+		gomock.Any(),
+	).Return(err)
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestAuthorities_Logout
+// ======================================================================
+func TestAuthorities_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	stubs := commonStubsAuthorities{
+		mockAccRepo:    mock_domain.NewMockAccountRepository(ctrl),
+		mockRedisRepo:  mock_domain.NewMockRedisRepository(ctrl),
+		mockJWTService: mock_domain.NewMockJWTService(ctrl),
+	}
+
+	stubs.mockRedisRepo.EXPECT().SetDataToRedisWithTTL(
+		gomock.Any(),
+		gomock.Eq("token_denylist_jti"),
+		gomock.Any(),
+		gomock.Any(),
+	).Return(nil)
+
+	stubs.mockRedisRepo.EXPECT().DeleteDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("login_"+unittestFamilyID),
+	).Return(nil)
+
+	loginUser := model.LoginUser{
+		Username:  "unittest",
+		TokenID:   "jti",
+		SessionID: unittestFamilyID,
+		ExpiresAt: time.Now().Add(time.Minute).Unix(),
+	}
+
+	usecase := NewAuthoritiesUsecase(stubs.mockAccRepo, stubs.mockRedisRepo, stubs.mockJWTService, authoritiesConfig)
+	if err := usecase.Logout(context.TODO(), &loginUser); err != nil {
+		t.Errorf("Authorities.Logout() error = %v", err)
+	}
+}
+
+// **********************************************************************
@@ -3,81 +3,75 @@ package usecase
 import (
 	"context"
 	"fmt"
-	"os"
-	"path"
-	"spider-go/config"
 	"spider-go/domain"
 	"spider-go/logger"
+	"spider-go/model"
+	"spider-go/repository"
+	"time"
 )
 
 var (
-	ErrorDeleteSpiderInfoUsecaseRemoveFileFailed       = fmt.Errorf("remove spider image file failed")
+	ErrorDeleteSpiderInfoUsecaseSpiderNotFound         = fmt.Errorf("spider info not found or already in trash")
 	ErrorDeleteSpiderInfoUsecaseDeleteSpiderInfoFailed = fmt.Errorf("delete spider info in mongodb failed")
 )
 
 type DeleteSpiderInfoUsecase struct {
-	spiderRepo domain.SpiderRepository
-	log        *logger.Logger
+	spiderRepo     domain.SpiderRepository
+	statisticsRepo domain.StatisticsRepository
+	revisionRepo   domain.SpiderRevisionRepository
+	unitOfWork     domain.UnitOfWork
+	log            *logger.Logger
 }
 
-func NewDeleteSpiderInfoUsecase(spiderRepo domain.SpiderRepository) domain.DeleteSpiderInfoUsecase {
+func NewDeleteSpiderInfoUsecase(
+	spiderRepo domain.SpiderRepository,
+	statisticsRepo domain.StatisticsRepository,
+	revisionRepo domain.SpiderRevisionRepository,
+	unitOfWork domain.UnitOfWork,
+) domain.DeleteSpiderInfoUsecase {
 	return &DeleteSpiderInfoUsecase{
-		spiderRepo: spiderRepo,
-		log:        logger.L().Named("DeleteSpiderInfoUsecase"),
+		spiderRepo:     spiderRepo,
+		statisticsRepo: statisticsRepo,
+		revisionRepo:   revisionRepo,
+		unitOfWork:     unitOfWork,
+		log:            logger.L().Named("DeleteSpiderInfoUsecase"),
 	}
 }
 
-func (u *DeleteSpiderInfoUsecase) DeleteSpiderInfoUsecase(ctx context.Context, spiderUUID string) error {
+// DeleteSpiderInfoUsecase move spider info to trash, spider info and image are removed permanently by purge job
+func (u *DeleteSpiderInfoUsecase) DeleteSpiderInfoUsecase(ctx context.Context, spiderUUID string, username string) error {
 	log := u.log.WithContext(ctx)
 
 	log.Infof("[DeleteSpiderInfoUsecase] delete spider info at spider_uuid is `%v`", spiderUUID)
 
-	// find spider info for get image name to delete
 	spiderInfo, err := u.spiderRepo.FindSpiderByUUID(ctx, spiderUUID)
 	if err != nil {
 		log.Errorf("[DeleteSpiderInfoUsecase] find spider info error: %+v", err)
+		if err == repository.ErrorMongoNotFound {
+			return ErrorDeleteSpiderInfoUsecaseSpiderNotFound
+		}
 		return ErrorMongoTechnicalFail
 	}
 
-	if err := u.spiderRepo.DeleteSpiderInfoWithSpiderUUID(ctx, spiderUUID); err != nil {
-		log.Errorf("[DeleteSpiderInfoUsecase] delete spider info at spider_uuid `%v` failed, error: %+v", spiderUUID, err)
-		return ErrorDeleteSpiderInfoUsecaseDeleteSpiderInfoFailed
+	if spiderInfo.IsDeleted() {
+		return ErrorDeleteSpiderInfoUsecaseSpiderNotFound
 	}
 
-	go func() {
-		u.removeSpiderImage(ctx, spiderInfo.ImageFile)
-	}()
-
-	return nil
-}
-
-func (u *DeleteSpiderInfoUsecase) removeSpiderImage(ctx context.Context, spiderImageList []string) {
-	log := u.log.WithContext(ctx)
-
-	for _, spiderImage := range spiderImageList {
-		filepath := path.Join(config.C().File.FileImagePath, spiderImage)
-		tryToRemove := true
-		tryCount := 0
-
-		var RemoveErr error
-
-		for tryToRemove && tryCount < 3 {
-			log.Debugf("[DeleteSpiderInfoUsecase] count `%v`, try to remove spider image name is `%v` failed, error: %v", tryCount, spiderImage, RemoveErr)
-
-			if err := os.Remove(filepath); err != nil {
-				RemoveErr = err
-				tryCount += 1
-			} else {
-				tryToRemove = false
-			}
+	// spider info in trash is not public so it is not counted in statistics
+	err = u.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := u.spiderRepo.MoveSpiderInfoToTrash(ctx, spiderUUID, spiderInfo.Status, username, time.Now()); err != nil {
+			return err
 		}
-		if tryToRemove {
-			log.Errorf("[DeleteSpiderInfoUsecase] remove spider image name is `%v` failed, error: %v", spiderImage, RemoveErr)
-		} else {
-			log.Infof("[DeleteSpiderInfoUsecase]remove spider image name is `%v` successfull", spiderImage)
+		if err := moveSpiderStatistics(ctx, u.statisticsRepo, spiderInfo, nil); err != nil {
+			return err
 		}
-
+		// snapshot of delete revision is the last state, it can be restored later
+		return recordSpiderRevision(ctx, u.revisionRepo, log, model.SPIDER_REVISION_ACTION_DELETE, *spiderInfo, username)
+	})
+	if err != nil {
+		log.Errorf("[DeleteSpiderInfoUsecase] move spider info at spider_uuid `%v` to trash failed, error: %+v", spiderUUID, err)
+		return ErrorDeleteSpiderInfoUsecaseDeleteSpiderInfoFailed
 	}
 
-	log.Infof("[DeleteSpiderInfoUsecase] removed spider image list successfull")
+	return nil
 }
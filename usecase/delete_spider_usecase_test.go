@@ -3,7 +3,6 @@ package usecase
 import (
 	"context"
 	"fmt"
-	"spider-go/config"
 	mock_domain "spider-go/domain/mock"
 	"spider-go/model"
 	"testing"
@@ -12,14 +11,13 @@ import (
 )
 
 type commonBuildStub struct {
-	spiderRepo *mock_domain.MockSpiderRepository
+	spiderRepo     *mock_domain.MockSpiderRepository
+	statisticsRepo *mock_domain.MockStatisticsRepository
+	revisionRepo   *mock_domain.MockSpiderRevisionRepository
 }
 
 func TestDeleteSpiderInfoUsecase(t *testing.T) {
 
-	tempDir := t.TempDir()
-	config.C().File.FileImagePath = tempDir
-
 	type arge struct {
 		spiderUUID string
 	}
@@ -38,6 +36,22 @@ func TestDeleteSpiderInfoUsecase(t *testing.T) {
 			buildStub: successfull,
 			wantErr:   false,
 		},
+		{
+			name: "update_spider_statistics_failed",
+			arge: arge{
+				spiderUUID: "SPIDER_565391ff-9197-47ce-b86e-311d7b901f53",
+			},
+			buildStub: update_spider_statistics_failed,
+			wantErr:   true,
+		},
+		{
+			name: "record_revision_failed",
+			arge: arge{
+				spiderUUID: "SPIDER_565391ff-9197-47ce-b86e-311d7b901f53",
+			},
+			buildStub: record_revision_failed,
+			wantErr:   true,
+		},
 		{
 			name: "find_spider_info_not_found",
 			arge: arge{
@@ -54,6 +68,14 @@ func TestDeleteSpiderInfoUsecase(t *testing.T) {
 			buildStub: delete_spider_info_failed,
 			wantErr:   true,
 		},
+		{
+			name: "spider_info_already_in_trash",
+			arge: arge{
+				spiderUUID: "SPIDER_565391ff-9197-47ce-b86e-311d7b901f53",
+			},
+			buildStub: spider_info_already_in_trash,
+			wantErr:   true,
+		},
 	}
 
 	for _, tt := range tc {
@@ -62,16 +84,20 @@ func TestDeleteSpiderInfoUsecase(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockSpiderRepo := mock_domain.NewMockSpiderRepository(ctrl)
+			mockStatisticsRepo := mock_domain.NewMockStatisticsRepository(ctrl)
+			mockRevisionRepo := mock_domain.NewMockSpiderRevisionRepository(ctrl)
 
 			commonStub := commonBuildStub{
-				spiderRepo: mockSpiderRepo,
+				spiderRepo:     mockSpiderRepo,
+				statisticsRepo: mockStatisticsRepo,
+				revisionRepo:   mockRevisionRepo,
 			}
 
 			tt.buildStub(&commonStub)
 
-			usecase := NewDeleteSpiderInfoUsecase(mockSpiderRepo)
+			usecase := NewDeleteSpiderInfoUsecase(mockSpiderRepo, mockStatisticsRepo, mockRevisionRepo, newPassThroughUnitOfWork(ctrl))
 
-			err := usecase.DeleteSpiderInfoUsecase(context.TODO(), tt.arge.spiderUUID)
+			err := usecase.DeleteSpiderInfoUsecase(context.TODO(), tt.arge.spiderUUID, "unittest")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("[TestDeleteSpiderInfoUsecase] failed, wantErr: %v, but got Err: %v, error: %+v", tt.wantErr, (err != nil), err)
 			}
@@ -80,21 +106,75 @@ func TestDeleteSpiderInfoUsecase(t *testing.T) {
 
 }
 
-func successfull(stub *commonBuildStub) {
-	spiderInfo := model.SpiderInfo{
-		SpiderUUID: "SPIDER_565391ff-9197-47ce-b86e-311d7b901f53",
-		ImageFile:  []string{},
-	}
+var unittestDeleteSpiderInfo = model.SpiderInfo{
+	SpiderUUID: "SPIDER_565391ff-9197-47ce-b86e-311d7b901f53",
+	Family:     "Agelenidae",
+	Genus:      "Draconarius",
+	Species:    "abbreviatus",
+	Status:     model.SPIDER_INFO_STATUS_ACTIVE,
+	ImageFile:  []string{},
+}
+
+func stubMoveDeleteSpiderInfoToTrash(stub *commonBuildStub) {
+	spiderInfo := unittestDeleteSpiderInfo
 
 	stub.spiderRepo.EXPECT().FindSpiderByUUID(
 		gomock.Any(),
 		gomock.Eq("SPIDER_565391ff-9197-47ce-b86e-311d7b901f53"),
 	).Return(&spiderInfo, nil)
 
-	stub.spiderRepo.EXPECT().DeleteSpiderInfoWithSpiderUUID(
+	stub.spiderRepo.EXPECT().MoveSpiderInfoToTrash(
 		gomock.Any(),
 		gomock.Eq("SPIDER_565391ff-9197-47ce-b86e-311d7b901f53"),
+		gomock.Eq(model.SPIDER_INFO_STATUS_ACTIVE),
+		gomock.Eq("unittest"),
+		gomock.Any(),
+	).Return(nil)
+}
+
+func successfull(stub *commonBuildStub) {
+	stubMoveDeleteSpiderInfoToTrash(stub)
+
+	stub.statisticsRepo.EXPECT().IncreaseSpiderStatistics(
+		gomock.Any(),
+		gomock.Eq("Agelenidae"),
+		gomock.Eq("Draconarius"),
+		gomock.Eq("abbreviatus"),
+		gomock.Eq(int64(-1)),
+	).Return(nil)
+
+	stubRecordSpiderRevision(stub.revisionRepo, model.SPIDER_REVISION_ACTION_DELETE, "unittest")
+}
+
+// spider info stay out of trash when statistics is not updated in same unit of work
+func update_spider_statistics_failed(stub *commonBuildStub) {
+	stubMoveDeleteSpiderInfoToTrash(stub)
+
+	stub.statisticsRepo.EXPECT().IncreaseSpiderStatistics(
+		gomock.Any(),
+		gomock.Eq("Agelenidae"),
+		gomock.Eq("Draconarius"),
+		gomock.Eq("abbreviatus"),
+		gomock.Eq(int64(-1)),
+	).Return(fmt.Errorf("write conflict"))
+}
+
+// spider info stay out of trash when its delete revision is not recorded in same unit of work
+func record_revision_failed(stub *commonBuildStub) {
+	stubMoveDeleteSpiderInfoToTrash(stub)
+
+	stub.statisticsRepo.EXPECT().IncreaseSpiderStatistics(
+		gomock.Any(),
+		gomock.Eq("Agelenidae"),
+		gomock.Eq("Draconarius"),
+		gomock.Eq("abbreviatus"),
+		gomock.Eq(int64(-1)),
 	).Return(nil)
+
+	stub.revisionRepo.EXPECT().InsertSpiderRevision(
+		gomock.Any(),
+		EqSpiderRevision(model.SPIDER_REVISION_ACTION_DELETE, "unittest"),
+	).Return(int64(0), fmt.Errorf("write conflict"))
 }
 
 func find_spider_info_not_found(stub *commonBuildStub) {
@@ -107,6 +187,7 @@ func find_spider_info_not_found(stub *commonBuildStub) {
 func delete_spider_info_failed(stub *commonBuildStub) {
 	spiderInfo := model.SpiderInfo{
 		SpiderUUID: "SPIDER_565391ff-9197-47ce-b86e-311d7b901f53",
+		Status:     model.SPIDER_INFO_STATUS_ACTIVE,
 		ImageFile:  []string{},
 	}
 
@@ -115,8 +196,21 @@ func delete_spider_info_failed(stub *commonBuildStub) {
 		gomock.Eq("SPIDER_565391ff-9197-47ce-b86e-311d7b901f53"),
 	).Return(&spiderInfo, nil)
 
-	stub.spiderRepo.EXPECT().DeleteSpiderInfoWithSpiderUUID(
+	stub.spiderRepo.EXPECT().MoveSpiderInfoToTrash(
 		gomock.Any(),
 		gomock.Eq("SPIDER_565391ff-9197-47ce-b86e-311d7b901f53"),
+		gomock.Eq(model.SPIDER_INFO_STATUS_ACTIVE),
+		gomock.Eq("unittest"),
+		gomock.Any(),
 	).Return(fmt.Errorf("delete spider result is zero"))
 }
+
+func spider_info_already_in_trash(stub *commonBuildStub) {
+	stub.spiderRepo.EXPECT().FindSpiderByUUID(
+		gomock.Any(),
+		gomock.Eq("SPIDER_565391ff-9197-47ce-b86e-311d7b901f53"),
+	).Return(&model.SpiderInfo{
+		SpiderUUID: "SPIDER_565391ff-9197-47ce-b86e-311d7b901f53",
+		Status:     model.SPIDER_INFO_STATUS_DELETED,
+	}, nil)
+}
@@ -2,60 +2,47 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 	"spider-go/domain"
 	"spider-go/logger"
 	"spider-go/model"
-	"spider-go/repository"
+)
+
+var (
+	ErrorGetFamilyListInvalidSortBy = fmt.Errorf("[Get Family List Usecase]: sort by must be name or count")
 )
 
 type GetFamilyListUsecase struct {
-	SpiderStatisticsRepo domain.StatisticsRepository
-	SpiderRepo           domain.SpiderRepository
-	log                  *logger.Logger
+	SpiderRepo domain.SpiderRepository
+	log        *logger.Logger
 }
 
-func NewGetFamilyListUsecase(SpiderStatisticsRepo domain.StatisticsRepository, SpiderRepo domain.SpiderRepository) *GetFamilyListUsecase {
+func NewGetFamilyListUsecase(SpiderRepo domain.SpiderRepository) *GetFamilyListUsecase {
 	return &GetFamilyListUsecase{
-		SpiderStatisticsRepo: SpiderStatisticsRepo,
-		SpiderRepo:           SpiderRepo,
-		log:                  logger.L().Named("GetFamilyList"),
+		SpiderRepo: SpiderRepo,
+		log:        logger.L().Named("GetFamilyList"),
 	}
 }
 
-func (u *GetFamilyListUsecase) Execute(ctx context.Context, page, size int32) ([]model.FamilyList, error) {
+// Execute return one page of family summary and number of family, family list is sorted by name when sortBy is empty
+// and sortBy that is not name or count is rejected
+func (u *GetFamilyListUsecase) Execute(ctx context.Context, sortBy string, page, size int32) ([]model.FamilyList, int64, error) {
 	log := u.log.WithContext(ctx)
 
-	spiderStatisticeList, err := u.SpiderStatisticsRepo.FindFamilyListWithLimitSizePage(ctx, page, size)
-	if err != nil {
-		log.Errorf("[get family list usecase] find family list from statistice failed, error: %+v", err)
-		return nil, ErrorMongoTechnicalFail
+	switch sortBy {
+	case "":
+		sortBy = model.FAMILY_LIST_SORT_NAME
+	case model.FAMILY_LIST_SORT_NAME, model.FAMILY_LIST_SORT_COUNT:
+	default:
+		log.Errorf("[get family list usecase] unknown sort by `%v`", sortBy)
+		return nil, 0, ErrorGetFamilyListInvalidSortBy
 	}
 
-	var familyList []model.FamilyList
-
-	for _, value := range spiderStatisticeList {
-		var tempFamilyList model.FamilyList
-
-		tempFamilyList.Family = value.FamilyName
-
-		spiderInfoList, err := u.SpiderRepo.FindSpiderInfoByFirstFamilyOrGenus(ctx, "family", value.FamilyName)
-		if err != nil {
-			log.Errorf("[get family list usecase] find one spider info failed, error: %+v", err)
-			if err == repository.ErrorMongoNotFound {
-				tempFamilyList.Author = "N/A"
-				tempFamilyList.Quantity = int32(0)
-				familyList = append(familyList, tempFamilyList)
-				continue
-			}
-
-			return nil, ErrorMongoTechnicalFail
-		}
-
-		tempFamilyList.Author = spiderInfoList[0].Author
-		tempFamilyList.Quantity = int32(len(spiderInfoList))
-
-		familyList = append(familyList, tempFamilyList)
+	familyList, total, err := u.SpiderRepo.FindFamilyList(ctx, sortBy, page, size)
+	if err != nil {
+		log.Errorf("[get family list usecase] find family list failed, error: %+v", err)
+		return nil, 0, ErrorMongoTechnicalFail
 	}
 
-	return familyList, nil
+	return familyList, total, nil
 }
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	mock_domain "spider-go/domain/mock"
+	"spider-go/model"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+)
+
+// ======================================================================
+// TestGetFamilyListUsecase_Execute
+// ======================================================================
+func TestGetFamilyListUsecase_Execute(t *testing.T) {
+
+	familyList := []model.FamilyList{
+		{Family: "Agelenidae", Author: "Dankittipakul & Wang", Quantity: 12, GenusCount: 2, SpeciesCount: 5},
+		{Family: "Theridiidae", Author: "Thorell", Quantity: 3, GenusCount: 1, SpeciesCount: 1},
+	}
+
+	tests := []struct {
+		name       string
+		sortBy     string
+		buildStubs func(*mock_domain.MockSpiderRepository)
+		want       []model.FamilyList
+		wantTotal  int64
+		wantErr    error
+	}{
+		{
+			name:   "success_default_sort_by_name",
+			sortBy: "",
+			buildStubs: func(spiderRepo *mock_domain.MockSpiderRepository) {
+				spiderRepo.EXPECT().FindFamilyList(
+					gomock.Any(),
+					gomock.Eq(model.FAMILY_LIST_SORT_NAME),
+					gomock.Eq(int32(0)),
+					gomock.Eq(int32(2)),
+				).Return(familyList, int64(7), nil)
+			},
+			want:      familyList,
+			wantTotal: 7,
+			wantErr:   nil,
+		},
+		{
+			name:   "success_sort_by_count",
+			sortBy: model.FAMILY_LIST_SORT_COUNT,
+			buildStubs: func(spiderRepo *mock_domain.MockSpiderRepository) {
+				spiderRepo.EXPECT().FindFamilyList(
+					gomock.Any(),
+					gomock.Eq(model.FAMILY_LIST_SORT_COUNT),
+					gomock.Eq(int32(0)),
+					gomock.Eq(int32(2)),
+				).Return(familyList, int64(7), nil)
+			},
+			want:      familyList,
+			wantTotal: 7,
+			wantErr:   nil,
+		},
+		{
+			name:       "unknown_sort_by",
+			sortBy:     "quantity",
+			buildStubs: func(spiderRepo *mock_domain.MockSpiderRepository) {},
+			want:       nil,
+			wantTotal:  0,
+			wantErr:    ErrorGetFamilyListInvalidSortBy,
+		},
+		{
+			name:       "sort_by_is_case_sensitive",
+			sortBy:     "Count",
+			buildStubs: func(spiderRepo *mock_domain.MockSpiderRepository) {},
+			want:       nil,
+			wantTotal:  0,
+			wantErr:    ErrorGetFamilyListInvalidSortBy,
+		},
+		{
+			name:   "find_family_list_error",
+			sortBy: model.FAMILY_LIST_SORT_NAME,
+			buildStubs: func(spiderRepo *mock_domain.MockSpiderRepository) {
+				spiderRepo.EXPECT().FindFamilyList(
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+					gomock.Any(),
+				).Return(nil, int64(0), errors.New("mongo error"))
+			},
+			want:      nil,
+			wantTotal: 0,
+			wantErr:   ErrorMongoTechnicalFail,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			spiderRepo := mock_domain.NewMockSpiderRepository(ctrl)
+			tt.buildStubs(spiderRepo)
+
+			u := NewGetFamilyListUsecase(spiderRepo)
+			got, total, err := u.Execute(context.TODO(), tt.sortBy, 0, 2)
+			if err != tt.wantErr {
+				t.Errorf("GetFamilyListUsecase.Execute() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) || total != tt.wantTotal {
+				t.Errorf("GetFamilyListUsecase.Execute() = %+v, %v, want %+v, %v", got, total, tt.want, tt.wantTotal)
+			}
+		})
+	}
+}
+
+// **********************************************************************
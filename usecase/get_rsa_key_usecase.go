@@ -7,8 +7,10 @@ import (
 	"spider-go/domain"
 	"spider-go/logger"
 	"spider-go/model"
+	"spider-go/repository"
 	"spider-go/utils/cryptography"
 	"spider-go/utils/random"
+	"time"
 )
 
 type GetRsaKey struct {
@@ -20,6 +22,10 @@ type GetRsaKey struct {
 var (
 	ErrorGenerateRSAKey  = fmt.Errorf("[Get RSA Key Usecase]: generate rsa key error")
 	ErrorSaveDataToRedis = fmt.Errorf("[Get RSA Key Usecase]: save data to redis failed")
+	ErrorRSAKeyExpired   = fmt.Errorf("[Get RSA Key Usecase]: rsa key is expired or not found")
+	ErrorRSAKeyReplayed  = fmt.Errorf("[Get RSA Key Usecase]: rsa key is already used")
+	ErrorDecryptRSA      = fmt.Errorf("[Get RSA Key Usecase]: decrypt data with rsa key failed")
+	ErrorGetRSAKey       = fmt.Errorf("[Get RSA Key Usecase]: get rsa key from redis failed")
 )
 
 func NewGetRsaKey(redisRepo domain.RedisRepository, conf *config.Root) domain.GetRsaKeyUsecase {
@@ -35,9 +41,11 @@ func (u *GetRsaKey) GenerateRsaKey(ctx context.Context) (publicKey, searchKey st
 	log := u.log.WithContext(ctx)
 
 	//  generate random string for make redis key
-	r := random.NewRandom()
-
-	searchKey = r.RandomString(u.config.RSAOption.RandomKeySize)
+	searchKey, err = random.NewRandom().RandomToken(u.config.RSAOption.RandomKeySize)
+	if err != nil {
+		log.Errorf("generate search key error: %+v", err)
+		return "", "", ErrorGenerateRSAKey
+	}
 
 	redisKey := fmt.Sprintf(u.config.RedisOption.RSA.KeyFormat, searchKey)
 
@@ -66,3 +74,54 @@ func (u *GetRsaKey) GenerateRsaKey(ctx context.Context) (publicKey, searchKey st
 	return publicKey, searchKey, nil
 
 }
+
+// DecryptWithRsaKey decrypt every cipher text with private key of search key,
+// private key is removed from redis at the first use
+func (u *GetRsaKey) DecryptWithRsaKey(ctx context.Context, searchKey string, cipherTexts ...string) (plainTexts []string, err error) {
+
+	log := u.log.WithContext(ctx)
+
+	var rsaKey model.RedisRsaKey
+
+	redisKey := fmt.Sprintf(u.config.RedisOption.RSA.KeyFormat, searchKey)
+	usedKey := fmt.Sprintf(u.config.RedisOption.RSAUsed.KeyFormat, searchKey)
+
+	if err := u.redisRepo.GetAndDeleteDataFromRedis(ctx, redisKey, &rsaKey); err != nil {
+		if err != repository.ErrorRedisNotFound {
+			log.Errorf("[DecryptWithRsaKey] get rsa key `%v` error: %+v", searchKey, err)
+			return nil, ErrorGetRSAKey
+		}
+
+		var used model.RedisRsaKeyUsed
+		if err := u.redisRepo.GetDataFromRedis(ctx, usedKey, &used); err == nil {
+			log.Warnf("[DecryptWithRsaKey] rsa key `%v` is replayed, it was used at %v", searchKey, used.UsedAt)
+			return nil, ErrorRSAKeyReplayed
+		}
+
+		log.Errorf("[DecryptWithRsaKey] rsa key `%v` is expired or not found", searchKey)
+		return nil, ErrorRSAKeyExpired
+	}
+
+	used := model.RedisRsaKeyUsed{
+		UsedAt: time.Now(),
+	}
+
+	if err := u.redisRepo.SetDataToRedisWithTTL(ctx, usedKey, used, u.config.RedisOption.RSAUsed.TTL); err != nil {
+		log.Errorf("[DecryptWithRsaKey] save used marker of rsa key `%v` error: %+v", searchKey, err)
+	}
+
+	crypto := cryptography.NewCrypto()
+
+	plainTexts = make([]string, 0, len(cipherTexts))
+
+	for _, cipherText := range cipherTexts {
+		plainText, err := crypto.DecryptRSAOAEP(rsaKey.PrivateKey, cipherText)
+		if err != nil {
+			log.Errorf("[DecryptWithRsaKey] decrypt with rsa key `%v` error: %+v", searchKey, err)
+			return nil, ErrorDecryptRSA
+		}
+		plainTexts = append(plainTexts, plainText)
+	}
+
+	return plainTexts, nil
+}
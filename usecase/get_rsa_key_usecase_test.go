@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"reflect"
+	"spider-go/config"
+	mock_domain "spider-go/domain/mock"
+	"spider-go/model"
+	"spider-go/repository"
+	"spider-go/utils/cryptography"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+type commonStubsGetRsaKey struct {
+	mockRedisRepo *mock_domain.MockRedisRepository
+}
+
+var getRsaKeyConfig = &config.Root{
+	RedisOption: config.RedisOptions{
+		RSA: config.RedisOption{
+			KeyFormat: "rsa_%s",
+			TTL:       5 * time.Minute,
+		},
+		RSAUsed: config.RedisOption{
+			KeyFormat: "rsa_used_%s",
+			TTL:       5 * time.Minute,
+		},
+	},
+}
+
+const unittestSearchKey = "search-key"
+
+// ======================================================================
+// TestGetRsaKey_DecryptWithRsaKey
+// ======================================================================
+func TestGetRsaKey_DecryptWithRsaKey(t *testing.T) {
+
+	privateKey, publicKey, err := cryptography.NewCrypto().GenerateRSAKey(2048)
+	if err != nil {
+		t.Fatalf("generate rsa key error: %+v", err)
+	}
+
+	tests := []struct {
+		name           string
+		cipherTexts    []string
+		buildStubs     func(*commonStubsGetRsaKey)
+		wantPlainTexts []string
+		wantErr        error
+	}{
+		{
+			name:        "success_decrypt_and_mark_used",
+			cipherTexts: []string{encryptForTest(t, publicKey, "password"), encryptForTest(t, publicKey, "confirm")},
+			buildStubs: func(stubs *commonStubsGetRsaKey) {
+				stubRsaKeyFound(stubs, privateKey, publicKey)
+			},
+			wantPlainTexts: []string{"password", "confirm"},
+			wantErr:        nil,
+		},
+		{
+			name:        "invalid_cipher_text",
+			cipherTexts: []string{base64.StdEncoding.EncodeToString([]byte("not encrypted"))},
+			buildStubs: func(stubs *commonStubsGetRsaKey) {
+				stubRsaKeyFound(stubs, privateKey, publicKey)
+			},
+			wantPlainTexts: nil,
+			wantErr:        ErrorDecryptRSA,
+		},
+		{
+			name:           "replayed_key",
+			cipherTexts:    []string{encryptForTest(t, publicKey, "password")},
+			buildStubs:     replayed_rsa_key,
+			wantPlainTexts: nil,
+			wantErr:        ErrorRSAKeyReplayed,
+		},
+		{
+			name:           "expired_key",
+			cipherTexts:    []string{encryptForTest(t, publicKey, "password")},
+			buildStubs:     expired_rsa_key,
+			wantPlainTexts: nil,
+			wantErr:        ErrorRSAKeyExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := commonStubsGetRsaKey{
+				mockRedisRepo: mock_domain.NewMockRedisRepository(ctrl),
+			}
+
+			tt.buildStubs(&stubs)
+
+			u := NewGetRsaKey(stubs.mockRedisRepo, getRsaKeyConfig)
+			gotPlainTexts, err := u.DecryptWithRsaKey(context.TODO(), unittestSearchKey, tt.cipherTexts...)
+			if err != tt.wantErr {
+				t.Errorf("GetRsaKey.DecryptWithRsaKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotPlainTexts, tt.wantPlainTexts) {
+				t.Errorf("GetRsaKey.DecryptWithRsaKey() = %v, want %v", gotPlainTexts, tt.wantPlainTexts)
+			}
+		})
+	}
+}
+
+func encryptForTest(t *testing.T, publicKey, plainText string) string {
+	pemBytes, _ := base64.StdEncoding.DecodeString(publicKey)
+	block, _ := pem.Decode(pemBytes)
+
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		t.Fatalf("parse public key error: %+v", err)
+	}
+
+	cipherText, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub.(*rsa.PublicKey), []byte(plainText), nil)
+	if err != nil {
+		t.Fatalf("encrypt error: %+v", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(cipherText)
+}
+
+func stubRsaKeyFound(stubs *commonStubsGetRsaKey, privateKey, publicKey string) {
+	stubs.mockRedisRepo.EXPECT().GetAndDeleteDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("rsa_"+unittestSearchKey),
+		gomock.Any(),
+	).DoAndReturn(func(_ context.Context, _ string, data interface{}) error {
+		*data.(*model.RedisRsaKey) = model.RedisRsaKey{
+			PrivateKey: privateKey,
+			PublicKey:  publicKey,
+		}
+		return nil
+	})
+
+	stubs.mockRedisRepo.EXPECT().SetDataToRedisWithTTL(
+		gomock.Any(),
+		gomock.Eq("rsa_used_"+unittestSearchKey),
+		gomock.Any(),
+		gomock.Eq(5*time.Minute),
+	).Return(nil)
+}
+
+func replayed_rsa_key(stubs *commonStubsGetRsaKey) {
+	stubs.mockRedisRepo.EXPECT().GetAndDeleteDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("rsa_"+unittestSearchKey),
+		gomock.Any(),
+	).Return(repository.ErrorRedisNotFound)
+
+	stubs.mockRedisRepo.EXPECT().GetDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("rsa_used_"+unittestSearchKey),
+		gomock.Any(),
+	).Return(nil)
+}
+
+func expired_rsa_key(stubs *commonStubsGetRsaKey) {
+	stubs.mockRedisRepo.EXPECT().GetAndDeleteDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("rsa_"+unittestSearchKey),
+		gomock.Any(),
+	).Return(repository.ErrorRedisNotFound)
+
+	stubs.mockRedisRepo.EXPECT().GetDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("rsa_used_"+unittestSearchKey),
+		gomock.Any(),
+	).Return(repository.ErrorRedisNotFound)
+}
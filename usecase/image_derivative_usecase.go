@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"spider-go/domain"
+	"spider-go/logger"
+	"spider-go/model"
+)
+
+type ImageDerivativeUsecase struct {
+	spiderRepo          domain.SpiderRepository
+	derivativeGenerator domain.ImageDerivativeGenerator
+	log                 *logger.Logger
+}
+
+var (
+	ErrorImageDerivativeMongoConnection = fmt.Errorf("[image derivative usecase] error mongo connection")
+)
+
+func NewImageDerivativeUsecase(spiderRepo domain.SpiderRepository, derivativeGenerator domain.ImageDerivativeGenerator) domain.ImageDerivativeUsecase {
+	return &ImageDerivativeUsecase{
+		spiderRepo:          spiderRepo,
+		derivativeGenerator: derivativeGenerator,
+		log:                 logger.L().Named("ImageDerivativeUsecase"),
+	}
+}
+
+// image that can not be generated is reported and backfill continue with next image,
+// backfill can be run again to retry only failed image
+func (u *ImageDerivativeUsecase) BackfillImageDerivatives(ctx context.Context, overwrite bool) (*model.ImageDerivativeBackfillReport, error) {
+	log := u.log.WithContext(ctx)
+
+	spiderInfoList, err := u.spiderRepo.FindSpiderImageFileList(ctx)
+	if err != nil {
+		log.Errorf("[BackfillImageDerivatives] find spider image file error: %+v", err)
+		return nil, ErrorImageDerivativeMongoConnection
+	}
+
+	report := &model.ImageDerivativeBackfillReport{
+		Failed: []model.ImageDerivativeBackfillFailure{},
+	}
+
+	for _, spiderInfo := range spiderInfoList {
+		for _, imageName := range spiderInfo.ImageFile {
+			report.ImageCount++
+
+			if !overwrite {
+				exists, err := u.derivativeGenerator.Exists(ctx, imageName)
+				if err != nil {
+					log.Errorf("[BackfillImageDerivatives] check derivative of `%v` error: %v", imageName, err)
+					report.Failed = append(report.Failed, model.ImageDerivativeBackfillFailure{
+						SpiderUUID: spiderInfo.SpiderUUID,
+						ImageName:  imageName,
+						Error:      err.Error(),
+					})
+					continue
+				}
+
+				if exists {
+					report.Skipped++
+					continue
+				}
+			}
+
+			if err := u.derivativeGenerator.Generate(ctx, imageName); err != nil {
+				log.Errorf("[BackfillImageDerivatives] generate derivative of `%v` error: %v", imageName, err)
+				report.Failed = append(report.Failed, model.ImageDerivativeBackfillFailure{
+					SpiderUUID: spiderInfo.SpiderUUID,
+					ImageName:  imageName,
+					Error:      err.Error(),
+				})
+				continue
+			}
+
+			report.Generated++
+		}
+	}
+
+	log.Infof("[BackfillImageDerivatives] image: %v, generated: %v, skipped: %v, failed: %v", report.ImageCount, report.Generated, report.Skipped, len(report.Failed))
+
+	return report, nil
+}
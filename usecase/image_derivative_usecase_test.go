@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	mock_domain "spider-go/domain/mock"
+	"spider-go/model"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+)
+
+// ======================================================================
+// TestImageDerivativeUsecase_BackfillImageDerivatives
+// ======================================================================
+func TestImageDerivativeUsecase_BackfillImageDerivatives(t *testing.T) {
+
+	spiderInfoList := []model.SpiderInfo{
+		{SpiderUUID: "SPIDER_1", ImageFile: []string{"SPIDER_1-a.png", "SPIDER_1-b.jpeg"}},
+		{SpiderUUID: "SPIDER_2", ImageFile: []string{"SPIDER_2-a.png"}},
+	}
+
+	tests := []struct {
+		name       string
+		overwrite  bool
+		buildStubs func(*mock_domain.MockSpiderRepository, *mock_domain.MockImageDerivativeGenerator)
+		want       *model.ImageDerivativeBackfillReport
+		wantErr    error
+	}{
+		{
+			name:       "success_skip_image_that_has_derivative",
+			overwrite:  false,
+			buildStubs: success_skip_image_that_has_derivative(spiderInfoList),
+			want: &model.ImageDerivativeBackfillReport{
+				ImageCount: 3,
+				Generated:  1,
+				Skipped:    1,
+				Failed: []model.ImageDerivativeBackfillFailure{
+					{SpiderUUID: "SPIDER_2", ImageName: "SPIDER_2-a.png", Error: "decode image failed"},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name:      "success_overwrite_every_image",
+			overwrite: true,
+			buildStubs: func(spiderRepo *mock_domain.MockSpiderRepository, derivativeGenerator *mock_domain.MockImageDerivativeGenerator) {
+				spiderRepo.EXPECT().FindSpiderImageFileList(gomock.Any()).Return(spiderInfoList, nil)
+				derivativeGenerator.EXPECT().Exists(gomock.Any(), gomock.Any()).Times(0)
+				derivativeGenerator.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+			},
+			want: &model.ImageDerivativeBackfillReport{
+				ImageCount: 3,
+				Generated:  3,
+				Failed:     []model.ImageDerivativeBackfillFailure{},
+			},
+			wantErr: nil,
+		},
+		{
+			name:      "find_spider_image_file_error",
+			overwrite: false,
+			buildStubs: func(spiderRepo *mock_domain.MockSpiderRepository, derivativeGenerator *mock_domain.MockImageDerivativeGenerator) {
+				spiderRepo.EXPECT().FindSpiderImageFileList(gomock.Any()).Return(nil, errors.New("mongo error"))
+			},
+			want:    nil,
+			wantErr: ErrorImageDerivativeMongoConnection,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			spiderRepo := mock_domain.NewMockSpiderRepository(ctrl)
+			derivativeGenerator := mock_domain.NewMockImageDerivativeGenerator(ctrl)
+			tt.buildStubs(spiderRepo, derivativeGenerator)
+
+			u := NewImageDerivativeUsecase(spiderRepo, derivativeGenerator)
+			got, err := u.BackfillImageDerivatives(context.TODO(), tt.overwrite)
+			if err != tt.wantErr {
+				t.Errorf("ImageDerivativeUsecase.BackfillImageDerivatives() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ImageDerivativeUsecase.BackfillImageDerivatives() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func success_skip_image_that_has_derivative(spiderInfoList []model.SpiderInfo) func(*mock_domain.MockSpiderRepository, *mock_domain.MockImageDerivativeGenerator) {
+	return func(spiderRepo *mock_domain.MockSpiderRepository, derivativeGenerator *mock_domain.MockImageDerivativeGenerator) {
+		spiderRepo.EXPECT().FindSpiderImageFileList(gomock.Any()).Return(spiderInfoList, nil)
+
+		derivativeGenerator.EXPECT().Exists(gomock.Any(), gomock.Eq("SPIDER_1-a.png")).Return(true, nil)
+		derivativeGenerator.EXPECT().Exists(gomock.Any(), gomock.Eq("SPIDER_1-b.jpeg")).Return(false, nil)
+		derivativeGenerator.EXPECT().Exists(gomock.Any(), gomock.Eq("SPIDER_2-a.png")).Return(false, nil)
+
+		derivativeGenerator.EXPECT().Generate(gomock.Any(), gomock.Eq("SPIDER_1-b.jpeg")).Return(nil)
+		derivativeGenerator.EXPECT().Generate(gomock.Any(), gomock.Eq("SPIDER_2-a.png")).Return(errors.New("decode image failed"))
+	}
+}
+
+// **********************************************************************
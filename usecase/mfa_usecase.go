@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"spider-go/config"
+	"spider-go/domain"
+	"spider-go/logger"
+	"spider-go/model"
+	"spider-go/repository"
+	"spider-go/utils/cryptography"
+	"spider-go/utils/totp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrorMFAInvalidCode        = fmt.Errorf("[MFA Usecase]: invalid second factor code")
+	ErrorMFAAlreadyEnabled     = fmt.Errorf("[MFA Usecase]: second factor is already enabled")
+	ErrorMFANotEnabled         = fmt.Errorf("[MFA Usecase]: second factor is not enabled")
+	ErrorMFAEnrollExpired      = fmt.Errorf("[MFA Usecase]: enrollment is expired, please enroll again")
+	ErrorMFARequiredByRole     = fmt.Errorf("[MFA Usecase]: second factor is required for role of account")
+	ErrorMFAGenerateSecretFail = fmt.Errorf("[MFA Usecase]: generate secret failed")
+	ErrorMFAAccountNotFound    = fmt.Errorf("[MFA Usecase]: account not found")
+	ErrorMFAMongoConnection    = fmt.Errorf("[MFA Usecase]: mongo error")
+	ErrorMFATempDataConnection = fmt.Errorf("[MFA Usecase]: redis error")
+)
+
+const (
+	MFA_RECOVERY_CODE_COUNT = 10
+	MFA_RECOVERY_CODE_SIZE  = 10
+	DEFAULT_MFA_ISSUER      = "spider-thailand"
+)
+
+type MFAUsecase struct {
+	accRepo   domain.AccountRepository
+	redisRepo domain.RedisRepository
+	config    *config.Root
+	log       *logger.Logger
+}
+
+func NewMFAUsecase(accRepo domain.AccountRepository, redisRepo domain.RedisRepository, conf *config.Root) domain.MFAUsecase {
+	return &MFAUsecase{
+		accRepo:   accRepo,
+		redisRepo: redisRepo,
+		config:    conf,
+		log:       logger.L().Named("MFAUsecase"),
+	}
+}
+
+// EnrollMFA create new secret that wait for activate by first code, second factor is not required until activate
+func (u *MFAUsecase) EnrollMFA(ctx context.Context, username string) (otpauthURI, secret string, err error) {
+	log := u.log.WithContext(ctx)
+
+	accountInfo, err := u.accRepo.FindAccountByUsername(ctx, username)
+	if err != nil {
+		log.Errorf("[EnrollMFA] find account `%v` error: %+v", username, err)
+		return "", "", u.mapRepositoryError(err)
+	}
+
+	if accountInfo.MFAEnabled() {
+		return "", "", ErrorMFAAlreadyEnabled
+	}
+
+	secret, err = totp.GenerateSecret()
+	if err != nil {
+		log.Errorf("[EnrollMFA] generate secret error: %+v", err)
+		return "", "", ErrorMFAGenerateSecretFail
+	}
+
+	enroll := model.MFAEnroll{
+		Secret:    secret,
+		CreatedAt: time.Now(),
+	}
+
+	key := fmt.Sprintf(u.config.RedisOption.MFAEnroll.KeyFormat, username)
+
+	if err := u.redisRepo.SetDataToRedisWithTTL(ctx, key, enroll, u.config.RedisOption.MFAEnroll.TTL); err != nil {
+		log.Errorf("[EnrollMFA] save enroll secret of `%v` error: %+v", username, err)
+		return "", "", ErrorMFATempDataConnection
+	}
+
+	return totp.URI(mfaIssuer(u.config.MFA), username, secret), secret, nil
+}
+
+// ActivateMFA enable second factor when code of enroll secret is valid and return recovery codes,
+// recovery codes are shown only this time
+func (u *MFAUsecase) ActivateMFA(ctx context.Context, username, code string) (recoveryCodes []string, err error) {
+	log := u.log.WithContext(ctx)
+
+	accountInfo, err := u.accRepo.FindAccountByUsername(ctx, username)
+	if err != nil {
+		log.Errorf("[ActivateMFA] find account `%v` error: %+v", username, err)
+		return nil, u.mapRepositoryError(err)
+	}
+
+	if accountInfo.MFAEnabled() {
+		return nil, ErrorMFAAlreadyEnabled
+	}
+
+	key := fmt.Sprintf(u.config.RedisOption.MFAEnroll.KeyFormat, username)
+
+	var enroll model.MFAEnroll
+	if err := u.redisRepo.GetDataFromRedis(ctx, key, &enroll); err != nil {
+		if err == repository.ErrorRedisNotFound {
+			return nil, ErrorMFAEnrollExpired
+		}
+		log.Errorf("[ActivateMFA] get enroll secret of `%v` error: %+v", username, err)
+		return nil, ErrorMFATempDataConnection
+	}
+
+	step, ok := totp.Validate(enroll.Secret, code, time.Now())
+	if !ok {
+		log.Warnf("[ActivateMFA] invalid code of account `%v`", username)
+		return nil, ErrorMFAInvalidCode
+	}
+
+	recoveryCodes, err = enableMFA(ctx, u.accRepo, username, enroll.Secret, step)
+	if err != nil {
+		log.Errorf("[ActivateMFA] enable second factor of `%v` error: %+v", username, err)
+		return nil, err
+	}
+
+	if err := u.redisRepo.DeleteDataFromRedis(ctx, key); err != nil {
+		log.Errorf("[ActivateMFA] delete enroll secret of `%v` error: %+v", username, err)
+	}
+
+	log.Infof("[ActivateMFA] account `%v` enable second factor", username)
+
+	return recoveryCodes, nil
+}
+
+// DisableMFA remove second factor by owner, account of enforced role can be reset only by administrator
+func (u *MFAUsecase) DisableMFA(ctx context.Context, username, code string) error {
+	log := u.log.WithContext(ctx)
+
+	accountInfo, err := u.accRepo.FindAccountByUsername(ctx, username)
+	if err != nil {
+		log.Errorf("[DisableMFA] find account `%v` error: %+v", username, err)
+		return u.mapRepositoryError(err)
+	}
+
+	if !accountInfo.MFAEnabled() {
+		return ErrorMFANotEnabled
+	}
+
+	if mfaEnforced(u.config.MFA, accountInfo.Role) {
+		return ErrorMFARequiredByRole
+	}
+
+	if err := verifySecondFactor(ctx, u.accRepo, accountInfo, code); err != nil {
+		log.Warnf("[DisableMFA] verify second factor of `%v` error: %+v", username, err)
+		return err
+	}
+
+	if err := u.accRepo.UpdateAccountMFA(ctx, username, nil); err != nil {
+		log.Errorf("[DisableMFA] remove second factor of `%v` error: %+v", username, err)
+		return u.mapRepositoryError(err)
+	}
+
+	log.Infof("[DisableMFA] account `%v` disable second factor", username)
+
+	return nil
+}
+
+func (u *MFAUsecase) mapRepositoryError(err error) error {
+	if err == repository.ErrorMongoNotFound {
+		return ErrorMFAAccountNotFound
+	}
+	return ErrorMFAMongoConnection
+}
+
+// ========================================================
+// second factor helper that shared with login
+// ========================================================
+
+func mfaIssuer(conf config.MFA) string {
+	if conf.Issuer == "" {
+		return DEFAULT_MFA_ISSUER
+	}
+	return conf.Issuer
+}
+
+func mfaEnforced(conf config.MFA, role string) bool {
+	for _, enforcedRole := range conf.EnforcedRoles {
+		if enforcedRole == role {
+			return true
+		}
+	}
+	return false
+}
+
+// enableMFA save secret with new recovery codes, step is time step of code that used for activate
+func enableMFA(ctx context.Context, accRepo domain.AccountRepository, username, secret string, step int64) (recoveryCodes []string, err error) {
+	recoveryCodes, hashedCodes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, ErrorMFAGenerateSecretFail
+	}
+
+	mfa := &model.AccountMFA{
+		Enabled:       true,
+		Secret:        secret,
+		RecoveryCodes: hashedCodes,
+		LastUsedStep:  step,
+		EnabledAt:     time.Now(),
+	}
+
+	if err := accRepo.UpdateAccountMFA(ctx, username, mfa); err != nil {
+		if err == repository.ErrorMongoNotFound {
+			return nil, ErrorMFAAccountNotFound
+		}
+		return nil, ErrorMFAMongoConnection
+	}
+
+	return recoveryCodes, nil
+}
+
+// verifySecondFactor accept totp code or recovery code, both are single use
+func verifySecondFactor(ctx context.Context, accRepo domain.AccountRepository, accountInfo *model.Account, code string) error {
+	if !accountInfo.MFAEnabled() {
+		return ErrorMFANotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+
+	if len(code) == totp.DIGITS {
+		step, ok := totp.Validate(accountInfo.MFA.Secret, code, time.Now())
+		if !ok {
+			return ErrorMFAInvalidCode
+		}
+
+		// code of step that already used is replay
+		if err := accRepo.UpdateMFALastUsedStep(ctx, accountInfo.Username, step); err != nil {
+			if err == repository.ErrorMongoNotFound {
+				return ErrorMFAInvalidCode
+			}
+			return ErrorMFAMongoConnection
+		}
+
+		return nil
+	}
+
+	codeHash := cryptography.NewCrypto().HashSHA256(normalizeRecoveryCode(code))
+
+	if err := accRepo.UseMFARecoveryCode(ctx, accountInfo.Username, codeHash); err != nil {
+		if err == repository.ErrorMongoNotFound {
+			return ErrorMFAInvalidCode
+		}
+		return ErrorMFAMongoConnection
+	}
+
+	return nil
+}
+
+// generateRecoveryCodes return plain codes in format xxxxx-xxxxx and sha256 of them
+func generateRecoveryCodes() (codes, hashedCodes []string, err error) {
+	crypto := cryptography.NewCrypto()
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+
+	codes = make([]string, 0, MFA_RECOVERY_CODE_COUNT)
+	hashedCodes = make([]string, 0, MFA_RECOVERY_CODE_COUNT)
+
+	for i := 0; i < MFA_RECOVERY_CODE_COUNT; i++ {
+		ranByte := make([]byte, MFA_RECOVERY_CODE_SIZE)
+		if _, err := rand.Read(ranByte); err != nil {
+			return nil, nil, err
+		}
+
+		raw := strings.ToLower(encoding.EncodeToString(ranByte))[:MFA_RECOVERY_CODE_SIZE]
+		code := raw[:MFA_RECOVERY_CODE_SIZE/2] + "-" + raw[MFA_RECOVERY_CODE_SIZE/2:]
+
+		codes = append(codes, code)
+		hashedCodes = append(hashedCodes, crypto.HashSHA256(normalizeRecoveryCode(code)))
+	}
+
+	return codes, hashedCodes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
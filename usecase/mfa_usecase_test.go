@@ -0,0 +1,254 @@
+package usecase
+
+import (
+	"context"
+	"spider-go/config"
+	mock_domain "spider-go/domain/mock"
+	"spider-go/model"
+	"spider-go/repository"
+	"spider-go/utils/cryptography"
+	"spider-go/utils/totp"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+type commonStubsMFA struct {
+	mockAccRepo   *mock_domain.MockAccountRepository
+	mockRedisRepo *mock_domain.MockRedisRepository
+}
+
+var mfaConfig = &config.Root{
+	RedisOption: config.RedisOptions{
+		MFAEnroll: config.RedisOption{
+			KeyFormat: "mfa_enroll_%s",
+			TTL:       10 * time.Minute,
+		},
+		MFAPending: config.RedisOption{
+			KeyFormat: "mfa_pending_%s",
+			TTL:       5 * time.Minute,
+		},
+	},
+	MFA: config.MFA{
+		Issuer:        "unittest",
+		EnforcedRoles: []string{model.ACCOUNT_ROLE_MASTER},
+	},
+}
+
+const (
+	unittestMFASecret       = "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+	unittestMFARecoveryCode = "abcde-fghij"
+)
+
+func unittestMFACode() string {
+	code, _ := totp.GenerateCode(unittestMFASecret, totp.Step(time.Now()))
+	return code
+}
+
+func unittestMFAAccount(role string) *model.Account {
+	return &model.Account{
+		Username: "unittest",
+		Role:     role,
+		MFA: &model.AccountMFA{
+			Enabled: true,
+			Secret:  unittestMFASecret,
+		},
+	}
+}
+
+// ======================================================================
+// TestMFAUsecase_ActivateMFA
+// ======================================================================
+func TestMFAUsecase_ActivateMFA(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		code       string
+		buildStubs func(*commonStubsMFA)
+		wantErr    error
+	}{
+		{
+			name:       "success_activate_and_return_recovery_codes",
+			code:       unittestMFACode(),
+			buildStubs: success_activate_mfa,
+			wantErr:    nil,
+		},
+		{
+			name:       "invalid_code",
+			code:       "000000",
+			buildStubs: stubMFAEnroll,
+			wantErr:    ErrorMFAInvalidCode,
+		},
+		{
+			name:       "enroll_expired",
+			code:       unittestMFACode(),
+			buildStubs: mfa_enroll_expired,
+			wantErr:    ErrorMFAEnrollExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := commonStubsMFA{
+				mockAccRepo:   mock_domain.NewMockAccountRepository(ctrl),
+				mockRedisRepo: mock_domain.NewMockRedisRepository(ctrl),
+			}
+
+			tt.buildStubs(&stubs)
+
+			u := NewMFAUsecase(stubs.mockAccRepo, stubs.mockRedisRepo, mfaConfig)
+			recoveryCodes, err := u.ActivateMFA(context.TODO(), "unittest", tt.code)
+			if err != tt.wantErr {
+				t.Errorf("MFAUsecase.ActivateMFA() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && len(recoveryCodes) != MFA_RECOVERY_CODE_COUNT {
+				t.Errorf("MFAUsecase.ActivateMFA() recovery codes = %v, want %v codes", recoveryCodes, MFA_RECOVERY_CODE_COUNT)
+			}
+		})
+	}
+}
+
+func stubMFAEnroll(stubs *commonStubsMFA) {
+	stubs.mockAccRepo.EXPECT().FindAccountByUsername(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+	).Return(&model.Account{Username: "unittest"}, nil)
+
+	stubs.mockRedisRepo.EXPECT().GetDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("mfa_enroll_unittest"),
+		gomock.Any(),
+	).DoAndReturn(func(_ context.Context, _ string, data interface{}) error {
+		data.(*model.MFAEnroll).Secret = unittestMFASecret
+		return nil
+	})
+}
+
+func success_activate_mfa(stubs *commonStubsMFA) {
+	stubMFAEnroll(stubs)
+
+	stubs.mockAccRepo.EXPECT().UpdateAccountMFA(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+		gomock.Any(),
+	).DoAndReturn(func(_ context.Context, _ string, mfa *model.AccountMFA) error {
+		if !mfa.Enabled || mfa.Secret != unittestMFASecret || len(mfa.RecoveryCodes) != MFA_RECOVERY_CODE_COUNT {
+			return repository.ErrorMongoNotFound
+		}
+		return nil
+	})
+
+	stubs.mockRedisRepo.EXPECT().DeleteDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("mfa_enroll_unittest"),
+	).Return(nil)
+}
+
+func mfa_enroll_expired(stubs *commonStubsMFA) {
+	stubs.mockAccRepo.EXPECT().FindAccountByUsername(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+	).Return(&model.Account{Username: "unittest"}, nil)
+
+	stubs.mockRedisRepo.EXPECT().GetDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("mfa_enroll_unittest"),
+		gomock.Any(),
+	).Return(repository.ErrorRedisNotFound)
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestMFAUsecase_DisableMFA
+// ======================================================================
+func TestMFAUsecase_DisableMFA(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		code       string
+		buildStubs func(*commonStubsMFA)
+		wantErr    error
+	}{
+		{
+			name:       "success_disable_with_recovery_code",
+			code:       "ABCDE-FGHIJ",
+			buildStubs: success_disable_with_recovery_code,
+			wantErr:    nil,
+		},
+		{
+			name:       "replay_totp_code",
+			code:       unittestMFACode(),
+			buildStubs: replay_totp_code,
+			wantErr:    ErrorMFAInvalidCode,
+		},
+		{
+			name: "enforced_role_cannot_disable",
+			code: unittestMFACode(),
+			buildStubs: func(stubs *commonStubsMFA) {
+				stubs.mockAccRepo.EXPECT().FindAccountByUsername(
+					gomock.Any(),
+					gomock.Eq("unittest"),
+				).Return(unittestMFAAccount(model.ACCOUNT_ROLE_MASTER), nil)
+			},
+			wantErr: ErrorMFARequiredByRole,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := commonStubsMFA{
+				mockAccRepo:   mock_domain.NewMockAccountRepository(ctrl),
+				mockRedisRepo: mock_domain.NewMockRedisRepository(ctrl),
+			}
+
+			tt.buildStubs(&stubs)
+
+			u := NewMFAUsecase(stubs.mockAccRepo, stubs.mockRedisRepo, mfaConfig)
+			if err := u.DisableMFA(context.TODO(), "unittest", tt.code); err != tt.wantErr {
+				t.Errorf("MFAUsecase.DisableMFA() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func success_disable_with_recovery_code(stubs *commonStubsMFA) {
+	stubs.mockAccRepo.EXPECT().FindAccountByUsername(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+	).Return(unittestMFAAccount(model.ACCOUNT_ROLE_ADMIN), nil)
+
+	stubs.mockAccRepo.EXPECT().UseMFARecoveryCode(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+		gomock.Eq(cryptography.NewCrypto().HashSHA256(normalizeRecoveryCode(unittestMFARecoveryCode))),
+	).Return(nil)
+
+	stubs.mockAccRepo.EXPECT().UpdateAccountMFA(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+		gomock.Nil(),
+	).Return(nil)
+}
+
+func replay_totp_code(stubs *commonStubsMFA) {
+	stubs.mockAccRepo.EXPECT().FindAccountByUsername(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+	).Return(unittestMFAAccount(model.ACCOUNT_ROLE_ADMIN), nil)
+
+	// last used step is not older than step of code
+	stubs.mockAccRepo.EXPECT().UpdateMFALastUsedStep(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+		gomock.Any(),
+	).Return(repository.ErrorMongoNotFound)
+}
+
+// **********************************************************************
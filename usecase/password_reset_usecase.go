@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"spider-go/config"
+	"spider-go/domain"
+	"spider-go/logger"
+	"spider-go/model"
+	"spider-go/repository"
+	"spider-go/utils/cryptography"
+	"spider-go/utils/policy"
+	"spider-go/utils/random"
+	"time"
+)
+
+var (
+	ErrorPasswordResetInvalidToken            = fmt.Errorf("[Password Reset Usecase]: reset token is invalid, expired or already used")
+	ErrorPasswordResetConfirmPasswordNotMatch = fmt.Errorf("[Password Reset Usecase]: password and confirm password not match")
+	ErrorPasswordResetPasswordNotQualify      = fmt.Errorf("[Password Reset Usecase]: password does not qualify")
+	ErrorPasswordResetHashPasswordFail        = fmt.Errorf("[Password Reset Usecase]: hashing password failed")
+	ErrorPasswordResetGenerateTokenFail       = fmt.Errorf("[Password Reset Usecase]: generate reset token failed")
+	ErrorPasswordResetNotifyFail              = fmt.Errorf("[Password Reset Usecase]: send reset token failed")
+	ErrorPasswordResetMongoConnection         = fmt.Errorf("[Password Reset Usecase]: mongo error")
+	ErrorPasswordResetTempDataConnection      = fmt.Errorf("[Password Reset Usecase]: redis error")
+)
+
+const (
+	DEFAULT_PASSWORD_RESET_TOKEN_SIZE = 32
+	PASSWORD_RESET_SUBJECT            = "Reset your password"
+)
+
+type PasswordResetUsecase struct {
+	accRepo          domain.AccountRepository
+	redisRepo        domain.RedisRepository
+	notifier         domain.Notifier
+	authorities      domain.Authorities
+	credentialPolicy *policy.CredentialPolicy
+	passwordHasher   cryptography.PasswordHasher
+	config           *config.Root
+	log              *logger.Logger
+}
+
+func NewPasswordResetUsecase(
+	accRepo domain.AccountRepository,
+	redisRepo domain.RedisRepository,
+	notifier domain.Notifier,
+	authorities domain.Authorities,
+	conf *config.Root,
+) domain.PasswordResetUsecase {
+	return &PasswordResetUsecase{
+		accRepo:          accRepo,
+		redisRepo:        redisRepo,
+		notifier:         notifier,
+		authorities:      authorities,
+		credentialPolicy: policy.NewCredentialPolicy(conf.CredentialPolicy),
+		passwordHasher:   cryptography.NewPasswordHasher(conf.PasswordHash),
+		config:           conf,
+		log:              logger.L().Named("PasswordResetUsecase"),
+	}
+}
+
+// RequestPasswordReset send one-time token to email of account, caller always get success
+// when account can not receive token so response does not tell which username exists
+func (u *PasswordResetUsecase) RequestPasswordReset(ctx context.Context, username string) error {
+	log := u.log.WithContext(ctx)
+
+	accountInfo, err := u.accRepo.FindAccountByUsername(ctx, username)
+	if err != nil {
+		if err == repository.ErrorMongoNotFound {
+			log.Warnf("[RequestPasswordReset] account `%v` not found", username)
+			return nil
+		}
+		log.Errorf("[RequestPasswordReset] find account `%v` error: %+v", username, err)
+		return ErrorPasswordResetMongoConnection
+	}
+
+	if accountInfo.IsDisabled() || accountInfo.Email == "" {
+		log.Warnf("[RequestPasswordReset] account `%v` is disabled or has no email", username)
+		return nil
+	}
+
+	tokenSize := u.config.PasswordReset.TokenSize
+	if tokenSize <= 0 {
+		tokenSize = DEFAULT_PASSWORD_RESET_TOKEN_SIZE
+	}
+
+	token, err := random.NewRandom().RandomToken(tokenSize)
+	if err != nil {
+		log.Errorf("[RequestPasswordReset] generate reset token error: %+v", err)
+		return ErrorPasswordResetGenerateTokenFail
+	}
+
+	resetData := model.PasswordReset{
+		Username:  accountInfo.Username,
+		TokenHash: cryptography.NewCrypto().HashSHA256(token),
+		CreatedAt: time.Now(),
+	}
+
+	// only latest token of user is usable, token that issued before is removed
+	userKey := fmt.Sprintf(u.config.RedisOption.PasswordResetUser.KeyFormat, accountInfo.Username)
+
+	var previous model.PasswordReset
+	if err := u.redisRepo.GetDataFromRedis(ctx, userKey, &previous); err == nil {
+		if err := u.redisRepo.DeleteDataFromRedis(ctx, u.tokenKey(previous.TokenHash)); err != nil {
+			log.Errorf("[RequestPasswordReset] delete previous reset token of `%v` error: %+v", username, err)
+		}
+	}
+
+	ttl := u.config.RedisOption.PasswordReset.TTL
+
+	if err := u.redisRepo.SetDataToRedisWithTTL(ctx, u.tokenKey(resetData.TokenHash), resetData, ttl); err != nil {
+		log.Errorf("[RequestPasswordReset] save reset token of `%v` error: %+v", username, err)
+		return ErrorPasswordResetTempDataConnection
+	}
+
+	if err := u.redisRepo.SetDataToRedisWithTTL(ctx, userKey, resetData, ttl); err != nil {
+		log.Errorf("[RequestPasswordReset] save latest reset token of `%v` error: %+v", username, err)
+		return ErrorPasswordResetTempDataConnection
+	}
+
+	if err := u.notifier.Send(ctx, u.buildResetNotification(accountInfo, token, ttl)); err != nil {
+		log.Errorf("[RequestPasswordReset] send reset token to `%v` error: %+v", username, err)
+		return ErrorPasswordResetNotifyFail
+	}
+
+	log.Infof("[RequestPasswordReset] reset token of account `%v` is sent", username)
+
+	return nil
+}
+
+// ConfirmPasswordReset set new password with reset token, token is consumed only when new password is qualified
+// and every session of account is revoked after password change
+func (u *PasswordResetUsecase) ConfirmPasswordReset(ctx context.Context, token, newPassword, confirmPassword string) error {
+	log := u.log.WithContext(ctx)
+
+	if newPassword != confirmPassword {
+		return ErrorPasswordResetConfirmPasswordNotMatch
+	}
+
+	tokenHash := cryptography.NewCrypto().HashSHA256(token)
+	tokenKey := u.tokenKey(tokenHash)
+
+	var resetData model.PasswordReset
+	if err := u.redisRepo.GetDataFromRedis(ctx, tokenKey, &resetData); err != nil {
+		if err == repository.ErrorRedisNotFound {
+			log.Warnf("[ConfirmPasswordReset] reset token is invalid or expired")
+			return ErrorPasswordResetInvalidToken
+		}
+		log.Errorf("[ConfirmPasswordReset] get reset token error: %+v", err)
+		return ErrorPasswordResetTempDataConnection
+	}
+
+	if err := u.credentialPolicy.ValidatePassword(resetData.Username, newPassword); err != nil {
+		log.Errorf("[ConfirmPasswordReset] new password of account `%v` does not qualify: %+v", resetData.Username, err)
+		return ErrorPasswordResetPasswordNotQualify
+	}
+
+	// consume token, concurrent confirm with the same token get not found here
+	if err := u.redisRepo.GetAndDeleteDataFromRedis(ctx, tokenKey, &resetData); err != nil {
+		if err == repository.ErrorRedisNotFound {
+			log.Warnf("[ConfirmPasswordReset] reset token of `%v` is already used", resetData.Username)
+			return ErrorPasswordResetInvalidToken
+		}
+		log.Errorf("[ConfirmPasswordReset] consume reset token error: %+v", err)
+		return ErrorPasswordResetTempDataConnection
+	}
+
+	userKey := fmt.Sprintf(u.config.RedisOption.PasswordResetUser.KeyFormat, resetData.Username)
+
+	var latest model.PasswordReset
+	if err := u.redisRepo.GetAndDeleteDataFromRedis(ctx, userKey, &latest); err != nil ||
+		subtle.ConstantTimeCompare([]byte(latest.TokenHash), []byte(tokenHash)) != 1 {
+		log.Warnf("[ConfirmPasswordReset] reset token of `%v` is not the latest token, error: %+v", resetData.Username, err)
+		return ErrorPasswordResetInvalidToken
+	}
+
+	hashPass, err := u.passwordHasher.Hash(newPassword)
+	if err != nil {
+		log.Errorf("[ConfirmPasswordReset] hashing password error: %+v", err)
+		return ErrorPasswordResetHashPasswordFail
+	}
+
+	if err := u.accRepo.UpdateAccountPassword(ctx, resetData.Username, hashPass); err != nil {
+		log.Errorf("[ConfirmPasswordReset] update password of account `%v` error: %+v", resetData.Username, err)
+		if err == repository.ErrorMongoNotFound {
+			return ErrorPasswordResetInvalidToken
+		}
+		return ErrorPasswordResetMongoConnection
+	}
+
+	if err := u.authorities.RevokeAllSessions(ctx, resetData.Username, resetData.Username); err != nil {
+		log.Errorf("[ConfirmPasswordReset] revoke sessions of account `%v` error: %+v", resetData.Username, err)
+		return ErrorPasswordResetTempDataConnection
+	}
+
+	log.Infof("[ConfirmPasswordReset] account `%v` reset password successful", resetData.Username)
+
+	return nil
+}
+
+// ********************************************************
+
+func (u *PasswordResetUsecase) tokenKey(tokenHash string) string {
+	return fmt.Sprintf(u.config.RedisOption.PasswordReset.KeyFormat, tokenHash)
+}
+
+func (u *PasswordResetUsecase) buildResetNotification(accountInfo *model.Account, token string, ttl time.Duration) model.Notification {
+	link := token
+	if u.config.PasswordReset.ResetURLFormat != "" {
+		link = fmt.Sprintf(u.config.PasswordReset.ResetURLFormat, token)
+	}
+
+	body := fmt.Sprintf(
+		"Hello %v,\n\nWe received a request to reset the password of your account.\n"+
+			"Use the following to set a new password, it can be used once and expires in %v:\n\n%v\n\n"+
+			"If you did not request a password reset, you can ignore this message.\n",
+		accountInfo.Username,
+		ttl,
+		link,
+	)
+
+	return model.Notification{
+		To:      accountInfo.Email,
+		Subject: PASSWORD_RESET_SUBJECT,
+		Body:    body,
+	}
+}
@@ -0,0 +1,331 @@
+package usecase
+
+import (
+	"context"
+	"spider-go/config"
+	mock_domain "spider-go/domain/mock"
+	"spider-go/model"
+	"spider-go/repository"
+	"spider-go/utils/cryptography"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+type commonStubsPasswordReset struct {
+	mockAccRepo     *mock_domain.MockAccountRepository
+	mockRedisRepo   *mock_domain.MockRedisRepository
+	mockNotifier    *mock_domain.MockNotifier
+	mockAuthorities *mock_domain.MockAuthorities
+}
+
+var passwordResetConfig = &config.Root{
+	PasswordHash: unittestPasswordHash,
+	RedisOption: config.RedisOptions{
+		PasswordReset: config.RedisOption{
+			KeyFormat: "password_reset_%s",
+			TTL:       15 * time.Minute,
+		},
+		PasswordResetUser: config.RedisOption{
+			KeyFormat: "password_reset_user_%s",
+			TTL:       15 * time.Minute,
+		},
+	},
+	PasswordReset: config.PasswordReset{
+		ResetURLFormat: "https://spider.example/reset-password?token=%s",
+	},
+}
+
+const (
+	unittestResetToken  = "unittest_reset_token"
+	unittestNewPassword = "N3w-Sp1der-Pass!"
+)
+
+var unittestResetTokenHash = cryptography.NewCrypto().HashSHA256(unittestResetToken)
+
+// ======================================================================
+// TestPasswordResetUsecase_RequestPasswordReset
+// ======================================================================
+func TestPasswordResetUsecase_RequestPasswordReset(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		buildStubs func(*commonStubsPasswordReset)
+		wantErr    error
+	}{
+		{
+			name:       "success_send_reset_token",
+			buildStubs: success_send_reset_token,
+			wantErr:    nil,
+		},
+		{
+			name:       "account_not_found_still_success",
+			buildStubs: reset_account_not_found,
+			wantErr:    nil,
+		},
+		{
+			name:       "account_without_email_still_success",
+			buildStubs: reset_account_without_email,
+			wantErr:    nil,
+		},
+		{
+			name:       "notify_failed",
+			buildStubs: reset_notify_failed,
+			wantErr:    ErrorPasswordResetNotifyFail,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := newCommonStubsPasswordReset(ctrl)
+			tt.buildStubs(&stubs)
+
+			u := NewPasswordResetUsecase(stubs.mockAccRepo, stubs.mockRedisRepo, stubs.mockNotifier, stubs.mockAuthorities, passwordResetConfig)
+			if err := u.RequestPasswordReset(context.TODO(), "unittest"); err != tt.wantErr {
+				t.Errorf("PasswordResetUsecase.RequestPasswordReset() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func newCommonStubsPasswordReset(ctrl *gomock.Controller) commonStubsPasswordReset {
+	return commonStubsPasswordReset{
+		mockAccRepo:     mock_domain.NewMockAccountRepository(ctrl),
+		mockRedisRepo:   mock_domain.NewMockRedisRepository(ctrl),
+		mockNotifier:    mock_domain.NewMockNotifier(ctrl),
+		mockAuthorities: mock_domain.NewMockAuthorities(ctrl),
+	}
+}
+
+func stubResetAccount(stubs *commonStubsPasswordReset, email string) {
+	stubs.mockAccRepo.EXPECT().FindAccountByUsername(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+	).Return(&model.Account{Username: "unittest", Email: email}, nil)
+}
+
+func stubSaveResetToken(stubs *commonStubsPasswordReset) {
+	// previous token is removed before issue new token
+	stubs.mockRedisRepo.EXPECT().GetDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("password_reset_user_unittest"),
+		gomock.Any(),
+	).DoAndReturn(func(_ context.Context, _ string, data interface{}) error {
+		data.(*model.PasswordReset).TokenHash = "previous"
+		return nil
+	})
+
+	stubs.mockRedisRepo.EXPECT().DeleteDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("password_reset_previous"),
+	).Return(nil)
+
+	stubs.mockRedisRepo.EXPECT().SetDataToRedisWithTTL(
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Eq(15*time.Minute),
+	).Return(nil).Times(2)
+}
+
+func success_send_reset_token(stubs *commonStubsPasswordReset) {
+	stubResetAccount(stubs, "unittest@spider.example")
+	stubSaveResetToken(stubs)
+
+	stubs.mockNotifier.EXPECT().Send(
+		gomock.Any(),
+		gomock.Any(),
+	).DoAndReturn(func(_ context.Context, notification model.Notification) error {
+		if notification.To != "unittest@spider.example" || !strings.Contains(notification.Body, "https://spider.example/reset-password?token=") {
+			return ErrorPasswordResetNotifyFail
+		}
+		return nil
+	})
+}
+
+func reset_account_not_found(stubs *commonStubsPasswordReset) {
+	stubs.mockAccRepo.EXPECT().FindAccountByUsername(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+	).Return(nil, repository.ErrorMongoNotFound)
+}
+
+func reset_account_without_email(stubs *commonStubsPasswordReset) {
+	stubResetAccount(stubs, "")
+}
+
+func reset_notify_failed(stubs *commonStubsPasswordReset) {
+	stubResetAccount(stubs, "unittest@spider.example")
+	stubSaveResetToken(stubs)
+
+	stubs.mockNotifier.EXPECT().Send(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(ErrorPasswordResetNotifyFail)
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestPasswordResetUsecase_ConfirmPasswordReset
+// ======================================================================
+func TestPasswordResetUsecase_ConfirmPasswordReset(t *testing.T) {
+
+	type args struct {
+		newPassword     string
+		confirmPassword string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		buildStubs func(*commonStubsPasswordReset)
+		wantErr    error
+	}{
+		{
+			name: "success_reset_and_revoke_sessions",
+			args: args{
+				newPassword:     unittestNewPassword,
+				confirmPassword: unittestNewPassword,
+			},
+			buildStubs: success_reset_and_revoke_sessions,
+			wantErr:    nil,
+		},
+		{
+			name: "confirm_password_not_match",
+			args: args{
+				newPassword:     unittestNewPassword,
+				confirmPassword: "other",
+			},
+			buildStubs: func(stubs *commonStubsPasswordReset) {},
+			wantErr:    ErrorPasswordResetConfirmPasswordNotMatch,
+		},
+		{
+			name: "token_expired",
+			args: args{
+				newPassword:     unittestNewPassword,
+				confirmPassword: unittestNewPassword,
+			},
+			buildStubs: reset_token_expired,
+			wantErr:    ErrorPasswordResetInvalidToken,
+		},
+		{
+			name: "password_not_qualify_keep_token",
+			args: args{
+				newPassword:     "short",
+				confirmPassword: "short",
+			},
+			buildStubs: stubFindResetToken,
+			wantErr:    ErrorPasswordResetPasswordNotQualify,
+		},
+		{
+			name: "token_already_used",
+			args: args{
+				newPassword:     unittestNewPassword,
+				confirmPassword: unittestNewPassword,
+			},
+			buildStubs: reset_token_already_used,
+			wantErr:    ErrorPasswordResetInvalidToken,
+		},
+		{
+			name: "token_is_not_latest",
+			args: args{
+				newPassword:     unittestNewPassword,
+				confirmPassword: unittestNewPassword,
+			},
+			buildStubs: reset_token_is_not_latest,
+			wantErr:    ErrorPasswordResetInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := newCommonStubsPasswordReset(ctrl)
+			tt.buildStubs(&stubs)
+
+			u := NewPasswordResetUsecase(stubs.mockAccRepo, stubs.mockRedisRepo, stubs.mockNotifier, stubs.mockAuthorities, passwordResetConfig)
+			if err := u.ConfirmPasswordReset(context.TODO(), unittestResetToken, tt.args.newPassword, tt.args.confirmPassword); err != tt.wantErr {
+				t.Errorf("PasswordResetUsecase.ConfirmPasswordReset() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func fillResetData(tokenHash string) func(context.Context, string, interface{}) error {
+	return func(_ context.Context, _ string, data interface{}) error {
+		*data.(*model.PasswordReset) = model.PasswordReset{
+			Username:  "unittest",
+			TokenHash: tokenHash,
+		}
+		return nil
+	}
+}
+
+func stubFindResetToken(stubs *commonStubsPasswordReset) {
+	stubs.mockRedisRepo.EXPECT().GetDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("password_reset_"+unittestResetTokenHash),
+		gomock.Any(),
+	).DoAndReturn(fillResetData(unittestResetTokenHash))
+}
+
+func stubConsumeResetToken(stubs *commonStubsPasswordReset, latestTokenHash string) {
+	stubFindResetToken(stubs)
+
+	stubs.mockRedisRepo.EXPECT().GetAndDeleteDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("password_reset_"+unittestResetTokenHash),
+		gomock.Any(),
+	).DoAndReturn(fillResetData(unittestResetTokenHash))
+
+	stubs.mockRedisRepo.EXPECT().GetAndDeleteDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("password_reset_user_unittest"),
+		gomock.Any(),
+	).DoAndReturn(fillResetData(latestTokenHash))
+}
+
+func success_reset_and_revoke_sessions(stubs *commonStubsPasswordReset) {
+	stubConsumeResetToken(stubs, unittestResetTokenHash)
+
+	stubs.mockAccRepo.EXPECT().UpdateAccountPassword(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+		gomock.Any(),
+	).Return(nil)
+
+	stubs.mockAuthorities.EXPECT().RevokeAllSessions(
+		gomock.Any(),
+		gomock.Eq("unittest"),
+		gomock.Eq("unittest"),
+	).Return(nil)
+}
+
+func reset_token_expired(stubs *commonStubsPasswordReset) {
+	stubs.mockRedisRepo.EXPECT().GetDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("password_reset_"+unittestResetTokenHash),
+		gomock.Any(),
+	).Return(repository.ErrorRedisNotFound)
+}
+
+func reset_token_already_used(stubs *commonStubsPasswordReset) {
+	stubFindResetToken(stubs)
+
+	stubs.mockRedisRepo.EXPECT().GetAndDeleteDataFromRedis(
+		gomock.Any(),
+		gomock.Eq("password_reset_"+unittestResetTokenHash),
+		gomock.Any(),
+	).Return(repository.ErrorRedisNotFound)
+}
+
+func reset_token_is_not_latest(stubs *commonStubsPasswordReset) {
+	stubConsumeResetToken(stubs, "newer")
+}
+
+// **********************************************************************
@@ -9,8 +9,6 @@ import (
 	"spider-go/model"
 	"spider-go/utils/uuid"
 	"time"
-
-	"golang.org/x/exp/slices"
 )
 
 const SPIDER_PREFIX = "SPIDER_%s"
@@ -18,123 +16,69 @@ const SPIDER_PREFIX = "SPIDER_%s"
 type RegisterSpiderUsecase struct {
 	spiderRepo     domain.SpiderRepository
 	statisticsRepo domain.StatisticsRepository
+	revisionRepo   domain.SpiderRevisionRepository
+	unitOfWork     domain.UnitOfWork
 	log            *logger.Logger
 }
 
 func NewRegisterSpiderUsecase(spiderRepo domain.SpiderRepository,
 	statisticsRepo domain.StatisticsRepository,
+	revisionRepo domain.SpiderRevisionRepository,
+	unitOfWork domain.UnitOfWork,
 ) domain.RegisterSpiderUsecase {
 	return &RegisterSpiderUsecase{
 		spiderRepo:     spiderRepo,
 		statisticsRepo: statisticsRepo,
+		revisionRepo:   revisionRepo,
+		unitOfWork:     unitOfWork,
 		log:            logger.L().Named("RegisterSpiderUsecase"),
 	}
 
 }
 
-func (u *RegisterSpiderUsecase) Register(ctx context.Context, req api_model.SpiderInfo, username string) (string, error) {
+func (u *RegisterSpiderUsecase) Register(ctx context.Context, req api_model.SpiderInfo, loginUser *model.LoginUser) (string, string, error) {
 	log := u.log.WithContext(ctx)
 
 	log.Infof("start regiter spider")
 
-	spiderInfo := u.prepareSpiderInfoFromRequest(req, username)
-
-	// =======================================================
-	// check family, genus, species is exist
-	// =======================================================
+	spiderInfo := u.prepareSpiderInfoFromRequest(req, loginUser.Username)
 
-	err := u.validateSpiderStatisticsIsFound(ctx, spiderInfo)
-	if err != nil {
-		return "", err
+	// record of contributor wait for review before public
+	if !loginUser.HasPermission(model.PERMISSION_SPIDER_REVIEW) {
+		spiderInfo.Status = model.SPIDER_INFO_STATUS_DRAFT
 	}
 
 	// =======================================================
-
-	// =======================================================
-	// save data to mongo
-	// =======================================================
-
-	if err := u.spiderRepo.InsertNewSpider(ctx, spiderInfo); err != nil {
-		log.Errorf("[redister spider usercase] insert spider info failed, error: %+v", err)
-		return "", ErrorMongoTechnicalFail
-	}
-
+	// save data to mongo with statistics of family, genus, species
 	// =======================================================
 
-	return spiderInfo.SpiderUUID, nil
-
-}
-
-func (u *RegisterSpiderUsecase) validateSpiderStatisticsIsFound(ctx context.Context, newSpiderInfo model.SpiderInfo) error {
-	log := u.log.WithContext(ctx)
-
-	spiderStatistics, err := u.statisticsRepo.FindSpiderStatisticsByFamily(ctx, newSpiderInfo.Family)
-	if err != nil {
-		// if mongo not found than insert new spider statistics
-		log.Errorf("[validate spider statistics]  find spider statistics by family `%v`, error: %+v", newSpiderInfo.Family, err)
-		if err.Error() == MONGO_NOT_FOUND {
-			// upsert new spider statistics
-			log.Infof("[validate spider statistics] insert new spider statistics, %v", newSpiderInfo.Family)
-			newSpiderStatistics := u.prepareNewSpiderStatistics(newSpiderInfo)
-			if err := u.statisticsRepo.UpsertSpiderStatistics(ctx, newSpiderInfo.Family, newSpiderStatistics); err != nil {
-				log.Errorf("[validate spider statistics] upsert new spider statistics is failed, error: %+v", err)
-				return ErrorMongoConnection
-			}
-			return nil
-		} else {
-			log.Errorf("[validate spider statistics] mongo error: %+v", err)
-			return ErrorMongoConnection
+	err := u.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := u.spiderRepo.InsertNewSpider(ctx, spiderInfo); err != nil {
+			log.Errorf("[redister spider usercase] insert spider info failed, error: %+v", err)
+			return ErrorMongoTechnicalFail
 		}
-	}
 
-	// find genus
-	genusIndex := slices.IndexFunc(spiderStatistics.Genus, func(s model.GenusGroup) bool {
-		return s.GenusName == newSpiderInfo.Genus
-	})
-
-	if genusIndex == -1 { // if genus index not found
-		newGenus := model.GenusGroup{
-			GenusName: newSpiderInfo.Genus,
-			Species: []model.SpeciesGroup{
-				{
-					SpeciesName: newSpiderInfo.Species,
-				},
-			},
-		}
-		spiderStatistics.Genus = append(spiderStatistics.Genus, newGenus)
-
-		if err := u.statisticsRepo.UpsertSpiderStatistics(ctx, newSpiderInfo.Family, *spiderStatistics); err != nil {
-			log.Errorf("[validate spider statistics] upsert new spider statistics is failed, error: %+v", err)
+		if err := moveSpiderStatistics(ctx, u.statisticsRepo, nil, &spiderInfo); err != nil {
+			log.Errorf("[redister spider usercase] add spider statistics failed, error: %+v", err)
 			return ErrorMongoConnection
 		}
 
-		log.Infof("[validate spider statistics] upsert new statistics (genus and species) success")
+		if err := recordSpiderRevision(ctx, u.revisionRepo, log, model.SPIDER_REVISION_ACTION_CREATE, spiderInfo, loginUser.Username); err != nil {
+			return ErrorMongoTechnicalFail
+		}
 
 		return nil
-	}
-
-	// find species
-	speciesIndex := slices.IndexFunc(spiderStatistics.Genus[genusIndex].Species, func(s model.SpeciesGroup) bool {
-		return s.SpeciesName == newSpiderInfo.Species
 	})
-
-	if speciesIndex == -1 { // if species index not found
-		newSpecies := model.SpeciesGroup{
-			SpeciesName: newSpiderInfo.Species,
-		}
-		spiderStatistics.Genus[genusIndex].Species = append(spiderStatistics.Genus[genusIndex].Species, newSpecies)
-
-		if err := u.statisticsRepo.UpsertSpiderStatistics(ctx, newSpiderInfo.Family, *spiderStatistics); err != nil {
-			log.Errorf("[validate spider statistics] upsert new spider statistics is failed, error: %+v", err)
-			return ErrorMongoConnection
+	if err != nil {
+		if err != ErrorMongoConnection {
+			err = ErrorMongoTechnicalFail
 		}
-
-		log.Infof("[validate spider statistics] upsert new statistics (species) success")
-
-		return nil
+		return "", "", err
 	}
 
-	return nil
+	// =======================================================
+
+	return spiderInfo.SpiderUUID, spiderInfo.Status, nil
 
 }
 
@@ -189,29 +133,10 @@ func (u *RegisterSpiderUsecase) prepareSpiderInfoFromRequest(req api_model.Spide
 		Paper:        req.Paper,
 		CreatedAt:    timeNow,
 		UpdatedAt:    timeNow,
-		Status:       model.SPIDER_INFO_STATUS_ACTIVE,
+		Status:       model.SPIDER_INFO_STATUS_PUBLISHED,
 		CreatedBy:    username,
+		Version:      1,
 	}
 
 	return spiderInfo
 }
-
-func (u *RegisterSpiderUsecase) prepareNewSpiderStatistics(newSpiderInfo model.SpiderInfo) model.SpiderStatistics {
-	data := model.SpiderStatistics{
-		FamilyName: newSpiderInfo.Family,
-		Genus: []model.GenusGroup{
-			{
-				GenusName: newSpiderInfo.Genus,
-				Species: []model.SpeciesGroup{
-					{
-						SpeciesName: newSpiderInfo.Species,
-					},
-				},
-			},
-		},
-		CreatedAt: newSpiderInfo.CreatedAt,
-		UpdatedAt: newSpiderInfo.CreatedAt,
-	}
-
-	return data
-}
@@ -8,7 +8,6 @@ import (
 	mock_domain "spider-go/domain/mock"
 	"spider-go/model"
 	"testing"
-	"time"
 
 	"github.com/golang/mock/gomock"
 )
@@ -47,37 +46,53 @@ func TestRegisterSpiderUsecase_Register(t *testing.T) {
 	config.LoadConfig("./../config", "config")
 
 	type args struct {
-		ctx      context.Context
-		req      api_model.SpiderInfo
-		username string
+		ctx       context.Context
+		req       api_model.SpiderInfo
+		loginUser *model.LoginUser
 	}
 	tests := []struct {
 		name       string
 		args       args
-		buildStubs func(mock_domain.MockSpiderRepository, mock_domain.MockStatisticsRepository)
+		buildStubs func(mock_domain.MockSpiderRepository, mock_domain.MockStatisticsRepository, mock_domain.MockSpiderRevisionRepository)
+		wantStatus string
 		wantErr    bool
 	}{
 		// TODO: Add test cases.
 		{
 			name: "success with insert new spider statistic case",
 			args: args{
-				ctx:      context.TODO(),
-				req:      mockSpiderInfo,
-				username: "testSuccess",
+				ctx:       context.TODO(),
+				req:       mockSpiderInfo,
+				loginUser: &model.LoginUser{Username: "testSuccess", Role: model.ACCOUNT_ROLE_ADMIN},
 			},
 			buildStubs: successRegisterWithInsertNewStatistic,
+			wantStatus: model.SPIDER_INFO_STATUS_PUBLISHED,
 			wantErr:    false,
 		},
 		{
-			name: "success with update spider statistic case",
+			name: "success draft by contributor without spider statistic case",
 			args: args{
-				ctx:      context.TODO(),
-				req:      mockSpiderInfo,
-				username: "testSuccess",
+				ctx:       context.TODO(),
+				req:       mockSpiderInfo,
+				loginUser: &model.LoginUser{Username: "testSuccess", Role: model.ACCOUNT_ROLE_GENERAL},
 			},
-			buildStubs: successRegisterWithUpdateStatistic,
+			buildStubs: successRegisterDraftWithoutStatistic,
+			wantStatus: model.SPIDER_INFO_STATUS_DRAFT,
 			wantErr:    false,
 		},
+		{
+			name: "insert spider failed without touch statistic case",
+			args: args{
+				ctx:       context.TODO(),
+				req:       mockSpiderInfo,
+				loginUser: &model.LoginUser{Username: "testSuccess", Role: model.ACCOUNT_ROLE_ADMIN},
+			},
+			buildStubs: func(spiderRepo mock_domain.MockSpiderRepository, _ mock_domain.MockStatisticsRepository, _ mock_domain.MockSpiderRevisionRepository) {
+				spiderRepo.EXPECT().InsertNewSpider(gomock.Any(), gomock.Any()).Return(fmt.Errorf("insert failed"))
+			},
+			wantStatus: "",
+			wantErr:    true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -86,16 +101,21 @@ func TestRegisterSpiderUsecase_Register(t *testing.T) {
 
 			mockSpiderRepo := mock_domain.NewMockSpiderRepository(ctrl)
 			mockStatisticsRepo := mock_domain.NewMockStatisticsRepository(ctrl)
+			mockRevisionRepo := mock_domain.NewMockSpiderRevisionRepository(ctrl)
 
-			tt.buildStubs(*mockSpiderRepo, *mockStatisticsRepo)
+			tt.buildStubs(*mockSpiderRepo, *mockStatisticsRepo, *mockRevisionRepo)
 
-			usecase := NewRegisterSpiderUsecase(mockSpiderRepo, mockStatisticsRepo)
+			usecase := NewRegisterSpiderUsecase(mockSpiderRepo, mockStatisticsRepo, mockRevisionRepo, newPassThroughUnitOfWork(ctrl))
 
-			_, err := usecase.Register(tt.args.ctx, tt.args.req, tt.args.username)
+			_, status, err := usecase.Register(tt.args.ctx, tt.args.req, tt.args.loginUser)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Register fail: wantErr %v, but got error %v, error is %v", tt.wantErr, (err != nil), err)
 			}
+
+			if status != tt.wantStatus {
+				t.Errorf("Register fail: want status %v, but got %v", tt.wantStatus, status)
+			}
 		})
 	}
 }
@@ -103,36 +123,16 @@ func TestRegisterSpiderUsecase_Register(t *testing.T) {
 func successRegisterWithInsertNewStatistic(
 	SpiderRepo mock_domain.MockSpiderRepository,
 	statisticsRepo mock_domain.MockStatisticsRepository,
+	revisionRepo mock_domain.MockSpiderRevisionRepository,
 ) {
 
-	tn := time.Now()
-
-	// find spider statistic with family in step of validating spider statistic
-	statisticsRepo.EXPECT().FindSpiderStatisticsByFamily(
-		gomock.Any(),
-		gomock.Eq("Agelenidae"),
-	).Return(&model.SpiderStatistics{}, fmt.Errorf("mongo not found"))
-
-	statisticsRepo.EXPECT().UpsertSpiderStatistics(
+	// species is counted in same unit of work as insert spider info
+	statisticsRepo.EXPECT().IncreaseSpiderStatistics(
 		gomock.Any(),
 		gomock.Eq("Agelenidae"),
-		EqSpiderStatistic(
-			model.SpiderStatistics{
-				FamilyName: "Agelenidae",
-				Genus: []model.GenusGroup{
-					{
-						GenusName: "Draconarius",
-						Species: []model.SpeciesGroup{
-							{
-								SpeciesName: "abbreviatus",
-							},
-						},
-					},
-				},
-				CreatedAt: tn,
-				UpdatedAt: tn,
-			},
-		),
+		gomock.Eq("Draconarius"),
+		gomock.Eq("abbreviatus"),
+		gomock.Eq(int64(1)),
 	).Return(nil)
 
 	SpiderRepo.EXPECT().InsertNewSpider(
@@ -151,7 +151,7 @@ func successRegisterWithInsertNewStatistic(
 				Habital:      mockSpiderInfo.Habital,
 				Microhabital: mockSpiderInfo.Microhabital,
 				Designate:    mockSpiderInfo.Designate,
-				Status:       model.SPIDER_INFO_STATUS_ACTIVE,
+				Status:       model.SPIDER_INFO_STATUS_PUBLISHED,
 				Address: []model.Address{
 					{
 						Province: mockSpiderInfo.Address[0].Province,
@@ -168,57 +168,21 @@ func successRegisterWithInsertNewStatistic(
 				},
 				Paper:     mockSpiderInfo.Paper,
 				CreatedBy: "testSuccess",
+				Version:   1,
 			},
 		),
 	).Return(nil)
+
+	stubRecordSpiderRevision(&revisionRepo, model.SPIDER_REVISION_ACTION_CREATE, "testSuccess")
 }
 
-func successRegisterWithUpdateStatistic(
+func successRegisterDraftWithoutStatistic(
 	SpiderRepo mock_domain.MockSpiderRepository,
 	statisticsRepo mock_domain.MockStatisticsRepository,
+	revisionRepo mock_domain.MockSpiderRevisionRepository,
 ) {
 
-	tn := time.Now()
-
-	// find spider statistic with family in step of validating spider statistic
-	prepareSpiderStatistics := model.SpiderStatistics{
-		FamilyName: "Agelenidae",
-		Genus: []model.GenusGroup{
-			{
-				GenusName: "Draconarius",
-			},
-		},
-		CreatedAt: tn,
-		UpdatedAt: tn,
-	}
-
-	statisticsRepo.EXPECT().FindSpiderStatisticsByFamily(
-		gomock.Any(),
-		gomock.Eq("Agelenidae"),
-	).Return(&prepareSpiderStatistics, nil)
-
-	// upsert spider statistic with family in step of validating spider statistic
-	statisticsRepo.EXPECT().UpsertSpiderStatistics(
-		gomock.Any(),
-		gomock.Eq("Agelenidae"),
-		EqSpiderStatistic(
-			model.SpiderStatistics{
-				FamilyName: "Agelenidae",
-				Genus: []model.GenusGroup{
-					{
-						GenusName: "Draconarius",
-						Species: []model.SpeciesGroup{
-							{
-								SpeciesName: "abbreviatus",
-							},
-						},
-					},
-				},
-				CreatedAt: tn,
-				UpdatedAt: tn,
-			},
-		),
-	).Return(nil)
+	// draft is not public so it is not counted until it is published
 
 	SpiderRepo.EXPECT().InsertNewSpider(
 		gomock.Any(),
@@ -236,7 +200,7 @@ func successRegisterWithUpdateStatistic(
 				Habital:      mockSpiderInfo.Habital,
 				Microhabital: mockSpiderInfo.Microhabital,
 				Designate:    mockSpiderInfo.Designate,
-				Status:       model.SPIDER_INFO_STATUS_ACTIVE,
+				Status:       model.SPIDER_INFO_STATUS_DRAFT,
 				Address: []model.Address{
 					{
 						Province: mockSpiderInfo.Address[0].Province,
@@ -253,7 +217,10 @@ func successRegisterWithUpdateStatistic(
 				},
 				Paper:     mockSpiderInfo.Paper,
 				CreatedBy: "testSuccess",
+				Version:   1,
 			},
 		),
 	).Return(nil)
+
+	stubRecordSpiderRevision(&revisionRepo, model.SPIDER_REVISION_ACTION_CREATE, "testSuccess")
 }
@@ -3,28 +3,26 @@ package usecase
 import (
 	"context"
 	"fmt"
-	"os"
-	"path"
 	"spider-go/domain"
 	"spider-go/logger"
 	"spider-go/repository"
 )
 
 type RemoveSpiderImageUsecase struct {
-	spiderRepo    domain.SpiderRepository
-	fileImagePath string
-	log           *logger.Logger
+	spiderRepo   domain.SpiderRepository
+	imageStorage domain.ImageStorage
+	log          *logger.Logger
 }
 
 var (
 	ErrorRemoveSpiderImageSpiderUUIDNotFound = fmt.Errorf("spider info not found")
 )
 
-func NewRemoveSpiderImageUsecase(spiderRepo domain.SpiderRepository, fileImagePath string) domain.RemoveSpiderImageUsecase {
+func NewRemoveSpiderImageUsecase(spiderRepo domain.SpiderRepository, imageStorage domain.ImageStorage) domain.RemoveSpiderImageUsecase {
 	return &RemoveSpiderImageUsecase{
-		spiderRepo:    spiderRepo,
-		fileImagePath: fileImagePath,
-		log:           logger.L().Named("RemoveSpiderImageUsecase"),
+		spiderRepo:   spiderRepo,
+		imageStorage: imageStorage,
+		log:          logger.L().Named("RemoveSpiderImageUsecase"),
 	}
 }
 
@@ -73,8 +71,7 @@ func (u *RemoveSpiderImageUsecase) RemoveSpiderImageBySpiderImageNameList(ctx co
 func (u *RemoveSpiderImageUsecase) removeSpiderImage(ctx context.Context, spiderImageList []string) {
 	log := u.log.WithContext(ctx)
 
-	for _, spiderImage := range spiderImageList {
-		filepath := path.Join(u.fileImagePath, spiderImage)
+	for _, spiderImage := range withImageDerivatives(spiderImageList) {
 		tryToRemove := true
 		tryCount := 0
 
@@ -83,7 +80,8 @@ func (u *RemoveSpiderImageUsecase) removeSpiderImage(ctx context.Context, spider
 		for tryToRemove && tryCount < 3 {
 			log.Debugf("[RemoveSpiderImageBySpiderImageNameList] count `%v`, try to remove spider image name is `%v` failed, error: %v", tryCount, spiderImage, RemoveErr)
 
-			if err := os.Remove(filepath); err != nil {
+			// image is removed after response, request context may be canceled already
+			if err := u.imageStorage.Delete(context.Background(), spiderImage); err != nil {
 				RemoveErr = err
 				tryCount += 1
 			} else {
@@ -5,6 +5,7 @@ import (
 	mock_domain "spider-go/domain/mock"
 	"spider-go/model"
 	"spider-go/repository"
+	"spider-go/utils/storage"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -70,7 +71,7 @@ func TestRemoveSpiderImageUsecase_RemoveSpiderImageBySpiderImageNameList(t *test
 
 			tt.buildStub(&commonStubs)
 
-			u := NewRemoveSpiderImageUsecase(commonStubs.spiderRepo, fileImagePathTemp)
+			u := NewRemoveSpiderImageUsecase(commonStubs.spiderRepo, storage.NewLocalImageStorage(fileImagePathTemp))
 			if err := u.RemoveSpiderImageBySpiderImageNameList(context.TODO(), tt.args.spiderUUID, tt.args.spiderImageListRM); (err != nil) != tt.wantErr {
 				t.Errorf("RemoveSpiderImageUsecase.RemoveSpiderImageBySpiderImageNameList() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"spider-go/config"
+	"spider-go/domain"
+	"spider-go/logger"
+	"spider-go/model"
+	jwt_service "spider-go/utils/jwt"
+	"time"
+)
+
+var (
+	ErrorSigningKeyMongoConnection = fmt.Errorf("[Signing Key Usecase]: mongo error")
+	ErrorSigningKeyGenerateFail    = fmt.Errorf("[Signing Key Usecase]: generate signing key failed")
+	ErrorSigningKeyLoadFail        = fmt.Errorf("[Signing Key Usecase]: load signing key to jwt service failed")
+)
+
+const DEFAULT_SIGNING_KEY_RELOAD_INTERVAL = time.Minute
+
+type SigningKeyUsecase struct {
+	signingKeyRepo domain.SigningKeyRepository
+	JWTService     domain.JWTService
+	config         *config.Root
+	log            *logger.Logger
+}
+
+func NewSigningKeyUsecase(signingKeyRepo domain.SigningKeyRepository, JWTService domain.JWTService, conf *config.Root) domain.SigningKeyUsecase {
+	return &SigningKeyUsecase{
+		signingKeyRepo: signingKeyRepo,
+		JWTService:     JWTService,
+		config:         conf,
+		log:            logger.L().Named("SigningKeyUsecase"),
+	}
+}
+
+// RefreshSigningKeys rotate active key when it is too old or not exist and load key set to jwt service,
+// retired key is still loaded for verify until every token that signed by it expire
+func (u *SigningKeyUsecase) RefreshSigningKeys(ctx context.Context) error {
+	log := u.log.WithContext(ctx)
+
+	algorithm := u.config.JWT.Algorithm
+	if algorithm == "" || algorithm == model.SIGNING_ALGORITHM_HS256 {
+		return nil
+	}
+
+	now := time.Now()
+
+	keys, err := u.signingKeyRepo.FindVerifiableSigningKeys(ctx, now)
+	if err != nil {
+		log.Errorf("[RefreshSigningKeys] find signing keys error: %+v", err)
+		return ErrorSigningKeyMongoConnection
+	}
+
+	if u.needRotate(keys, now) {
+		newKey, err := jwt_service.GenerateSigningKey(algorithm, u.config.JWT.RSAKeySize)
+		if err != nil {
+			log.Errorf("[RefreshSigningKeys] generate %v signing key error: %+v", algorithm, err)
+			return ErrorSigningKeyGenerateFail
+		}
+
+		if err := u.signingKeyRepo.InsertSigningKey(ctx, newKey); err != nil {
+			log.Errorf("[RefreshSigningKeys] insert signing key error: %+v", err)
+			return ErrorSigningKeyMongoConnection
+		}
+
+		// other instance keep signing with retired key until it reload key set, so last token that
+		// signed by retired key expire after reload interval and expire time of token
+		verifyUntil := now.Add(u.config.JWT.ExpireTime + u.config.JWT.ReloadInterval)
+		if err := u.signingKeyRepo.RetireSigningKeys(ctx, newKey.KID, verifyUntil); err != nil {
+			log.Errorf("[RefreshSigningKeys] retire signing keys error: %+v", err)
+			return ErrorSigningKeyMongoConnection
+		}
+
+		if err := u.signingKeyRepo.DeleteExpiredSigningKeys(ctx, now); err != nil {
+			log.Errorf("[RefreshSigningKeys] delete expired signing keys error: %+v", err)
+		}
+
+		log.Infof("[RefreshSigningKeys] rotate to new %v signing key `%v`", algorithm, newKey.KID)
+
+		if keys, err = u.signingKeyRepo.FindVerifiableSigningKeys(ctx, now); err != nil {
+			log.Errorf("[RefreshSigningKeys] find signing keys after rotate error: %+v", err)
+			return ErrorSigningKeyMongoConnection
+		}
+	}
+
+	if err := u.JWTService.SetSigningKeys(keys); err != nil {
+		log.Errorf("[RefreshSigningKeys] set signing keys error: %+v", err)
+		return ErrorSigningKeyLoadFail
+	}
+
+	return nil
+}
+
+// needRotate return true when there is no active key of current algorithm or it is older than rotation interval
+func (u *SigningKeyUsecase) needRotate(keys []model.SigningKey, now time.Time) bool {
+	for _, key := range keys {
+		if !key.Active || key.Algorithm != u.config.JWT.Algorithm {
+			continue
+		}
+
+		rotationInterval := u.config.JWT.RotationInterval
+		if rotationInterval > 0 && now.Sub(key.CreatedAt) >= rotationInterval {
+			continue
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// RunKeyRotation refresh signing keys every interval until ctx is done
+func (u *SigningKeyUsecase) RunKeyRotation(ctx context.Context, interval time.Duration) {
+	log := u.log.WithContext(ctx)
+
+	algorithm := u.config.JWT.Algorithm
+	if algorithm == "" || algorithm == model.SIGNING_ALGORITHM_HS256 {
+		return
+	}
+
+	if interval <= 0 {
+		interval = DEFAULT_SIGNING_KEY_RELOAD_INTERVAL
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := u.RefreshSigningKeys(ctx); err != nil {
+				log.Errorf("[RunKeyRotation] refresh signing keys error: %+v", err)
+			}
+		}
+	}
+}
+
+func (u *SigningKeyUsecase) GetJWKS(ctx context.Context) (model.JWKS, error) {
+	log := u.log.WithContext(ctx)
+
+	jwks, err := u.JWTService.GetJWKS()
+	if err != nil {
+		log.Errorf("[GetJWKS] get jwks error: %+v", err)
+		return model.JWKS{}, ErrorSigningKeyLoadFail
+	}
+
+	return jwks, nil
+}
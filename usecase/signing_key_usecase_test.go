@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"spider-go/config"
+	mock_domain "spider-go/domain/mock"
+	"spider-go/model"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+type commonStubsSigningKey struct {
+	mockSigningKeyRepo *mock_domain.MockSigningKeyRepository
+	mockJWTService     *mock_domain.MockJWTService
+}
+
+var signingKeyConfig = &config.Root{
+	JWT: config.JWT{
+		ExpireTime:       15 * time.Minute,
+		Algorithm:        model.SIGNING_ALGORITHM_EDDSA,
+		RotationInterval: 24 * time.Hour,
+		ReloadInterval:   5 * time.Minute,
+	},
+}
+
+// ======================================================================
+// TestSigningKeyUsecase_RefreshSigningKeys
+// ======================================================================
+func TestSigningKeyUsecase_RefreshSigningKeys(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		conf       *config.Root
+		buildStubs func(*commonStubsSigningKey)
+		wantErr    error
+	}{
+		{
+			name:       "active_key_is_fresh_not_rotate",
+			conf:       signingKeyConfig,
+			buildStubs: fresh_signing_key_not_rotate,
+			wantErr:    nil,
+		},
+		{
+			name: "no_active_key_rotate",
+			conf: signingKeyConfig,
+			buildStubs: func(stubs *commonStubsSigningKey) {
+				rotate_signing_key(stubs, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "active_key_is_old_rotate",
+			conf: signingKeyConfig,
+			buildStubs: func(stubs *commonStubsSigningKey) {
+				rotate_signing_key(stubs, []model.SigningKey{
+					{
+						KID:       "old-key",
+						Algorithm: model.SIGNING_ALGORITHM_EDDSA,
+						Active:    true,
+						CreatedAt: time.Now().Add(-25 * time.Hour),
+					},
+				})
+			},
+			wantErr: nil,
+		},
+		{
+			name: "algorithm_changed_rotate",
+			conf: signingKeyConfig,
+			buildStubs: func(stubs *commonStubsSigningKey) {
+				rotate_signing_key(stubs, []model.SigningKey{
+					{
+						KID:       "rsa-key",
+						Algorithm: model.SIGNING_ALGORITHM_RS256,
+						Active:    true,
+						CreatedAt: time.Now(),
+					},
+				})
+			},
+			wantErr: nil,
+		},
+		{
+			name:       "hs256_do_nothing",
+			conf:       &config.Root{JWT: config.JWT{Algorithm: model.SIGNING_ALGORITHM_HS256}},
+			buildStubs: func(stubs *commonStubsSigningKey) {},
+			wantErr:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := commonStubsSigningKey{
+				mockSigningKeyRepo: mock_domain.NewMockSigningKeyRepository(ctrl),
+				mockJWTService:     mock_domain.NewMockJWTService(ctrl),
+			}
+
+			tt.buildStubs(&stubs)
+
+			u := NewSigningKeyUsecase(stubs.mockSigningKeyRepo, stubs.mockJWTService, tt.conf)
+			if err := u.RefreshSigningKeys(context.TODO()); err != tt.wantErr {
+				t.Errorf("SigningKeyUsecase.RefreshSigningKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func fresh_signing_key_not_rotate(stubs *commonStubsSigningKey) {
+	keys := []model.SigningKey{
+		{
+			KID:       "active-key",
+			Algorithm: model.SIGNING_ALGORITHM_EDDSA,
+			Active:    true,
+			CreatedAt: time.Now().Add(-time.Hour),
+		},
+		{
+			KID:         "retired-key",
+			Algorithm:   model.SIGNING_ALGORITHM_EDDSA,
+			VerifyUntil: time.Now().Add(time.Minute),
+			CreatedAt:   time.Now().Add(-25 * time.Hour),
+		},
+	}
+
+	stubs.mockSigningKeyRepo.EXPECT().FindVerifiableSigningKeys(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(keys, nil)
+
+	stubs.mockJWTService.EXPECT().SetSigningKeys(
+		gomock.Eq(keys),
+	).Return(nil)
+}
+
+func rotate_signing_key(stubs *commonStubsSigningKey, currentKeys []model.SigningKey) {
+	var newKID string
+
+	stubs.mockSigningKeyRepo.EXPECT().FindVerifiableSigningKeys(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(currentKeys, nil)
+
+	stubs.mockSigningKeyRepo.EXPECT().InsertSigningKey(
+		gomock.Any(),
+		gomock.Any(),
+	).DoAndReturn(func(_ context.Context, key model.SigningKey) error {
+		newKID = key.KID
+		return nil
+	})
+
+	stubs.mockSigningKeyRepo.EXPECT().RetireSigningKeys(
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+	).DoAndReturn(func(_ context.Context, exceptKID string, verifyUntil time.Time) error {
+		if exceptKID != newKID {
+			return ErrorSigningKeyMongoConnection
+		}
+		// token signed by other instance before it reload key set must still be verifiable
+		verifyFor := signingKeyConfig.JWT.ExpireTime + signingKeyConfig.JWT.ReloadInterval
+		if until := time.Until(verifyUntil); until > verifyFor || until < verifyFor-time.Minute {
+			return ErrorSigningKeyMongoConnection
+		}
+		return nil
+	})
+
+	stubs.mockSigningKeyRepo.EXPECT().DeleteExpiredSigningKeys(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(nil)
+
+	stubs.mockSigningKeyRepo.EXPECT().FindVerifiableSigningKeys(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(currentKeys, nil)
+
+	stubs.mockJWTService.EXPECT().SetSigningKeys(
+		gomock.Any(),
+	).Return(nil)
+}
@@ -4,21 +4,26 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
-	"os"
-	"path"
+	"spider-go/config"
 	"spider-go/domain"
 	"spider-go/logger"
 	"spider-go/model"
 	"spider-go/repository"
+	"spider-go/utils/storage"
+	"time"
+
+	"golang.org/x/exp/slices"
 )
 
 type SpiderInfoUsecase struct {
-	spiderRepo domain.SpiderRepository
-	accRepo    domain.AccountRepository
-	// separated from config to prevent unit tests from generating data races
-	fileImagePath string
-	log           *logger.Logger
+	spiderRepo     domain.SpiderRepository
+	imageStorage   domain.ImageStorage
+	imageURLSigner domain.ImageURLSigner
+	conf           *config.Root
+	log            *logger.Logger
 }
 
 var (
@@ -26,18 +31,23 @@ var (
 	ErrorSpiderInfoUsecaseAccountInsufficientPermissions = fmt.Errorf("[spider info usecase] this user account is insufficient permissions")
 	ErrorSpiderInfoUsecaseReadFileFail                   = fmt.Errorf("[spider info usecase] read file fail")
 	ErrorSpiderInfoUsecaseValidateDataFail               = fmt.Errorf("invalid data request")
+	ErrorSpiderInfoUsecaseImageNotFound                  = fmt.Errorf("[spider info usecase] spider image not found")
+	ErrorSpiderInfoUsecaseImageURLInvalid                = fmt.Errorf("[spider info usecase] signed url of spider image is invalid or expired")
+	ErrorSpiderInfoUsecaseNoImageExif                    = fmt.Errorf("[spider info usecase] no image of spider info has gps or taken time")
 )
 
 func NewSpiderInfoUsecase(
 	spiderRepo domain.SpiderRepository,
-	accRepo domain.AccountRepository,
-	fileImagePath string,
+	imageStorage domain.ImageStorage,
+	imageURLSigner domain.ImageURLSigner,
+	conf *config.Root,
 ) domain.SpiderInfoUsecase {
 	return &SpiderInfoUsecase{
-		spiderRepo:    spiderRepo,
-		accRepo:       accRepo,
-		fileImagePath: fileImagePath,
-		log:           logger.L().Named("SpiderInfoUsecase"),
+		spiderRepo:     spiderRepo,
+		imageStorage:   imageStorage,
+		imageURLSigner: imageURLSigner,
+		conf:           conf,
+		log:            logger.L().Named("SpiderInfoUsecase"),
 	}
 }
 
@@ -45,15 +55,10 @@ func NewSpiderInfoUsecase(
 // get spider info from mongodb
 // ========================================================
 
-func (u *SpiderInfoUsecase) GetSpiderInfoUsecase(ctx context.Context, spiderUUID, username string) (*model.SpiderInfo, error) {
+// loginUser is principal of request, nil when request not login
+func (u *SpiderInfoUsecase) GetSpiderInfoUsecase(ctx context.Context, spiderUUID string, loginUser *model.LoginUser) (*model.SpiderInfo, error) {
 	log := u.log.WithContext(ctx)
 
-	account, err := u.accRepo.FindAccountByUsername(ctx, username)
-	if err != nil && err != repository.ErrorMongoNotFound {
-		log.Errorf("[get spider info] find account by username error: %+v", err)
-		return &model.SpiderInfo{}, ErrorMongoTechnicalFail
-	}
-
 	SpiderInfo, err := u.spiderRepo.FindSpiderByUUID(ctx, spiderUUID)
 	if err != nil {
 		log.Errorf("[get spider info] error mongo, error: %v", err)
@@ -63,7 +68,16 @@ func (u *SpiderInfoUsecase) GetSpiderInfoUsecase(ctx context.Context, spiderUUID
 		return &model.SpiderInfo{}, ErrorMongoConnection
 	}
 
-	if SpiderInfo.Status != model.SPIDER_INFO_STATUS_ACTIVE && account.Role != model.ACCOUNT_ROLE_ADMIN {
+	// spider info in trash is shown only in trash list
+	if SpiderInfo.IsDeleted() {
+		return &model.SpiderInfo{}, ErrorSpiderInfoUsecaseSpiderNotFound
+	}
+
+	// contributor can follow review of own draft
+	canReadInactive := loginUser != nil &&
+		(loginUser.HasPermission(model.PERMISSION_SPIDER_READ) || (SpiderInfo.CreatedBy != "" && loginUser.Username == SpiderInfo.CreatedBy))
+
+	if SpiderInfo.Status != model.SPIDER_INFO_STATUS_ACTIVE && !canReadInactive {
 		log.Errorf("user permissions denied")
 		return &model.SpiderInfo{}, ErrorSpiderInfoUsecaseAccountInsufficientPermissions
 	}
@@ -78,20 +92,39 @@ func (u *SpiderInfoUsecase) GetSpiderInfoUsecase(ctx context.Context, spiderUUID
 // get multi image
 // ========================================================
 
-func (u *SpiderInfoUsecase) GetSpiderImagesUsecase(ctx context.Context, fileImages []string) ([]model.SpiderImageList, error) {
+// image is read without login, so only image of public spider info is returned,
+// image of other spider info is read by signed url
+func (u *SpiderInfoUsecase) GetSpiderImagesUsecase(ctx context.Context, spiderUUID string, fileImages []string) ([]model.SpiderImageList, error) {
 	log := u.log.WithContext(ctx)
 
+	spiderInfo, err := u.spiderRepo.FindSpiderByUUID(ctx, spiderUUID)
+	if err != nil {
+		log.Errorf("[GetSpiderImagesUsecase] find spider info `%v` error: %v", spiderUUID, err)
+		if err == repository.ErrorMongoNotFound {
+			return nil, ErrorSpiderInfoUsecaseSpiderNotFound
+		}
+		return nil, ErrorMongoConnection
+	}
+
+	if spiderInfo.IsDeleted() {
+		return nil, ErrorSpiderInfoUsecaseSpiderNotFound
+	}
+
+	if !spiderInfo.IsPublic() {
+		return nil, ErrorSpiderInfoUsecaseAccountInsufficientPermissions
+	}
+
 	var imageEncodeFiles []model.SpiderImageList
 
 	for _, imageName := range fileImages {
 
-		pathFile := path.Join(u.fileImagePath, imageName)
-
-		log.Debugf("path file image is `%v`", pathFile)
+		if !slices.Contains(spiderInfo.ImageFile, imageName) {
+			return nil, ErrorSpiderInfoUsecaseImageNotFound
+		}
 
-		bytes, err := os.ReadFile(pathFile)
+		bytes, err := u.readSpiderImage(ctx, imageName)
 		if err != nil {
-			log.Errorf("[GetSpiderImagesUsecase] read file at path `%v` failed, error: %v", pathFile, err)
+			log.Errorf("[GetSpiderImagesUsecase] read image `%v` failed, error: %v", imageName, err)
 			return nil, ErrorSpiderInfoUsecaseReadFileFail
 		}
 
@@ -121,25 +154,184 @@ func (u *SpiderInfoUsecase) GetSpiderImagesUsecase(ctx context.Context, fileImag
 
 }
 
+func (u *SpiderInfoUsecase) readSpiderImage(ctx context.Context, imageName string) ([]byte, error) {
+	content, _, err := u.imageStorage.Open(ctx, imageName)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	return io.ReadAll(content)
+}
+
 // ********************************************************
 
 // ========================================================
-// get spider info list manager
+// open spider image for image endpoint
 // ========================================================
 
-func (u *SpiderInfoUsecase) GetSpiderInfoListManager(ctx context.Context, usecase string, page, limit int) ([]model.SpiderInfo, error) {
+// image of spider info in trash or in review is read only by signed url that is given in spider info response
+func (u *SpiderInfoUsecase) OpenSpiderImage(ctx context.Context, param model.OpenSpiderImageParam) (*model.SpiderImageFile, error) {
 	log := u.log.WithContext(ctx)
 
-	account, err := u.accRepo.FindAccountByUsername(ctx, usecase)
+	spiderInfo, err := u.spiderRepo.FindSpiderByUUID(ctx, param.SpiderUUID)
 	if err != nil {
-		log.Errorf("[GetSpiderInfoListManager] find account by username error: %+v", err)
-		return []model.SpiderInfo{}, ErrorMongoTechnicalFail
+		log.Errorf("[OpenSpiderImage] find spider info `%v` error: %v", param.SpiderUUID, err)
+		if err == repository.ErrorMongoNotFound {
+			return nil, ErrorSpiderInfoUsecaseSpiderNotFound
+		}
+		return nil, ErrorMongoConnection
+	}
+
+	// image that is removed from spider info may still in storage until remove is done
+	if !slices.Contains(spiderInfo.ImageFile, param.ImageName) {
+		return nil, ErrorSpiderInfoUsecaseImageNotFound
+	}
+
+	if param.Size != "" && param.Size != model.IMAGE_SIZE_ORIGINAL && !slices.Contains(model.IMAGE_DERIVATIVE_SIZES, param.Size) {
+		return nil, ErrorSpiderInfoUsecaseValidateDataFail
+	}
+
+	imageFile := &model.SpiderImageFile{
+		Public: spiderInfo.IsPublic(),
+		MaxAge: u.conf.File.ImageURL.CacheMaxAge,
+	}
+
+	if !imageFile.Public {
+		expiresAt, err := u.imageURLSigner.VerifySignature(param.SpiderUUID, param.ImageName, param.Expires, param.Signature)
+		if err != nil {
+			log.Errorf("[OpenSpiderImage] verify signed url of image `%v` failed, error: %v", param.ImageName, err)
+			return nil, ErrorSpiderInfoUsecaseImageURLInvalid
+		}
+		imageFile.MaxAge = time.Until(expiresAt)
+	}
+
+	content, object, err := u.openImageSize(ctx, param.ImageName, param.Size)
+	if err != nil {
+		log.Errorf("[OpenSpiderImage] open image `%v` size `%v` failed, error: %v", param.ImageName, param.Size, err)
+		if err == storage.ErrorImageNotFound {
+			return nil, ErrorSpiderInfoUsecaseImageNotFound
+		}
+		return nil, ErrorSpiderInfoUsecaseReadFileFail
+	}
+
+	imageFile.Content = content
+	imageFile.Object = *object
+
+	return imageFile, nil
+}
+
+// openImageSize open derivative of image, original is served when derivative is not generated yet
+func (u *SpiderInfoUsecase) openImageSize(ctx context.Context, imageName, size string) (io.ReadCloser, *model.ImageObject, error) {
+	log := u.log.WithContext(ctx)
+
+	if size == "" || size == model.IMAGE_SIZE_ORIGINAL {
+		return u.imageStorage.Open(ctx, imageName)
+	}
+
+	content, object, err := u.imageStorage.Open(ctx, storage.DerivativeImageName(imageName, size))
+	if err == storage.ErrorImageNotFound {
+		log.Warnf("[openImageSize] `%v` of image `%v` is not found, serve original", size, imageName)
+		return u.imageStorage.Open(ctx, imageName)
+	}
+
+	return content, object, err
+}
+
+// ********************************************************
+
+// ========================================================
+// suggest position of spider info from exif of its image
+// ========================================================
+
+func (u *SpiderInfoUsecase) SuggestSpiderPosition(ctx context.Context, spiderUUID string) (*model.SpiderPositionSuggestion, error) {
+	log := u.log.WithContext(ctx)
+
+	spiderInfo, err := u.spiderRepo.FindSpiderByUUID(ctx, spiderUUID)
+	if err != nil {
+		log.Errorf("[SuggestSpiderPosition] find spider info `%v` error: %v", spiderUUID, err)
+		if err == repository.ErrorMongoNotFound {
+			return nil, ErrorSpiderInfoUsecaseSpiderNotFound
+		}
+		return nil, ErrorMongoConnection
+	}
+
+	// spider info in trash can not be changed
+	if spiderInfo.IsDeleted() {
+		return nil, ErrorSpiderInfoUsecaseSpiderNotFound
+	}
+
+	suggestion := &model.SpiderPositionSuggestion{}
+
+	var minAltitude, maxAltitude *float64
+
+	for _, imageExif := range spiderInfo.ImageExif {
+		// exif of image that is being removed is not used
+		if !slices.Contains(spiderInfo.ImageFile, imageExif.ImageName) {
+			continue
+		}
+
+		if !imageExif.HasPosition() && imageExif.Altitude == nil && imageExif.TakenAt == nil {
+			continue
+		}
+
+		suggestion.Images = append(suggestion.Images, imageExif)
+
+		if imageExif.HasPosition() {
+			suggestion.Position = append(suggestion.Position, model.Position{
+				Latitude:  roundCoordinate(*imageExif.Latitude),
+				Longitude: roundCoordinate(*imageExif.Longitude),
+			})
+		}
+
+		if imageExif.Altitude != nil {
+			if minAltitude == nil || *imageExif.Altitude < *minAltitude {
+				minAltitude = imageExif.Altitude
+			}
+			if maxAltitude == nil || *imageExif.Altitude > *maxAltitude {
+				maxAltitude = imageExif.Altitude
+			}
+		}
+
+		if imageExif.TakenAt != nil && (suggestion.TakenAt == nil || imageExif.TakenAt.Before(*suggestion.TakenAt)) {
+			suggestion.TakenAt = imageExif.TakenAt
+		}
+	}
+
+	if len(suggestion.Images) == 0 {
+		return nil, ErrorSpiderInfoUsecaseNoImageExif
 	}
 
-	if account.Role != model.ACCOUNT_ROLE_ADMIN {
-		log.Errorf("[GetSpiderInfoListManager] user permissions denied")
-		return []model.SpiderInfo{}, ErrorSpiderInfoUsecaseAccountInsufficientPermissions
+	if minAltitude != nil {
+		suggestion.Altitude = formatAltitude(*minAltitude, *maxAltitude)
+	}
+
+	return suggestion, nil
+}
+
+// roundCoordinate keep six decimal places, it is less than one meter and more than gps of camera can tell
+func roundCoordinate(degrees float64) float64 {
+	return math.Round(degrees*1e6) / 1e6
+}
+
+// formatAltitude format altitude as spider info, e.g. `1000 m` or `1000, 1750 m`
+func formatAltitude(minAltitude, maxAltitude float64) string {
+	minAltitude, maxAltitude = math.Round(minAltitude), math.Round(maxAltitude)
+	if minAltitude == maxAltitude {
+		return fmt.Sprintf("%.0f m", minAltitude)
 	}
+	return fmt.Sprintf("%.0f, %.0f m", minAltitude, maxAltitude)
+}
+
+// ********************************************************
+
+// ========================================================
+// get spider info list manager
+// ========================================================
+
+// user permission is checked by route middleware
+func (u *SpiderInfoUsecase) GetSpiderInfoListManager(ctx context.Context, page, limit int) ([]model.SpiderInfo, error) {
+	log := u.log.WithContext(ctx)
 
 	spiderInfoList, err := u.spiderRepo.FindAllSpiderListManager(ctx, page, limit)
 	if err != nil {
@@ -12,14 +12,15 @@ import (
 	mock_domain "spider-go/domain/mock"
 	"spider-go/model"
 	"spider-go/repository"
+	"spider-go/utils/storage"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 )
 
 type mockStubsSpiderInfoUsecase struct {
-	mockAccRepo    *mock_domain.MockAccountRepository
 	mockSpiderRepo *mock_domain.MockSpiderRepository
 }
 
@@ -67,7 +68,7 @@ func TestSpiderInfoUsecase_GetSpiderInfoUsecase(t *testing.T) {
 	type args struct {
 		ctx        context.Context
 		spiderUUID string
-		username   string
+		loginUser  *model.LoginUser
 	}
 	tests := []struct {
 		name       string
@@ -82,7 +83,7 @@ func TestSpiderInfoUsecase_GetSpiderInfoUsecase(t *testing.T) {
 			args: args{
 				context.TODO(),
 				"SPIDER_c6ef5023-94fc-41c8-a88d-87303c75999b",
-				"",
+				nil,
 			},
 			buildStubs: success_without_login,
 			want:       mockResultSpiderInfo,
@@ -93,12 +94,23 @@ func TestSpiderInfoUsecase_GetSpiderInfoUsecase(t *testing.T) {
 			args: args{
 				context.TODO(),
 				"SPIDER_c6ef5023-94fc-41c8-a88d-87303c75999b",
-				"test",
+				&model.LoginUser{Role: model.ACCOUNT_ROLE_ADMIN},
 			},
 			buildStubs: success_with_login,
 			want:       mockResultSpiderInfo,
 			wantErr:    false,
 		},
+		{
+			name: "inactive_spider_insufficient_permissions",
+			args: args{
+				context.TODO(),
+				"SPIDER_c6ef5023-94fc-41c8-a88d-87303c75999b",
+				&model.LoginUser{Role: model.ACCOUNT_ROLE_GENERAL},
+			},
+			buildStubs: inactive_spider_insufficient_permissions,
+			want:       model.SpiderInfo{},
+			wantErr:    true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -106,15 +118,14 @@ func TestSpiderInfoUsecase_GetSpiderInfoUsecase(t *testing.T) {
 			defer ctrl.Finish()
 
 			stubs := mockStubsSpiderInfoUsecase{
-				mockAccRepo:    mock_domain.NewMockAccountRepository(ctrl),
 				mockSpiderRepo: mock_domain.NewMockSpiderRepository(ctrl),
 			}
 
 			tt.buildStubs(&stubs)
 
-			usecase := NewSpiderInfoUsecase(stubs.mockSpiderRepo, stubs.mockAccRepo, "")
+			usecase := NewSpiderInfoUsecase(stubs.mockSpiderRepo, nil, nil, &config.Root{})
 
-			spiderInfoResult, err := usecase.GetSpiderInfoUsecase(context.TODO(), tt.args.spiderUUID, tt.args.username)
+			spiderInfoResult, err := usecase.GetSpiderInfoUsecase(context.TODO(), tt.args.spiderUUID, tt.args.loginUser)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("[TestSpiderInfoUsecase_GetSpiderInfoUsecase] wantErr is `%v` but got `%v`, and error is `%+v`", tt.wantErr, (err != nil), err)
@@ -129,11 +140,6 @@ func TestSpiderInfoUsecase_GetSpiderInfoUsecase(t *testing.T) {
 
 func success_without_login(commonStubs *mockStubsSpiderInfoUsecase) {
 
-	commonStubs.mockAccRepo.EXPECT().FindAccountByUsername(
-		gomock.Any(),
-		gomock.Eq(""),
-	).Return(nil, repository.ErrorMongoNotFound)
-
 	commonStubs.mockSpiderRepo.EXPECT().FindSpiderByUUID(
 		gomock.Any(),
 		gomock.Eq("SPIDER_c6ef5023-94fc-41c8-a88d-87303c75999b"),
@@ -142,20 +148,21 @@ func success_without_login(commonStubs *mockStubsSpiderInfoUsecase) {
 
 func success_with_login(commonStubs *mockStubsSpiderInfoUsecase) {
 
-	account := model.Account{
-		Username: "test",
-		Role:     "admin",
-	}
-
-	commonStubs.mockAccRepo.EXPECT().FindAccountByUsername(
+	commonStubs.mockSpiderRepo.EXPECT().FindSpiderByUUID(
 		gomock.Any(),
-		gomock.Eq("test"),
-	).Return(&account, nil)
+		gomock.Eq("SPIDER_c6ef5023-94fc-41c8-a88d-87303c75999b"),
+	).Return(&mockResultSpiderInfo, nil)
+}
+
+func inactive_spider_insufficient_permissions(commonStubs *mockStubsSpiderInfoUsecase) {
+
+	inactiveSpiderInfo := mockResultSpiderInfo
+	inactiveSpiderInfo.Status = model.SPIDER_INFO_STATUS_INACTIVE
 
 	commonStubs.mockSpiderRepo.EXPECT().FindSpiderByUUID(
 		gomock.Any(),
 		gomock.Eq("SPIDER_c6ef5023-94fc-41c8-a88d-87303c75999b"),
-	).Return(&mockResultSpiderInfo, nil)
+	).Return(&inactiveSpiderInfo, nil)
 }
 
 // **********************************************************************
@@ -166,6 +173,9 @@ func success_with_login(commonStubs *mockStubsSpiderInfoUsecase) {
 func TestSpiderInfoUsecase_GetSpiderImageUsecase(t *testing.T) {
 	tempDir := t.TempDir()
 
+	imageName := "SPIDER_dcb5dd72-d7c9-4b89-a039-abe670fcf3002023-03-04T19:44:22+0700-volume-0.png"
+	missingImageName := "SPIDER_dcb5dd72-d7c9-4b89-a039-abe670fcf3002023-03-04T19:44:22+0700-volume-1.png"
+
 	type args struct {
 		fileImages []string
 	}
@@ -173,33 +183,66 @@ func TestSpiderInfoUsecase_GetSpiderImageUsecase(t *testing.T) {
 	tc := []struct {
 		name         string
 		args         args
+		buildStubs   func(*mock_domain.MockSpiderRepository)
 		needTempFile bool
 		want         []model.SpiderImageList
-		wantErr      bool
+		wantErr      error
 	}{
 		// can't not test jpeg case
 		{
 			name: "success_get_png_file",
 			args: args{
-				fileImages: []string{"SPIDER_dcb5dd72-d7c9-4b89-a039-abe670fcf3002023-03-04T19:44:22+0700-volume-0.png"},
+				fileImages: []string{imageName},
 			},
+			buildStubs:   get_spider_image_of_status(model.SPIDER_INFO_STATUS_PUBLISHED, imageName),
 			needTempFile: true,
 			want: []model.SpiderImageList{
 				{
-					Name:        "SPIDER_dcb5dd72-d7c9-4b89-a039-abe670fcf3002023-03-04T19:44:22+0700-volume-0.png",
+					Name:        imageName,
 					ImageBase64: PNG_IMAGE_BASE64,
 				},
 			},
-			wantErr: false,
+			wantErr: nil,
 		},
 		{
 			name: "read_file_failed",
 			args: args{
-				fileImages: []string{"SPIDER_dcb5dd72-d7c9-4b89-a039-abe670fcf3002023-03-04T19:44:22+0700-volume-1.png"},
+				fileImages: []string{missingImageName},
 			},
+			buildStubs:   get_spider_image_of_status(model.SPIDER_INFO_STATUS_PUBLISHED, missingImageName),
 			needTempFile: false,
 			want:         []model.SpiderImageList{},
-			wantErr:      true,
+			wantErr:      ErrorSpiderInfoUsecaseReadFileFail,
+		},
+		{
+			name: "image_of_draft_is_refused",
+			args: args{
+				fileImages: []string{imageName},
+			},
+			buildStubs:   get_spider_image_of_status(model.SPIDER_INFO_STATUS_DRAFT, imageName),
+			needTempFile: true,
+			want:         nil,
+			wantErr:      ErrorSpiderInfoUsecaseAccountInsufficientPermissions,
+		},
+		{
+			name: "image_in_trash_is_not_found",
+			args: args{
+				fileImages: []string{imageName},
+			},
+			buildStubs:   get_spider_image_of_status(model.SPIDER_INFO_STATUS_DELETED, imageName),
+			needTempFile: true,
+			want:         nil,
+			wantErr:      ErrorSpiderInfoUsecaseSpiderNotFound,
+		},
+		{
+			name: "image_of_other_spider_is_not_found",
+			args: args{
+				fileImages: []string{imageName},
+			},
+			buildStubs:   get_spider_image_of_status(model.SPIDER_INFO_STATUS_PUBLISHED, missingImageName),
+			needTempFile: true,
+			want:         nil,
+			wantErr:      ErrorSpiderInfoUsecaseImageNotFound,
 		},
 	}
 
@@ -216,13 +259,13 @@ func TestSpiderInfoUsecase_GetSpiderImageUsecase(t *testing.T) {
 			}
 
 			mockSpiderRepo := mock_domain.NewMockSpiderRepository(ctrl)
-			mockAccRepo := mock_domain.NewMockAccountRepository(ctrl)
+			tt.buildStubs(mockSpiderRepo)
 
-			usecase := NewSpiderInfoUsecase(mockSpiderRepo, mockAccRepo, tempDir)
+			usecase := NewSpiderInfoUsecase(mockSpiderRepo, storage.NewLocalImageStorage(tempDir), nil, &config.Root{})
 
-			spiderImageEncode, err := usecase.GetSpiderImagesUsecase(context.TODO(), tt.args.fileImages)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("[TestSpiderInfoUsecase_GetSpiderImageUsecase] want error: %v, but got error: %v, and error: %v", tt.wantErr, (err != nil), err)
+			spiderImageEncode, err := usecase.GetSpiderImagesUsecase(context.TODO(), mockResultSpiderInfo.SpiderUUID, tt.args.fileImages)
+			if err != tt.wantErr {
+				t.Errorf("[TestSpiderInfoUsecase_GetSpiderImageUsecase] want error: %v, but got error: %v", tt.wantErr, err)
 			}
 
 			if !reflect.DeepEqual(tt.want, spiderImageEncode) && (len(tt.want) > 0 && len(spiderImageEncode) > 0) {
@@ -233,6 +276,16 @@ func TestSpiderInfoUsecase_GetSpiderImageUsecase(t *testing.T) {
 
 }
 
+func get_spider_image_of_status(status string, imageFile ...string) func(*mock_domain.MockSpiderRepository) {
+	return func(spiderRepo *mock_domain.MockSpiderRepository) {
+		spiderInfo := mockResultSpiderInfo
+		spiderInfo.Status = status
+		spiderInfo.ImageFile = imageFile
+
+		spiderRepo.EXPECT().FindSpiderByUUID(gomock.Any(), gomock.Eq(spiderInfo.SpiderUUID)).Return(&spiderInfo, nil)
+	}
+}
+
 func tempImagePNG(pathDir, name string) {
 
 	filePath := path.Join(pathDir, name)
@@ -254,6 +307,305 @@ func tempImagePNG(pathDir, name string) {
 
 // **********************************************************************
 
+// ======================================================================
+// TestSpiderInfoUsecase_OpenSpiderImage
+// ======================================================================
+func TestSpiderInfoUsecase_OpenSpiderImage(t *testing.T) {
+	tempDir := t.TempDir()
+
+	imageName := "SPIDER_c6ef5023-94fc-41c8-a88d-87303c75999b-image-0.png"
+	tempImagePNG(tempDir, imageName)
+
+	// only thumbnail is generated, medium is not backfilled yet
+	thumbnailName := storage.DerivativeImageName(imageName, model.IMAGE_SIZE_THUMBNAIL)
+	tempImagePNG(tempDir, thumbnailName)
+
+	conf := &config.Root{}
+	conf.File.ImageURL.CacheMaxAge = 24 * time.Hour
+
+	publicParam := model.OpenSpiderImageParam{
+		SpiderUUID: mockResultSpiderInfo.SpiderUUID,
+		ImageName:  imageName,
+	}
+
+	signedParam := model.OpenSpiderImageParam{
+		SpiderUUID: mockResultSpiderInfo.SpiderUUID,
+		ImageName:  imageName,
+		Expires:    "1700000000",
+		Signature:  "signature",
+	}
+
+	tests := []struct {
+		name       string
+		param      model.OpenSpiderImageParam
+		buildStubs func(*mock_domain.MockSpiderRepository, *mock_domain.MockImageURLSigner)
+		wantPublic bool
+		wantName   string
+		wantErr    error
+	}{
+		{
+			name:       "success_open_public_image",
+			param:      publicParam,
+			buildStubs: success_open_public_image(imageName),
+			wantPublic: true,
+			wantName:   imageName,
+			wantErr:    nil,
+		},
+		{
+			name:       "success_open_signed_image",
+			param:      signedParam,
+			buildStubs: success_open_signed_image(imageName),
+			wantPublic: false,
+			wantName:   imageName,
+			wantErr:    nil,
+		},
+		{
+			name: "success_open_thumbnail",
+			param: model.OpenSpiderImageParam{
+				SpiderUUID: mockResultSpiderInfo.SpiderUUID,
+				ImageName:  imageName,
+				Size:       model.IMAGE_SIZE_THUMBNAIL,
+			},
+			buildStubs: success_open_public_image(imageName),
+			wantPublic: true,
+			wantName:   thumbnailName,
+			wantErr:    nil,
+		},
+		{
+			name: "success_open_original_when_derivative_not_generated",
+			param: model.OpenSpiderImageParam{
+				SpiderUUID: mockResultSpiderInfo.SpiderUUID,
+				ImageName:  imageName,
+				Size:       model.IMAGE_SIZE_MEDIUM,
+			},
+			buildStubs: success_open_public_image(imageName),
+			wantPublic: true,
+			wantName:   imageName,
+			wantErr:    nil,
+		},
+		{
+			name: "invalid_image_size",
+			param: model.OpenSpiderImageParam{
+				SpiderUUID: mockResultSpiderInfo.SpiderUUID,
+				ImageName:  imageName,
+				Size:       "huge",
+			},
+			buildStubs: func(spiderRepo *mock_domain.MockSpiderRepository, imageURLSigner *mock_domain.MockImageURLSigner) {
+				spiderInfo := mockResultSpiderInfo
+				spiderInfo.ImageFile = []string{imageName}
+
+				spiderRepo.EXPECT().FindSpiderByUUID(gomock.Any(), gomock.Any()).Return(&spiderInfo, nil)
+			},
+			wantErr: ErrorSpiderInfoUsecaseValidateDataFail,
+		},
+		{
+			name:  "signed_url_invalid",
+			param: publicParam,
+			buildStubs: func(spiderRepo *mock_domain.MockSpiderRepository, imageURLSigner *mock_domain.MockImageURLSigner) {
+				spiderInfo := mockResultSpiderInfo
+				spiderInfo.Status = model.SPIDER_INFO_STATUS_INACTIVE
+				spiderInfo.ImageFile = []string{imageName}
+
+				spiderRepo.EXPECT().FindSpiderByUUID(gomock.Any(), gomock.Eq(spiderInfo.SpiderUUID)).Return(&spiderInfo, nil)
+				imageURLSigner.EXPECT().VerifySignature(
+					gomock.Eq(spiderInfo.SpiderUUID),
+					gomock.Eq(imageName),
+					gomock.Eq(""),
+					gomock.Eq(""),
+				).Return(time.Time{}, storage.ErrorImageURLSignatureInvalid)
+			},
+			wantErr: ErrorSpiderInfoUsecaseImageURLInvalid,
+		},
+		{
+			name:  "image_not_in_spider_info",
+			param: publicParam,
+			buildStubs: func(spiderRepo *mock_domain.MockSpiderRepository, imageURLSigner *mock_domain.MockImageURLSigner) {
+				spiderRepo.EXPECT().FindSpiderByUUID(gomock.Any(), gomock.Any()).Return(&mockResultSpiderInfo, nil)
+			},
+			wantErr: ErrorSpiderInfoUsecaseImageNotFound,
+		},
+		{
+			name: "image_not_in_storage",
+			param: model.OpenSpiderImageParam{
+				SpiderUUID: mockResultSpiderInfo.SpiderUUID,
+				ImageName:  mockResultSpiderInfo.ImageFile[0],
+			},
+			buildStubs: func(spiderRepo *mock_domain.MockSpiderRepository, imageURLSigner *mock_domain.MockImageURLSigner) {
+				spiderRepo.EXPECT().FindSpiderByUUID(gomock.Any(), gomock.Any()).Return(&mockResultSpiderInfo, nil)
+			},
+			wantErr: ErrorSpiderInfoUsecaseImageNotFound,
+		},
+		{
+			name:  "spider_info_not_found",
+			param: publicParam,
+			buildStubs: func(spiderRepo *mock_domain.MockSpiderRepository, imageURLSigner *mock_domain.MockImageURLSigner) {
+				spiderRepo.EXPECT().FindSpiderByUUID(gomock.Any(), gomock.Any()).Return(nil, repository.ErrorMongoNotFound)
+			},
+			wantErr: ErrorSpiderInfoUsecaseSpiderNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			spiderRepo := mock_domain.NewMockSpiderRepository(ctrl)
+			imageURLSigner := mock_domain.NewMockImageURLSigner(ctrl)
+			tt.buildStubs(spiderRepo, imageURLSigner)
+
+			u := NewSpiderInfoUsecase(spiderRepo, storage.NewLocalImageStorage(tempDir), imageURLSigner, conf)
+			got, err := u.OpenSpiderImage(context.TODO(), tt.param)
+			if err != tt.wantErr {
+				t.Errorf("SpiderInfoUsecase.OpenSpiderImage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			defer got.Content.Close()
+
+			if got.Public != tt.wantPublic || got.Object.Name != tt.wantName || got.Object.ContentType != PNG_IMAGE_TYPE || got.Object.ETag == "" {
+				t.Errorf("SpiderInfoUsecase.OpenSpiderImage() = %+v, want public %v", got, tt.wantPublic)
+			}
+
+			// public image is cached by config, signed image is cached until url expire
+			if tt.wantPublic && got.MaxAge != conf.File.ImageURL.CacheMaxAge {
+				t.Errorf("SpiderInfoUsecase.OpenSpiderImage() max age = %v, want %v", got.MaxAge, conf.File.ImageURL.CacheMaxAge)
+			}
+			if !tt.wantPublic && (got.MaxAge <= 0 || got.MaxAge > time.Hour) {
+				t.Errorf("SpiderInfoUsecase.OpenSpiderImage() max age = %v, want until signed url expire", got.MaxAge)
+			}
+		})
+	}
+}
+
+func success_open_public_image(imageName string) func(*mock_domain.MockSpiderRepository, *mock_domain.MockImageURLSigner) {
+	return func(spiderRepo *mock_domain.MockSpiderRepository, imageURLSigner *mock_domain.MockImageURLSigner) {
+		spiderInfo := mockResultSpiderInfo
+		spiderInfo.ImageFile = []string{imageName}
+
+		spiderRepo.EXPECT().FindSpiderByUUID(gomock.Any(), gomock.Eq(spiderInfo.SpiderUUID)).Return(&spiderInfo, nil)
+		imageURLSigner.EXPECT().VerifySignature(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	}
+}
+
+func success_open_signed_image(imageName string) func(*mock_domain.MockSpiderRepository, *mock_domain.MockImageURLSigner) {
+	return func(spiderRepo *mock_domain.MockSpiderRepository, imageURLSigner *mock_domain.MockImageURLSigner) {
+		spiderInfo := mockResultSpiderInfo
+		spiderInfo.Status = model.SPIDER_INFO_STATUS_INACTIVE
+		spiderInfo.ImageFile = []string{imageName}
+
+		spiderRepo.EXPECT().FindSpiderByUUID(gomock.Any(), gomock.Eq(spiderInfo.SpiderUUID)).Return(&spiderInfo, nil)
+		imageURLSigner.EXPECT().VerifySignature(
+			gomock.Eq(spiderInfo.SpiderUUID),
+			gomock.Eq(imageName),
+			gomock.Eq("1700000000"),
+			gomock.Eq("signature"),
+		).Return(time.Now().Add(time.Hour), nil)
+	}
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestSpiderInfoUsecase_SuggestSpiderPosition
+// ======================================================================
+func TestSpiderInfoUsecase_SuggestSpiderPosition(t *testing.T) {
+
+	latitude, longitude := 18.58889133, 98.48697532
+	lowAltitude, highAltitude, removedAltitude := 1000.2, 1750.4, 3000.0
+	earlier := time.Date(2023, 3, 4, 9, 15, 30, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+
+	lowImage := model.ImageExif{ImageName: "low.jpeg", TakenAt: &later, Latitude: &latitude, Longitude: &longitude, Altitude: &lowAltitude}
+	highImage := model.ImageExif{ImageName: "high.jpeg", TakenAt: &earlier, Altitude: &highAltitude, CameraMake: "Canon"}
+	cameraOnlyImage := model.ImageExif{ImageName: "camera.jpeg", CameraMake: "Canon"}
+	// removed image is not in image file
+	removedImage := model.ImageExif{ImageName: "removed.jpeg", Altitude: &removedAltitude}
+
+	tests := []struct {
+		name       string
+		buildStubs func(*mock_domain.MockSpiderRepository)
+		want       *model.SpiderPositionSuggestion
+		wantErr    error
+	}{
+		{
+			name:       "success_suggest_from_every_image",
+			buildStubs: success_suggest_from_every_image(lowImage, highImage, cameraOnlyImage, removedImage),
+			want: &model.SpiderPositionSuggestion{
+				Position: []model.Position{{Latitude: 18.588891, Longitude: 98.486975}},
+				Altitude: "1000, 1750 m",
+				TakenAt:  &earlier,
+				Images:   []model.ImageExif{lowImage, highImage},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "image_without_gps_and_taken_time",
+			buildStubs: func(spiderRepo *mock_domain.MockSpiderRepository) {
+				spiderInfo := mockResultSpiderInfo
+				spiderInfo.ImageFile = []string{"camera.jpeg", "removed.jpeg"}
+				spiderInfo.ImageExif = []model.ImageExif{cameraOnlyImage}
+				spiderRepo.EXPECT().FindSpiderByUUID(gomock.Any(), gomock.Eq(spiderInfo.SpiderUUID)).Return(&spiderInfo, nil)
+			},
+			want:    nil,
+			wantErr: ErrorSpiderInfoUsecaseNoImageExif,
+		},
+		{
+			name: "spider_info_not_found",
+			buildStubs: func(spiderRepo *mock_domain.MockSpiderRepository) {
+				spiderRepo.EXPECT().FindSpiderByUUID(gomock.Any(), gomock.Any()).Return(nil, repository.ErrorMongoNotFound)
+			},
+			want:    nil,
+			wantErr: ErrorSpiderInfoUsecaseSpiderNotFound,
+		},
+		{
+			name: "spider_info_in_trash",
+			buildStubs: func(spiderRepo *mock_domain.MockSpiderRepository) {
+				spiderInfo := mockResultSpiderInfo
+				spiderInfo.Status = model.SPIDER_INFO_STATUS_DELETED
+				spiderInfo.ImageFile = []string{"low.jpeg"}
+				spiderInfo.ImageExif = []model.ImageExif{lowImage}
+				spiderRepo.EXPECT().FindSpiderByUUID(gomock.Any(), gomock.Any()).Return(&spiderInfo, nil)
+			},
+			want:    nil,
+			wantErr: ErrorSpiderInfoUsecaseSpiderNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			spiderRepo := mock_domain.NewMockSpiderRepository(ctrl)
+			tt.buildStubs(spiderRepo)
+
+			u := NewSpiderInfoUsecase(spiderRepo, nil, nil, &config.Root{})
+			got, err := u.SuggestSpiderPosition(context.TODO(), mockResultSpiderInfo.SpiderUUID)
+			if err != tt.wantErr {
+				t.Errorf("SpiderInfoUsecase.SuggestSpiderPosition() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SpiderInfoUsecase.SuggestSpiderPosition() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func success_suggest_from_every_image(imageExif ...model.ImageExif) func(*mock_domain.MockSpiderRepository) {
+	return func(spiderRepo *mock_domain.MockSpiderRepository) {
+		spiderInfo := mockResultSpiderInfo
+		spiderInfo.ImageFile = []string{"low.jpeg", "high.jpeg", "camera.jpeg", "spider.png"}
+		spiderInfo.ImageExif = imageExif
+
+		spiderRepo.EXPECT().FindSpiderByUUID(gomock.Any(), gomock.Eq(spiderInfo.SpiderUUID)).Return(&spiderInfo, nil)
+	}
+}
+
+// **********************************************************************
+
 // ======================================================================
 // TestSpiderInfoUsecase_GetSpiderImageUsecase
 // ======================================================================
@@ -261,9 +613,8 @@ func TestSpiderInfoUsecase_GetSpiderInfoListManagerUsecase(t *testing.T) {
 	config.LoadConfig("./../config", "config")
 
 	type args struct {
-		username string
-		page     int
-		size     int
+		page int
+		size int
 	}
 	testcase := []struct {
 		name       string
@@ -274,9 +625,8 @@ func TestSpiderInfoUsecase_GetSpiderInfoListManagerUsecase(t *testing.T) {
 		{
 			name: "success_get_spider_info_list_manager",
 			args: args{
-				username: "test",
-				page:     0,
-				size:     5,
+				page: 0,
+				size: 5,
 			},
 			buildStubs: success_get_spider_info_list_manager,
 			wantErr:    false,
@@ -289,15 +639,14 @@ func TestSpiderInfoUsecase_GetSpiderInfoListManagerUsecase(t *testing.T) {
 			defer ctrl.Finish()
 
 			commonStubs := mockStubsSpiderInfoUsecase{
-				mockAccRepo:    mock_domain.NewMockAccountRepository(ctrl),
 				mockSpiderRepo: mock_domain.NewMockSpiderRepository(ctrl),
 			}
 
 			tt.buildStubs(&commonStubs)
 
-			usecase := NewSpiderInfoUsecase(commonStubs.mockSpiderRepo, commonStubs.mockAccRepo, "")
+			usecase := NewSpiderInfoUsecase(commonStubs.mockSpiderRepo, nil, nil, &config.Root{})
 
-			_, err := usecase.GetSpiderInfoListManager(context.TODO(), tt.args.username, tt.args.page, tt.args.size)
+			_, err := usecase.GetSpiderInfoListManager(context.TODO(), tt.args.page, tt.args.size)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("want error bool %v, but got error bool %v, error is %+v", tt.wantErr, (err != nil), err)
@@ -309,16 +658,6 @@ func TestSpiderInfoUsecase_GetSpiderInfoListManagerUsecase(t *testing.T) {
 
 func success_get_spider_info_list_manager(commonStubs *mockStubsSpiderInfoUsecase) {
 
-	account := model.Account{
-		Username: "test",
-		Role:     "admin",
-	}
-
-	commonStubs.mockAccRepo.EXPECT().FindAccountByUsername(
-		gomock.Any(),
-		gomock.Eq("test"),
-	).Return(&account, nil)
-
 	spiderInfoList := []model.SpiderInfo{
 		mockResultSpiderInfo,
 	}
@@ -387,12 +726,11 @@ func TestSpiderInfoUsecase_GetSpiderInfoListByGeographies(t *testing.T) {
 			defer ctrl.Finish()
 
 			commonStubs := mockStubsSpiderInfoUsecase{
-				mockAccRepo:    mock_domain.NewMockAccountRepository(ctrl),
 				mockSpiderRepo: mock_domain.NewMockSpiderRepository(ctrl),
 			}
 			tt.buildStubs(&commonStubs)
 
-			usecase := NewSpiderInfoUsecase(commonStubs.mockSpiderRepo, commonStubs.mockAccRepo, "")
+			usecase := NewSpiderInfoUsecase(commonStubs.mockSpiderRepo, nil, nil, &config.Root{})
 
 			_, err := usecase.GetSpiderInfoListByGeographies(context.TODO(), tt.args.province, tt.args.district, tt.args.position)
 
@@ -470,13 +808,12 @@ func TestSpiderInfoUsecase_GetSpiderInfoListByLocality(t *testing.T) {
 			defer ctrl.Finish()
 
 			commonBuildStub := mockStubsSpiderInfoUsecase{
-				mockAccRepo:    mock_domain.NewMockAccountRepository(ctrl),
 				mockSpiderRepo: mock_domain.NewMockSpiderRepository(ctrl),
 			}
 
 			tt.stubs(&commonBuildStub)
 
-			usecase := NewSpiderInfoUsecase(commonBuildStub.mockSpiderRepo, commonBuildStub.mockAccRepo, "")
+			usecase := NewSpiderInfoUsecase(commonBuildStub.mockSpiderRepo, nil, nil, &config.Root{})
 
 			got, err := usecase.GetSpiderInfoListByLocality(context.TODO(), tt.args.locality, tt.args.page, tt.args.size)
 			if (err != nil) != tt.wantErr {
@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"spider-go/domain"
+	"spider-go/logger"
+	"spider-go/model"
+	"spider-go/repository"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	ErrorSpiderRevisionNotFound        = fmt.Errorf("[Spider Revision Usecase]: revision not found")
+	ErrorSpiderRevisionVersionConflict = fmt.Errorf("[Spider Revision Usecase]: spider info is changed by other request")
+	ErrorSpiderRevisionSpiderInTrash   = fmt.Errorf("[Spider Revision Usecase]: spider info is in trash")
+	ErrorSpiderRevisionMongoConnection = fmt.Errorf("[Spider Revision Usecase]: mongo error")
+)
+
+// fields that change on every write, they are not part of diff
+var spiderRevisionDiffIgnoreFields = []string{"_id", "updated_at", "version"}
+
+type SpiderRevisionUsecase struct {
+	spiderRepo     domain.SpiderRepository
+	statisticsRepo domain.StatisticsRepository
+	revisionRepo   domain.SpiderRevisionRepository
+	unitOfWork     domain.UnitOfWork
+	log            *logger.Logger
+}
+
+func NewSpiderRevisionUsecase(
+	spiderRepo domain.SpiderRepository,
+	statisticsRepo domain.StatisticsRepository,
+	revisionRepo domain.SpiderRevisionRepository,
+	unitOfWork domain.UnitOfWork,
+) domain.SpiderRevisionUsecase {
+	return &SpiderRevisionUsecase{
+		spiderRepo:     spiderRepo,
+		statisticsRepo: statisticsRepo,
+		revisionRepo:   revisionRepo,
+		unitOfWork:     unitOfWork,
+		log:            logger.L().Named("SpiderRevisionUsecase"),
+	}
+}
+
+// GetSpiderRevisionList return revision of spider_uuid, latest revision first
+func (u *SpiderRevisionUsecase) GetSpiderRevisionList(ctx context.Context, spiderUUID string, page, size int32) ([]model.SpiderRevision, int64, error) {
+	log := u.log.WithContext(ctx)
+
+	revisionList, total, err := u.revisionRepo.FindSpiderRevisionList(ctx, spiderUUID, page, size)
+	if err != nil {
+		log.Errorf("[GetSpiderRevisionList] find revision list of `%v` error: %+v", spiderUUID, err)
+		return nil, 0, ErrorSpiderRevisionMongoConnection
+	}
+
+	return revisionList, total, nil
+}
+
+// DiffSpiderRevision return fields of snapshot that differ from `fromRevision` to `toRevision`
+func (u *SpiderRevisionUsecase) DiffSpiderRevision(ctx context.Context, spiderUUID string, fromRevision, toRevision int64) ([]model.SpiderRevisionDiff, error) {
+	log := u.log.WithContext(ctx)
+
+	from, err := u.findRevision(ctx, spiderUUID, fromRevision)
+	if err != nil {
+		log.Errorf("[DiffSpiderRevision] find revision `%v` of `%v` error: %+v", fromRevision, spiderUUID, err)
+		return nil, err
+	}
+
+	to, err := u.findRevision(ctx, spiderUUID, toRevision)
+	if err != nil {
+		log.Errorf("[DiffSpiderRevision] find revision `%v` of `%v` error: %+v", toRevision, spiderUUID, err)
+		return nil, err
+	}
+
+	return diffSpiderInfo(from.Snapshot, to.Snapshot), nil
+}
+
+// RestoreSpiderRevision apply snapshot of old revision to spider info and record it as new revision,
+// spider info that was purged is inserted again without image because image files are removed on purge.
+// spider info in trash must be restored from trash before its revision is restored
+func (u *SpiderRevisionUsecase) RestoreSpiderRevision(ctx context.Context, spiderUUID string, revisionNumber int64, username string) (*model.SpiderRevision, error) {
+	log := u.log.WithContext(ctx)
+
+	revision, err := u.findRevision(ctx, spiderUUID, revisionNumber)
+	if err != nil {
+		log.Errorf("[RestoreSpiderRevision] find revision `%v` of `%v` error: %+v", revisionNumber, spiderUUID, err)
+		return nil, err
+	}
+
+	tn := time.Now()
+
+	var restored model.SpiderInfo
+	var newRevision model.SpiderRevision
+
+	// restore revision is recorded in same unit of work as write of spider info
+	recordRestoreRevision := func(ctx context.Context, snapshot model.SpiderInfo) error {
+		newRevision = newSpiderRevision(model.SPIDER_REVISION_ACTION_RESTORE, snapshot, username)
+		newRevision.RestoredFrom = revision.Revision
+
+		var err error
+		newRevision.Revision, err = u.revisionRepo.InsertSpiderRevision(ctx, newRevision)
+		return err
+	}
+
+	currentSpiderInfo, err := u.spiderRepo.FindSpiderByUUID(ctx, spiderUUID)
+	switch {
+	case err == repository.ErrorMongoNotFound:
+		restored = revision.Snapshot
+
+		// snapshot that is taken in trash is inserted with status before delete, same as restore from trash
+		if restored.IsDeleted() {
+			restored.Status = restored.StatusBeforeDelete
+			if restored.Status == "" {
+				restored.Status = model.SPIDER_INFO_STATUS_INACTIVE
+			}
+		}
+
+		restored.ID = primitive.NilObjectID
+		restored.ImageFile = nil
+		restored.ImageExif = nil
+		restored.DeletedBy = ""
+		restored.DeletedAt = nil
+		restored.StatusBeforeDelete = ""
+		restored.UpdatedAt = tn
+
+		err := u.unitOfWork.Do(ctx, func(ctx context.Context) error {
+			if err := u.spiderRepo.InsertNewSpider(ctx, restored); err != nil {
+				return err
+			}
+			if err := moveSpiderStatistics(ctx, u.statisticsRepo, nil, &restored); err != nil {
+				return err
+			}
+			return recordRestoreRevision(ctx, restored)
+		})
+		if err != nil {
+			log.Errorf("[RestoreSpiderRevision] insert spider info `%v` error: %+v", spiderUUID, err)
+			return nil, ErrorSpiderRevisionMongoConnection
+		}
+
+	case err != nil:
+		log.Errorf("[RestoreSpiderRevision] find spider info `%v` error: %+v", spiderUUID, err)
+		return nil, ErrorSpiderRevisionMongoConnection
+
+	case currentSpiderInfo.IsDeleted():
+		return nil, ErrorSpiderRevisionSpiderInTrash
+
+	default:
+		restored = mergeEditableSpiderInfo(*currentSpiderInfo, revision.Snapshot, tn)
+
+		err := u.unitOfWork.Do(ctx, func(ctx context.Context) error {
+			isUpdate, err := u.spiderRepo.UpdateSpiderInfo(ctx, spiderUUID, currentSpiderInfo.Version, restored)
+			if err != nil {
+				return err
+			}
+			if !isUpdate {
+				return ErrorSpiderRevisionVersionConflict
+			}
+			if err := moveSpiderStatistics(ctx, u.statisticsRepo, currentSpiderInfo, &restored); err != nil {
+				return err
+			}
+
+			snapshot := restored
+			snapshot.ID = currentSpiderInfo.ID
+			snapshot.Version = currentSpiderInfo.Version + 1
+			return recordRestoreRevision(ctx, snapshot)
+		})
+		if err == ErrorSpiderRevisionVersionConflict {
+			return nil, err
+		}
+		if err != nil {
+			log.Errorf("[RestoreSpiderRevision] update spider info `%v` error: %+v", spiderUUID, err)
+			return nil, ErrorSpiderRevisionMongoConnection
+		}
+	}
+
+	log.Infof("[RestoreSpiderRevision] `%v` restore revision `%v` of `%v` as revision `%v`", username, revision.Revision, spiderUUID, newRevision.Revision)
+
+	return &newRevision, nil
+}
+
+func (u *SpiderRevisionUsecase) findRevision(ctx context.Context, spiderUUID string, revisionNumber int64) (*model.SpiderRevision, error) {
+	revision, err := u.revisionRepo.FindSpiderRevision(ctx, spiderUUID, revisionNumber)
+	if err != nil {
+		if err == repository.ErrorMongoNotFound {
+			return nil, ErrorSpiderRevisionNotFound
+		}
+		return nil, ErrorSpiderRevisionMongoConnection
+	}
+
+	return revision, nil
+}
+
+// ========================================================
+// revision helper that shared with register, update and delete
+// ========================================================
+
+func newSpiderRevision(action string, spiderInfo model.SpiderInfo, username string) model.SpiderRevision {
+	return model.SpiderRevision{
+		SpiderUUID: spiderInfo.SpiderUUID,
+		Action:     action,
+		Snapshot:   spiderInfo,
+		CreatedBy:  username,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// recordSpiderRevision save snapshot of spider info that is written, it must be called in same unit of work
+// as write of spider info so change without revision is never committed
+func recordSpiderRevision(ctx context.Context, revisionRepo domain.SpiderRevisionRepository, log *logger.Logger, action string, spiderInfo model.SpiderInfo, username string) error {
+	revisionNumber, err := revisionRepo.InsertSpiderRevision(ctx, newSpiderRevision(action, spiderInfo, username))
+	if err != nil {
+		log.Errorf("[recordSpiderRevision] record %v revision of `%v` error: %+v", action, spiderInfo.SpiderUUID, err)
+		return err
+	}
+
+	log.Infof("[recordSpiderRevision] record %v revision `%v` of `%v` by `%v`", action, revisionNumber, spiderInfo.SpiderUUID, username)
+
+	return nil
+}
+
+// diffSpiderInfo compare every field of spider info by json name
+func diffSpiderInfo(from, to model.SpiderInfo) []model.SpiderRevisionDiff {
+	diffList := []model.SpiderRevisionDiff{}
+
+	fromValue := reflect.ValueOf(from)
+	toValue := reflect.ValueOf(to)
+	spiderInfoType := fromValue.Type()
+
+	for i := 0; i < spiderInfoType.NumField(); i++ {
+		field := strings.Split(spiderInfoType.Field(i).Tag.Get("json"), ",")[0]
+		if field == "" || field == "-" || slices.Contains(spiderRevisionDiffIgnoreFields, field) {
+			continue
+		}
+
+		oldValue := fromValue.Field(i).Interface()
+		newValue := toValue.Field(i).Interface()
+
+		if isEqualRevisionValue(fromValue.Field(i), toValue.Field(i)) {
+			continue
+		}
+
+		diffList = append(diffList, model.SpiderRevisionDiff{
+			Field: field,
+			Old:   oldValue,
+			New:   newValue,
+		})
+	}
+
+	return diffList
+}
+
+// isEqualRevisionValue treat nil and empty slice as the same value
+func isEqualRevisionValue(a, b reflect.Value) bool {
+	if a.Kind() == reflect.Slice && a.Len() == 0 && b.Len() == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
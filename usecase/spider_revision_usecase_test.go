@@ -0,0 +1,340 @@
+package usecase
+
+import (
+	"context"
+	mock_domain "spider-go/domain/mock"
+	"spider-go/model"
+	"spider-go/repository"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+type commonStubsSpiderRevision struct {
+	mockSpiderRepo     *mock_domain.MockSpiderRepository
+	mockStatisticsRepo *mock_domain.MockStatisticsRepository
+	mockRevisionRepo   *mock_domain.MockSpiderRevisionRepository
+}
+
+const unittestRevisionSpiderUUID = "SPIDER_565391ff-9197-47ce-b86e-311d7b901f53"
+
+func unittestRevision(revisionNumber int64, action string, spiderInfo model.SpiderInfo) *model.SpiderRevision {
+	spiderInfo.SpiderUUID = unittestRevisionSpiderUUID
+	return &model.SpiderRevision{
+		SpiderUUID: unittestRevisionSpiderUUID,
+		Revision:   revisionNumber,
+		Action:     action,
+		Snapshot:   spiderInfo,
+	}
+}
+
+func stubRecordSpiderRevision(revisionRepo *mock_domain.MockSpiderRevisionRepository, action, username string) {
+	revisionRepo.EXPECT().InsertSpiderRevision(
+		gomock.Any(),
+		EqSpiderRevision(action, username),
+	).Return(int64(2), nil)
+}
+
+func stubFindRevision(stubs *commonStubsSpiderRevision, revision *model.SpiderRevision) {
+	stubs.mockRevisionRepo.EXPECT().FindSpiderRevision(
+		gomock.Any(),
+		gomock.Eq(unittestRevisionSpiderUUID),
+		gomock.Eq(revision.Revision),
+	).Return(revision, nil)
+}
+
+// ======================================================================
+// TestSpiderRevisionUsecase_DiffSpiderRevision
+// ======================================================================
+func TestSpiderRevisionUsecase_DiffSpiderRevision(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		buildStubs func(*commonStubsSpiderRevision)
+		wantFields []string
+		wantErr    error
+	}{
+		{
+			name:       "success_diff_changed_fields_only",
+			buildStubs: success_diff_changed_fields_only,
+			wantFields: []string{"genus", "address"},
+			wantErr:    nil,
+		},
+		{
+			name: "revision_not_found",
+			buildStubs: func(stubs *commonStubsSpiderRevision) {
+				stubs.mockRevisionRepo.EXPECT().FindSpiderRevision(
+					gomock.Any(),
+					gomock.Eq(unittestRevisionSpiderUUID),
+					gomock.Eq(int64(1)),
+				).Return(nil, repository.ErrorMongoNotFound)
+			},
+			wantErr: ErrorSpiderRevisionNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := commonStubsSpiderRevision{
+				mockSpiderRepo:     mock_domain.NewMockSpiderRepository(ctrl),
+				mockStatisticsRepo: mock_domain.NewMockStatisticsRepository(ctrl),
+				mockRevisionRepo:   mock_domain.NewMockSpiderRevisionRepository(ctrl),
+			}
+
+			tt.buildStubs(&stubs)
+
+			u := NewSpiderRevisionUsecase(stubs.mockSpiderRepo, stubs.mockStatisticsRepo, stubs.mockRevisionRepo, newPassThroughUnitOfWork(ctrl))
+			diffList, err := u.DiffSpiderRevision(context.TODO(), unittestRevisionSpiderUUID, 1, 2)
+			if err != tt.wantErr {
+				t.Errorf("SpiderRevisionUsecase.DiffSpiderRevision() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			var gotFields []string
+			for _, diff := range diffList {
+				gotFields = append(gotFields, diff.Field)
+			}
+			if len(gotFields) != len(tt.wantFields) {
+				t.Errorf("SpiderRevisionUsecase.DiffSpiderRevision() fields = %v, want %v", gotFields, tt.wantFields)
+				return
+			}
+			for i := range gotFields {
+				if gotFields[i] != tt.wantFields[i] {
+					t.Errorf("SpiderRevisionUsecase.DiffSpiderRevision() fields = %v, want %v", gotFields, tt.wantFields)
+				}
+			}
+		})
+	}
+}
+
+func success_diff_changed_fields_only(stubs *commonStubsSpiderRevision) {
+	stubFindRevision(stubs, unittestRevision(1, model.SPIDER_REVISION_ACTION_CREATE, model.SpiderInfo{
+		Family: "Agelenidae",
+		Genus:  "Agelena",
+		Paper:  nil,
+	}))
+
+	stubFindRevision(stubs, unittestRevision(2, model.SPIDER_REVISION_ACTION_UPDATE, model.SpiderInfo{
+		Family: "Agelenidae",
+		Genus:  "Draconarius",
+		Paper:  []string{},
+		Address: []model.Address{
+			{
+				Province: "Chiang Mai",
+			},
+		},
+	}))
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestSpiderRevisionUsecase_RestoreSpiderRevision
+// ======================================================================
+func TestSpiderRevisionUsecase_RestoreSpiderRevision(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		buildStubs func(*commonStubsSpiderRevision)
+		wantErr    error
+	}{
+		{
+			name:       "success_restore_to_existing_record",
+			buildStubs: success_restore_to_existing_record,
+			wantErr:    nil,
+		},
+		{
+			name:       "success_restore_deleted_record",
+			buildStubs: success_restore_deleted_record,
+			wantErr:    nil,
+		},
+		{
+			name:       "success_restore_purged_record_from_snapshot_in_trash",
+			buildStubs: success_restore_purged_record_from_snapshot_in_trash,
+			wantErr:    nil,
+		},
+		{
+			name: "restore_to_record_in_trash",
+			buildStubs: func(stubs *commonStubsSpiderRevision) {
+				stubFindRevision(stubs, unittestRevision(1, model.SPIDER_REVISION_ACTION_CREATE, model.SpiderInfo{
+					Family: "Agelenidae",
+					Status: model.SPIDER_INFO_STATUS_ACTIVE,
+				}))
+
+				stubs.mockSpiderRepo.EXPECT().FindSpiderByUUID(
+					gomock.Any(),
+					gomock.Eq(unittestRevisionSpiderUUID),
+				).Return(&model.SpiderInfo{
+					SpiderUUID: unittestRevisionSpiderUUID,
+					Family:     "Agelenidae",
+					Status:     model.SPIDER_INFO_STATUS_DELETED,
+				}, nil)
+			},
+			wantErr: ErrorSpiderRevisionSpiderInTrash,
+		},
+		{
+			name: "restore_revision_not_found",
+			buildStubs: func(stubs *commonStubsSpiderRevision) {
+				stubs.mockRevisionRepo.EXPECT().FindSpiderRevision(
+					gomock.Any(),
+					gomock.Eq(unittestRevisionSpiderUUID),
+					gomock.Eq(int64(1)),
+				).Return(nil, repository.ErrorMongoNotFound)
+			},
+			wantErr: ErrorSpiderRevisionNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := commonStubsSpiderRevision{
+				mockSpiderRepo:     mock_domain.NewMockSpiderRepository(ctrl),
+				mockStatisticsRepo: mock_domain.NewMockStatisticsRepository(ctrl),
+				mockRevisionRepo:   mock_domain.NewMockSpiderRevisionRepository(ctrl),
+			}
+
+			tt.buildStubs(&stubs)
+
+			u := NewSpiderRevisionUsecase(stubs.mockSpiderRepo, stubs.mockStatisticsRepo, stubs.mockRevisionRepo, newPassThroughUnitOfWork(ctrl))
+			revision, err := u.RestoreSpiderRevision(context.TODO(), unittestRevisionSpiderUUID, 1, "unittest")
+			if err != tt.wantErr {
+				t.Errorf("SpiderRevisionUsecase.RestoreSpiderRevision() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && (revision.Action != model.SPIDER_REVISION_ACTION_RESTORE || revision.RestoredFrom != 1 || revision.Revision != 2) {
+				t.Errorf("SpiderRevisionUsecase.RestoreSpiderRevision() revision = %+v, want restore of revision 1", revision)
+			}
+		})
+	}
+}
+
+func success_restore_to_existing_record(stubs *commonStubsSpiderRevision) {
+	stubFindRevision(stubs, unittestRevision(1, model.SPIDER_REVISION_ACTION_CREATE, model.SpiderInfo{
+		Family: "Agelenidae",
+		Genus:  "Agelena",
+		Status: model.SPIDER_INFO_STATUS_ACTIVE,
+	}))
+
+	stubs.mockSpiderRepo.EXPECT().FindSpiderByUUID(
+		gomock.Any(),
+		gomock.Eq(unittestRevisionSpiderUUID),
+	).Return(&model.SpiderInfo{
+		SpiderUUID: unittestRevisionSpiderUUID,
+		Family:     "Agelenidae",
+		Genus:      "Draconarius",
+		Status:     model.SPIDER_INFO_STATUS_PUBLISHED,
+		ImageFile:  []string{"image_1.png"},
+	}, nil)
+
+	// status and image of current record are kept
+	stubs.mockSpiderRepo.EXPECT().UpdateSpiderInfo(
+		gomock.Any(),
+		gomock.Eq(unittestRevisionSpiderUUID),
+		gomock.Eq(int64(0)),
+		EqSpiderInfo(model.SpiderInfo{
+			Family:    "Agelenidae",
+			Genus:     "Agelena",
+			Status:    model.SPIDER_INFO_STATUS_PUBLISHED,
+			ImageFile: []string{"image_1.png"},
+		}),
+	).Return(true, nil)
+
+	// record count move from old genus to restored genus
+	gomock.InOrder(
+		stubs.mockStatisticsRepo.EXPECT().IncreaseSpiderStatistics(
+			gomock.Any(),
+			gomock.Eq("Agelenidae"),
+			gomock.Eq("Draconarius"),
+			gomock.Eq(""),
+			gomock.Eq(int64(-1)),
+		).Return(nil),
+		stubs.mockStatisticsRepo.EXPECT().IncreaseSpiderStatistics(
+			gomock.Any(),
+			gomock.Eq("Agelenidae"),
+			gomock.Eq("Agelena"),
+			gomock.Eq(""),
+			gomock.Eq(int64(1)),
+		).Return(nil),
+	)
+
+	stubRecordSpiderRevision(stubs.mockRevisionRepo, model.SPIDER_REVISION_ACTION_RESTORE, "unittest")
+}
+
+func success_restore_deleted_record(stubs *commonStubsSpiderRevision) {
+	stubFindRevision(stubs, unittestRevision(1, model.SPIDER_REVISION_ACTION_DELETE, model.SpiderInfo{
+		Family:    "Agelenidae",
+		Genus:     "Agelena",
+		Status:    model.SPIDER_INFO_STATUS_ACTIVE,
+		ImageFile: []string{"image_1.png"},
+	}))
+
+	stubs.mockSpiderRepo.EXPECT().FindSpiderByUUID(
+		gomock.Any(),
+		gomock.Eq(unittestRevisionSpiderUUID),
+	).Return(nil, repository.ErrorMongoNotFound)
+
+	// image files are removed with purged record
+	stubs.mockSpiderRepo.EXPECT().InsertNewSpider(
+		gomock.Any(),
+		EqSpiderInfo(model.SpiderInfo{
+			Family: "Agelenidae",
+			Genus:  "Agelena",
+			Status: model.SPIDER_INFO_STATUS_ACTIVE,
+		}),
+	).Return(nil)
+
+	stubs.mockStatisticsRepo.EXPECT().IncreaseSpiderStatistics(
+		gomock.Any(),
+		gomock.Eq("Agelenidae"),
+		gomock.Eq("Agelena"),
+		gomock.Eq(""),
+		gomock.Eq(int64(1)),
+	).Return(nil)
+
+	stubRecordSpiderRevision(stubs.mockRevisionRepo, model.SPIDER_REVISION_ACTION_RESTORE, "unittest")
+}
+
+// snapshot with status deleted is inserted with status before delete and without trash fields
+func success_restore_purged_record_from_snapshot_in_trash(stubs *commonStubsSpiderRevision) {
+	deletedAt := time.Now()
+
+	stubFindRevision(stubs, unittestRevision(1, model.SPIDER_REVISION_ACTION_UPDATE, model.SpiderInfo{
+		Family:             "Agelenidae",
+		Genus:              "Agelena",
+		Status:             model.SPIDER_INFO_STATUS_DELETED,
+		StatusBeforeDelete: model.SPIDER_INFO_STATUS_ACTIVE,
+		DeletedBy:          "admin",
+		DeletedAt:          &deletedAt,
+	}))
+
+	stubs.mockSpiderRepo.EXPECT().FindSpiderByUUID(
+		gomock.Any(),
+		gomock.Eq(unittestRevisionSpiderUUID),
+	).Return(nil, repository.ErrorMongoNotFound)
+
+	stubs.mockSpiderRepo.EXPECT().InsertNewSpider(
+		gomock.Any(),
+		EqSpiderInfo(model.SpiderInfo{
+			Family: "Agelenidae",
+			Genus:  "Agelena",
+			Status: model.SPIDER_INFO_STATUS_ACTIVE,
+		}),
+	).Return(nil)
+
+	stubs.mockStatisticsRepo.EXPECT().IncreaseSpiderStatistics(
+		gomock.Any(),
+		gomock.Eq("Agelenidae"),
+		gomock.Eq("Agelena"),
+		gomock.Eq(""),
+		gomock.Eq(int64(1)),
+	).Return(nil)
+
+	stubRecordSpiderRevision(stubs.mockRevisionRepo, model.SPIDER_REVISION_ACTION_RESTORE, "unittest")
+}
+
+// **********************************************************************
@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"spider-go/domain"
+	"spider-go/logger"
+	"spider-go/model"
+	"time"
+)
+
+var (
+	ErrorSpiderStatisticsRebuildMongoConnection = fmt.Errorf("[Spider Statistics Rebuild Usecase]: mongo error")
+)
+
+type SpiderStatisticsRebuildUsecase struct {
+	statisticsRepo domain.StatisticsRepository
+	unitOfWork     domain.UnitOfWork
+	log            *logger.Logger
+}
+
+func NewSpiderStatisticsRebuildUsecase(statisticsRepo domain.StatisticsRepository, unitOfWork domain.UnitOfWork) domain.SpiderStatisticsRebuildUsecase {
+	return &SpiderStatisticsRebuildUsecase{
+		statisticsRepo: statisticsRepo,
+		unitOfWork:     unitOfWork,
+		log:            logger.L().Named("SpiderStatisticsRebuildUsecase"),
+	}
+}
+
+// RebuildSpiderStatistics recompute statistics from spider info and report difference from current statistics,
+// statistics is replaced only when it is not dry run. rebuild is applied in one unit of work with read of spider info,
+// so spider info that is written at same time conflict with rebuild and one of them is retried instead of lost
+func (u *SpiderStatisticsRebuildUsecase) RebuildSpiderStatistics(ctx context.Context, dryRun bool) (*model.SpiderStatisticsRebuildReport, error) {
+	log := u.log.WithContext(ctx)
+
+	var report *model.SpiderStatisticsRebuildReport
+
+	rebuild := func(ctx context.Context) error {
+		current, err := u.statisticsRepo.FindAllSpiderStatistics(ctx)
+		if err != nil {
+			log.Errorf("[RebuildSpiderStatistics] find current spider statistics error: %+v", err)
+			return err
+		}
+
+		rebuilt, err := u.statisticsRepo.AggregateSpiderStatistics(ctx)
+		if err != nil {
+			log.Errorf("[RebuildSpiderStatistics] aggregate spider statistics error: %+v", err)
+			return err
+		}
+
+		report = &model.SpiderStatisticsRebuildReport{
+			DryRun:      dryRun,
+			FamilyCount: len(rebuilt),
+			Changes:     diffSpiderStatistics(current, rebuilt),
+		}
+
+		for _, statistics := range rebuilt {
+			report.RecordCount += statistics.RecordCount
+		}
+
+		if dryRun {
+			return nil
+		}
+
+		// family that is already counted keep its created time
+		createdAt := make(map[string]time.Time, len(current))
+		for _, statistics := range current {
+			createdAt[statistics.FamilyName] = statistics.CreatedAt
+		}
+
+		tn := time.Now()
+		for i := range rebuilt {
+			rebuilt[i].CreatedAt = tn
+			if t, ok := createdAt[rebuilt[i].FamilyName]; ok && !t.IsZero() {
+				rebuilt[i].CreatedAt = t
+			}
+			rebuilt[i].UpdatedAt = tn
+		}
+
+		if err := u.statisticsRepo.ReplaceAllSpiderStatistics(ctx, rebuilt); err != nil {
+			log.Errorf("[RebuildSpiderStatistics] replace spider statistics error: %+v", err)
+			return err
+		}
+
+		return nil
+	}
+
+	// dry run does not write, so it does not need transaction
+	var err error
+	if dryRun {
+		err = rebuild(ctx)
+	} else {
+		err = u.unitOfWork.Do(ctx, rebuild)
+	}
+	if err != nil {
+		return nil, ErrorSpiderStatisticsRebuildMongoConnection
+	}
+
+	if dryRun {
+		log.Infof("[RebuildSpiderStatistics] dry run found %v change", len(report.Changes))
+	} else {
+		log.Infof("[RebuildSpiderStatistics] rebuild %v family with %v change", report.FamilyCount, len(report.Changes))
+	}
+
+	return report, nil
+}
+
+// *************************************************
+
+type spiderStatisticsNode struct {
+	family  string
+	genus   string
+	species string
+}
+
+// diffSpiderStatistics compare record count of every family, genus and species node, sorted by node name
+func diffSpiderStatistics(current, rebuilt []model.SpiderStatistics) []model.SpiderStatisticsChange {
+	before := flattenSpiderStatistics(current)
+	after := flattenSpiderStatistics(rebuilt)
+
+	changes := []model.SpiderStatisticsChange{}
+
+	for node, beforeCount := range before {
+		change := model.SpiderStatisticsChange{
+			FamilyName:  node.family,
+			GenusName:   node.genus,
+			SpeciesName: node.species,
+			Before:      beforeCount,
+		}
+
+		afterCount, ok := after[node]
+		switch {
+		case !ok:
+			change.Action = model.SPIDER_STATISTICS_CHANGE_REMOVED
+		case afterCount != beforeCount:
+			change.Action = model.SPIDER_STATISTICS_CHANGE_CHANGED
+			change.After = afterCount
+		default:
+			continue
+		}
+
+		changes = append(changes, change)
+	}
+
+	for node, afterCount := range after {
+		if _, ok := before[node]; ok {
+			continue
+		}
+
+		changes = append(changes, model.SpiderStatisticsChange{
+			FamilyName:  node.family,
+			GenusName:   node.genus,
+			SpeciesName: node.species,
+			Action:      model.SPIDER_STATISTICS_CHANGE_ADDED,
+			After:       afterCount,
+		})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].FamilyName != changes[j].FamilyName {
+			return changes[i].FamilyName < changes[j].FamilyName
+		}
+		if changes[i].GenusName != changes[j].GenusName {
+			return changes[i].GenusName < changes[j].GenusName
+		}
+		return changes[i].SpeciesName < changes[j].SpeciesName
+	})
+
+	return changes
+}
+
+func flattenSpiderStatistics(statisticsList []model.SpiderStatistics) map[spiderStatisticsNode]int64 {
+	nodes := map[spiderStatisticsNode]int64{}
+
+	for _, family := range statisticsList {
+		nodes[spiderStatisticsNode{family: family.FamilyName}] = family.RecordCount
+
+		for _, genus := range family.Genus {
+			nodes[spiderStatisticsNode{family: family.FamilyName, genus: genus.GenusName}] = genus.RecordCount
+
+			for _, species := range genus.Species {
+				nodes[spiderStatisticsNode{family: family.FamilyName, genus: genus.GenusName, species: species.SpeciesName}] = species.RecordCount
+			}
+		}
+	}
+
+	return nodes
+}
@@ -0,0 +1,329 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	mock_domain "spider-go/domain/mock"
+	"spider-go/model"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+var unittestStatisticsCreatedAt = time.Date(2023, 3, 4, 0, 0, 0, 0, time.UTC)
+
+// current statistics drift from spider info, renamed species and deleted genus are still counted
+func unittestCurrentSpiderStatistics() []model.SpiderStatistics {
+	return []model.SpiderStatistics{
+		{
+			FamilyName: "Agelenidae",
+			Genus: []model.GenusGroup{
+				{
+					GenusName: "Agelena",
+					Species: []model.SpeciesGroup{
+						{SpeciesName: "limbata"},
+					},
+				},
+				{
+					GenusName: "Draconarius",
+					Species: []model.SpeciesGroup{
+						{SpeciesName: "abbreviatus", RecordCount: 2},
+					},
+					RecordCount: 2,
+				},
+			},
+			RecordCount: 2,
+			CreatedAt:   unittestStatisticsCreatedAt,
+		},
+	}
+}
+
+func unittestRebuiltSpiderStatistics() []model.SpiderStatistics {
+	return []model.SpiderStatistics{
+		{
+			FamilyName: "Agelenidae",
+			Genus: []model.GenusGroup{
+				{
+					GenusName: "Draconarius",
+					Species: []model.SpeciesGroup{
+						{SpeciesName: "abbreviatus", RecordCount: 1},
+						{SpeciesName: "brunneus", RecordCount: 1},
+					},
+					RecordCount: 2,
+				},
+			},
+			RecordCount: 2,
+		},
+		{
+			FamilyName: "Theridiidae",
+			Genus: []model.GenusGroup{
+				{
+					GenusName: "Chrysso",
+					Species: []model.SpeciesGroup{
+						{SpeciesName: "nigra", RecordCount: 1},
+					},
+					RecordCount: 1,
+				},
+			},
+			RecordCount: 1,
+		},
+	}
+}
+
+// ======================================================================
+// TestSpiderStatisticsRebuildUsecase_RebuildSpiderStatistics
+// ======================================================================
+func TestSpiderStatisticsRebuildUsecase_RebuildSpiderStatistics(t *testing.T) {
+
+	wantChanges := []model.SpiderStatisticsChange{
+		{FamilyName: "Agelenidae", GenusName: "Agelena", Action: model.SPIDER_STATISTICS_CHANGE_REMOVED},
+		{FamilyName: "Agelenidae", GenusName: "Agelena", SpeciesName: "limbata", Action: model.SPIDER_STATISTICS_CHANGE_REMOVED},
+		{FamilyName: "Agelenidae", GenusName: "Draconarius", SpeciesName: "abbreviatus", Action: model.SPIDER_STATISTICS_CHANGE_CHANGED, Before: 2, After: 1},
+		{FamilyName: "Agelenidae", GenusName: "Draconarius", SpeciesName: "brunneus", Action: model.SPIDER_STATISTICS_CHANGE_ADDED, After: 1},
+		{FamilyName: "Theridiidae", Action: model.SPIDER_STATISTICS_CHANGE_ADDED, After: 1},
+		{FamilyName: "Theridiidae", GenusName: "Chrysso", Action: model.SPIDER_STATISTICS_CHANGE_ADDED, After: 1},
+		{FamilyName: "Theridiidae", GenusName: "Chrysso", SpeciesName: "nigra", Action: model.SPIDER_STATISTICS_CHANGE_ADDED, After: 1},
+	}
+
+	// dry run only read statistics, so it is not run in unit of work
+	tests := []struct {
+		name           string
+		dryRun         bool
+		buildStubs     func(*mock_domain.MockStatisticsRepository)
+		unitOfWorkRuns int
+		want           *model.SpiderStatisticsRebuildReport
+		wantErr        error
+	}{
+		{
+			name:       "success_dry_run_report_changes",
+			dryRun:     true,
+			buildStubs: success_dry_run_report_changes,
+			want: &model.SpiderStatisticsRebuildReport{
+				DryRun:      true,
+				FamilyCount: 2,
+				RecordCount: 3,
+				Changes:     wantChanges,
+			},
+			wantErr: nil,
+		},
+		{
+			name:           "success_apply_replace_statistics",
+			dryRun:         false,
+			buildStubs:     success_apply_replace_statistics,
+			unitOfWorkRuns: 1,
+			want: &model.SpiderStatisticsRebuildReport{
+				DryRun:      false,
+				FamilyCount: 2,
+				RecordCount: 3,
+				Changes:     wantChanges,
+			},
+			wantErr: nil,
+		},
+		{
+			name:   "success_apply_retried_by_unit_of_work",
+			dryRun: false,
+			buildStubs: func(statisticsRepo *mock_domain.MockStatisticsRepository) {
+				// first attempt conflict with increase of statistics and is retried with new read
+				statisticsRepo.EXPECT().FindAllSpiderStatistics(gomock.Any()).Return(unittestCurrentSpiderStatistics(), nil).Times(2)
+				statisticsRepo.EXPECT().AggregateSpiderStatistics(gomock.Any()).Return(unittestRebuiltSpiderStatistics(), nil).Times(2)
+				gomock.InOrder(
+					statisticsRepo.EXPECT().ReplaceAllSpiderStatistics(gomock.Any(), gomock.Any()).Return(errors.New("write conflict")),
+					statisticsRepo.EXPECT().ReplaceAllSpiderStatistics(gomock.Any(), gomock.Any()).Return(nil),
+				)
+			},
+			unitOfWorkRuns: 2,
+			want: &model.SpiderStatisticsRebuildReport{
+				DryRun:      false,
+				FamilyCount: 2,
+				RecordCount: 3,
+				Changes:     wantChanges,
+			},
+			wantErr: nil,
+		},
+		{
+			name:   "aggregate_spider_statistics_error",
+			dryRun: true,
+			buildStubs: func(statisticsRepo *mock_domain.MockStatisticsRepository) {
+				statisticsRepo.EXPECT().FindAllSpiderStatistics(gomock.Any()).Return(unittestCurrentSpiderStatistics(), nil)
+				statisticsRepo.EXPECT().AggregateSpiderStatistics(gomock.Any()).Return(nil, errors.New("mongo error"))
+			},
+			want:    nil,
+			wantErr: ErrorSpiderStatisticsRebuildMongoConnection,
+		},
+		{
+			name:   "replace_spider_statistics_error",
+			dryRun: false,
+			buildStubs: func(statisticsRepo *mock_domain.MockStatisticsRepository) {
+				statisticsRepo.EXPECT().FindAllSpiderStatistics(gomock.Any()).Return(unittestCurrentSpiderStatistics(), nil)
+				statisticsRepo.EXPECT().AggregateSpiderStatistics(gomock.Any()).Return(unittestRebuiltSpiderStatistics(), nil)
+				statisticsRepo.EXPECT().ReplaceAllSpiderStatistics(gomock.Any(), gomock.Any()).Return(errors.New("mongo error"))
+			},
+			unitOfWorkRuns: 1,
+			want:           nil,
+			wantErr:        ErrorSpiderStatisticsRebuildMongoConnection,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			statisticsRepo := mock_domain.NewMockStatisticsRepository(ctrl)
+			tt.buildStubs(statisticsRepo)
+
+			// unit of work retry work until it succeed, like transient error of transaction
+			doTimes := 0
+			if tt.unitOfWorkRuns > 0 {
+				doTimes = 1
+			}
+
+			unitOfWork := mock_domain.NewMockUnitOfWork(ctrl)
+			unitOfWork.EXPECT().Do(
+				gomock.Any(),
+				gomock.Any(),
+			).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				var err error
+				for i := 0; i < tt.unitOfWorkRuns; i++ {
+					if err = fn(ctx); err == nil {
+						return nil
+					}
+				}
+				return err
+			}).Times(doTimes)
+
+			u := NewSpiderStatisticsRebuildUsecase(statisticsRepo, unitOfWork)
+			got, err := u.RebuildSpiderStatistics(context.TODO(), tt.dryRun)
+			if err != tt.wantErr {
+				t.Errorf("SpiderStatisticsRebuildUsecase.RebuildSpiderStatistics() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SpiderStatisticsRebuildUsecase.RebuildSpiderStatistics() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func success_dry_run_report_changes(statisticsRepo *mock_domain.MockStatisticsRepository) {
+	statisticsRepo.EXPECT().FindAllSpiderStatistics(gomock.Any()).Return(unittestCurrentSpiderStatistics(), nil)
+	statisticsRepo.EXPECT().AggregateSpiderStatistics(gomock.Any()).Return(unittestRebuiltSpiderStatistics(), nil)
+	statisticsRepo.EXPECT().ReplaceAllSpiderStatistics(gomock.Any(), gomock.Any()).Times(0)
+}
+
+func success_apply_replace_statistics(statisticsRepo *mock_domain.MockStatisticsRepository) {
+	statisticsRepo.EXPECT().FindAllSpiderStatistics(gomock.Any()).Return(unittestCurrentSpiderStatistics(), nil)
+	statisticsRepo.EXPECT().AggregateSpiderStatistics(gomock.Any()).Return(unittestRebuiltSpiderStatistics(), nil)
+
+	statisticsRepo.EXPECT().ReplaceAllSpiderStatistics(
+		gomock.Any(),
+		gomock.Any(),
+	).DoAndReturn(func(_ context.Context, data []model.SpiderStatistics) error {
+		// counted family keep created time, new family is created now
+		if len(data) != 2 || !data[0].CreatedAt.Equal(unittestStatisticsCreatedAt) || data[1].CreatedAt.IsZero() {
+			return errors.New("unexpected rebuilt statistics")
+		}
+		return nil
+	})
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestDiffSpiderStatistics
+// ======================================================================
+
+// dry run report is difference between current statistics and statistics that is rebuilt from spider info
+func TestDiffSpiderStatistics(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		current []model.SpiderStatistics
+		rebuilt []model.SpiderStatistics
+		want    []model.SpiderStatisticsChange
+	}{
+		{
+			name:    "same_statistics_no_change",
+			current: unittestRebuiltSpiderStatistics(),
+			rebuilt: unittestRebuiltSpiderStatistics(),
+			want:    []model.SpiderStatisticsChange{},
+		},
+		{
+			name:    "both_empty_no_change",
+			current: nil,
+			rebuilt: []model.SpiderStatistics{},
+			want:    []model.SpiderStatisticsChange{},
+		},
+		{
+			name:    "no_current_statistics_every_node_added",
+			current: nil,
+			rebuilt: unittestRebuiltSpiderStatistics()[1:],
+			want: []model.SpiderStatisticsChange{
+				{FamilyName: "Theridiidae", Action: model.SPIDER_STATISTICS_CHANGE_ADDED, After: 1},
+				{FamilyName: "Theridiidae", GenusName: "Chrysso", Action: model.SPIDER_STATISTICS_CHANGE_ADDED, After: 1},
+				{FamilyName: "Theridiidae", GenusName: "Chrysso", SpeciesName: "nigra", Action: model.SPIDER_STATISTICS_CHANGE_ADDED, After: 1},
+			},
+		},
+		{
+			name:    "no_published_spider_every_node_removed",
+			current: unittestRebuiltSpiderStatistics()[1:],
+			rebuilt: []model.SpiderStatistics{},
+			want: []model.SpiderStatisticsChange{
+				{FamilyName: "Theridiidae", Action: model.SPIDER_STATISTICS_CHANGE_REMOVED, Before: 1},
+				{FamilyName: "Theridiidae", GenusName: "Chrysso", Action: model.SPIDER_STATISTICS_CHANGE_REMOVED, Before: 1},
+				{FamilyName: "Theridiidae", GenusName: "Chrysso", SpeciesName: "nigra", Action: model.SPIDER_STATISTICS_CHANGE_REMOVED, Before: 1},
+			},
+		},
+		{
+			// family and genus count is still two, only species under it move
+			name:    "species_moved_in_genus",
+			current: unittestCurrentSpiderStatistics(),
+			rebuilt: unittestRebuiltSpiderStatistics()[:1],
+			want: []model.SpiderStatisticsChange{
+				{FamilyName: "Agelenidae", GenusName: "Agelena", Action: model.SPIDER_STATISTICS_CHANGE_REMOVED},
+				{FamilyName: "Agelenidae", GenusName: "Agelena", SpeciesName: "limbata", Action: model.SPIDER_STATISTICS_CHANGE_REMOVED},
+				{FamilyName: "Agelenidae", GenusName: "Draconarius", SpeciesName: "abbreviatus", Action: model.SPIDER_STATISTICS_CHANGE_CHANGED, Before: 2, After: 1},
+				{FamilyName: "Agelenidae", GenusName: "Draconarius", SpeciesName: "brunneus", Action: model.SPIDER_STATISTICS_CHANGE_ADDED, After: 1},
+			},
+		},
+		{
+			// species with the same name in other genus or family is other node
+			name: "same_species_name_in_other_genus",
+			current: []model.SpiderStatistics{
+				{
+					FamilyName: "Salticidae",
+					Genus: []model.GenusGroup{
+						{GenusName: "Phintella", Species: []model.SpeciesGroup{{SpeciesName: "vittata", RecordCount: 1}}, RecordCount: 1},
+					},
+					RecordCount: 1,
+				},
+			},
+			rebuilt: []model.SpiderStatistics{
+				{
+					FamilyName: "Salticidae",
+					Genus: []model.GenusGroup{
+						{GenusName: "Plexippus", Species: []model.SpeciesGroup{{SpeciesName: "vittata", RecordCount: 1}}, RecordCount: 1},
+					},
+					RecordCount: 1,
+				},
+			},
+			want: []model.SpiderStatisticsChange{
+				{FamilyName: "Salticidae", GenusName: "Phintella", Action: model.SPIDER_STATISTICS_CHANGE_REMOVED, Before: 1},
+				{FamilyName: "Salticidae", GenusName: "Phintella", SpeciesName: "vittata", Action: model.SPIDER_STATISTICS_CHANGE_REMOVED, Before: 1},
+				{FamilyName: "Salticidae", GenusName: "Plexippus", Action: model.SPIDER_STATISTICS_CHANGE_ADDED, After: 1},
+				{FamilyName: "Salticidae", GenusName: "Plexippus", SpeciesName: "vittata", Action: model.SPIDER_STATISTICS_CHANGE_ADDED, After: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffSpiderStatistics(tt.current, tt.rebuilt); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffSpiderStatistics() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// **********************************************************************
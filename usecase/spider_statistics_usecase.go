@@ -30,3 +30,37 @@ func (u *SpiderStatistics) GetSpiderStatisticsList(ctx context.Context) ([]model
 
 	return spiderStatistics, nil
 }
+
+// *************************************************
+
+// moveSpiderStatistics move record count of spider info from taxon of `from` to taxon of `to`,
+// only published spider info is counted so create is (nil, new), delete is (old, nil) and
+// publish is (submitted, published). it must be called in same unit of work as write of spider info
+func moveSpiderStatistics(ctx context.Context, statisticsRepo domain.StatisticsRepository, from, to *model.SpiderInfo) error {
+	if from != nil && !from.IsPublic() {
+		from = nil
+	}
+
+	if to != nil && !to.IsPublic() {
+		to = nil
+	}
+
+	if from != nil && to != nil &&
+		from.Family == to.Family && from.Genus == to.Genus && from.Species == to.Species {
+		return nil
+	}
+
+	if from != nil {
+		if err := statisticsRepo.IncreaseSpiderStatistics(ctx, from.Family, from.Genus, from.Species, -1); err != nil {
+			return err
+		}
+	}
+
+	if to != nil {
+		if err := statisticsRepo.IncreaseSpiderStatistics(ctx, to.Family, to.Genus, to.Species, 1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"spider-go/config"
+	"spider-go/domain"
+	"spider-go/logger"
+	"spider-go/model"
+	"spider-go/repository"
+	"spider-go/utils/storage"
+	"time"
+)
+
+var (
+	ErrorSpiderTrashNotFound        = fmt.Errorf("[Spider Trash Usecase]: spider info is not in trash")
+	ErrorSpiderTrashMongoConnection = fmt.Errorf("[Spider Trash Usecase]: mongo error")
+)
+
+const (
+	DEFAULT_SPIDER_TRASH_RETENTION_PERIOD = 30 * 24 * time.Hour
+	DEFAULT_SPIDER_TRASH_PURGE_INTERVAL   = time.Hour
+)
+
+type SpiderTrashUsecase struct {
+	spiderRepo     domain.SpiderRepository
+	statisticsRepo domain.StatisticsRepository
+	revisionRepo   domain.SpiderRevisionRepository
+	unitOfWork     domain.UnitOfWork
+	imageStorage   domain.ImageStorage
+	config         *config.Root
+	log            *logger.Logger
+}
+
+func NewSpiderTrashUsecase(
+	spiderRepo domain.SpiderRepository,
+	statisticsRepo domain.StatisticsRepository,
+	revisionRepo domain.SpiderRevisionRepository,
+	unitOfWork domain.UnitOfWork,
+	imageStorage domain.ImageStorage,
+	conf *config.Root,
+) domain.SpiderTrashUsecase {
+	return &SpiderTrashUsecase{
+		spiderRepo:     spiderRepo,
+		statisticsRepo: statisticsRepo,
+		revisionRepo:   revisionRepo,
+		unitOfWork:     unitOfWork,
+		imageStorage:   imageStorage,
+		config:         conf,
+		log:            logger.L().Named("SpiderTrashUsecase"),
+	}
+}
+
+// GetSpiderTrashList return spider info in trash, latest deleted first
+func (u *SpiderTrashUsecase) GetSpiderTrashList(ctx context.Context, page, size int32) ([]model.SpiderInfo, int64, error) {
+	log := u.log.WithContext(ctx)
+
+	spiderInfoList, total, err := u.spiderRepo.FindSpiderTrashList(ctx, page, size)
+	if err != nil {
+		log.Errorf("[GetSpiderTrashList] find spider trash list error: %+v", err)
+		return nil, 0, ErrorSpiderTrashMongoConnection
+	}
+
+	return spiderInfoList, total, nil
+}
+
+// RestoreSpiderInfo move spider info out of trash with status before delete
+func (u *SpiderTrashUsecase) RestoreSpiderInfo(ctx context.Context, spiderUUID string, username string) error {
+	log := u.log.WithContext(ctx)
+
+	spiderInfo, err := u.spiderRepo.FindSpiderByUUID(ctx, spiderUUID)
+	if err != nil {
+		log.Errorf("[RestoreSpiderInfo] find spider info `%v` error: %+v", spiderUUID, err)
+		if err == repository.ErrorMongoNotFound {
+			return ErrorSpiderTrashNotFound
+		}
+		return ErrorSpiderTrashMongoConnection
+	}
+
+	if !spiderInfo.IsDeleted() {
+		return ErrorSpiderTrashNotFound
+	}
+
+	status := spiderInfo.StatusBeforeDelete
+	if status == "" {
+		status = model.SPIDER_INFO_STATUS_INACTIVE
+	}
+
+	restored := *spiderInfo
+	restored.Status = status
+	restored.StatusBeforeDelete = ""
+	restored.DeletedBy = ""
+	restored.DeletedAt = nil
+	restored.UpdatedAt = time.Now()
+
+	err = u.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := u.spiderRepo.RestoreSpiderInfoFromTrash(ctx, spiderUUID, status); err != nil {
+			return err
+		}
+		if err := moveSpiderStatistics(ctx, u.statisticsRepo, nil, &restored); err != nil {
+			return err
+		}
+		return recordSpiderRevision(ctx, u.revisionRepo, log, model.SPIDER_REVISION_ACTION_RESTORE, restored, username)
+	})
+	if err != nil {
+		log.Errorf("[RestoreSpiderInfo] restore spider info `%v` error: %+v", spiderUUID, err)
+		if err == repository.ErrorMongoNotFound {
+			return ErrorSpiderTrashNotFound
+		}
+		return ErrorSpiderTrashMongoConnection
+	}
+
+	log.Infof("[RestoreSpiderInfo] `%v` restore spider info `%v` from trash", username, spiderUUID)
+
+	return nil
+}
+
+// PurgeExpiredSpiderTrash remove spider info that stay in trash longer than retention period and its image files,
+// revision history is kept so purged spider info can still be restored from revision
+func (u *SpiderTrashUsecase) PurgeExpiredSpiderTrash(ctx context.Context) (int, error) {
+	log := u.log.WithContext(ctx)
+
+	retentionPeriod := u.config.SpiderTrash.RetentionPeriod
+	if retentionPeriod <= 0 {
+		retentionPeriod = DEFAULT_SPIDER_TRASH_RETENTION_PERIOD
+	}
+
+	deletedBefore := time.Now().Add(-retentionPeriod)
+
+	spiderInfoList, err := u.spiderRepo.FindExpiredSpiderTrash(ctx, deletedBefore)
+	if err != nil {
+		log.Errorf("[PurgeExpiredSpiderTrash] find spider info deleted before `%v` error: %+v", deletedBefore, err)
+		return 0, ErrorSpiderTrashMongoConnection
+	}
+
+	purgedCount := 0
+
+	for _, spiderInfo := range spiderInfoList {
+		if err := u.spiderRepo.PurgeSpiderInfo(ctx, spiderInfo.SpiderUUID, deletedBefore); err != nil {
+			// spider info is restored after it was listed
+			if err == repository.ErrorSpiderRepositoryDeleteSpiderIsZero {
+				continue
+			}
+			log.Errorf("[PurgeExpiredSpiderTrash] purge spider info `%v` error: %+v", spiderInfo.SpiderUUID, err)
+			return purgedCount, ErrorSpiderTrashMongoConnection
+		}
+
+		removeSpiderImageFiles(ctx, log, u.imageStorage, spiderInfo.ImageFile)
+		purgedCount++
+	}
+
+	if purgedCount > 0 {
+		log.Infof("[PurgeExpiredSpiderTrash] purge `%v` spider info deleted before `%v`", purgedCount, deletedBefore)
+	}
+
+	return purgedCount, nil
+}
+
+// RunTrashPurge purge expired spider trash every interval until ctx is done
+func (u *SpiderTrashUsecase) RunTrashPurge(ctx context.Context, interval time.Duration) {
+	log := u.log.WithContext(ctx)
+
+	if interval <= 0 {
+		interval = DEFAULT_SPIDER_TRASH_PURGE_INTERVAL
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := u.PurgeExpiredSpiderTrash(ctx); err != nil {
+				log.Errorf("[RunTrashPurge] purge expired spider trash error: %+v", err)
+			}
+		}
+	}
+}
+
+// removeSpiderImageFiles remove image files of spider info, file that can not be removed is only logged
+func removeSpiderImageFiles(ctx context.Context, log *logger.Logger, imageStorage domain.ImageStorage, spiderImageList []string) {
+	for _, spiderImage := range withImageDerivatives(spiderImageList) {
+		tryToRemove := true
+		tryCount := 0
+
+		var RemoveErr error
+
+		for tryToRemove && tryCount < 3 {
+			if err := imageStorage.Delete(ctx, spiderImage); err != nil {
+				RemoveErr = err
+				tryCount += 1
+			} else {
+				tryToRemove = false
+			}
+		}
+		if tryToRemove {
+			log.Errorf("[removeSpiderImageFiles] remove spider image name is `%v` failed, error: %v", spiderImage, RemoveErr)
+		} else {
+			log.Infof("[removeSpiderImageFiles] remove spider image name is `%v` successfull", spiderImage)
+		}
+	}
+}
+
+// withImageDerivatives add derivative of each image to list, derivative is removed together with its original
+func withImageDerivatives(spiderImageList []string) []string {
+	var imageList []string
+
+	for _, spiderImage := range spiderImageList {
+		for _, size := range model.IMAGE_DERIVATIVE_SIZES {
+			imageList = append(imageList, storage.DerivativeImageName(spiderImage, size))
+		}
+		imageList = append(imageList, spiderImage)
+	}
+
+	return imageList
+}
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"os"
+	"path"
+	"spider-go/config"
+	mock_domain "spider-go/domain/mock"
+	"spider-go/model"
+	"spider-go/repository"
+	"spider-go/utils/storage"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+type commonStubsSpiderTrash struct {
+	mockSpiderRepo     *mock_domain.MockSpiderRepository
+	mockStatisticsRepo *mock_domain.MockStatisticsRepository
+	mockRevisionRepo   *mock_domain.MockSpiderRevisionRepository
+	mockUnitOfWork     *mock_domain.MockUnitOfWork
+}
+
+const unittestTrashSpiderUUID = "SPIDER_565391ff-9197-47ce-b86e-311d7b901f53"
+
+func newCommonStubsSpiderTrash(ctrl *gomock.Controller) commonStubsSpiderTrash {
+	return commonStubsSpiderTrash{
+		mockSpiderRepo:     mock_domain.NewMockSpiderRepository(ctrl),
+		mockStatisticsRepo: mock_domain.NewMockStatisticsRepository(ctrl),
+		mockRevisionRepo:   mock_domain.NewMockSpiderRevisionRepository(ctrl),
+		mockUnitOfWork:     newPassThroughUnitOfWork(ctrl),
+	}
+}
+
+// ======================================================================
+// TestSpiderTrashUsecase_RestoreSpiderInfo
+// ======================================================================
+func TestSpiderTrashUsecase_RestoreSpiderInfo(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		buildStubs func(*commonStubsSpiderTrash)
+		wantErr    error
+	}{
+		{
+			name:       "success_restore_with_status_before_delete",
+			buildStubs: success_restore_with_status_before_delete,
+			wantErr:    nil,
+		},
+		{
+			name: "spider_info_not_in_trash",
+			buildStubs: func(stubs *commonStubsSpiderTrash) {
+				stubs.mockSpiderRepo.EXPECT().FindSpiderByUUID(
+					gomock.Any(),
+					gomock.Eq(unittestTrashSpiderUUID),
+				).Return(&model.SpiderInfo{
+					SpiderUUID: unittestTrashSpiderUUID,
+					Status:     model.SPIDER_INFO_STATUS_ACTIVE,
+				}, nil)
+			},
+			wantErr: ErrorSpiderTrashNotFound,
+		},
+		{
+			name: "spider_info_already_purged",
+			buildStubs: func(stubs *commonStubsSpiderTrash) {
+				stubs.mockSpiderRepo.EXPECT().FindSpiderByUUID(
+					gomock.Any(),
+					gomock.Eq(unittestTrashSpiderUUID),
+				).Return(nil, repository.ErrorMongoNotFound)
+			},
+			wantErr: ErrorSpiderTrashNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := newCommonStubsSpiderTrash(ctrl)
+			tt.buildStubs(&stubs)
+
+			u := NewSpiderTrashUsecase(stubs.mockSpiderRepo, stubs.mockStatisticsRepo, stubs.mockRevisionRepo, stubs.mockUnitOfWork, nil, &config.Root{})
+			if err := u.RestoreSpiderInfo(context.TODO(), unittestTrashSpiderUUID, "unittest"); err != tt.wantErr {
+				t.Errorf("SpiderTrashUsecase.RestoreSpiderInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func success_restore_with_status_before_delete(stubs *commonStubsSpiderTrash) {
+	deletedAt := time.Now()
+
+	stubs.mockSpiderRepo.EXPECT().FindSpiderByUUID(
+		gomock.Any(),
+		gomock.Eq(unittestTrashSpiderUUID),
+	).Return(&model.SpiderInfo{
+		SpiderUUID:         unittestTrashSpiderUUID,
+		Family:             "Agelenidae",
+		Genus:              "Draconarius",
+		Species:            "abbreviatus",
+		Status:             model.SPIDER_INFO_STATUS_DELETED,
+		StatusBeforeDelete: model.SPIDER_INFO_STATUS_INACTIVE,
+		DeletedBy:          "admin",
+		DeletedAt:          &deletedAt,
+	}, nil)
+
+	stubs.mockSpiderRepo.EXPECT().RestoreSpiderInfoFromTrash(
+		gomock.Any(),
+		gomock.Eq(unittestTrashSpiderUUID),
+		gomock.Eq(model.SPIDER_INFO_STATUS_INACTIVE),
+	).Return(nil)
+
+	// restored spider info is inactive, only published spider info is counted in statistics
+
+	stubRecordSpiderRevision(stubs.mockRevisionRepo, model.SPIDER_REVISION_ACTION_RESTORE, "unittest")
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestSpiderTrashUsecase_PurgeExpiredSpiderTrash
+// ======================================================================
+func TestSpiderTrashUsecase_PurgeExpiredSpiderTrash(t *testing.T) {
+
+	tempDir := t.TempDir()
+	imageName := "SPIDER_565391ff-volume-0.png"
+
+	if err := os.WriteFile(path.Join(tempDir, imageName), []byte("image"), 0644); err != nil {
+		t.Fatalf("prepare image file error: %+v", err)
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	stubs := newCommonStubsSpiderTrash(ctrl)
+
+	stubs.mockSpiderRepo.EXPECT().FindExpiredSpiderTrash(
+		gomock.Any(),
+		gomock.Any(),
+	).Return([]model.SpiderInfo{
+		{
+			SpiderUUID: unittestTrashSpiderUUID,
+			ImageFile:  []string{imageName},
+		},
+		{
+			SpiderUUID: "SPIDER_restored_after_listed",
+		},
+	}, nil)
+
+	stubs.mockSpiderRepo.EXPECT().PurgeSpiderInfo(
+		gomock.Any(),
+		gomock.Eq(unittestTrashSpiderUUID),
+		gomock.Any(),
+	).Return(nil)
+
+	stubs.mockSpiderRepo.EXPECT().PurgeSpiderInfo(
+		gomock.Any(),
+		gomock.Eq("SPIDER_restored_after_listed"),
+		gomock.Any(),
+	).Return(repository.ErrorSpiderRepositoryDeleteSpiderIsZero)
+
+	conf := &config.Root{
+		SpiderTrash: config.SpiderTrash{
+			RetentionPeriod: 24 * time.Hour,
+		},
+	}
+
+	u := NewSpiderTrashUsecase(stubs.mockSpiderRepo, stubs.mockStatisticsRepo, stubs.mockRevisionRepo, stubs.mockUnitOfWork, storage.NewLocalImageStorage(tempDir), conf)
+
+	purgedCount, err := u.PurgeExpiredSpiderTrash(context.TODO())
+	if err != nil || purgedCount != 1 {
+		t.Errorf("SpiderTrashUsecase.PurgeExpiredSpiderTrash() = %v, %v, want 1, nil", purgedCount, err)
+	}
+
+	if _, err := os.Stat(path.Join(tempDir, imageName)); !os.IsNotExist(err) {
+		t.Errorf("SpiderTrashUsecase.PurgeExpiredSpiderTrash() image file is not removed, stat error: %v", err)
+	}
+}
+
+// **********************************************************************
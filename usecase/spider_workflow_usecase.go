@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"spider-go/domain"
+	"spider-go/logger"
+	"spider-go/model"
+	"spider-go/repository"
+	"strings"
+	"time"
+)
+
+var (
+	ErrorSpiderWorkflowNotFound          = fmt.Errorf("[Spider Workflow Usecase]: spider info not found")
+	ErrorSpiderWorkflowInvalidTransition = fmt.Errorf("[Spider Workflow Usecase]: invalid status transition")
+	ErrorSpiderWorkflowPermissionDenied  = fmt.Errorf("[Spider Workflow Usecase]: permission denied")
+	ErrorSpiderWorkflowCommentRequired   = fmt.Errorf("[Spider Workflow Usecase]: review comment is required")
+	ErrorSpiderWorkflowMongoConnection   = fmt.Errorf("[Spider Workflow Usecase]: mongo error")
+)
+
+type SpiderWorkflowUsecase struct {
+	spiderRepo     domain.SpiderRepository
+	statisticsRepo domain.StatisticsRepository
+	revisionRepo   domain.SpiderRevisionRepository
+	unitOfWork     domain.UnitOfWork
+	log            *logger.Logger
+}
+
+func NewSpiderWorkflowUsecase(
+	spiderRepo domain.SpiderRepository,
+	statisticsRepo domain.StatisticsRepository,
+	revisionRepo domain.SpiderRevisionRepository,
+	unitOfWork domain.UnitOfWork,
+) domain.SpiderWorkflowUsecase {
+	return &SpiderWorkflowUsecase{
+		spiderRepo:     spiderRepo,
+		statisticsRepo: statisticsRepo,
+		revisionRepo:   revisionRepo,
+		unitOfWork:     unitOfWork,
+		log:            logger.L().Named("SpiderWorkflowUsecase"),
+	}
+}
+
+// SubmitSpiderInfo send draft or rejected spider info to review queue
+func (u *SpiderWorkflowUsecase) SubmitSpiderInfo(ctx context.Context, spiderUUID string, actor *model.LoginUser) error {
+	return u.transitSpiderInfo(ctx, spiderUUID, actor, model.SPIDER_INFO_STATUS_SUBMITTED, nil)
+}
+
+// ReviewSpiderInfo publish or reject submitted spider info, comment is required when reject
+func (u *SpiderWorkflowUsecase) ReviewSpiderInfo(ctx context.Context, spiderUUID string, actor *model.LoginUser, approve bool, comment string) error {
+	toStatus := model.SPIDER_INFO_STATUS_PUBLISHED
+
+	if !approve {
+		if strings.TrimSpace(comment) == "" {
+			return ErrorSpiderWorkflowCommentRequired
+		}
+		toStatus = model.SPIDER_INFO_STATUS_REJECTED
+	}
+
+	review := &model.SpiderReview{
+		Comment:    comment,
+		ReviewedBy: actor.Username,
+		ReviewedAt: time.Now(),
+	}
+
+	return u.transitSpiderInfo(ctx, spiderUUID, actor, toStatus, review)
+}
+
+// GetReviewQueue return submitted spider info, oldest submit first
+func (u *SpiderWorkflowUsecase) GetReviewQueue(ctx context.Context, page, size int32) ([]model.SpiderInfo, int64, error) {
+	log := u.log.WithContext(ctx)
+
+	spiderInfoList, total, err := u.spiderRepo.FindSpiderInfoListByStatus(ctx, model.SPIDER_INFO_STATUS_SUBMITTED, page, size)
+	if err != nil {
+		log.Errorf("[GetReviewQueue] find submitted spider info error: %+v", err)
+		return nil, 0, ErrorSpiderWorkflowMongoConnection
+	}
+
+	return spiderInfoList, total, nil
+}
+
+// *************************************************
+
+func (u *SpiderWorkflowUsecase) transitSpiderInfo(ctx context.Context, spiderUUID string, actor *model.LoginUser, toStatus string, review *model.SpiderReview) error {
+	log := u.log.WithContext(ctx)
+
+	spiderInfo, err := u.spiderRepo.FindSpiderByUUID(ctx, spiderUUID)
+	if err != nil {
+		log.Errorf("[transitSpiderInfo] find spider info `%v` error: %+v", spiderUUID, err)
+		if err == repository.ErrorMongoNotFound {
+			return ErrorSpiderWorkflowNotFound
+		}
+		return ErrorSpiderWorkflowMongoConnection
+	}
+
+	if spiderInfo.IsDeleted() {
+		return ErrorSpiderWorkflowNotFound
+	}
+
+	permission, ok := model.SpiderWorkflowPermission(spiderInfo.Status, toStatus)
+	if !ok {
+		log.Errorf("[transitSpiderInfo] can not move spider info `%v` from `%v` to `%v`", spiderUUID, spiderInfo.Status, toStatus)
+		return ErrorSpiderWorkflowInvalidTransition
+	}
+
+	if !actor.HasPermission(permission) {
+		return ErrorSpiderWorkflowPermissionDenied
+	}
+
+	// contributor can move only own record, reviewer can act on any record
+	if permission == model.PERMISSION_SPIDER_CREATE &&
+		spiderInfo.CreatedBy != actor.Username &&
+		!actor.HasPermission(model.PERMISSION_SPIDER_REVIEW) {
+		return ErrorSpiderWorkflowPermissionDenied
+	}
+
+	// status update increase version, revision snapshot must carry version that is written
+	transited := *spiderInfo
+	transited.Status = toStatus
+	transited.UpdatedAt = time.Now()
+	transited.Version = spiderInfo.Version + 1
+	if review != nil {
+		transited.Review = review
+	}
+
+	// spider info is counted in statistics when it is published
+	err = u.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := u.spiderRepo.UpdateSpiderStatus(ctx, spiderUUID, spiderInfo.Status, toStatus, review); err != nil {
+			return err
+		}
+		if err := moveSpiderStatistics(ctx, u.statisticsRepo, spiderInfo, &transited); err != nil {
+			return err
+		}
+		return recordSpiderRevision(ctx, u.revisionRepo, log, model.SPIDER_REVISION_ACTION_UPDATE, transited, actor.Username)
+	})
+	if err != nil {
+		log.Errorf("[transitSpiderInfo] update status of spider info `%v` error: %+v", spiderUUID, err)
+		if err == repository.ErrorMongoNotFound {
+			// status is changed by other request after find
+			return ErrorSpiderWorkflowInvalidTransition
+		}
+		return ErrorSpiderWorkflowMongoConnection
+	}
+
+	log.Infof("[transitSpiderInfo] `%v` move spider info `%v` from `%v` to `%v`", actor.Username, spiderUUID, spiderInfo.Status, toStatus)
+
+	return nil
+}
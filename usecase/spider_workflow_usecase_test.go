@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"context"
+	mock_domain "spider-go/domain/mock"
+	"spider-go/model"
+	"spider-go/repository"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+)
+
+type commonStubsSpiderWorkflow struct {
+	mockSpiderRepo     *mock_domain.MockSpiderRepository
+	mockStatisticsRepo *mock_domain.MockStatisticsRepository
+	mockRevisionRepo   *mock_domain.MockSpiderRevisionRepository
+	mockUnitOfWork     *mock_domain.MockUnitOfWork
+}
+
+const unittestWorkflowSpiderUUID = "SPIDER_2f1b9d6e-3c0a-4f5e-9a8b-7d6c5e4f3a21"
+
+var (
+	unittestContributor = &model.LoginUser{Username: "contributor", Role: model.ACCOUNT_ROLE_GENERAL}
+	unittestReviewer    = &model.LoginUser{Username: "reviewer", Role: model.ACCOUNT_ROLE_ADMIN}
+)
+
+func newCommonStubsSpiderWorkflow(ctrl *gomock.Controller) commonStubsSpiderWorkflow {
+	return commonStubsSpiderWorkflow{
+		mockSpiderRepo:     mock_domain.NewMockSpiderRepository(ctrl),
+		mockStatisticsRepo: mock_domain.NewMockStatisticsRepository(ctrl),
+		mockRevisionRepo:   mock_domain.NewMockSpiderRevisionRepository(ctrl),
+		mockUnitOfWork:     newPassThroughUnitOfWork(ctrl),
+	}
+}
+
+func stubFindWorkflowSpiderInfo(stubs *commonStubsSpiderWorkflow, status, createdBy string) {
+	stubs.mockSpiderRepo.EXPECT().FindSpiderByUUID(
+		gomock.Any(),
+		gomock.Eq(unittestWorkflowSpiderUUID),
+	).Return(&model.SpiderInfo{
+		SpiderUUID: unittestWorkflowSpiderUUID,
+		Family:     "Agelenidae",
+		Genus:      "Draconarius",
+		Species:    "abbreviatus",
+		Status:     status,
+		CreatedBy:  createdBy,
+		Version:    3,
+	}, nil)
+}
+
+// ======================================================================
+// TestSpiderWorkflowUsecase_SubmitSpiderInfo
+// ======================================================================
+func TestSpiderWorkflowUsecase_SubmitSpiderInfo(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		actor      *model.LoginUser
+		buildStubs func(*commonStubsSpiderWorkflow)
+		wantErr    error
+	}{
+		{
+			name:       "success_submit_own_draft",
+			actor:      unittestContributor,
+			buildStubs: success_submit_own_draft,
+			wantErr:    nil,
+		},
+		{
+			name:  "submit_draft_of_other_contributor",
+			actor: unittestContributor,
+			buildStubs: func(stubs *commonStubsSpiderWorkflow) {
+				stubFindWorkflowSpiderInfo(stubs, model.SPIDER_INFO_STATUS_DRAFT, "other")
+			},
+			wantErr: ErrorSpiderWorkflowPermissionDenied,
+		},
+		{
+			name:  "submit_published_spider_info",
+			actor: unittestContributor,
+			buildStubs: func(stubs *commonStubsSpiderWorkflow) {
+				stubFindWorkflowSpiderInfo(stubs, model.SPIDER_INFO_STATUS_PUBLISHED, "contributor")
+			},
+			wantErr: ErrorSpiderWorkflowInvalidTransition,
+		},
+		{
+			name:  "status_changed_by_other_request",
+			actor: unittestContributor,
+			buildStubs: func(stubs *commonStubsSpiderWorkflow) {
+				stubFindWorkflowSpiderInfo(stubs, model.SPIDER_INFO_STATUS_REJECTED, "contributor")
+
+				stubs.mockSpiderRepo.EXPECT().UpdateSpiderStatus(
+					gomock.Any(),
+					gomock.Eq(unittestWorkflowSpiderUUID),
+					gomock.Eq(model.SPIDER_INFO_STATUS_REJECTED),
+					gomock.Eq(model.SPIDER_INFO_STATUS_SUBMITTED),
+					gomock.Nil(),
+				).Return(repository.ErrorMongoNotFound)
+			},
+			wantErr: ErrorSpiderWorkflowInvalidTransition,
+		},
+		{
+			name:  "spider_info_not_found",
+			actor: unittestContributor,
+			buildStubs: func(stubs *commonStubsSpiderWorkflow) {
+				stubs.mockSpiderRepo.EXPECT().FindSpiderByUUID(
+					gomock.Any(),
+					gomock.Eq(unittestWorkflowSpiderUUID),
+				).Return(nil, repository.ErrorMongoNotFound)
+			},
+			wantErr: ErrorSpiderWorkflowNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := newCommonStubsSpiderWorkflow(ctrl)
+			tt.buildStubs(&stubs)
+
+			u := NewSpiderWorkflowUsecase(stubs.mockSpiderRepo, stubs.mockStatisticsRepo, stubs.mockRevisionRepo, stubs.mockUnitOfWork)
+			if err := u.SubmitSpiderInfo(context.TODO(), unittestWorkflowSpiderUUID, tt.actor); err != tt.wantErr {
+				t.Errorf("SpiderWorkflowUsecase.SubmitSpiderInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func success_submit_own_draft(stubs *commonStubsSpiderWorkflow) {
+	stubFindWorkflowSpiderInfo(stubs, model.SPIDER_INFO_STATUS_DRAFT, "contributor")
+
+	stubs.mockSpiderRepo.EXPECT().UpdateSpiderStatus(
+		gomock.Any(),
+		gomock.Eq(unittestWorkflowSpiderUUID),
+		gomock.Eq(model.SPIDER_INFO_STATUS_DRAFT),
+		gomock.Eq(model.SPIDER_INFO_STATUS_SUBMITTED),
+		gomock.Nil(),
+	).Return(nil)
+
+	// snapshot carry status and version that are written by status update
+	stubs.mockRevisionRepo.EXPECT().InsertSpiderRevision(
+		gomock.Any(),
+		EqSpiderRevision(model.SPIDER_REVISION_ACTION_UPDATE, "contributor"),
+	).DoAndReturn(func(_ context.Context, revision model.SpiderRevision) (int64, error) {
+		if revision.Snapshot.Status != model.SPIDER_INFO_STATUS_SUBMITTED || revision.Snapshot.Version != 4 {
+			return 0, repository.ErrorMongoNotFound
+		}
+		return 2, nil
+	})
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestSpiderWorkflowUsecase_ReviewSpiderInfo
+// ======================================================================
+func TestSpiderWorkflowUsecase_ReviewSpiderInfo(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		actor      *model.LoginUser
+		approve    bool
+		comment    string
+		buildStubs func(*commonStubsSpiderWorkflow)
+		wantErr    error
+	}{
+		{
+			name:       "success_reject_with_comment",
+			actor:      unittestReviewer,
+			approve:    false,
+			comment:    "species name is misspelled",
+			buildStubs: success_reject_with_comment,
+			wantErr:    nil,
+		},
+		{
+			name:       "success_approve_count_statistics",
+			actor:      unittestReviewer,
+			approve:    true,
+			buildStubs: success_approve_count_statistics,
+			wantErr:    nil,
+		},
+		{
+			name:       "reject_without_comment",
+			actor:      unittestReviewer,
+			approve:    false,
+			comment:    " ",
+			buildStubs: func(stubs *commonStubsSpiderWorkflow) {},
+			wantErr:    ErrorSpiderWorkflowCommentRequired,
+		},
+		{
+			name:    "contributor_approve_own_submission",
+			actor:   unittestContributor,
+			approve: true,
+			buildStubs: func(stubs *commonStubsSpiderWorkflow) {
+				stubFindWorkflowSpiderInfo(stubs, model.SPIDER_INFO_STATUS_SUBMITTED, "contributor")
+			},
+			wantErr: ErrorSpiderWorkflowPermissionDenied,
+		},
+		{
+			name:    "approve_draft_before_submit",
+			actor:   unittestReviewer,
+			approve: true,
+			buildStubs: func(stubs *commonStubsSpiderWorkflow) {
+				stubFindWorkflowSpiderInfo(stubs, model.SPIDER_INFO_STATUS_DRAFT, "contributor")
+			},
+			wantErr: ErrorSpiderWorkflowInvalidTransition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stubs := newCommonStubsSpiderWorkflow(ctrl)
+			tt.buildStubs(&stubs)
+
+			u := NewSpiderWorkflowUsecase(stubs.mockSpiderRepo, stubs.mockStatisticsRepo, stubs.mockRevisionRepo, stubs.mockUnitOfWork)
+			if err := u.ReviewSpiderInfo(context.TODO(), unittestWorkflowSpiderUUID, tt.actor, tt.approve, tt.comment); err != tt.wantErr {
+				t.Errorf("SpiderWorkflowUsecase.ReviewSpiderInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func success_reject_with_comment(stubs *commonStubsSpiderWorkflow) {
+	stubFindWorkflowSpiderInfo(stubs, model.SPIDER_INFO_STATUS_SUBMITTED, "contributor")
+
+	stubs.mockSpiderRepo.EXPECT().UpdateSpiderStatus(
+		gomock.Any(),
+		gomock.Eq(unittestWorkflowSpiderUUID),
+		gomock.Eq(model.SPIDER_INFO_STATUS_SUBMITTED),
+		gomock.Eq(model.SPIDER_INFO_STATUS_REJECTED),
+		gomock.Any(),
+	).DoAndReturn(func(_ context.Context, _, _, _ string, review *model.SpiderReview) error {
+		if review == nil || review.Comment != "species name is misspelled" || review.ReviewedBy != "reviewer" {
+			return repository.ErrorMongoNotFound
+		}
+		return nil
+	})
+
+	stubRecordSpiderRevision(stubs.mockRevisionRepo, model.SPIDER_REVISION_ACTION_UPDATE, "reviewer")
+}
+
+func success_approve_count_statistics(stubs *commonStubsSpiderWorkflow) {
+	stubFindWorkflowSpiderInfo(stubs, model.SPIDER_INFO_STATUS_SUBMITTED, "contributor")
+
+	stubs.mockSpiderRepo.EXPECT().UpdateSpiderStatus(
+		gomock.Any(),
+		gomock.Eq(unittestWorkflowSpiderUUID),
+		gomock.Eq(model.SPIDER_INFO_STATUS_SUBMITTED),
+		gomock.Eq(model.SPIDER_INFO_STATUS_PUBLISHED),
+		gomock.Any(),
+	).Return(nil)
+
+	// submitted record is not counted until it is published
+	stubs.mockStatisticsRepo.EXPECT().IncreaseSpiderStatistics(
+		gomock.Any(),
+		gomock.Eq("Agelenidae"),
+		gomock.Eq("Draconarius"),
+		gomock.Eq("abbreviatus"),
+		gomock.Eq(int64(1)),
+	).Return(nil)
+
+	stubRecordSpiderRevision(stubs.mockRevisionRepo, model.SPIDER_REVISION_ACTION_UPDATE, "reviewer")
+}
+
+// **********************************************************************
@@ -1,8 +1,10 @@
 package usecase
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	mock_domain "spider-go/domain/mock"
 	"spider-go/model"
 
 	"github.com/golang/mock/gomock"
@@ -72,3 +74,53 @@ func (eq eqSpiderInfo) Matches(x interface{}) bool {
 }
 
 // xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+
+// =======================================================
+// GROUP EqSpiderRevision
+// =======================================================
+func EqSpiderRevision(action, createdBy string) gomock.Matcher {
+	return eqSpiderRevision{action: action, createdBy: createdBy}
+}
+
+type eqSpiderRevision struct {
+	action    string
+	createdBy string
+}
+
+func (eq eqSpiderRevision) String() string {
+	return fmt.Sprintf("matches revision with action %v created by %v", eq.action, eq.createdBy)
+}
+
+func (eq eqSpiderRevision) Matches(x interface{}) bool {
+	actualValue, ok := x.(model.SpiderRevision)
+
+	if !ok {
+		return false
+	}
+
+	return actualValue.Action == eq.action &&
+		actualValue.CreatedBy == eq.createdBy &&
+		actualValue.SpiderUUID == actualValue.Snapshot.SpiderUUID
+}
+
+// xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+
+// =======================================================
+// GROUP UnitOfWork
+// =======================================================
+
+// newPassThroughUnitOfWork return unit of work that run work without transaction, error of work is returned as is
+func newPassThroughUnitOfWork(ctrl *gomock.Controller) *mock_domain.MockUnitOfWork {
+	unitOfWork := mock_domain.NewMockUnitOfWork(ctrl)
+
+	unitOfWork.EXPECT().Do(
+		gomock.Any(),
+		gomock.Any(),
+	).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	}).AnyTimes()
+
+	return unitOfWork
+}
+
+// xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
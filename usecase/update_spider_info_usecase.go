@@ -1,50 +1,376 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
 	api_model "spider-go/api/model"
 	"spider-go/domain"
 	"spider-go/logger"
 	"spider-go/model"
+	"spider-go/repository"
+	"spider-go/utils/jsonpatch"
+	"strings"
 	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type UpdateSpiderInfoUsecase struct {
-	spiderRepo domain.SpiderRepository
-	log        *logger.Logger
+	spiderRepo     domain.SpiderRepository
+	statisticsRepo domain.StatisticsRepository
+	revisionRepo   domain.SpiderRevisionRepository
+	unitOfWork     domain.UnitOfWork
+	log            *logger.Logger
 }
 
 var (
 	ErrorUpdateSpiderInfoUsecaseSpiderUUIDNotFound = fmt.Errorf("spider uuid is not found in mongodb")
+	ErrorUpdateSpiderInfoUsecaseVersionRequired    = fmt.Errorf("version of edited spider info is required")
+	ErrorUpdateSpiderInfoUsecaseVersionConflict    = fmt.Errorf("spider info is changed by other request")
+	ErrorUpdateSpiderInfoUsecaseInvalidPatch       = fmt.Errorf("patch of spider info is invalid")
+	ErrorUpdateSpiderInfoUsecasePermissionDenied   = fmt.Errorf("spider info can not be edited by this account")
 )
 
-func NewUpdateSpiderInfoUsecase(spiderRepo domain.SpiderRepository) domain.UpdateSpiderInfoUsecase {
+// SPIDER_INFO_PATCH_FIELDS map json name of api spider info that can be patched to field of model.SpiderInfo
+var SPIDER_INFO_PATCH_FIELDS = map[string]string{
+	"family":          "Family",
+	"genus":           "Genus",
+	"species":         "Species",
+	"author":          "Author",
+	"publish_year":    "PublishYear",
+	"country":         "Country",
+	"other_countries": "CountryOther",
+	"altitude":        "Altitude",
+	"method":          "Method",
+	"habital":         "Habital",
+	"microhabital":    "Microhabital",
+	"designate":       "Designate",
+	"address":         "Address",
+	"paper":           "Paper",
+}
+
+func NewUpdateSpiderInfoUsecase(
+	spiderRepo domain.SpiderRepository,
+	statisticsRepo domain.StatisticsRepository,
+	revisionRepo domain.SpiderRevisionRepository,
+	unitOfWork domain.UnitOfWork,
+) domain.UpdateSpiderInfoUsecase {
 	return &UpdateSpiderInfoUsecase{
-		spiderRepo: spiderRepo,
-		log:        logger.L().Named("UpdateSpiderInfoUsecase"),
+		spiderRepo:     spiderRepo,
+		statisticsRepo: statisticsRepo,
+		revisionRepo:   revisionRepo,
+		unitOfWork:     unitOfWork,
+		log:            logger.L().Named("UpdateSpiderInfoUsecase"),
 	}
 }
 
-func (u *UpdateSpiderInfoUsecase) UpdateSpiderInfoUsecase(ctx context.Context, spiderInfoReq api_model.SpiderInfo) error {
+// UpdateSpiderInfoUsecase update spider info when version in request is still current version,
+// on conflict current server copy is returned with error so editor can merge
+func (u *UpdateSpiderInfoUsecase) UpdateSpiderInfoUsecase(ctx context.Context, spiderInfoReq api_model.SpiderInfo, loginUser *model.LoginUser) (*model.SpiderInfo, error) {
 	log := u.log.WithContext(ctx)
 
 	spiderUUID := spiderInfoReq.SpiderUUID
-	spiderInfo := u.prepareSpiderInfoFromRequest(spiderInfoReq)
 
 	log.Infof("[UpdateSpiderInfoUsecase] update spider with spider uuid: %v", spiderUUID)
 
-	isUpdate, err := u.spiderRepo.UpdateSpiderInfo(ctx, spiderUUID, spiderInfo)
+	if spiderInfoReq.Version == nil {
+		return nil, ErrorUpdateSpiderInfoUsecaseVersionRequired
+	}
+
+	expectedVersion := *spiderInfoReq.Version
+
+	currentSpiderInfo, err := u.findEditableSpiderInfo(ctx, spiderUUID, loginUser)
 	if err != nil {
-		return ErrorMongoTechnicalFail
+		return nil, err
 	}
 
-	if !isUpdate {
-		return ErrorUpdateSpiderInfoUsecaseSpiderUUIDNotFound
+	if currentSpiderInfo.Version != expectedVersion {
+		log.Warnf("[UpdateSpiderInfoUsecase] version conflict of `%v`, edited: %v, current: %v", spiderUUID, expectedVersion, currentSpiderInfo.Version)
+		return currentSpiderInfo, ErrorUpdateSpiderInfoUsecaseVersionConflict
+	}
+
+	spiderInfo := mergeEditableSpiderInfo(*currentSpiderInfo, u.prepareSpiderInfoFromRequest(spiderInfoReq), time.Now())
+
+	err = u.saveSpiderInfo(ctx, currentSpiderInfo, &spiderInfo, loginUser.Username, func(ctx context.Context) (bool, error) {
+		return u.spiderRepo.UpdateSpiderInfo(ctx, spiderUUID, expectedVersion, spiderInfo)
+	})
+	if err == ErrorUpdateSpiderInfoUsecaseVersionConflict {
+		// record is changed or deleted between find and update
+		latestSpiderInfo, err := u.findEditableSpiderInfo(ctx, spiderUUID, loginUser)
+		if err != nil {
+			return nil, err
+		}
+		return latestSpiderInfo, ErrorUpdateSpiderInfoUsecaseVersionConflict
+	}
+	if err != nil {
+		return nil, ErrorMongoTechnicalFail
+	}
+
+	spiderInfo.ID = currentSpiderInfo.ID
+	spiderInfo.Version = expectedVersion + 1
+
+	return &spiderInfo, nil
+
+}
+
+// saveSpiderInfo run write of spider info, move of statistics and record of revision in one unit of work,
+// ErrorUpdateSpiderInfoUsecaseVersionConflict is returned when write match no record
+func (u *UpdateSpiderInfoUsecase) saveSpiderInfo(ctx context.Context, current, updated *model.SpiderInfo, username string, write func(ctx context.Context) (bool, error)) error {
+	log := u.log.WithContext(ctx)
+
+	return u.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		isUpdate, err := write(ctx)
+		if err != nil {
+			log.Errorf("[saveSpiderInfo] write spider info `%v` error: %+v", updated.SpiderUUID, err)
+			return ErrorMongoTechnicalFail
+		}
+
+		if !isUpdate {
+			return ErrorUpdateSpiderInfoUsecaseVersionConflict
+		}
+
+		if err := moveSpiderStatistics(ctx, u.statisticsRepo, current, updated); err != nil {
+			log.Errorf("[saveSpiderInfo] move spider statistics of `%v` error: %+v", updated.SpiderUUID, err)
+			return ErrorMongoTechnicalFail
+		}
+
+		// snapshot is written spider info, version is increased by write
+		snapshot := *updated
+		snapshot.ID = current.ID
+		snapshot.Version = current.Version + 1
+
+		if err := recordSpiderRevision(ctx, u.revisionRepo, log, model.SPIDER_REVISION_ACTION_UPDATE, snapshot, username); err != nil {
+			return ErrorMongoTechnicalFail
+		}
+
+		return nil
+	})
+}
+
+func (u *UpdateSpiderInfoUsecase) findEditableSpiderInfo(ctx context.Context, spiderUUID string, loginUser *model.LoginUser) (*model.SpiderInfo, error) {
+	log := u.log.WithContext(ctx)
+
+	spiderInfo, err := u.spiderRepo.FindSpiderByUUID(ctx, spiderUUID)
+	if err != nil {
+		log.Errorf("[findEditableSpiderInfo] find spider info error: %+v", err)
+		if err == repository.ErrorMongoNotFound {
+			return nil, ErrorUpdateSpiderInfoUsecaseSpiderUUIDNotFound
+		}
+		return nil, ErrorMongoTechnicalFail
+	}
+
+	// spider info in trash must be restored before edit
+	if spiderInfo.IsDeleted() {
+		return nil, ErrorUpdateSpiderInfoUsecaseSpiderUUIDNotFound
+	}
+
+	if !model.CanEditSpiderInfo(*spiderInfo, loginUser) {
+		log.Warnf("[findEditableSpiderInfo] `%v` can not edit spider info `%v` of `%v` in status `%v`", loginUser.Username, spiderUUID, spiderInfo.CreatedBy, spiderInfo.Status)
+		return nil, ErrorUpdateSpiderInfoUsecasePermissionDenied
+	}
+
+	return spiderInfo, nil
+}
+
+// PatchSpiderInfoUsecase apply merge patch or json patch to editable fields of spider info,
+// only changed fields are written and version rule is same as UpdateSpiderInfoUsecase
+func (u *UpdateSpiderInfoUsecase) PatchSpiderInfoUsecase(ctx context.Context, req api_model.PatchSpiderInfoRequestData, loginUser *model.LoginUser) (*model.SpiderInfo, error) {
+	log := u.log.WithContext(ctx)
+
+	spiderUUID := req.SpiderUUID
+
+	log.Infof("[PatchSpiderInfoUsecase] patch spider with spider uuid: %v", spiderUUID)
+
+	if req.Version == nil {
+		return nil, ErrorUpdateSpiderInfoUsecaseVersionRequired
+	}
+
+	// exactly one kind of patch
+	if (len(req.MergePatch) == 0) == (len(req.JSONPatch) == 0) {
+		return nil, ErrorUpdateSpiderInfoUsecaseInvalidPatch
+	}
+
+	expectedVersion := *req.Version
+
+	currentSpiderInfo, err := u.findEditableSpiderInfo(ctx, spiderUUID, loginUser)
+	if err != nil {
+		return nil, err
+	}
+
+	if currentSpiderInfo.Version != expectedVersion {
+		log.Warnf("[PatchSpiderInfoUsecase] version conflict of `%v`, edited: %v, current: %v", spiderUUID, expectedVersion, currentSpiderInfo.Version)
+		return currentSpiderInfo, ErrorUpdateSpiderInfoUsecaseVersionConflict
+	}
+
+	patchedReq, err := applySpiderInfoPatch(*currentSpiderInfo, req)
+	if err != nil {
+		log.Errorf("[PatchSpiderInfoUsecase] apply patch to `%v` error: %+v", spiderUUID, err)
+		return nil, ErrorUpdateSpiderInfoUsecaseInvalidPatch
+	}
+
+	spiderInfo := mergeEditableSpiderInfo(*currentSpiderInfo, u.prepareSpiderInfoFromRequest(patchedReq), time.Now())
+
+	fields := changedSpiderInfoFields(*currentSpiderInfo, spiderInfo)
+	if len(fields) == 0 {
+		return currentSpiderInfo, nil
+	}
+
+	err = u.saveSpiderInfo(ctx, currentSpiderInfo, &spiderInfo, loginUser.Username, func(ctx context.Context) (bool, error) {
+		return u.spiderRepo.PatchSpiderInfo(ctx, spiderUUID, expectedVersion, spiderInfo, fields)
+	})
+	if err == ErrorUpdateSpiderInfoUsecaseVersionConflict {
+		latestSpiderInfo, err := u.findEditableSpiderInfo(ctx, spiderUUID, loginUser)
+		if err != nil {
+			return nil, err
+		}
+		return latestSpiderInfo, ErrorUpdateSpiderInfoUsecaseVersionConflict
+	}
+	if err != nil {
+		return nil, ErrorMongoTechnicalFail
+	}
+
+	spiderInfo.ID = currentSpiderInfo.ID
+	spiderInfo.Version = expectedVersion + 1
+
+	return &spiderInfo, nil
+}
+
+// applySpiderInfoPatch apply patch to json document of editable fields and decode result as api spider info,
+// patch that touch field outside SPIDER_INFO_PATCH_FIELDS or break type of field is rejected
+func applySpiderInfoPatch(current model.SpiderInfo, req api_model.PatchSpiderInfoRequestData) (api_model.SpiderInfo, error) {
+	var patchedReq api_model.SpiderInfo
+
+	value := reflect.ValueOf(current)
+	document := map[string]interface{}{}
+
+	for jsonName, fieldName := range SPIDER_INFO_PATCH_FIELDS {
+		field := value.FieldByName(fieldName)
+
+		// empty array instead of null so `/address/-` can add first address
+		if field.Kind() == reflect.Slice && field.IsNil() {
+			field = reflect.MakeSlice(field.Type(), 0, 0)
+		}
+
+		document[jsonName] = field.Interface()
+	}
+
+	raw, err := json.Marshal(document)
+	if err != nil {
+		return patchedReq, err
+	}
+
+	if len(req.MergePatch) != 0 {
+		raw, err = jsonpatch.MergePatch(raw, req.MergePatch)
+	} else {
+		operations := make([]jsonpatch.Operation, 0, len(req.JSONPatch))
+		for _, op := range req.JSONPatch {
+			operations = append(operations, jsonpatch.Operation{
+				Op:    op.Op,
+				Path:  op.Path,
+				From:  op.From,
+				Value: op.Value,
+			})
+		}
+		raw, err = jsonpatch.Apply(raw, operations)
+	}
+	if err != nil {
+		return patchedReq, err
+	}
+
+	var patched map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &patched); err != nil {
+		return patchedReq, err
+	}
+
+	for jsonName := range patched {
+		if _, ok := SPIDER_INFO_PATCH_FIELDS[jsonName]; !ok {
+			return patchedReq, fmt.Errorf("field `%v` can not be patched", jsonName)
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&patchedReq); err != nil {
+		return patchedReq, err
+	}
+
+	return patchedReq, validatePatchedSpiderInfo(patchedReq)
+}
+
+func validatePatchedSpiderInfo(req api_model.SpiderInfo) error {
+	if req.Family == "" || req.Genus == "" || req.Species == "" {
+		return fmt.Errorf("family, genus and species are required")
+	}
+
+	for _, address := range req.Address {
+		for _, position := range address.Position {
+			if position.Latitude < -90 || position.Latitude > 90 || position.Longitude < -180 || position.Longitude > 180 {
+				return fmt.Errorf("position `%v` is out of range", position.Name)
+			}
+		}
 	}
 
 	return nil
+}
+
+// changedSpiderInfoFields return bson name of patchable fields that value is changed, empty and nil slice are equal
+func changedSpiderInfoFields(current, patched model.SpiderInfo) []string {
+	var fields []string
+
+	currentValue := reflect.ValueOf(current)
+	patchedValue := reflect.ValueOf(patched)
+	spiderInfoType := currentValue.Type()
+
+	for _, fieldName := range SPIDER_INFO_PATCH_FIELDS {
+		oldValue := currentValue.FieldByName(fieldName)
+		newValue := patchedValue.FieldByName(fieldName)
+
+		if oldValue.Kind() == reflect.Slice && oldValue.Len() == 0 && newValue.Len() == 0 {
+			continue
+		}
+
+		if reflect.DeepEqual(oldValue.Interface(), newValue.Interface()) {
+			continue
+		}
+
+		field, _ := spiderInfoType.FieldByName(fieldName)
+		fields = append(fields, strings.Split(field.Tag.Get("bson"), ",")[0])
+	}
+
+	// map iteration is random, sorted fields keep log and mock expectation stable
+	sort.Strings(fields)
+
+	return fields
+}
+
+// mergeEditableSpiderInfo copy editable fields of `edited` to `current`, status, owner and image of record are kept
+func mergeEditableSpiderInfo(current, edited model.SpiderInfo, updatedAt time.Time) model.SpiderInfo {
+	merged := current
+
+	// _id is immutable, it is not part of $set
+	merged.ID = primitive.NilObjectID
+	merged.Family = edited.Family
+	merged.Genus = edited.Genus
+	merged.Species = edited.Species
+	merged.Author = edited.Author
+	merged.PublishYear = edited.PublishYear
+	merged.Country = edited.Country
+	merged.CountryOther = edited.CountryOther
+	merged.Altitude = edited.Altitude
+	merged.Method = edited.Method
+	merged.Habital = edited.Habital
+	merged.Microhabital = edited.Microhabital
+	merged.Designate = edited.Designate
+	merged.Address = edited.Address
+	merged.Paper = edited.Paper
+	merged.UpdatedAt = updatedAt
 
+	return merged
 }
 
 func (u *UpdateSpiderInfoUsecase) prepareSpiderInfoFromRequest(req api_model.SpiderInfo) model.SpiderInfo {
@@ -2,18 +2,27 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
 	api_model "spider-go/api/model"
 	mock_domain "spider-go/domain/mock"
 	"spider-go/model"
+	"spider-go/repository"
+	"spider-go/utils/jsonpatch"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 )
 
 type commonStubsUpdateSpider struct {
-	mockSpiderRepo *mock_domain.MockSpiderRepository
+	mockSpiderRepo     *mock_domain.MockSpiderRepository
+	mockStatisticsRepo *mock_domain.MockStatisticsRepository
+	mockRevisionRepo   *mock_domain.MockSpiderRevisionRepository
 }
 
+const unittestEditedSpiderVersion int64 = 3
+
+var unittestEditor = &model.LoginUser{Username: "unittest", Role: model.ACCOUNT_ROLE_ADMIN}
+
 var mockDataSpiderInfo = api_model.SpiderInfo{
 	SpiderUUID:   "SPIDER_8a5bbf23-8ccd-4068-ae19-145095e0847b",
 	Family:       "Agelenidae",
@@ -41,7 +50,8 @@ var mockDataSpiderInfo = api_model.SpiderInfo{
 			},
 		},
 	},
-	Paper: []string{"Test2023"},
+	Paper:   []string{"Test2023"},
+	Version: func(v int64) *int64 { return &v }(unittestEditedSpiderVersion),
 }
 
 func TestUpdateSpiderInfoUsecase(t *testing.T) {
@@ -50,11 +60,18 @@ func TestUpdateSpiderInfoUsecase(t *testing.T) {
 		spiderInfoReq api_model.SpiderInfo
 	}
 
+	staleVersionReq := mockDataSpiderInfo
+	staleVersionReq.Version = func(v int64) *int64 { return &v }(unittestEditedSpiderVersion - 1)
+
+	noVersionReq := mockDataSpiderInfo
+	noVersionReq.Version = nil
+
 	tests := []struct {
-		name    string
-		args    args
-		stubs   func(*commonStubsUpdateSpider)
-		wantErr bool
+		name        string
+		args        args
+		stubs       func(*commonStubsUpdateSpider)
+		wantErr     bool
+		wantVersion int64
 	}{
 		// TODO: Add test cases.
 		{
@@ -62,15 +79,44 @@ func TestUpdateSpiderInfoUsecase(t *testing.T) {
 			args: args{
 				spiderInfoReq: mockDataSpiderInfo,
 			},
-			stubs:   update_spider_info_success_case,
-			wantErr: false,
+			stubs:       update_spider_info_success_case,
+			wantErr:     false,
+			wantVersion: unittestEditedSpiderVersion + 1,
 		},
 		{
-			name: "update_spider_info_not_found_case",
+			name: "update_spider_info_changed_after_find_case",
 			args: args{
 				spiderInfoReq: mockDataSpiderInfo,
 			},
-			stubs:   update_spider_info_not_found_case,
+			stubs:       update_spider_info_changed_after_find_case,
+			wantErr:     true,
+			wantVersion: unittestEditedSpiderVersion + 1,
+		},
+		{
+			name: "update_spider_info_stale_version_case",
+			args: args{
+				spiderInfoReq: staleVersionReq,
+			},
+			stubs: func(mockStubs *commonStubsUpdateSpider) {
+				stubFindCurrentSpiderInfo(mockStubs, unittestEditedSpiderVersion)
+			},
+			wantErr:     true,
+			wantVersion: unittestEditedSpiderVersion,
+		},
+		{
+			name: "update_spider_info_without_version_case",
+			args: args{
+				spiderInfoReq: noVersionReq,
+			},
+			stubs:   func(mockStubs *commonStubsUpdateSpider) {},
+			wantErr: true,
+		},
+		{
+			name: "update_spider_info_find_not_found_case",
+			args: args{
+				spiderInfoReq: mockDataSpiderInfo,
+			},
+			stubs:   update_spider_info_find_not_found_case,
 			wantErr: true,
 		},
 	}
@@ -81,19 +127,26 @@ func TestUpdateSpiderInfoUsecase(t *testing.T) {
 			defer ctrl.Finish()
 
 			commonStubs := commonStubsUpdateSpider{
-				mockSpiderRepo: mock_domain.NewMockSpiderRepository(ctrl),
+				mockSpiderRepo:     mock_domain.NewMockSpiderRepository(ctrl),
+				mockStatisticsRepo: mock_domain.NewMockStatisticsRepository(ctrl),
+				mockRevisionRepo:   mock_domain.NewMockSpiderRevisionRepository(ctrl),
 			}
 
 			tt.stubs(&commonStubs)
 
-			usecase := NewUpdateSpiderInfoUsecase(commonStubs.mockSpiderRepo)
+			usecase := NewUpdateSpiderInfoUsecase(commonStubs.mockSpiderRepo, commonStubs.mockStatisticsRepo, commonStubs.mockRevisionRepo, newPassThroughUnitOfWork(ctrl))
 
-			err := usecase.UpdateSpiderInfoUsecase(context.TODO(), tt.args.spiderInfoReq)
+			spiderInfo, err := usecase.UpdateSpiderInfoUsecase(context.TODO(), tt.args.spiderInfoReq, unittestEditor)
 			gotErr := err != nil
 			if gotErr != tt.wantErr {
 				t.Errorf("[TestUpdateSpiderInfoUsecase] fail wantErr is %v, but gotErr is %v, error: %+v", tt.wantErr, gotErr, err)
 			}
 
+			// updated record on success and server copy on conflict
+			if tt.wantVersion != 0 && (spiderInfo == nil || spiderInfo.Version != tt.wantVersion) {
+				t.Errorf("[TestUpdateSpiderInfoUsecase] fail want version %v, but got spider info %+v", tt.wantVersion, spiderInfo)
+			}
+
 		})
 	}
 }
@@ -127,17 +180,44 @@ func update_spider_info_success_case(mockStubs *commonStubsUpdateSpider) {
 				},
 			},
 		},
-		Paper: []string{"Test2023"},
+		Paper:     []string{"Test2023"},
+		Status:    model.SPIDER_INFO_STATUS_ACTIVE,
+		CreatedBy: "creator",
+		ImageFile: []string{"image_1.png"},
+		Version:   unittestEditedSpiderVersion,
 	}
 
+	stubFindCurrentSpiderInfo(mockStubs, unittestEditedSpiderVersion)
+
 	mockStubs.mockSpiderRepo.EXPECT().UpdateSpiderInfo(
 		gomock.Any(),
 		gomock.Eq("SPIDER_8a5bbf23-8ccd-4068-ae19-145095e0847b"),
+		gomock.Eq(unittestEditedSpiderVersion),
 		EqSpiderInfo(spiderInfo),
 	).Return(true, nil)
+
+	// record is moved from Agelena limbata to Draconarius abbreviatus
+	gomock.InOrder(
+		mockStubs.mockStatisticsRepo.EXPECT().IncreaseSpiderStatistics(
+			gomock.Any(),
+			gomock.Eq("Agelenidae"),
+			gomock.Eq("Agelena"),
+			gomock.Eq("limbata"),
+			gomock.Eq(int64(-1)),
+		).Return(nil),
+		mockStubs.mockStatisticsRepo.EXPECT().IncreaseSpiderStatistics(
+			gomock.Any(),
+			gomock.Eq("Agelenidae"),
+			gomock.Eq("Draconarius"),
+			gomock.Eq("abbreviatus"),
+			gomock.Eq(int64(1)),
+		).Return(nil),
+	)
+
+	stubRecordSpiderRevision(mockStubs.mockRevisionRepo, model.SPIDER_REVISION_ACTION_UPDATE, "unittest")
 }
 
-func update_spider_info_not_found_case(mockStubs *commonStubsUpdateSpider) {
+func update_spider_info_changed_after_find_case(mockStubs *commonStubsUpdateSpider) {
 
 	spiderInfo := model.SpiderInfo{
 		SpiderUUID:   "SPIDER_8a5bbf23-8ccd-4068-ae19-145095e0847b",
@@ -166,12 +246,308 @@ func update_spider_info_not_found_case(mockStubs *commonStubsUpdateSpider) {
 				},
 			},
 		},
-		Paper: []string{"Test2023"},
+		Paper:     []string{"Test2023"},
+		Status:    model.SPIDER_INFO_STATUS_ACTIVE,
+		CreatedBy: "creator",
+		ImageFile: []string{"image_1.png"},
+		Version:   unittestEditedSpiderVersion,
 	}
 
-	mockStubs.mockSpiderRepo.EXPECT().UpdateSpiderInfo(
+	gomock.InOrder(
+		stubFindCurrentSpiderInfo(mockStubs, unittestEditedSpiderVersion),
+		mockStubs.mockSpiderRepo.EXPECT().UpdateSpiderInfo(
+			gomock.Any(),
+			gomock.Eq("SPIDER_8a5bbf23-8ccd-4068-ae19-145095e0847b"),
+			gomock.Eq(unittestEditedSpiderVersion),
+			EqSpiderInfo(spiderInfo),
+		).Return(false, nil),
+		// other curator save first, latest copy is returned for merge
+		stubFindCurrentSpiderInfo(mockStubs, unittestEditedSpiderVersion+1),
+	)
+}
+
+// status, owner and image of current record are kept, only editable fields are replaced
+func stubFindCurrentSpiderInfo(mockStubs *commonStubsUpdateSpider, version int64) *gomock.Call {
+	return mockStubs.mockSpiderRepo.EXPECT().FindSpiderByUUID(
 		gomock.Any(),
 		gomock.Eq("SPIDER_8a5bbf23-8ccd-4068-ae19-145095e0847b"),
-		EqSpiderInfo(spiderInfo),
-	).Return(false, nil)
+	).Return(&model.SpiderInfo{
+		SpiderUUID: "SPIDER_8a5bbf23-8ccd-4068-ae19-145095e0847b",
+		Family:     "Agelenidae",
+		Genus:      "Agelena",
+		Species:    "limbata",
+		Status:     model.SPIDER_INFO_STATUS_ACTIVE,
+		CreatedBy:  "creator",
+		ImageFile:  []string{"image_1.png"},
+		Version:    version,
+	}, nil)
+}
+
+func update_spider_info_find_not_found_case(mockStubs *commonStubsUpdateSpider) {
+	mockStubs.mockSpiderRepo.EXPECT().FindSpiderByUUID(
+		gomock.Any(),
+		gomock.Eq("SPIDER_8a5bbf23-8ccd-4068-ae19-145095e0847b"),
+	).Return(nil, repository.ErrorMongoNotFound)
+}
+
+// ======================================================================
+// TestUpdateSpiderInfoUsecase_PatchSpiderInfoUsecase
+// ======================================================================
+func TestUpdateSpiderInfoUsecase_PatchSpiderInfoUsecase(t *testing.T) {
+
+	patchReq := func(mergePatch string, jsonPatch ...api_model.PatchOperation) api_model.PatchSpiderInfoRequestData {
+		version := unittestEditedSpiderVersion
+		req := api_model.PatchSpiderInfoRequestData{
+			SpiderUUID: "SPIDER_8a5bbf23-8ccd-4068-ae19-145095e0847b",
+			Version:    &version,
+			JSONPatch:  jsonPatch,
+		}
+		if mergePatch != "" {
+			req.MergePatch = json.RawMessage(mergePatch)
+		}
+		return req
+	}
+
+	tests := []struct {
+		name    string
+		req     api_model.PatchSpiderInfoRequestData
+		stubs   func(*commonStubsUpdateSpider)
+		wantErr error
+	}{
+		{
+			name:    "merge_patch_only_changed_field_case",
+			req:     patchReq(`{"author": "Koch", "genus": "Agelena"}`),
+			stubs:   merge_patch_only_changed_field_case,
+			wantErr: nil,
+		},
+		{
+			name: "json_patch_add_first_address_case",
+			req: patchReq("", api_model.PatchOperation{
+				Op:    jsonpatch.OP_ADD,
+				Path:  "/address/-",
+				Value: json.RawMessage(`{"province": "Chiang Mai", "district": "Chomthong", "locality": "Doi Inthanon", "position": []}`),
+			}),
+			stubs:   json_patch_add_first_address_case,
+			wantErr: nil,
+		},
+		{
+			name: "json_patch_failed_test_operation_case",
+			req: patchReq("", api_model.PatchOperation{
+				Op:    jsonpatch.OP_TEST,
+				Path:  "/species",
+				Value: json.RawMessage(`"abbreviatus"`),
+			}, api_model.PatchOperation{
+				Op:    jsonpatch.OP_REPLACE,
+				Path:  "/species",
+				Value: json.RawMessage(`"lineata"`),
+			}),
+			stubs: func(mockStubs *commonStubsUpdateSpider) {
+				stubFindCurrentSpiderInfo(mockStubs, unittestEditedSpiderVersion)
+			},
+			wantErr: ErrorUpdateSpiderInfoUsecaseInvalidPatch,
+		},
+		{
+			name: "patch_not_editable_field_case",
+			req:  patchReq(`{"status": "active"}`),
+			stubs: func(mockStubs *commonStubsUpdateSpider) {
+				stubFindCurrentSpiderInfo(mockStubs, unittestEditedSpiderVersion)
+			},
+			wantErr: ErrorUpdateSpiderInfoUsecaseInvalidPatch,
+		},
+		{
+			name: "merge_patch_remove_required_field_case",
+			req:  patchReq(`{"family": null}`),
+			stubs: func(mockStubs *commonStubsUpdateSpider) {
+				stubFindCurrentSpiderInfo(mockStubs, unittestEditedSpiderVersion)
+			},
+			wantErr: ErrorUpdateSpiderInfoUsecaseInvalidPatch,
+		},
+		{
+			name: "both_kind_of_patch_case",
+			req: patchReq(`{"author": "Koch"}`, api_model.PatchOperation{
+				Op:   jsonpatch.OP_REMOVE,
+				Path: "/author",
+			}),
+			stubs:   func(mockStubs *commonStubsUpdateSpider) {},
+			wantErr: ErrorUpdateSpiderInfoUsecaseInvalidPatch,
+		},
+		{
+			name: "patch_stale_version_case",
+			req:  patchReq(`{"author": "Koch"}`),
+			stubs: func(mockStubs *commonStubsUpdateSpider) {
+				stubFindCurrentSpiderInfo(mockStubs, unittestEditedSpiderVersion+1)
+			},
+			wantErr: ErrorUpdateSpiderInfoUsecaseVersionConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			commonStubs := commonStubsUpdateSpider{
+				mockSpiderRepo:     mock_domain.NewMockSpiderRepository(ctrl),
+				mockStatisticsRepo: mock_domain.NewMockStatisticsRepository(ctrl),
+				mockRevisionRepo:   mock_domain.NewMockSpiderRevisionRepository(ctrl),
+			}
+
+			tt.stubs(&commonStubs)
+
+			usecase := NewUpdateSpiderInfoUsecase(commonStubs.mockSpiderRepo, commonStubs.mockStatisticsRepo, commonStubs.mockRevisionRepo, newPassThroughUnitOfWork(ctrl))
+
+			if _, err := usecase.PatchSpiderInfoUsecase(context.TODO(), tt.req, unittestEditor); err != tt.wantErr {
+				t.Errorf("[TestUpdateSpiderInfoUsecase_PatchSpiderInfoUsecase] fail wantErr is %v, but got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func merge_patch_only_changed_field_case(mockStubs *commonStubsUpdateSpider) {
+	stubFindCurrentSpiderInfo(mockStubs, unittestEditedSpiderVersion)
+
+	// genus is same as current record so only author is written
+	mockStubs.mockSpiderRepo.EXPECT().PatchSpiderInfo(
+		gomock.Any(),
+		gomock.Eq("SPIDER_8a5bbf23-8ccd-4068-ae19-145095e0847b"),
+		gomock.Eq(unittestEditedSpiderVersion),
+		EqSpiderInfo(model.SpiderInfo{
+			Family:    "Agelenidae",
+			Genus:     "Agelena",
+			Species:   "limbata",
+			Author:    "Koch",
+			Status:    model.SPIDER_INFO_STATUS_ACTIVE,
+			CreatedBy: "creator",
+			Paper:     []string{},
+			ImageFile: []string{"image_1.png"},
+			Version:   unittestEditedSpiderVersion,
+		}),
+		gomock.Eq([]string{"author"}),
+	).Return(true, nil)
+
+	stubRecordSpiderRevision(mockStubs.mockRevisionRepo, model.SPIDER_REVISION_ACTION_UPDATE, "unittest")
+}
+
+func json_patch_add_first_address_case(mockStubs *commonStubsUpdateSpider) {
+	stubFindCurrentSpiderInfo(mockStubs, unittestEditedSpiderVersion)
+
+	mockStubs.mockSpiderRepo.EXPECT().PatchSpiderInfo(
+		gomock.Any(),
+		gomock.Eq("SPIDER_8a5bbf23-8ccd-4068-ae19-145095e0847b"),
+		gomock.Eq(unittestEditedSpiderVersion),
+		EqSpiderInfo(model.SpiderInfo{
+			Family:  "Agelenidae",
+			Genus:   "Agelena",
+			Species: "limbata",
+			Address: []model.Address{
+				{
+					Province: "Chiang Mai",
+					District: "Chomthong",
+					Locality: "Doi Inthanon",
+				},
+			},
+			Status:    model.SPIDER_INFO_STATUS_ACTIVE,
+			CreatedBy: "creator",
+			Paper:     []string{},
+			ImageFile: []string{"image_1.png"},
+			Version:   unittestEditedSpiderVersion,
+		}),
+		gomock.Eq([]string{"address"}),
+	).Return(true, nil)
+
+	stubRecordSpiderRevision(mockStubs.mockRevisionRepo, model.SPIDER_REVISION_ACTION_UPDATE, "unittest")
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestUpdateSpiderInfoUsecase_EditPermission
+// ======================================================================
+
+// request has stale version so version conflict mean that edit is allowed
+func TestUpdateSpiderInfoUsecase_EditPermission(t *testing.T) {
+
+	contributor := &model.LoginUser{Username: "creator", Role: model.ACCOUNT_ROLE_GENERAL}
+	otherContributor := &model.LoginUser{Username: "other", Role: model.ACCOUNT_ROLE_GENERAL}
+
+	tests := []struct {
+		name      string
+		loginUser *model.LoginUser
+		status    string
+		wantErr   error
+	}{
+		{
+			name:      "contributor_edit_own_draft",
+			loginUser: contributor,
+			status:    model.SPIDER_INFO_STATUS_DRAFT,
+			wantErr:   ErrorUpdateSpiderInfoUsecaseVersionConflict,
+		},
+		{
+			name:      "contributor_edit_own_rejected",
+			loginUser: contributor,
+			status:    model.SPIDER_INFO_STATUS_REJECTED,
+			wantErr:   ErrorUpdateSpiderInfoUsecaseVersionConflict,
+		},
+		{
+			name:      "contributor_edit_own_legacy_inactive",
+			loginUser: contributor,
+			status:    model.SPIDER_INFO_STATUS_INACTIVE,
+			wantErr:   ErrorUpdateSpiderInfoUsecaseVersionConflict,
+		},
+		{
+			name:      "contributor_edit_own_submitted",
+			loginUser: contributor,
+			status:    model.SPIDER_INFO_STATUS_SUBMITTED,
+			wantErr:   ErrorUpdateSpiderInfoUsecasePermissionDenied,
+		},
+		{
+			name:      "contributor_edit_own_published",
+			loginUser: contributor,
+			status:    model.SPIDER_INFO_STATUS_PUBLISHED,
+			wantErr:   ErrorUpdateSpiderInfoUsecasePermissionDenied,
+		},
+		{
+			name:      "contributor_edit_draft_of_other",
+			loginUser: otherContributor,
+			status:    model.SPIDER_INFO_STATUS_DRAFT,
+			wantErr:   ErrorUpdateSpiderInfoUsecasePermissionDenied,
+		},
+		{
+			name:      "editor_edit_published_of_other",
+			loginUser: unittestEditor,
+			status:    model.SPIDER_INFO_STATUS_PUBLISHED,
+			wantErr:   ErrorUpdateSpiderInfoUsecaseVersionConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			commonStubs := commonStubsUpdateSpider{
+				mockSpiderRepo:     mock_domain.NewMockSpiderRepository(ctrl),
+				mockStatisticsRepo: mock_domain.NewMockStatisticsRepository(ctrl),
+				mockRevisionRepo:   mock_domain.NewMockSpiderRevisionRepository(ctrl),
+			}
+
+			commonStubs.mockSpiderRepo.EXPECT().FindSpiderByUUID(
+				gomock.Any(),
+				gomock.Eq("SPIDER_8a5bbf23-8ccd-4068-ae19-145095e0847b"),
+			).Return(&model.SpiderInfo{
+				SpiderUUID: "SPIDER_8a5bbf23-8ccd-4068-ae19-145095e0847b",
+				Status:     tt.status,
+				CreatedBy:  "creator",
+				Version:    unittestEditedSpiderVersion + 1,
+			}, nil)
+
+			usecase := NewUpdateSpiderInfoUsecase(commonStubs.mockSpiderRepo, commonStubs.mockStatisticsRepo, commonStubs.mockRevisionRepo, newPassThroughUnitOfWork(ctrl))
+
+			if _, err := usecase.UpdateSpiderInfoUsecase(context.TODO(), mockDataSpiderInfo, tt.loginUser); err != tt.wantErr {
+				t.Errorf("[TestUpdateSpiderInfoUsecase_EditPermission] fail wantErr is %v, but got %v", tt.wantErr, err)
+			}
+		})
+	}
 }
+
+// **********************************************************************
@@ -7,31 +7,53 @@ import (
 	"fmt"
 	"image/jpeg"
 	"image/png"
-	"os"
-	"path"
+	"io"
+	"net/http"
 	"sort"
 	"spider-go/config"
 	"spider-go/domain"
 	"spider-go/logger"
+	"spider-go/model"
+	"spider-go/utils/imaging"
 	"spider-go/utils/uuid"
 	"strings"
 )
 
 type UploadImageUsecase struct {
-	spiderRepo domain.SpiderRepository
-	log        *logger.Logger
+	spiderRepo          domain.SpiderRepository
+	imageStorage        domain.ImageStorage
+	derivativeGenerator domain.ImageDerivativeGenerator
+	conf                *config.Root
+	log                 *logger.Logger
 }
 
 var (
 	ErrorUploadImageUsecaseVlidateSpiderUUID = fmt.Errorf("validate spider uuid failed not found")
 	ErrorUploadImageUsecaseSaveImageFileFail = fmt.Errorf("save spider image file failed")
 	ErrorUploadImageUsecaseFileTypeNotMatch  = fmt.Errorf("file type of image not match")
+	ErrorUploadImageUsecaseFileTooLarge      = fmt.Errorf("image file is larger than maximum file size")
+	ErrorUploadImageUsecaseTooManyFiles      = fmt.Errorf("image file is over maximum files of request")
+	ErrorUploadImageUsecaseNoImageFile       = fmt.Errorf("no image file in request")
+	ErrorUploadImageUsecaseReadRequestFail   = fmt.Errorf("read upload request failed")
 )
 
-func NewUploadImageUsecase(spiderRepo domain.SpiderRepository) domain.UploadImageUsecase {
+const (
+	DEFAULT_UPLOAD_IMAGE_MAX_FILE_SIZE = 10 << 20
+	DEFAULT_UPLOAD_IMAGE_MAX_FILES     = 20
+)
+
+func NewUploadImageUsecase(
+	spiderRepo domain.SpiderRepository,
+	imageStorage domain.ImageStorage,
+	derivativeGenerator domain.ImageDerivativeGenerator,
+	conf *config.Root,
+) domain.UploadImageUsecase {
 	return &UploadImageUsecase{
-		spiderRepo: spiderRepo,
-		log:        logger.L().Named("UploadImageUsecase"),
+		spiderRepo:          spiderRepo,
+		imageStorage:        imageStorage,
+		derivativeGenerator: derivativeGenerator,
+		conf:                conf,
+		log:                 logger.L().Named("UploadImageUsecase"),
 	}
 }
 
@@ -42,14 +64,20 @@ func (u *UploadImageUsecase) UploadImageSpiderUsecase(ctx context.Context, spide
 	// validate spider uuid
 	// =======================================================
 	spiderInfo, err := u.spiderRepo.FindSpiderByUUID(ctx, spiderUUID)
-	if err != nil && err.Error() != MONGO_NOT_FOUND {
+	if err != nil {
+		log.Errorf("[UploadImageSpiderUsecase] find spider info `%v` error: %+v", spiderUUID, err)
+		return ErrorUploadImageUsecaseVlidateSpiderUUID
+	}
+
+	// spider info in trash can not be changed
+	if spiderInfo.IsDeleted() {
 		return ErrorUploadImageUsecaseVlidateSpiderUUID
 	}
 
 	// =======================================================
 	// save file
 	// =======================================================
-	listImageName, err := u.handleFileImage(ctx, spiderUUID, listImageEncode64)
+	listImageName, listImageExif, err := u.handleFileImage(ctx, spiderUUID, listImageEncode64)
 	if err != nil {
 		return err
 	}
@@ -68,13 +96,17 @@ func (u *UploadImageUsecase) UploadImageSpiderUsecase(ctx context.Context, spide
 		return ErrorMongoTechnicalFail
 	}
 
+	u.saveImageExif(ctx, spiderUUID, listImageExif)
+
 	return nil
 }
 
-func (u *UploadImageUsecase) handleFileImage(ctx context.Context, spiderUUID string, listImageEncode64 []string) ([]string, error) {
+// handleFileImage save base64 image, exif of jpeg is read from uploaded data because saved image is re-encoded without it
+func (u *UploadImageUsecase) handleFileImage(ctx context.Context, spiderUUID string, listImageEncode64 []string) ([]string, []model.ImageExif, error) {
 	log := u.log.WithContext(ctx)
 
 	var listImageName []string
+	var listImageExif []model.ImageExif
 
 	for _, image := range listImageEncode64 {
 
@@ -85,8 +117,7 @@ func (u *UploadImageUsecase) handleFileImage(ctx context.Context, spiderUUID str
 
 		spiderImageUUID := uuid.GernerateUUID32()
 
-		fileName := fmt.Sprintf(config.C().File.SpiderImage, spiderUUID, spiderImageUUID)
-		filePath := path.Join(config.C().File.FileImagePath, fileName)
+		fileName := fmt.Sprintf(u.conf.File.SpiderImage, spiderUUID, spiderImageUUID)
 
 		log.Infof("[handleFileImage] filename: %v", fileName)
 
@@ -94,7 +125,8 @@ func (u *UploadImageUsecase) handleFileImage(ctx context.Context, spiderUUID str
 		if err != nil {
 			log.Errorf("[handleFileImage] base64 decode original image error: %v", err)
 			fmt.Printf("point: 1")
-			return nil, ErrorTechnicalError
+			u.deleteFile(ctx, listImageName)
+			return nil, nil, ErrorTechnicalError
 		}
 
 		renderImageDecode := bytes.NewReader(imageDecode)
@@ -107,93 +139,338 @@ func (u *UploadImageUsecase) handleFileImage(ctx context.Context, spiderUUID str
 		switch imageType {
 		case PNG_IMAGE_TYPE:
 			// save png
-			err := u.savePNGFile(ctx, renderImageDecode, filePath)
+			fileNameType := fmt.Sprintf("%s.png", fileName)
+
+			err := u.savePNGFile(ctx, renderImageDecode, fileNameType)
 			if err != nil {
-				return nil, err
+				u.deleteFile(ctx, listImageName)
+				return nil, nil, err
 			}
 
-			fileNameType := fmt.Sprintf("%s.png", fileName)
-
 			listImageName = append(listImageName, fileNameType)
 
 		case JPEG_IMAGE_TYPE:
 			// save jpeg
-			err := u.saveJPEGFile(ctx, renderImageDecode, filePath)
+			fileNameType := fmt.Sprintf("%s.jpeg", fileName)
+
+			err := u.saveJPEGFile(ctx, renderImageDecode, fileNameType)
 			if err != nil {
-				return nil, err
+				u.deleteFile(ctx, listImageName)
+				return nil, nil, err
 			}
 
-			fileNameType := fmt.Sprintf("%s.jpeg", fileName)
-
 			listImageName = append(listImageName, fileNameType)
 
+			if imageExif := u.readImageExif(ctx, fileNameType, bytes.NewReader(imageDecode)); imageExif != nil {
+				listImageExif = append(listImageExif, *imageExif)
+			}
+
 		default:
-			return nil, ErrorUploadImageUsecaseFileTypeNotMatch
+			u.deleteFile(ctx, listImageName)
+			return nil, nil, ErrorUploadImageUsecaseFileTypeNotMatch
 		}
 
 	}
 
-	return listImageName, nil
+	return listImageName, listImageExif, nil
 }
 
-func (u *UploadImageUsecase) savePNGFile(ctx context.Context, renderImageDecode *bytes.Reader, filePath string) error {
+func (u *UploadImageUsecase) savePNGFile(ctx context.Context, renderImageDecode *bytes.Reader, fileName string) error {
 	log := u.log.WithContext(ctx)
 
 	pngImage, err := png.Decode(renderImageDecode)
 	if err != nil {
 		log.Errorf("[savePNGFile] png decode error: %v", err)
-		fmt.Printf("point: 2")
 		return ErrorTechnicalError
 	}
 
-	file, err := os.OpenFile(filePath+".png", os.O_WRONLY|os.O_CREATE, 0777)
-	if err != nil {
-		log.Errorf("[savePNGFile] open file error: %v", err)
-		fmt.Printf("point: 3, error: %v", err)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, pngImage); err != nil {
+		log.Errorf("[savePNGFile] png encode error: %v", err)
 		return ErrorTechnicalError
 	}
 
-	defer file.Close()
-
-	png.Encode(file, pngImage)
+	if err := u.imageStorage.Save(ctx, fileName, &buf, int64(buf.Len()), PNG_IMAGE_TYPE); err != nil {
+		log.Errorf("[savePNGFile] save image `%v` error: %v", fileName, err)
+		return ErrorUploadImageUsecaseSaveImageFileFail
+	}
 
-	return nil
+	return u.generateDerivatives(ctx, fileName)
 }
 
-func (u *UploadImageUsecase) saveJPEGFile(ctx context.Context, renderImageDecode *bytes.Reader, filePath string) error {
+func (u *UploadImageUsecase) saveJPEGFile(ctx context.Context, renderImageDecode *bytes.Reader, fileName string) error {
 	log := u.log.WithContext(ctx)
 
 	jpegImage, err := jpeg.Decode(renderImageDecode)
 	if err != nil {
 		log.Errorf("[saveJPEGFile] jpeg decode error: %v", err)
-		fmt.Printf("point: 4")
 		return ErrorTechnicalError
 	}
 
-	file, err := os.OpenFile(filePath+".jpeg", os.O_WRONLY|os.O_CREATE, 0777)
-	if err != nil {
-		log.Errorf("[saveJPEGFile] open file error: %v", err)
-		fmt.Printf("point: 5")
+	jpegOpts := jpeg.Options{Quality: 100}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, jpegImage, &jpegOpts); err != nil {
+		log.Errorf("[saveJPEGFile] jpeg encode error: %v", err)
 		return ErrorTechnicalError
 	}
 
-	defer file.Close()
+	if err := u.imageStorage.Save(ctx, fileName, &buf, int64(buf.Len()), JPEG_IMAGE_TYPE); err != nil {
+		log.Errorf("[saveJPEGFile] save image `%v` error: %v", fileName, err)
+		return ErrorUploadImageUsecaseSaveImageFileFail
+	}
 
-	jpegOpts := jpeg.Options{Quality: 100}
+	return u.generateDerivatives(ctx, fileName)
+}
+
+// =======================================================
+// upload image file from multipart request
+// =======================================================
+
+func (u *UploadImageUsecase) UploadImageFiles(ctx context.Context, spiderUUID string, nextFile func() (*model.UploadImageFile, error)) ([]model.UploadImageResult, error) {
+	log := u.log.WithContext(ctx)
+
+	spiderInfo, err := u.spiderRepo.FindSpiderByUUID(ctx, spiderUUID)
+	if err != nil {
+		log.Errorf("[UploadImageFiles] find spider info `%v` error: %+v", spiderUUID, err)
+		return nil, ErrorUploadImageUsecaseVlidateSpiderUUID
+	}
+
+	// spider info in trash can not be changed
+	if spiderInfo.IsDeleted() {
+		return nil, ErrorUploadImageUsecaseVlidateSpiderUUID
+	}
+
+	maxFiles := u.conf.File.Upload.MaxFiles
+	if maxFiles <= 0 {
+		maxFiles = DEFAULT_UPLOAD_IMAGE_MAX_FILES
+	}
+
+	var results []model.UploadImageResult
+	var listImageName []string
+	var listImageExif []model.ImageExif
+
+	for {
+		file, err := nextFile()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Errorf("[UploadImageFiles] read next file error: %v", err)
+			u.deleteFile(ctx, listImageName)
+			return nil, ErrorUploadImageUsecaseReadRequestFail
+		}
+
+		result := model.UploadImageResult{FileName: file.FileName}
+
+		// file over limit is not read, multipart reader skip it when next file is requested
+		if len(results) >= maxFiles {
+			result.Err = ErrorUploadImageUsecaseTooManyFiles
+			results = append(results, result)
+			continue
+		}
+
+		imageName, imageExif, err := u.saveImageFile(ctx, spiderUUID, file)
+		if err == ErrorUploadImageUsecaseReadRequestFail {
+			u.deleteFile(ctx, listImageName)
+			return nil, err
+		}
+
+		result.ImageName = imageName
+		result.Err = err
+		results = append(results, result)
+
+		if err == nil {
+			listImageName = append(listImageName, imageName)
+		}
+		if imageExif != nil {
+			listImageExif = append(listImageExif, *imageExif)
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, ErrorUploadImageUsecaseNoImageFile
+	}
+
+	if len(listImageName) == 0 {
+		return results, nil
+	}
+
+	spiderInfo.ImageFile = append(spiderInfo.ImageFile, listImageName...)
+
+	if err := u.spiderRepo.UpdateImageFileToSpiderInfo(ctx, spiderInfo.ImageFile, spiderUUID); err != nil {
+		log.Errorf("[UploadImageFiles] update spider info mongo failed, error: %v", err)
+		u.deleteFile(ctx, listImageName)
+		return nil, ErrorMongoTechnicalFail
+	}
+
+	u.saveImageExif(ctx, spiderUUID, listImageExif)
+
+	return results, nil
+}
+
+// saveImageFile check type by magic bytes of file header then stream file to storage without decode,
+// exif of jpeg is read back from saved image
+func (u *UploadImageUsecase) saveImageFile(ctx context.Context, spiderUUID string, file *model.UploadImageFile) (string, *model.ImageExif, error) {
+	log := u.log.WithContext(ctx)
+
+	maxFileSize := u.conf.File.Upload.MaxFileSize
+	if maxFileSize <= 0 {
+		maxFileSize = DEFAULT_UPLOAD_IMAGE_MAX_FILE_SIZE
+	}
+
+	content := &uploadImageReader{reader: file.Content, remaining: maxFileSize}
+
+	// http.DetectContentType read at most 512 bytes
+	header := make([]byte, 512)
+	n, err := io.ReadFull(content, header)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		log.Errorf("[saveImageFile] read header of file `%v` error: %v", file.FileName, err)
+		return "", nil, content.failure()
+	}
+	header = header[:n]
+
+	var extension string
+
+	contentType := http.DetectContentType(header)
+	switch contentType {
+	case PNG_IMAGE_TYPE:
+		extension = "png"
+	case JPEG_IMAGE_TYPE:
+		extension = "jpeg"
+	default:
+		log.Warnf("[saveImageFile] file `%v` is `%v`, not image", file.FileName, contentType)
+		return "", nil, ErrorUploadImageUsecaseFileTypeNotMatch
+	}
+
+	imageName := fmt.Sprintf("%s.%s", fmt.Sprintf(u.conf.File.SpiderImage, spiderUUID, uuid.GernerateUUID32()), extension)
+
+	// size is unknown until file is read to the end
+	if err := u.imageStorage.Save(ctx, imageName, io.MultiReader(bytes.NewReader(header), content), -1, contentType); err != nil {
+		log.Errorf("[saveImageFile] save file `%v` as `%v` error: %v", file.FileName, imageName, err)
+		return "", nil, content.failure()
+	}
+
+	if err := u.generateDerivatives(ctx, imageName); err != nil {
+		return "", nil, err
+	}
+
+	log.Infof("[saveImageFile] save file `%v` as `%v`", file.FileName, imageName)
+
+	if contentType != JPEG_IMAGE_TYPE {
+		return imageName, nil, nil
+	}
+
+	return imageName, u.readSavedImageExif(ctx, imageName), nil
+}
+
+// generateDerivatives resize saved image, image is removed when derivative can not be generated
+// so image without derivative is never added to spider info
+func (u *UploadImageUsecase) generateDerivatives(ctx context.Context, imageName string) error {
+	log := u.log.WithContext(ctx)
 
-	jpeg.Encode(file, jpegImage, &jpegOpts)
+	if err := u.derivativeGenerator.Generate(ctx, imageName); err != nil {
+		log.Errorf("[generateDerivatives] generate derivative of `%v` error: %v", imageName, err)
+		u.deleteFile(ctx, []string{imageName})
+		if err == imaging.ErrorDecodeImage {
+			return ErrorUploadImageUsecaseFileTypeNotMatch
+		}
+		return ErrorUploadImageUsecaseSaveImageFileFail
+	}
 
 	return nil
 }
 
-func (u *UploadImageUsecase) deleteFile(ctx context.Context, files []string) {
+// readImageExif return nil when image has no exif, exif is additional data so error is only logged
+func (u *UploadImageUsecase) readImageExif(ctx context.Context, imageName string, content io.Reader) *model.ImageExif {
 	log := u.log.WithContext(ctx)
 
-	for _, file := range files {
+	imageExif, err := imaging.ReadExif(content)
+	if err != nil {
+		if err != imaging.ErrorExifNotFound {
+			log.Warnf("[readImageExif] read exif of `%v` error: %v", imageName, err)
+		}
+		return nil
+	}
+
+	imageExif.ImageName = imageName
 
-		filePath := fmt.Sprintf(config.C().File.FileImagePath, file)
+	return imageExif
+}
+
+func (u *UploadImageUsecase) readSavedImageExif(ctx context.Context, imageName string) *model.ImageExif {
+	log := u.log.WithContext(ctx)
+
+	content, _, err := u.imageStorage.Open(ctx, imageName)
+	if err != nil {
+		log.Warnf("[readSavedImageExif] open image `%v` error: %v", imageName, err)
+		return nil
+	}
+	defer content.Close()
+
+	return u.readImageExif(ctx, imageName, content)
+}
+
+// saveImageExif is called after image is added to spider info, image is kept when exif can not be saved
+func (u *UploadImageUsecase) saveImageExif(ctx context.Context, spiderUUID string, listImageExif []model.ImageExif) {
+	log := u.log.WithContext(ctx)
+
+	if len(listImageExif) == 0 {
+		return
+	}
+
+	if err := u.spiderRepo.PushSpiderImageExif(ctx, spiderUUID, listImageExif); err != nil {
+		log.Errorf("[saveImageExif] save exif of spider `%v` error: %v", spiderUUID, err)
+	}
+}
+
+// uploadImageReader stop file that is larger than remaining and keep error of request,
+// so error of storage can be told from error of request
+type uploadImageReader struct {
+	reader    io.Reader
+	remaining int64
+	tooLarge  bool
+	readErr   error
+}
+
+func (r *uploadImageReader) Read(p []byte) (int, error) {
+	// read one more byte than remaining to know that file is larger than limit
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+
+	n, err := r.reader.Read(p)
+	if int64(n) > r.remaining {
+		r.tooLarge = true
+		return 0, ErrorUploadImageUsecaseFileTooLarge
+	}
+	r.remaining -= int64(n)
+
+	if err != nil && err != io.EOF {
+		r.readErr = err
+	}
+
+	return n, err
+}
+
+func (r *uploadImageReader) failure() error {
+	switch {
+	case r.tooLarge:
+		return ErrorUploadImageUsecaseFileTooLarge
+	case r.readErr != nil:
+		return ErrorUploadImageUsecaseReadRequestFail
+	default:
+		return ErrorUploadImageUsecaseSaveImageFileFail
+	}
+}
+
+// *******************************************************
+
+func (u *UploadImageUsecase) deleteFile(ctx context.Context, files []string) {
+	log := u.log.WithContext(ctx)
 
-		if err := os.Remove(filePath); err != nil {
+	for _, file := range withImageDerivatives(files) {
+		if err := u.imageStorage.Delete(ctx, file); err != nil {
 			log.Warnf("[deleteFile] remove file: `%s` failed, error: %v", file, err)
 		}
 	}
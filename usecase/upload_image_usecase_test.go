@@ -1,12 +1,25 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/binary"
 	"errors"
+	"image"
+	"image/jpeg"
+	"io"
+	"math"
+	"os"
+	"reflect"
 	"spider-go/config"
 	mock_domain "spider-go/domain/mock"
 	"spider-go/model"
+	"spider-go/utils/imaging"
+	"spider-go/utils/storage"
+	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/golang/mock/gomock"
@@ -24,7 +37,6 @@ var (
 func TestUploadImageSpiderUsecase(t *testing.T) {
 
 	tmpDir := t.TempDir()
-	config.C().File.FileImagePath = tmpDir
 	config.C().RedisOption.Login.KeyFormat = "login_%s_%s"
 	config.C().RedisOption.Login.TTL = time.Duration(12)
 
@@ -106,7 +118,8 @@ func TestUploadImageSpiderUsecase(t *testing.T) {
 
 			tt.stubs(&commonStubsUploadImage)
 
-			usecase := NewUploadImageUsecase(mockSpiderRepo)
+			imageStorage := storage.NewLocalImageStorage(tmpDir)
+			usecase := NewUploadImageUsecase(mockSpiderRepo, imageStorage, imaging.NewDerivativeGenerator(imageStorage, config.C().File.Derivative), config.C())
 
 			err := usecase.UploadImageSpiderUsecase(
 				context.TODO(),
@@ -181,3 +194,462 @@ func fail_update_file_name_to_mongo_error_case(stubs *commonStubsUploadImage) {
 		gomock.Eq(normal_spiderUUID),
 	).Return(ErrorMongoTechnicalFail)
 }
+
+// ======================================================================
+// TestUploadImageUsecase_UploadImageFiles
+// ======================================================================
+func TestUploadImageUsecase_UploadImageFiles(t *testing.T) {
+
+	conf := &config.Root{}
+	conf.File.SpiderImage = "%s-%s"
+	conf.File.Upload.MaxFileSize = 4096
+	conf.File.Upload.MaxFiles = 3
+
+	pngImage, _ := base64.StdEncoding.DecodeString(normal_image[strings.Index(normal_image, ",")+1:])
+	largePNGImage := append(append([]byte{}, pngImage...), make([]byte, 4096)...)
+	// png magic bytes with content that can not be decoded
+	corruptPNGImage := append(append([]byte{}, pngImage[:16]...), make([]byte, 64)...)
+
+	readFailedFile := io.MultiReader(bytes.NewReader(pngImage), iotest.ErrReader(errors.New("connection reset")))
+
+	tests := []struct {
+		name        string
+		files       []model.UploadImageFile
+		buildStubs  func(*mock_domain.MockSpiderRepository)
+		wantResults []error
+		wantSaved   int
+		wantErr     error
+	}{
+		{
+			name: "success_report_each_file",
+			files: []model.UploadImageFile{
+				{FileName: "spider.png", Content: bytes.NewReader(pngImage)},
+				{FileName: "note.txt", Content: strings.NewReader("not an image")},
+				{FileName: "large.png", Content: bytes.NewReader(largePNGImage)},
+				{FileName: "over.png", Content: bytes.NewReader(pngImage)},
+			},
+			buildStubs:  success_report_each_file,
+			wantResults: []error{nil, ErrorUploadImageUsecaseFileTypeNotMatch, ErrorUploadImageUsecaseFileTooLarge, ErrorUploadImageUsecaseTooManyFiles},
+			// original and its thumbnail, medium and large
+			wantSaved: 4,
+			wantErr:   nil,
+		},
+		{
+			name: "corrupt_image_not_saved",
+			files: []model.UploadImageFile{
+				{FileName: "corrupt.png", Content: bytes.NewReader(corruptPNGImage)},
+			},
+			buildStubs: func(spiderRepo *mock_domain.MockSpiderRepository) {
+				spiderRepo.EXPECT().FindSpiderByUUID(gomock.Any(), gomock.Eq(normal_spiderUUID)).Return(&model.SpiderInfo{SpiderUUID: normal_spiderUUID}, nil)
+				spiderRepo.EXPECT().UpdateImageFileToSpiderInfo(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantResults: []error{ErrorUploadImageUsecaseFileTypeNotMatch},
+			wantSaved:   0,
+			wantErr:     nil,
+		},
+		{
+			name: "read_request_failed_remove_saved_file",
+			files: []model.UploadImageFile{
+				{FileName: "spider.png", Content: bytes.NewReader(pngImage)},
+				{FileName: "broken.png", Content: readFailedFile},
+			},
+			buildStubs: func(spiderRepo *mock_domain.MockSpiderRepository) {
+				spiderRepo.EXPECT().FindSpiderByUUID(gomock.Any(), gomock.Eq(normal_spiderUUID)).Return(&model.SpiderInfo{SpiderUUID: normal_spiderUUID}, nil)
+				spiderRepo.EXPECT().UpdateImageFileToSpiderInfo(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantSaved: 0,
+			wantErr:   ErrorUploadImageUsecaseReadRequestFail,
+		},
+		{
+			name:  "no_image_file",
+			files: nil,
+			buildStubs: func(spiderRepo *mock_domain.MockSpiderRepository) {
+				spiderRepo.EXPECT().FindSpiderByUUID(gomock.Any(), gomock.Eq(normal_spiderUUID)).Return(&model.SpiderInfo{SpiderUUID: normal_spiderUUID}, nil)
+			},
+			wantSaved: 0,
+			wantErr:   ErrorUploadImageUsecaseNoImageFile,
+		},
+		{
+			name: "update_spider_info_failed_remove_saved_file",
+			files: []model.UploadImageFile{
+				{FileName: "spider.png", Content: bytes.NewReader(pngImage)},
+			},
+			buildStubs: func(spiderRepo *mock_domain.MockSpiderRepository) {
+				spiderRepo.EXPECT().FindSpiderByUUID(gomock.Any(), gomock.Eq(normal_spiderUUID)).Return(&model.SpiderInfo{SpiderUUID: normal_spiderUUID}, nil)
+				spiderRepo.EXPECT().UpdateImageFileToSpiderInfo(gomock.Any(), gomock.Any(), gomock.Eq(normal_spiderUUID)).Return(errors.New("mongo error"))
+			},
+			wantSaved: 0,
+			wantErr:   ErrorMongoTechnicalFail,
+		},
+		{
+			name: "spider_info_in_trash",
+			files: []model.UploadImageFile{
+				{FileName: "spider.png", Content: bytes.NewReader(pngImage)},
+			},
+			buildStubs: func(spiderRepo *mock_domain.MockSpiderRepository) {
+				spiderInfo := model.SpiderInfo{SpiderUUID: normal_spiderUUID, Status: model.SPIDER_INFO_STATUS_DELETED}
+				spiderRepo.EXPECT().FindSpiderByUUID(gomock.Any(), gomock.Eq(normal_spiderUUID)).Return(&spiderInfo, nil)
+			},
+			wantSaved: 0,
+			wantErr:   ErrorUploadImageUsecaseVlidateSpiderUUID,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tmpDir := t.TempDir()
+
+			spiderRepo := mock_domain.NewMockSpiderRepository(ctrl)
+			tt.buildStubs(spiderRepo)
+
+			files := tt.files
+			nextFile := func() (*model.UploadImageFile, error) {
+				if len(files) == 0 {
+					return nil, io.EOF
+				}
+				file := files[0]
+				files = files[1:]
+				return &file, nil
+			}
+
+			imageStorage := storage.NewLocalImageStorage(tmpDir)
+			u := NewUploadImageUsecase(spiderRepo, imageStorage, imaging.NewDerivativeGenerator(imageStorage, conf.File.Derivative), conf)
+			results, err := u.UploadImageFiles(context.TODO(), normal_spiderUUID, nextFile)
+			if err != tt.wantErr {
+				t.Errorf("UploadImageUsecase.UploadImageFiles() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			var gotResults []error
+			for _, result := range results {
+				gotResults = append(gotResults, result.Err)
+			}
+			if !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("UploadImageUsecase.UploadImageFiles() results = %v, want %v", gotResults, tt.wantResults)
+			}
+
+			// failed file and temp file are not left in storage
+			saved, _ := os.ReadDir(tmpDir)
+			if len(saved) != tt.wantSaved {
+				t.Errorf("UploadImageUsecase.UploadImageFiles() saved %v files, want %v", len(saved), tt.wantSaved)
+			}
+		})
+	}
+}
+
+func success_report_each_file(spiderRepo *mock_domain.MockSpiderRepository) {
+	spiderInfo := model.SpiderInfo{
+		SpiderUUID: normal_spiderUUID,
+		ImageFile:  []string{"existing.png"},
+	}
+
+	spiderRepo.EXPECT().FindSpiderByUUID(gomock.Any(), gomock.Eq(normal_spiderUUID)).Return(&spiderInfo, nil)
+
+	spiderRepo.EXPECT().UpdateImageFileToSpiderInfo(
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Eq(normal_spiderUUID),
+	).DoAndReturn(func(_ context.Context, filesName []string, _ string) error {
+		// new image is appended to existing image
+		if len(filesName) != 2 || filesName[0] != "existing.png" || !strings.HasSuffix(filesName[1], ".png") {
+			return errors.New("unexpected image file")
+		}
+		return nil
+	})
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestUploadImageUsecase_ReadImageExif
+// ======================================================================
+func TestUploadImageUsecase_ReadImageExif(t *testing.T) {
+
+	conf := &config.Root{}
+	conf.File.SpiderImage = "%s-%s"
+
+	le, be := binary.LittleEndian, binary.BigEndian
+
+	fieldPhoto := unittestExifJPEG(t, le,
+		[]unittestExifEntry{
+			unittestExifASCII(0x010f, "Canon"),
+			unittestExifASCII(0x0110, "Canon EOS 90D"),
+		},
+		[]unittestExifEntry{
+			unittestExifASCII(0x9003, "2023:03:04 09:15:30"),
+			unittestExifASCII(0x9011, "+07:00"),
+		},
+		[]unittestExifEntry{
+			unittestExifASCII(0x0001, "N"),
+			unittestExifRational(le, 0x0002, 18, 1, 35, 1, 200088, 10000),
+			unittestExifASCII(0x0003, "E"),
+			unittestExifRational(le, 0x0004, 98, 1, 29, 1, 131112, 10000),
+			{tag: 0x0005, dataType: 1, count: 1, value: []byte{0}},
+			unittestExifRational(le, 0x0006, 17505, 10),
+		},
+	)
+
+	southWestPhoto := unittestExifJPEG(t, be,
+		nil,
+		nil,
+		[]unittestExifEntry{
+			unittestExifASCII(0x0001, "S"),
+			unittestExifRational(be, 0x0002, 33, 1, 52, 1, 0, 1),
+			unittestExifASCII(0x0003, "W"),
+			unittestExifRational(be, 0x0004, 151, 1, 12, 1, 30, 1),
+			{tag: 0x0005, dataType: 1, count: 1, value: []byte{1}},
+			unittestExifRational(be, 0x0006, 12, 1),
+		},
+	)
+
+	// camera without gps fix write zero denominator
+	noGPSFixPhoto := unittestExifJPEG(t, le,
+		nil,
+		[]unittestExifEntry{
+			unittestExifASCII(0x9003, "2023:03:04 09:15:30"),
+		},
+		[]unittestExifEntry{
+			unittestExifASCII(0x0001, "N"),
+			unittestExifRational(le, 0x0002, 0, 0, 0, 0, 0, 0),
+			unittestExifASCII(0x0003, "E"),
+			unittestExifRational(le, 0x0004, 0, 0, 0, 0, 0, 0),
+		},
+	)
+
+	plainPhoto := unittestExifJPEG(t, le, nil, nil, nil)
+
+	takenAt := time.Date(2023, 3, 4, 2, 15, 30, 0, time.UTC)
+	localTakenAt := time.Date(2023, 3, 4, 9, 15, 30, 0, time.Local)
+
+	tests := []struct {
+		name   string
+		image  []byte
+		base64 bool
+		want   *model.ImageExif
+	}{
+		{
+			name:  "read_gps_taken_time_and_camera",
+			image: fieldPhoto,
+			want: &model.ImageExif{
+				TakenAt:     &takenAt,
+				Latitude:    unittestFloat(18 + 35.0/60 + 20.0088/3600),
+				Longitude:   unittestFloat(98 + 29.0/60 + 13.1112/3600),
+				Altitude:    unittestFloat(1750.5),
+				CameraMake:  "Canon",
+				CameraModel: "Canon EOS 90D",
+			},
+		},
+		{
+			name:  "read_gps_taken_time_and_camera_base64",
+			image: fieldPhoto,
+			// base64 image is re-encoded, exif is read from uploaded data
+			base64: true,
+			want: &model.ImageExif{
+				TakenAt:     &takenAt,
+				Latitude:    unittestFloat(18 + 35.0/60 + 20.0088/3600),
+				Longitude:   unittestFloat(98 + 29.0/60 + 13.1112/3600),
+				Altitude:    unittestFloat(1750.5),
+				CameraMake:  "Canon",
+				CameraModel: "Canon EOS 90D",
+			},
+		},
+		{
+			name:  "south_west_below_sea_level_big_endian",
+			image: southWestPhoto,
+			want: &model.ImageExif{
+				Latitude:  unittestFloat(-(33 + 52.0/60)),
+				Longitude: unittestFloat(-(151 + 12.0/60 + 30.0/3600)),
+				Altitude:  unittestFloat(-12),
+			},
+		},
+		{
+			name:  "no_gps_fix_keep_taken_time",
+			image: noGPSFixPhoto,
+			want: &model.ImageExif{
+				TakenAt: &localTakenAt,
+			},
+		},
+		{
+			name:  "jpeg_without_exif",
+			image: plainPhoto,
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			spiderRepo := mock_domain.NewMockSpiderRepository(ctrl)
+			spiderRepo.EXPECT().FindSpiderByUUID(gomock.Any(), gomock.Eq(normal_spiderUUID)).Return(&model.SpiderInfo{SpiderUUID: normal_spiderUUID}, nil)
+			spiderRepo.EXPECT().UpdateImageFileToSpiderInfo(gomock.Any(), gomock.Any(), gomock.Eq(normal_spiderUUID)).Return(nil)
+
+			var got []model.ImageExif
+			if tt.want != nil {
+				spiderRepo.EXPECT().PushSpiderImageExif(gomock.Any(), gomock.Eq(normal_spiderUUID), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, imageExif []model.ImageExif) error {
+						got = imageExif
+						return nil
+					})
+			} else {
+				spiderRepo.EXPECT().PushSpiderImageExif(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			}
+
+			imageStorage := storage.NewLocalImageStorage(t.TempDir())
+			u := NewUploadImageUsecase(spiderRepo, imageStorage, imaging.NewDerivativeGenerator(imageStorage, conf.File.Derivative), conf)
+
+			var err error
+			if tt.base64 {
+				err = u.UploadImageSpiderUsecase(context.TODO(), normal_spiderUUID, []string{"data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(tt.image)})
+			} else {
+				files := []model.UploadImageFile{{FileName: "spider.jpg", Content: bytes.NewReader(tt.image)}}
+				_, err = u.UploadImageFiles(context.TODO(), normal_spiderUUID, func() (*model.UploadImageFile, error) {
+					if len(files) == 0 {
+						return nil, io.EOF
+					}
+					file := files[0]
+					files = files[1:]
+					return &file, nil
+				})
+			}
+			if err != nil {
+				t.Errorf("UploadImageUsecase.ReadImageExif() error = %v", err)
+				return
+			}
+
+			if tt.want == nil {
+				return
+			}
+
+			if len(got) != 1 || !strings.HasSuffix(got[0].ImageName, ".jpeg") || !unittestEqualImageExif(got[0], *tt.want) {
+				t.Errorf("UploadImageUsecase.ReadImageExif() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+type unittestExifEntry struct {
+	tag      uint16
+	dataType uint16
+	count    uint32
+	value    []byte
+}
+
+func unittestExifASCII(tag uint16, value string) unittestExifEntry {
+	return unittestExifEntry{tag: tag, dataType: 2, count: uint32(len(value) + 1), value: append([]byte(value), 0)}
+}
+
+// unittestExifRational take numerator and denominator pairs
+func unittestExifRational(order binary.ByteOrder, tag uint16, values ...uint32) unittestExifEntry {
+	value := make([]byte, len(values)*4)
+	for i, v := range values {
+		order.PutUint32(value[i*4:], v)
+	}
+	return unittestExifEntry{tag: tag, dataType: 5, count: uint32(len(values) / 2), value: value}
+}
+
+// unittestExifJPEG insert exif segment into small jpeg, exif and gps ifd are linked from ifd0 when they are not nil,
+// jpeg has no exif when every ifd is nil
+func unittestExifJPEG(t *testing.T, order binary.ByteOrder, ifd0, exifIFD, gpsIFD []unittestExifEntry) []byte {
+	var jpegImage bytes.Buffer
+	if err := jpeg.Encode(&jpegImage, image.NewGray(image.Rect(0, 0, 8, 8)), nil); err != nil {
+		t.Fatalf("encode jpeg error: %v", err)
+	}
+
+	if ifd0 == nil && exifIFD == nil && gpsIFD == nil {
+		return jpegImage.Bytes()
+	}
+
+	tiff := []byte("II*\x00\x00\x00\x00\x00")
+	if order == binary.BigEndian {
+		tiff = []byte("MM\x00*\x00\x00\x00\x00")
+	}
+
+	appendUint := func(size int, v uint32) {
+		b := make([]byte, 4)
+		if size == 2 {
+			order.PutUint16(b, uint16(v))
+		} else {
+			order.PutUint32(b, v)
+		}
+		tiff = append(tiff, b[:size]...)
+	}
+
+	writeIFD := func(entries []unittestExifEntry) uint32 {
+		offset := uint32(len(tiff))
+		valueOffset := offset + 2 + uint32(len(entries))*12 + 4
+
+		var values []byte
+
+		appendUint(2, uint32(len(entries)))
+		for _, entry := range entries {
+			appendUint(2, uint32(entry.tag))
+			appendUint(2, uint32(entry.dataType))
+			appendUint(4, entry.count)
+			if len(entry.value) <= 4 {
+				inline := make([]byte, 4)
+				copy(inline, entry.value)
+				tiff = append(tiff, inline...)
+			} else {
+				appendUint(4, valueOffset+uint32(len(values)))
+				values = append(values, entry.value...)
+			}
+		}
+		appendUint(4, 0)
+		tiff = append(tiff, values...)
+
+		return offset
+	}
+
+	if exifIFD != nil {
+		ifd0 = append(ifd0, unittestExifEntry{tag: 0x8769, dataType: 4, count: 1, value: unittestExifLong(order, writeIFD(exifIFD))})
+	}
+	if gpsIFD != nil {
+		ifd0 = append(ifd0, unittestExifEntry{tag: 0x8825, dataType: 4, count: 1, value: unittestExifLong(order, writeIFD(gpsIFD))})
+	}
+	ifd0Offset := writeIFD(ifd0)
+	order.PutUint32(tiff[4:], ifd0Offset)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+
+	var result []byte
+	result = append(result, jpegImage.Bytes()[:2]...)
+	result = append(result, 0xff, 0xe1, byte((len(segment)+2)>>8), byte(len(segment)+2))
+	result = append(result, segment...)
+	result = append(result, jpegImage.Bytes()[2:]...)
+
+	return result
+}
+
+func unittestExifLong(order binary.ByteOrder, v uint32) []byte {
+	value := make([]byte, 4)
+	order.PutUint32(value, v)
+	return value
+}
+
+func unittestFloat(v float64) *float64 {
+	return &v
+}
+
+func unittestEqualImageExif(got, want model.ImageExif) bool {
+	equalFloat := func(a, b *float64) bool {
+		if a == nil || b == nil {
+			return a == b
+		}
+		return math.Abs(*a-*b) < 1e-9
+	}
+
+	equalTime := (got.TakenAt == nil && want.TakenAt == nil) ||
+		(got.TakenAt != nil && want.TakenAt != nil && got.TakenAt.Equal(*want.TakenAt))
+
+	return equalTime &&
+		equalFloat(got.Latitude, want.Latitude) &&
+		equalFloat(got.Longitude, want.Longitude) &&
+		equalFloat(got.Altitude, want.Altitude) &&
+		got.CameraMake == want.CameraMake &&
+		got.CameraModel == want.CameraModel
+}
+
+// **********************************************************************
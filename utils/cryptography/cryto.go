@@ -1,9 +1,8 @@
 package cryptography
 
 import (
-	"context"
-
-	"golang.org/x/crypto/bcrypt"
+	"crypto/sha256"
+	"encoding/hex"
 )
 
 type Crypto struct{}
@@ -12,12 +11,8 @@ func NewCrypto() *Crypto {
 	return &Crypto{}
 }
 
-func (c *Crypto) HashPassword(ctx context.Context, password string) (hashPassword string, err error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
-	return string(bytes), err
-}
-
-func (c *Crypto) CheckPasswordHash(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+// HashSHA256 return hex of sha256, used for store secret token that not need slow hash
+func (c *Crypto) HashSHA256(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,186 @@
+package cryptography
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"spider-go/config"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	PASSWORD_HASH_ALGORITHM_ARGON2ID = "argon2id"
+	PASSWORD_HASH_ALGORITHM_BCRYPT   = "bcrypt"
+)
+
+// default parameter when config is not set, argon2id follow OWASP recommendation
+var (
+	DEFAULT_BCRYPT_COST        = 12
+	DEFAULT_ARGON2_MEMORY      = uint32(19 * 1024)
+	DEFAULT_ARGON2_ITERATIONS  = uint32(2)
+	DEFAULT_ARGON2_PARALLELISM = uint8(1)
+	DEFAULT_ARGON2_SALT_LENGTH = uint32(16)
+	DEFAULT_ARGON2_KEY_LENGTH  = uint32(32)
+)
+
+var (
+	ErrorUnknownHashFormat = errors.New("unknown password hash format")
+	ErrorInvalidArgon2Hash = errors.New("invalid argon2id hash")
+)
+
+// PasswordHasher hash password with current policy and verify hash of every supported algorithm,
+// encoded hash contain algorithm and parameter so policy can change without break old hash
+type PasswordHasher interface {
+	Hash(password string) (encodedHash string, err error)
+	Verify(password, encodedHash string) (match bool, err error)
+	// NeedsRehash return true when hash is not created by current algorithm and parameter
+	NeedsRehash(encodedHash string) bool
+}
+
+type passwordHasher struct {
+	algorithm  string
+	bcryptCost int
+	argon2     config.Argon2Params
+}
+
+func NewPasswordHasher(conf config.PasswordHash) PasswordHasher {
+	h := &passwordHasher{
+		algorithm:  conf.Algorithm,
+		bcryptCost: conf.BcryptCost,
+		argon2:     conf.Argon2,
+	}
+
+	if h.algorithm == "" {
+		h.algorithm = PASSWORD_HASH_ALGORITHM_ARGON2ID
+	}
+	if h.bcryptCost <= 0 {
+		h.bcryptCost = DEFAULT_BCRYPT_COST
+	}
+	if h.argon2.Memory == 0 {
+		h.argon2.Memory = DEFAULT_ARGON2_MEMORY
+	}
+	if h.argon2.Iterations == 0 {
+		h.argon2.Iterations = DEFAULT_ARGON2_ITERATIONS
+	}
+	if h.argon2.Parallelism == 0 {
+		h.argon2.Parallelism = DEFAULT_ARGON2_PARALLELISM
+	}
+	if h.argon2.SaltLength == 0 {
+		h.argon2.SaltLength = DEFAULT_ARGON2_SALT_LENGTH
+	}
+	if h.argon2.KeyLength == 0 {
+		h.argon2.KeyLength = DEFAULT_ARGON2_KEY_LENGTH
+	}
+
+	return h
+}
+
+func (h *passwordHasher) Hash(password string) (string, error) {
+	if h.algorithm == PASSWORD_HASH_ALGORITHM_BCRYPT {
+		bytes, err := bcrypt.GenerateFromPassword([]byte(password), h.bcryptCost)
+		return string(bytes), err
+	}
+
+	salt := make([]byte, h.argon2.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.argon2.Iterations, h.argon2.Memory, h.argon2.Parallelism, h.argon2.KeyLength)
+
+	return encodeArgon2Hash(h.argon2, salt, key), nil
+}
+
+func (h *passwordHasher) Verify(password, encodedHash string) (bool, error) {
+	switch {
+	case isBcryptHash(encodedHash):
+		err := bcrypt.CompareHashAndPassword([]byte(encodedHash), []byte(password))
+		if err == bcrypt.ErrMismatchedHashAndPassword {
+			return false, nil
+		}
+		return err == nil, err
+	case isArgon2Hash(encodedHash):
+		params, salt, key, err := decodeArgon2Hash(encodedHash)
+		if err != nil {
+			return false, err
+		}
+		otherKey := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+		return subtle.ConstantTimeCompare(key, otherKey) == 1, nil
+	default:
+		return false, ErrorUnknownHashFormat
+	}
+}
+
+func (h *passwordHasher) NeedsRehash(encodedHash string) bool {
+	switch h.algorithm {
+	case PASSWORD_HASH_ALGORITHM_BCRYPT:
+		if !isBcryptHash(encodedHash) {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(encodedHash))
+		return err != nil || cost != h.bcryptCost
+	default:
+		if !isArgon2Hash(encodedHash) {
+			return true
+		}
+		params, salt, key, err := decodeArgon2Hash(encodedHash)
+		if err != nil {
+			return true
+		}
+		params.SaltLength = uint32(len(salt))
+		params.KeyLength = uint32(len(key))
+		return params != h.argon2
+	}
+}
+
+func isBcryptHash(encodedHash string) bool {
+	return strings.HasPrefix(encodedHash, "$2a$") || strings.HasPrefix(encodedHash, "$2b$") || strings.HasPrefix(encodedHash, "$2y$")
+}
+
+func isArgon2Hash(encodedHash string) bool {
+	return strings.HasPrefix(encodedHash, "$argon2id$")
+}
+
+// encodeArgon2Hash encode in PHC string format $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>
+func encodeArgon2Hash(params config.Argon2Params, salt, key []byte) string {
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		params.Memory,
+		params.Iterations,
+		params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+}
+
+func decodeArgon2Hash(encodedHash string) (params config.Argon2Params, salt, key []byte, err error) {
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, ErrorInvalidArgon2Hash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrorInvalidArgon2Hash
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, ErrorInvalidArgon2Hash
+	}
+
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return params, nil, nil, ErrorInvalidArgon2Hash
+	}
+
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return params, nil, nil, ErrorInvalidArgon2Hash
+	}
+
+	return params, salt, key, nil
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/pem"
@@ -105,3 +106,23 @@ func (c *Crypto) GenerateRSAKey(bitSize int) (string, string, error) {
 	return privStr, pubStr, nil
 
 }
+
+// DecryptRSAOAEP decrypt base64 cipher text that encrypted by RSA-OAEP with SHA-256
+func (c *Crypto) DecryptRSAOAEP(privateKeyStr, cipherText string) (string, error) {
+	privKey, err := c.decryptPKCS1RsaPrivateKey(privateKeyStr)
+	if err != nil {
+		return "", err
+	}
+
+	cipherBytes, err := base64.StdEncoding.DecodeString(cipherText)
+	if err != nil {
+		return "", err
+	}
+
+	plainText, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, privKey, cipherBytes, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plainText), nil
+}
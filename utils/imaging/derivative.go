@@ -0,0 +1,115 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"spider-go/config"
+	"spider-go/domain"
+	"spider-go/model"
+	"spider-go/utils/storage"
+)
+
+const (
+	DEFAULT_THUMBNAIL_SIZE = 320
+	DEFAULT_MEDIUM_SIZE    = 1024
+	DEFAULT_LARGE_SIZE     = 2048
+	DEFAULT_JPEG_QUALITY   = 85
+)
+
+var (
+	ErrorDecodeImage = errors.New("[imaging] decode image failed")
+)
+
+// DerivativeGenerator resize original image in storage and save resized image alongside original,
+// derivative keep format of original
+type DerivativeGenerator struct {
+	imageStorage domain.ImageStorage
+	sizes        map[string]int
+	jpegQuality  int
+}
+
+func NewDerivativeGenerator(imageStorage domain.ImageStorage, conf config.ImageDerivative) *DerivativeGenerator {
+	generator := &DerivativeGenerator{
+		imageStorage: imageStorage,
+		sizes: map[string]int{
+			model.IMAGE_SIZE_THUMBNAIL: conf.Thumbnail,
+			model.IMAGE_SIZE_MEDIUM:    conf.Medium,
+			model.IMAGE_SIZE_LARGE:     conf.Large,
+		},
+		jpegQuality: conf.JPEGQuality,
+	}
+
+	defaultSizes := map[string]int{
+		model.IMAGE_SIZE_THUMBNAIL: DEFAULT_THUMBNAIL_SIZE,
+		model.IMAGE_SIZE_MEDIUM:    DEFAULT_MEDIUM_SIZE,
+		model.IMAGE_SIZE_LARGE:     DEFAULT_LARGE_SIZE,
+	}
+	for size, maxSize := range generator.sizes {
+		if maxSize <= 0 {
+			generator.sizes[size] = defaultSizes[size]
+		}
+	}
+
+	if generator.jpegQuality <= 0 || generator.jpegQuality > 100 {
+		generator.jpegQuality = DEFAULT_JPEG_QUALITY
+	}
+
+	return generator
+}
+
+func (g *DerivativeGenerator) Generate(ctx context.Context, imageName string) error {
+	content, _, err := g.imageStorage.Open(ctx, imageName)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	original, format, err := image.Decode(content)
+	if err != nil {
+		return ErrorDecodeImage
+	}
+
+	for _, size := range model.IMAGE_DERIVATIVE_SIZES {
+		var buf bytes.Buffer
+		var contentType string
+
+		resized := Fit(original, g.sizes[size])
+
+		switch format {
+		case "png":
+			contentType = "image/png"
+			err = png.Encode(&buf, resized)
+		case "jpeg":
+			contentType = "image/jpeg"
+			err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: g.jpegQuality})
+		default:
+			return ErrorDecodeImage
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := g.imageStorage.Save(ctx, storage.DerivativeImageName(imageName, size), &buf, int64(buf.Len()), contentType); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (g *DerivativeGenerator) Exists(ctx context.Context, imageName string) (bool, error) {
+	for _, size := range model.IMAGE_DERIVATIVE_SIZES {
+		if _, err := g.imageStorage.Stat(ctx, storage.DerivativeImageName(imageName, size)); err != nil {
+			if err == storage.ErrorImageNotFound {
+				return false, nil
+			}
+			return false, err
+		}
+	}
+
+	return true, nil
+}
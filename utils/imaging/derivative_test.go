@@ -0,0 +1,252 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"reflect"
+	"spider-go/config"
+	"spider-go/model"
+	"spider-go/utils/storage"
+	"strings"
+	"testing"
+)
+
+var unittestDerivativeConfig = config.ImageDerivative{
+	Thumbnail:   100,
+	Medium:      200,
+	Large:       1000,
+	JPEGQuality: 90,
+}
+
+func saveUnittestImage(t *testing.T, imageStorage *storage.LocalImageStorage, imageName, format string, width, height int) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	src := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	var err error
+	if format == "png" {
+		err = png.Encode(&buf, src)
+	} else {
+		err = jpeg.Encode(&buf, src, nil)
+	}
+	if err != nil {
+		t.Fatalf("encode %v error: %v", format, err)
+	}
+
+	if err := imageStorage.Save(context.TODO(), imageName, &buf, int64(buf.Len()), "image/"+format); err != nil {
+		t.Fatalf("save image `%v` error: %v", imageName, err)
+	}
+}
+
+// ======================================================================
+// TestNewDerivativeGenerator
+// ======================================================================
+
+// size or quality that is not configured fall back to default
+func TestNewDerivativeGenerator(t *testing.T) {
+
+	tests := []struct {
+		name            string
+		conf            config.ImageDerivative
+		wantSizes       map[string]int
+		wantJPEGQuality int
+	}{
+		{
+			name: "configured_size_and_quality",
+			conf: unittestDerivativeConfig,
+			wantSizes: map[string]int{
+				model.IMAGE_SIZE_THUMBNAIL: 100,
+				model.IMAGE_SIZE_MEDIUM:    200,
+				model.IMAGE_SIZE_LARGE:     1000,
+			},
+			wantJPEGQuality: 90,
+		},
+		{
+			name: "empty_config_use_default",
+			conf: config.ImageDerivative{},
+			wantSizes: map[string]int{
+				model.IMAGE_SIZE_THUMBNAIL: DEFAULT_THUMBNAIL_SIZE,
+				model.IMAGE_SIZE_MEDIUM:    DEFAULT_MEDIUM_SIZE,
+				model.IMAGE_SIZE_LARGE:     DEFAULT_LARGE_SIZE,
+			},
+			wantJPEGQuality: DEFAULT_JPEG_QUALITY,
+		},
+		{
+			name: "only_missing_size_use_default",
+			conf: config.ImageDerivative{Medium: 640, Large: -1, JPEGQuality: 101},
+			wantSizes: map[string]int{
+				model.IMAGE_SIZE_THUMBNAIL: DEFAULT_THUMBNAIL_SIZE,
+				model.IMAGE_SIZE_MEDIUM:    640,
+				model.IMAGE_SIZE_LARGE:     DEFAULT_LARGE_SIZE,
+			},
+			wantJPEGQuality: DEFAULT_JPEG_QUALITY,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewDerivativeGenerator(storage.NewLocalImageStorage(t.TempDir()), tt.conf)
+
+			if !reflect.DeepEqual(got.sizes, tt.wantSizes) {
+				t.Errorf("NewDerivativeGenerator() sizes = %v, want %v", got.sizes, tt.wantSizes)
+			}
+			if got.jpegQuality != tt.wantJPEGQuality {
+				t.Errorf("NewDerivativeGenerator() jpeg quality = %v, want %v", got.jpegQuality, tt.wantJPEGQuality)
+			}
+		})
+	}
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestDerivativeGenerator_Generate
+// ======================================================================
+func TestDerivativeGenerator_Generate(t *testing.T) {
+
+	type derivativeSize struct {
+		width  int
+		height int
+	}
+
+	tests := []struct {
+		name       string
+		imageName  string
+		format     string
+		width      int
+		height     int
+		wantSizes  map[string]derivativeSize
+		wantFormat string
+		wantErr    error
+	}{
+		{
+			name:      "success_png_keep_aspect_ratio",
+			imageName: "spider.png",
+			format:    "png",
+			width:     400,
+			height:    300,
+			wantSizes: map[string]derivativeSize{
+				model.IMAGE_SIZE_THUMBNAIL: {100, 75},
+				model.IMAGE_SIZE_MEDIUM:    {200, 150},
+				// original is smaller than large size so it is not upscaled
+				model.IMAGE_SIZE_LARGE: {400, 300},
+			},
+			wantFormat: "png",
+			wantErr:    nil,
+		},
+		{
+			name:      "success_jpeg_portrait",
+			imageName: "spider.jpeg",
+			format:    "jpeg",
+			width:     300,
+			height:    1200,
+			wantSizes: map[string]derivativeSize{
+				model.IMAGE_SIZE_THUMBNAIL: {25, 100},
+				model.IMAGE_SIZE_MEDIUM:    {50, 200},
+				model.IMAGE_SIZE_LARGE:     {250, 1000},
+			},
+			wantFormat: "jpeg",
+			wantErr:    nil,
+		},
+		{
+			name:      "image_not_found",
+			imageName: "missing.png",
+			wantErr:   storage.ErrorImageNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imageStorage := storage.NewLocalImageStorage(t.TempDir())
+			if tt.format != "" {
+				saveUnittestImage(t, imageStorage, tt.imageName, tt.format, tt.width, tt.height)
+			}
+
+			g := NewDerivativeGenerator(imageStorage, unittestDerivativeConfig)
+			if err := g.Generate(context.TODO(), tt.imageName); err != tt.wantErr {
+				t.Errorf("DerivativeGenerator.Generate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			for size, want := range tt.wantSizes {
+				content, _, err := imageStorage.Open(context.TODO(), storage.DerivativeImageName(tt.imageName, size))
+				if err != nil {
+					t.Errorf("open %v derivative error: %v", size, err)
+					continue
+				}
+
+				imageConfig, format, err := image.DecodeConfig(content)
+				content.Close()
+				if err != nil {
+					t.Errorf("decode %v derivative error: %v", size, err)
+					continue
+				}
+
+				if format != tt.wantFormat || imageConfig.Width != want.width || imageConfig.Height != want.height {
+					t.Errorf("%v derivative = %v %vx%v, want %v %vx%v", size, format, imageConfig.Width, imageConfig.Height, tt.wantFormat, want.width, want.height)
+				}
+			}
+		})
+	}
+}
+
+// content that is not image is not saved as derivative
+func TestDerivativeGenerator_GenerateInvalidImage(t *testing.T) {
+	imageStorage := storage.NewLocalImageStorage(t.TempDir())
+	if err := imageStorage.Save(context.TODO(), "spider.png", strings.NewReader("not image"), 9, "image/png"); err != nil {
+		t.Fatalf("save image error: %v", err)
+	}
+
+	g := NewDerivativeGenerator(imageStorage, unittestDerivativeConfig)
+	if err := g.Generate(context.TODO(), "spider.png"); err != ErrorDecodeImage {
+		t.Errorf("DerivativeGenerator.Generate() error = %v, wantErr %v", err, ErrorDecodeImage)
+	}
+
+	if exists, err := g.Exists(context.TODO(), "spider.png"); err != nil || exists {
+		t.Errorf("DerivativeGenerator.Exists() = %v, error = %v, want false", exists, err)
+	}
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestDerivativeGenerator_Exists
+// ======================================================================
+
+// image has derivative only when every size is generated, so backfill generate image that miss any size
+func TestDerivativeGenerator_Exists(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		generated []string
+		want      bool
+	}{
+		{name: "every_size_generated", generated: model.IMAGE_DERIVATIVE_SIZES, want: true},
+		{name: "no_size_generated", generated: nil, want: false},
+		{name: "large_size_missing", generated: []string{model.IMAGE_SIZE_THUMBNAIL, model.IMAGE_SIZE_MEDIUM}, want: false},
+		{name: "thumbnail_size_missing", generated: []string{model.IMAGE_SIZE_MEDIUM, model.IMAGE_SIZE_LARGE}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imageStorage := storage.NewLocalImageStorage(t.TempDir())
+			saveUnittestImage(t, imageStorage, "spider.png", "png", 10, 10)
+			for _, size := range tt.generated {
+				saveUnittestImage(t, imageStorage, storage.DerivativeImageName("spider.png", size), "png", 10, 10)
+			}
+
+			g := NewDerivativeGenerator(imageStorage, unittestDerivativeConfig)
+			got, err := g.Exists(context.TODO(), "spider.png")
+			if err != nil {
+				t.Errorf("DerivativeGenerator.Exists() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("DerivativeGenerator.Exists() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// **********************************************************************
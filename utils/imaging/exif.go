@@ -0,0 +1,366 @@
+package imaging
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"spider-go/model"
+	"strings"
+	"time"
+)
+
+const (
+	EXIF_DATE_TIME_FORMAT = "2006:01:02 15:04:05"
+	EXIF_OFFSET_FORMAT    = "-07:00"
+)
+
+// tag of ifd0, exif ifd and gps ifd that is read
+const (
+	exifTagMake               = 0x010f
+	exifTagModel              = 0x0110
+	exifTagExifIFD            = 0x8769
+	exifTagGPSIFD             = 0x8825
+	exifTagDateTimeOriginal   = 0x9003
+	exifTagOffsetTimeOriginal = 0x9011
+	exifTagGPSLatitudeRef     = 0x0001
+	exifTagGPSLatitude        = 0x0002
+	exifTagGPSLongitudeRef    = 0x0003
+	exifTagGPSLongitude       = 0x0004
+	exifTagGPSAltitudeRef     = 0x0005
+	exifTagGPSAltitude        = 0x0006
+)
+
+// type of ifd entry that is read
+const (
+	exifTypeByte      = 1
+	exifTypeASCII     = 2
+	exifTypeShort     = 3
+	exifTypeLong      = 4
+	exifTypeRational  = 5
+	exifTypeUndefined = 7
+)
+
+var exifTypeSize = map[uint16]uint64{
+	exifTypeByte:      1,
+	exifTypeASCII:     1,
+	exifTypeShort:     2,
+	exifTypeLong:      4,
+	exifTypeRational:  8,
+	exifTypeUndefined: 1,
+}
+
+var exifHeader = []byte("Exif\x00\x00")
+
+var (
+	ErrorExifNotFound = errors.New("[imaging] exif not found")
+	ErrorInvalidExif  = errors.New("[imaging] invalid exif")
+)
+
+// ReadExif read taken time, gps position and camera from exif of jpeg, only segment before image data is read.
+// time without offset is camera clock and it is read in local time zone, image name of result is empty
+func ReadExif(content io.Reader) (*model.ImageExif, error) {
+	segment, err := readExifSegment(bufio.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	exif, err := parseExif(segment)
+	if err != nil {
+		return nil, err
+	}
+
+	if exif.TakenAt == nil && !exif.HasPosition() && exif.Altitude == nil && exif.CameraMake == "" && exif.CameraModel == "" {
+		return nil, ErrorExifNotFound
+	}
+
+	return exif, nil
+}
+
+// readExifSegment return tiff data of app1 exif segment of jpeg
+func readExifSegment(reader *bufio.Reader) ([]byte, error) {
+	var soi [2]byte
+	if _, err := io.ReadFull(reader, soi[:]); err != nil || soi != [2]byte{0xff, 0xd8} {
+		return nil, ErrorExifNotFound
+	}
+
+	for {
+		b, err := reader.ReadByte()
+		if err != nil || b != 0xff {
+			return nil, ErrorExifNotFound
+		}
+
+		// marker can be padded with fill bytes
+		marker := b
+		for marker == 0xff {
+			if marker, err = reader.ReadByte(); err != nil {
+				return nil, ErrorExifNotFound
+			}
+		}
+
+		switch {
+		// start of scan and end of image, exif is always before them
+		case marker == 0xda || marker == 0xd9:
+			return nil, ErrorExifNotFound
+		// marker without length
+		case marker == 0x01 || (marker >= 0xd0 && marker <= 0xd7):
+			continue
+		}
+
+		var length [2]byte
+		if _, err := io.ReadFull(reader, length[:]); err != nil {
+			return nil, ErrorExifNotFound
+		}
+
+		// length include its two bytes
+		size := int(binary.BigEndian.Uint16(length[:])) - 2
+		if size < 0 {
+			return nil, ErrorInvalidExif
+		}
+
+		if marker != 0xe1 {
+			if _, err := reader.Discard(size); err != nil {
+				return nil, ErrorExifNotFound
+			}
+			continue
+		}
+
+		data := make([]byte, size)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, ErrorInvalidExif
+		}
+
+		// app1 is also used by xmp
+		if bytes.HasPrefix(data, exifHeader) {
+			return data[len(exifHeader):], nil
+		}
+	}
+}
+
+type exifEntry struct {
+	dataType uint16
+	count    uint32
+	value    []byte
+}
+
+type exifReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+func parseExif(data []byte) (*model.ImageExif, error) {
+	if len(data) < 8 {
+		return nil, ErrorInvalidExif
+	}
+
+	reader := &exifReader{data: data}
+
+	switch string(data[:2]) {
+	case "II":
+		reader.order = binary.LittleEndian
+	case "MM":
+		reader.order = binary.BigEndian
+	default:
+		return nil, ErrorInvalidExif
+	}
+
+	if reader.order.Uint16(data[2:]) != 42 {
+		return nil, ErrorInvalidExif
+	}
+
+	ifd0, err := reader.readIFD(reader.order.Uint32(data[4:]))
+	if err != nil {
+		return nil, err
+	}
+
+	exif := &model.ImageExif{
+		CameraMake:  reader.asciiValue(ifd0[exifTagMake]),
+		CameraModel: reader.asciiValue(ifd0[exifTagModel]),
+	}
+
+	if offset, ok := reader.uintValue(ifd0[exifTagExifIFD]); ok {
+		exifIFD, err := reader.readIFD(offset)
+		if err != nil {
+			return nil, err
+		}
+		exif.TakenAt = reader.takenAt(exifIFD)
+	}
+
+	if offset, ok := reader.uintValue(ifd0[exifTagGPSIFD]); ok {
+		gpsIFD, err := reader.readIFD(offset)
+		if err != nil {
+			return nil, err
+		}
+
+		latitude, latitudeOK := reader.degrees(gpsIFD[exifTagGPSLatitude], gpsIFD[exifTagGPSLatitudeRef], "S", 90)
+		longitude, longitudeOK := reader.degrees(gpsIFD[exifTagGPSLongitude], gpsIFD[exifTagGPSLongitudeRef], "W", 180)
+
+		// position is used only when both of them are recorded
+		if latitudeOK && longitudeOK {
+			exif.Latitude = &latitude
+			exif.Longitude = &longitude
+		}
+
+		exif.Altitude = reader.altitude(gpsIFD[exifTagGPSAltitude], gpsIFD[exifTagGPSAltitudeRef])
+	}
+
+	return exif, nil
+}
+
+// readIFD read entries of ifd, entry of unknown type or value outside of data is skipped
+func (r *exifReader) readIFD(offset uint32) (map[uint16]exifEntry, error) {
+	start := uint64(offset)
+	if start+2 > uint64(len(r.data)) {
+		return nil, ErrorInvalidExif
+	}
+
+	count := uint64(r.order.Uint16(r.data[start:]))
+	start += 2
+
+	if start+count*12 > uint64(len(r.data)) {
+		return nil, ErrorInvalidExif
+	}
+
+	entries := make(map[uint16]exifEntry, count)
+
+	for i := uint64(0); i < count; i++ {
+		raw := r.data[start+i*12 : start+i*12+12]
+
+		entry := exifEntry{
+			dataType: r.order.Uint16(raw[2:]),
+			count:    r.order.Uint32(raw[4:]),
+		}
+
+		typeSize, ok := exifTypeSize[entry.dataType]
+		if !ok {
+			continue
+		}
+
+		// value that fit in four bytes is kept in entry, otherwise entry keep offset of value
+		size := typeSize * uint64(entry.count)
+		if size <= 4 {
+			entry.value = raw[8 : 8+size]
+		} else {
+			valueOffset := uint64(r.order.Uint32(raw[8:]))
+			if valueOffset+size > uint64(len(r.data)) {
+				continue
+			}
+			entry.value = r.data[valueOffset : valueOffset+size]
+		}
+
+		entries[r.order.Uint16(raw)] = entry
+	}
+
+	return entries, nil
+}
+
+func (r *exifReader) asciiValue(entry exifEntry) string {
+	if entry.dataType != exifTypeASCII {
+		return ""
+	}
+
+	value := string(entry.value)
+	if i := strings.IndexByte(value, 0); i >= 0 {
+		value = value[:i]
+	}
+
+	return strings.TrimSpace(value)
+}
+
+func (r *exifReader) uintValue(entry exifEntry) (uint32, bool) {
+	if entry.count < 1 {
+		return 0, false
+	}
+
+	switch entry.dataType {
+	case exifTypeShort:
+		return uint32(r.order.Uint16(entry.value)), true
+	case exifTypeLong:
+		return r.order.Uint32(entry.value), true
+	default:
+		return 0, false
+	}
+}
+
+// rationalValues return false when any denominator is zero, camera without gps fix write 0/0
+func (r *exifReader) rationalValues(entry exifEntry) ([]float64, bool) {
+	if entry.dataType != exifTypeRational || entry.count < 1 {
+		return nil, false
+	}
+
+	values := make([]float64, entry.count)
+	for i := range values {
+		numerator := r.order.Uint32(entry.value[i*8:])
+		denominator := r.order.Uint32(entry.value[i*8+4:])
+		if denominator == 0 {
+			return nil, false
+		}
+		values[i] = float64(numerator) / float64(denominator)
+	}
+
+	return values, true
+}
+
+// degrees convert degrees, minutes and seconds rational to decimal degrees, negative ref is south or west
+func (r *exifReader) degrees(value, ref exifEntry, negativeRef string, limit float64) (float64, bool) {
+	dms, ok := r.rationalValues(value)
+	if !ok {
+		return 0, false
+	}
+
+	var degrees float64
+	for i, divisor := range []float64{1, 60, 3600} {
+		if i < len(dms) {
+			degrees += dms[i] / divisor
+		}
+	}
+
+	if degrees > limit {
+		return 0, false
+	}
+
+	if strings.EqualFold(r.asciiValue(ref), negativeRef) {
+		degrees = -degrees
+	}
+
+	return degrees, true
+}
+
+// altitude is meter from sea level, ref 1 is below sea level
+func (r *exifReader) altitude(value, ref exifEntry) *float64 {
+	values, ok := r.rationalValues(value)
+	if !ok {
+		return nil
+	}
+
+	altitude := values[0]
+	if (ref.dataType == exifTypeByte || ref.dataType == exifTypeUndefined) && len(ref.value) > 0 && ref.value[0] == 1 {
+		altitude = -altitude
+	}
+
+	return &altitude
+}
+
+func (r *exifReader) takenAt(exifIFD map[uint16]exifEntry) *time.Time {
+	dateTime := r.asciiValue(exifIFD[exifTagDateTimeOriginal])
+	if dateTime == "" {
+		return nil
+	}
+
+	var takenAt time.Time
+	var err error
+
+	if offset := r.asciiValue(exifIFD[exifTagOffsetTimeOriginal]); offset != "" {
+		takenAt, err = time.Parse(EXIF_DATE_TIME_FORMAT+EXIF_OFFSET_FORMAT, dateTime+offset)
+	} else {
+		takenAt, err = time.ParseInLocation(EXIF_DATE_TIME_FORMAT, dateTime, time.Local)
+	}
+
+	// camera without clock write blank or zero date
+	if err != nil {
+		return nil
+	}
+
+	return &takenAt
+}
@@ -0,0 +1,308 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"spider-go/model"
+	"testing"
+	"time"
+)
+
+// fixtures in testdata is jpeg that is encoded by image/jpeg with app1 exif segment,
+// tiff data of exif_little_endian.jpg start after soi, app1 marker, length and exif header
+const unittestTiffStart = 2 + 4 + 6
+
+// offset in tiff data of exif_little_endian.jpg, ifd0 has make, model, exif ifd and gps ifd entry,
+// exif ifd has two entry and gps ifd is after it, value of entry is at 8 bytes after start of entry
+const (
+	unittestIFD0OffsetAt   = 4
+	unittestIFD0At         = 8
+	unittestExifIFDAt      = unittestIFD0At + 2 + 4*12 + 4
+	unittestGPSIFDAt       = unittestExifIFDAt + 2 + 2*12 + 4
+	unittestMakeValueAt    = unittestIFD0At + 2 + 8
+	unittestExifIFDValueAt = unittestIFD0At + 2 + 2*12 + 8
+	unittestGPSIFDValueAt  = unittestIFD0At + 2 + 3*12 + 8
+	unittestLatitudeAt     = unittestGPSIFDAt + 2 + 12
+)
+
+func readUnittestFixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("read fixture `%v` error: %v", name, err)
+	}
+
+	return data
+}
+
+// mutateTiff return copy of exif_little_endian.jpg that uint32 at offset of tiff data is replaced
+func mutateTiff(t *testing.T, offset int, value uint32) []byte {
+	data := readUnittestFixture(t, "exif_little_endian.jpg")
+	binary.LittleEndian.PutUint32(data[unittestTiffStart+offset:], value)
+	return data
+}
+
+func float64Pointer(value float64) *float64 {
+	return &value
+}
+
+func timePointer(value time.Time) *time.Time {
+	return &value
+}
+
+func equalFloat64Pointer(got, want *float64) bool {
+	if got == nil || want == nil {
+		return got == want
+	}
+	return math.Abs(*got-*want) < 1e-9
+}
+
+func equalImageExif(got, want *model.ImageExif) bool {
+	if got == nil || want == nil {
+		return got == want
+	}
+
+	if (got.TakenAt == nil) != (want.TakenAt == nil) || (got.TakenAt != nil && !got.TakenAt.Equal(*want.TakenAt)) {
+		return false
+	}
+
+	return got.ImageName == want.ImageName &&
+		equalFloat64Pointer(got.Latitude, want.Latitude) &&
+		equalFloat64Pointer(got.Longitude, want.Longitude) &&
+		equalFloat64Pointer(got.Altitude, want.Altitude) &&
+		got.CameraMake == want.CameraMake &&
+		got.CameraModel == want.CameraModel
+}
+
+// ======================================================================
+// TestReadExif
+// ======================================================================
+func TestReadExif(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		content func(*testing.T) []byte
+		want    *model.ImageExif
+		wantErr error
+	}{
+		{
+			name: "little_endian_with_offset_and_gps",
+			content: func(t *testing.T) []byte {
+				return readUnittestFixture(t, "exif_little_endian.jpg")
+			},
+			want: &model.ImageExif{
+				TakenAt:     timePointer(time.Date(2023, 3, 4, 19, 44, 22, 0, time.FixedZone("", 7*60*60))),
+				Latitude:    float64Pointer(18 + 35.0/60 + 20.4/3600),
+				Longitude:   float64Pointer(98 + 29.0/60 + 13.2/3600),
+				Altitude:    float64Pointer(1750),
+				CameraMake:  "Canon",
+				CameraModel: "EOS 80D",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "big_endian_south_west_below_sea_level_after_xmp",
+			content: func(t *testing.T) []byte {
+				return readUnittestFixture(t, "exif_big_endian_after_xmp.jpg")
+			},
+			want: &model.ImageExif{
+				TakenAt:    timePointer(time.Date(2022, 12, 31, 23, 59, 59, 0, time.Local)),
+				Latitude:   float64Pointer(-(33 + 52.0/60)),
+				Longitude:  float64Pointer(-70.5),
+				Altitude:   float64Pointer(-12.5),
+				CameraMake: "Sony",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "gps_without_fix_is_skipped",
+			content: func(t *testing.T) []byte {
+				return readUnittestFixture(t, "exif_gps_without_fix.jpg")
+			},
+			want: &model.ImageExif{
+				CameraModel: "EOS 80D",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "jpeg_without_exif",
+			content: func(t *testing.T) []byte {
+				return readUnittestFixture(t, "without_exif.jpg")
+			},
+			want:    nil,
+			wantErr: ErrorExifNotFound,
+		},
+		{
+			name: "not_jpeg",
+			content: func(t *testing.T) []byte {
+				return []byte("\x89PNG\r\n\x1a\n")
+			},
+			want:    nil,
+			wantErr: ErrorExifNotFound,
+		},
+		{
+			name: "empty_content",
+			content: func(t *testing.T) []byte {
+				return nil
+			},
+			want:    nil,
+			wantErr: ErrorExifNotFound,
+		},
+		{
+			name: "truncated_in_exif_segment",
+			content: func(t *testing.T) []byte {
+				return readUnittestFixture(t, "exif_little_endian.jpg")[:unittestTiffStart+40]
+			},
+			want:    nil,
+			wantErr: ErrorInvalidExif,
+		},
+		{
+			name: "truncated_in_segment_length",
+			content: func(t *testing.T) []byte {
+				return readUnittestFixture(t, "exif_little_endian.jpg")[:5]
+			},
+			want:    nil,
+			wantErr: ErrorExifNotFound,
+		},
+		{
+			name: "invalid_byte_order",
+			content: func(t *testing.T) []byte {
+				data := readUnittestFixture(t, "exif_little_endian.jpg")
+				copy(data[unittestTiffStart:], "XX")
+				return data
+			},
+			want:    nil,
+			wantErr: ErrorInvalidExif,
+		},
+		{
+			name: "ifd0_offset_out_of_bounds",
+			content: func(t *testing.T) []byte {
+				return mutateTiff(t, unittestIFD0OffsetAt, math.MaxUint32)
+			},
+			want:    nil,
+			wantErr: ErrorInvalidExif,
+		},
+		{
+			name: "ifd0_entry_count_out_of_bounds",
+			content: func(t *testing.T) []byte {
+				data := readUnittestFixture(t, "exif_little_endian.jpg")
+				binary.LittleEndian.PutUint16(data[unittestTiffStart+unittestIFD0At:], math.MaxUint16)
+				return data
+			},
+			want:    nil,
+			wantErr: ErrorInvalidExif,
+		},
+		{
+			name: "exif_ifd_offset_out_of_bounds",
+			content: func(t *testing.T) []byte {
+				return mutateTiff(t, unittestExifIFDValueAt, math.MaxUint32-1)
+			},
+			want:    nil,
+			wantErr: ErrorInvalidExif,
+		},
+		{
+			name: "value_offset_out_of_bounds_is_skipped",
+			content: func(t *testing.T) []byte {
+				return mutateTiff(t, unittestMakeValueAt, math.MaxUint32)
+			},
+			want: &model.ImageExif{
+				TakenAt:     timePointer(time.Date(2023, 3, 4, 19, 44, 22, 0, time.FixedZone("", 7*60*60))),
+				Latitude:    float64Pointer(18 + 35.0/60 + 20.4/3600),
+				Longitude:   float64Pointer(98 + 29.0/60 + 13.2/3600),
+				Altitude:    float64Pointer(1750),
+				CameraModel: "EOS 80D",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "value_count_out_of_bounds_is_skipped",
+			content: func(t *testing.T) []byte {
+				// count is after tag and type of entry
+				return mutateTiff(t, unittestLatitudeAt+4, math.MaxUint32)
+			},
+			want: &model.ImageExif{
+				TakenAt:     timePointer(time.Date(2023, 3, 4, 19, 44, 22, 0, time.FixedZone("", 7*60*60))),
+				Altitude:    float64Pointer(1750),
+				CameraMake:  "Canon",
+				CameraModel: "EOS 80D",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "exif_ifd_loop_back_to_ifd0",
+			content: func(t *testing.T) []byte {
+				return mutateTiff(t, unittestExifIFDValueAt, unittestIFD0At)
+			},
+			want: &model.ImageExif{
+				Latitude:    float64Pointer(18 + 35.0/60 + 20.4/3600),
+				Longitude:   float64Pointer(98 + 29.0/60 + 13.2/3600),
+				Altitude:    float64Pointer(1750),
+				CameraMake:  "Canon",
+				CameraModel: "EOS 80D",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "gps_ifd_loop_back_to_ifd0",
+			content: func(t *testing.T) []byte {
+				return mutateTiff(t, unittestGPSIFDValueAt, unittestIFD0At)
+			},
+			want: &model.ImageExif{
+				TakenAt:     timePointer(time.Date(2023, 3, 4, 19, 44, 22, 0, time.FixedZone("", 7*60*60))),
+				CameraMake:  "Canon",
+				CameraModel: "EOS 80D",
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadExif(bytes.NewReader(tt.content(t)))
+			if err != tt.wantErr {
+				t.Errorf("ReadExif() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !equalImageExif(got, tt.want) {
+				t.Errorf("ReadExif() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestReadExif_Truncated
+// ======================================================================
+
+// every prefix of image is read without panic, exif is found only when exif segment is complete
+func TestReadExif_Truncated(t *testing.T) {
+
+	for _, name := range []string{"exif_little_endian.jpg", "exif_big_endian_after_xmp.jpg", "exif_gps_without_fix.jpg"} {
+		t.Run(name, func(t *testing.T) {
+			data := readUnittestFixture(t, name)
+
+			var wantExif *model.ImageExif
+			var err error
+			if wantExif, err = ReadExif(bytes.NewReader(data)); err != nil {
+				t.Fatalf("ReadExif() of complete image error = %v", err)
+			}
+
+			for size := 0; size < len(data); size++ {
+				got, err := ReadExif(bytes.NewReader(data[:size]))
+				if err != nil && err != ErrorExifNotFound && err != ErrorInvalidExif {
+					t.Errorf("ReadExif() of %v bytes error = %v", size, err)
+				}
+				if err == nil && !equalImageExif(got, wantExif) {
+					t.Errorf("ReadExif() of %v bytes = %+v, want %+v", size, got, wantExif)
+				}
+			}
+		})
+	}
+}
+
+// **********************************************************************
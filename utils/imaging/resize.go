@@ -0,0 +1,90 @@
+package imaging
+
+import (
+	"image"
+	"image/draw"
+)
+
+// Fit return image that longest side is not longer than maxSize, image is downscaled by
+// average of source pixels that each pixel cover, image that is already small is not upscaled
+func Fit(src image.Image, maxSize int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if maxSize <= 0 || (width <= maxSize && height <= maxSize) {
+		return src
+	}
+
+	dstWidth, dstHeight := maxSize, maxSize
+	if width >= height {
+		dstHeight = height * maxSize / width
+	} else {
+		dstWidth = width * maxSize / height
+	}
+
+	if dstWidth < 1 {
+		dstWidth = 1
+	}
+	if dstHeight < 1 {
+		dstHeight = 1
+	}
+
+	return resizeArea(toRGBA(src), dstWidth, dstHeight)
+}
+
+// toRGBA convert image to premultiplied rgba so transparent pixel does not darken average
+func toRGBA(src image.Image) *image.RGBA {
+	if rgba, ok := src.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
+
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+
+	return dst
+}
+
+func resizeArea(src *image.RGBA, dstWidth, dstHeight int) *image.RGBA {
+	srcWidth, srcHeight := src.Rect.Dx(), src.Rect.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < dstHeight; y++ {
+		y0, y1 := areaRange(y, srcHeight, dstHeight)
+
+		for x := 0; x < dstWidth; x++ {
+			x0, x1 := areaRange(x, srcWidth, dstWidth)
+
+			var r, g, b, a uint64
+			for sy := y0; sy < y1; sy++ {
+				i := sy*src.Stride + x0*4
+				for sx := x0; sx < x1; sx++ {
+					r += uint64(src.Pix[i])
+					g += uint64(src.Pix[i+1])
+					b += uint64(src.Pix[i+2])
+					a += uint64(src.Pix[i+3])
+					i += 4
+				}
+			}
+
+			count := uint64((y1 - y0) * (x1 - x0))
+			j := y*dst.Stride + x*4
+			dst.Pix[j] = uint8(r / count)
+			dst.Pix[j+1] = uint8(g / count)
+			dst.Pix[j+2] = uint8(b / count)
+			dst.Pix[j+3] = uint8(a / count)
+		}
+	}
+
+	return dst
+}
+
+// areaRange is source pixel range that destination pixel cover, it cover at least one pixel
+func areaRange(dst, srcLength, dstLength int) (int, int) {
+	start := dst * srcLength / dstLength
+	end := (dst + 1) * srcLength / dstLength
+	if end <= start {
+		end = start + 1
+	}
+	return start, end
+}
@@ -0,0 +1,114 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// ======================================================================
+// TestFit
+// ======================================================================
+func TestFit(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		src        image.Image
+		maxSize    int
+		wantWidth  int
+		wantHeight int
+		wantSame   bool
+	}{
+		{name: "landscape_longest_side_is_width", src: image.NewRGBA(image.Rect(0, 0, 400, 300)), maxSize: 100, wantWidth: 100, wantHeight: 75},
+		{name: "portrait_longest_side_is_height", src: image.NewRGBA(image.Rect(0, 0, 300, 400)), maxSize: 100, wantWidth: 75, wantHeight: 100},
+		{name: "square", src: image.NewRGBA(image.Rect(0, 0, 500, 500)), maxSize: 320, wantWidth: 320, wantHeight: 320},
+		{name: "ratio_is_rounded_down", src: image.NewRGBA(image.Rect(0, 0, 1000, 333)), maxSize: 100, wantWidth: 100, wantHeight: 33},
+		{name: "thin_image_keep_one_pixel", src: image.NewRGBA(image.Rect(0, 0, 1000, 2)), maxSize: 10, wantWidth: 10, wantHeight: 1},
+		{name: "bounds_not_start_at_zero", src: image.NewRGBA(image.Rect(50, 50, 450, 250)), maxSize: 100, wantWidth: 100, wantHeight: 50},
+		{name: "gray_image", src: image.NewGray(image.Rect(0, 0, 64, 32)), maxSize: 16, wantWidth: 16, wantHeight: 8},
+		{name: "small_image_is_not_upscaled", src: image.NewRGBA(image.Rect(0, 0, 80, 60)), maxSize: 100, wantWidth: 80, wantHeight: 60, wantSame: true},
+		{name: "image_equal_max_size", src: image.NewRGBA(image.Rect(0, 0, 100, 60)), maxSize: 100, wantWidth: 100, wantHeight: 60, wantSame: true},
+		{name: "zero_max_size_keep_image", src: image.NewRGBA(image.Rect(0, 0, 400, 300)), maxSize: 0, wantWidth: 400, wantHeight: 300, wantSame: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Fit(tt.src, tt.maxSize)
+
+			if got.Bounds().Dx() != tt.wantWidth || got.Bounds().Dy() != tt.wantHeight {
+				t.Errorf("Fit() size = %vx%v, want %vx%v", got.Bounds().Dx(), got.Bounds().Dy(), tt.wantWidth, tt.wantHeight)
+			}
+
+			if (got == tt.src) != tt.wantSame {
+				t.Errorf("Fit() return source image = %v, want %v", got == tt.src, tt.wantSame)
+			}
+		})
+	}
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestFit_Average
+// ======================================================================
+
+// pixel of resized image is average of source pixels that it cover
+func TestFit_Average(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		left  color.RGBA
+		right color.RGBA
+		want  color.RGBA
+	}{
+		{
+			name:  "black_and_white",
+			left:  color.RGBA{0, 0, 0, 255},
+			right: color.RGBA{255, 255, 255, 255},
+			want:  color.RGBA{127, 127, 127, 255},
+		},
+		{
+			name:  "same_color",
+			left:  color.RGBA{10, 200, 30, 255},
+			right: color.RGBA{10, 200, 30, 255},
+			want:  color.RGBA{10, 200, 30, 255},
+		},
+		{
+			// transparent pixel does not darken opaque pixel because color is premultiplied
+			name:  "opaque_and_transparent",
+			left:  color.RGBA{200, 100, 50, 255},
+			right: color.RGBA{0, 0, 0, 0},
+			want:  color.RGBA{100, 50, 25, 127},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+			for y := 0; y < 2; y++ {
+				for x := 0; x < 4; x++ {
+					if x < 2 {
+						src.SetRGBA(x, y, tt.left)
+					} else {
+						src.SetRGBA(x, y, tt.right)
+					}
+				}
+			}
+
+			got := Fit(src, 2)
+
+			// two source columns of same color become one pixel
+			if c := color.RGBAModel.Convert(got.At(0, 0)).(color.RGBA); c != tt.left {
+				t.Errorf("Fit() left pixel = %v, want %v", c, tt.left)
+			}
+			if c := color.RGBAModel.Convert(got.At(1, 0)).(color.RGBA); c != tt.right {
+				t.Errorf("Fit() right pixel = %v, want %v", c, tt.right)
+			}
+
+			// one pixel cover every source pixel
+			if c := color.RGBAModel.Convert(Fit(src, 1).At(0, 0)).(color.RGBA); c != tt.want {
+				t.Errorf("Fit() average pixel = %v, want %v", c, tt.want)
+			}
+		})
+	}
+}
+
+// **********************************************************************
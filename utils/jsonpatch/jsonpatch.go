@@ -0,0 +1,360 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidOperation = errors.New("jsonpatch: invalid operation")
+	ErrInvalidPath      = errors.New("jsonpatch: invalid path")
+	ErrTestFailed       = errors.New("jsonpatch: test operation failed")
+)
+
+const (
+	OP_ADD     = "add"
+	OP_REMOVE  = "remove"
+	OP_REPLACE = "replace"
+	OP_MOVE    = "move"
+	OP_COPY    = "copy"
+	OP_TEST    = "test"
+)
+
+// Operation is operation of JSON Patch (RFC 6902)
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// MergePatch apply JSON Merge Patch (RFC 7386) to document
+func MergePatch(document, patch []byte) ([]byte, error) {
+	var doc, p interface{}
+
+	if err := json.Unmarshal(document, &doc); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(mergeValue(doc, p))
+}
+
+func mergeValue(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergeValue(targetObject[key], value)
+	}
+
+	return targetObject
+}
+
+// Apply apply JSON Patch (RFC 6902) to document, document is not changed when any operation fail
+func Apply(document []byte, operations []Operation) ([]byte, error) {
+	var doc interface{}
+
+	if err := json.Unmarshal(document, &doc); err != nil {
+		return nil, err
+	}
+
+	for i, operation := range operations {
+		var err error
+
+		doc, err = applyOperation(doc, operation)
+		if err != nil {
+			return nil, fmt.Errorf("%w at operation %d (%s %s)", err, i, operation.Op, operation.Path)
+		}
+	}
+
+	return json.Marshal(doc)
+}
+
+func applyOperation(doc interface{}, operation Operation) (interface{}, error) {
+	path, err := parsePointer(operation.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation.Op {
+	case OP_ADD, OP_REPLACE, OP_TEST:
+		var value interface{}
+		if len(operation.Value) == 0 {
+			return nil, ErrInvalidOperation
+		}
+		if err := json.Unmarshal(operation.Value, &value); err != nil {
+			return nil, ErrInvalidOperation
+		}
+
+		switch operation.Op {
+		case OP_ADD:
+			return add(doc, path, value)
+		case OP_REPLACE:
+			return replace(doc, path, value)
+		default:
+			current, err := get(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, ErrTestFailed
+			}
+			return doc, nil
+		}
+
+	case OP_REMOVE:
+		return remove(doc, path)
+
+	case OP_MOVE, OP_COPY:
+		from, err := parsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+
+		if operation.Op == OP_MOVE {
+			// value can not be moved into own child
+			if isPrefix(from, path) && len(from) < len(path) {
+				return nil, ErrInvalidPath
+			}
+			if doc, err = remove(doc, from); err != nil {
+				return nil, err
+			}
+		} else if value, err = deepCopy(value); err != nil {
+			return nil, err
+		}
+
+		return add(doc, path, value)
+
+	default:
+		return nil, ErrInvalidOperation
+	}
+}
+
+// *************************************************
+
+// parsePointer split JSON Pointer (RFC 6901) to reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, ErrInvalidPath
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+
+	// leading zero is not allowed by RFC 6901
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, ErrInvalidPath
+	}
+
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 {
+		return 0, ErrInvalidPath
+	}
+
+	if index > length || (index == length && !allowEnd) {
+		return 0, ErrInvalidPath
+	}
+
+	return index, nil
+}
+
+func get(doc interface{}, path []string) (interface{}, error) {
+	node := doc
+
+	for _, token := range path {
+		switch container := node.(type) {
+		case map[string]interface{}:
+			value, ok := container[token]
+			if !ok {
+				return nil, ErrInvalidPath
+			}
+			node = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(container), false)
+			if err != nil {
+				return nil, err
+			}
+			node = container[index]
+		default:
+			return nil, ErrInvalidPath
+		}
+	}
+
+	return node, nil
+}
+
+// update call fn with parent container of path and return document with replaced container
+func update(node interface{}, path []string, fn func(container interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return fn(node, path[0])
+	}
+
+	switch container := node.(type) {
+	case map[string]interface{}:
+		child, ok := container[path[0]]
+		if !ok {
+			return nil, ErrInvalidPath
+		}
+		child, err := update(child, path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		container[path[0]] = child
+		return container, nil
+
+	case []interface{}:
+		index, err := arrayIndex(path[0], len(container), false)
+		if err != nil {
+			return nil, err
+		}
+		child, err := update(container[index], path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		container[index] = child
+		return container, nil
+
+	default:
+		return nil, ErrInvalidPath
+	}
+}
+
+func add(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return update(doc, path, func(node interface{}, token string) (interface{}, error) {
+		switch container := node.(type) {
+		case map[string]interface{}:
+			container[token] = value
+			return container, nil
+		case []interface{}:
+			index, err := arrayIndex(token, len(container), true)
+			if err != nil {
+				return nil, err
+			}
+			container = append(container, nil)
+			copy(container[index+1:], container[index:])
+			container[index] = value
+			return container, nil
+		default:
+			return nil, ErrInvalidPath
+		}
+	})
+}
+
+func replace(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return update(doc, path, func(node interface{}, token string) (interface{}, error) {
+		switch container := node.(type) {
+		case map[string]interface{}:
+			if _, ok := container[token]; !ok {
+				return nil, ErrInvalidPath
+			}
+			container[token] = value
+			return container, nil
+		case []interface{}:
+			index, err := arrayIndex(token, len(container), false)
+			if err != nil {
+				return nil, err
+			}
+			container[index] = value
+			return container, nil
+		default:
+			return nil, ErrInvalidPath
+		}
+	})
+}
+
+func remove(doc interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, ErrInvalidPath
+	}
+
+	return update(doc, path, func(node interface{}, token string) (interface{}, error) {
+		switch container := node.(type) {
+		case map[string]interface{}:
+			if _, ok := container[token]; !ok {
+				return nil, ErrInvalidPath
+			}
+			delete(container, token)
+			return container, nil
+		case []interface{}:
+			index, err := arrayIndex(token, len(container), false)
+			if err != nil {
+				return nil, err
+			}
+			return append(container[:index], container[index+1:]...), nil
+		default:
+			return nil, ErrInvalidPath
+		}
+	})
+}
+
+func deepCopy(value interface{}) (interface{}, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var copied interface{}
+	if err := json.Unmarshal(raw, &copied); err != nil {
+		return nil, err
+	}
+
+	return copied, nil
+}
@@ -0,0 +1,316 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// equalJSON compare json by value so order of object member does not matter
+func equalJSON(t *testing.T, got, want []byte) bool {
+	t.Helper()
+
+	var gotValue, wantValue interface{}
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("unmarshal result `%s` error: %v", got, err)
+	}
+	if err := json.Unmarshal(want, &wantValue); err != nil {
+		t.Fatalf("unmarshal want `%s` error: %v", want, err)
+	}
+
+	return reflect.DeepEqual(gotValue, wantValue)
+}
+
+// ======================================================================
+// TestApply
+// ======================================================================
+
+// case that start with `rfc6902_` is example of appendix A of RFC 6902
+func TestApply(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		document string
+		patch    string
+		want     string
+		wantErr  error
+	}{
+		{
+			name:     "rfc6902_a1_add_object_member",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/baz", "value": "qux"}]`,
+			want:     `{"baz": "qux", "foo": "bar"}`,
+		},
+		{
+			name:     "rfc6902_a2_add_array_element",
+			document: `{"foo": ["bar", "baz"]}`,
+			patch:    `[{"op": "add", "path": "/foo/1", "value": "qux"}]`,
+			want:     `{"foo": ["bar", "qux", "baz"]}`,
+		},
+		{
+			name:     "rfc6902_a3_remove_object_member",
+			document: `{"baz": "qux", "foo": "bar"}`,
+			patch:    `[{"op": "remove", "path": "/baz"}]`,
+			want:     `{"foo": "bar"}`,
+		},
+		{
+			name:     "rfc6902_a4_remove_array_element",
+			document: `{"foo": ["bar", "qux", "baz"]}`,
+			patch:    `[{"op": "remove", "path": "/foo/1"}]`,
+			want:     `{"foo": ["bar", "baz"]}`,
+		},
+		{
+			name:     "rfc6902_a5_replace_value",
+			document: `{"baz": "qux", "foo": "bar"}`,
+			patch:    `[{"op": "replace", "path": "/baz", "value": "boo"}]`,
+			want:     `{"baz": "boo", "foo": "bar"}`,
+		},
+		{
+			name:     "rfc6902_a6_move_value",
+			document: `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			patch:    `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			want:     `{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`,
+		},
+		{
+			name:     "rfc6902_a7_move_array_element",
+			document: `{"foo": ["all", "grass", "cows", "eat"]}`,
+			patch:    `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`,
+			want:     `{"foo": ["all", "cows", "eat", "grass"]}`,
+		},
+		{
+			name:     "rfc6902_a8_test_success",
+			document: `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			patch:    `[{"op": "test", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/foo/1", "value": 2}]`,
+			want:     `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+		},
+		{
+			name:     "rfc6902_a9_test_failure",
+			document: `{"baz": "qux"}`,
+			patch:    `[{"op": "test", "path": "/baz", "value": "bar"}]`,
+			wantErr:  ErrTestFailed,
+		},
+		{
+			name:     "rfc6902_a10_add_nested_member",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`,
+			want:     `{"foo": "bar", "child": {"grandchild": {}}}`,
+		},
+		{
+			name:     "rfc6902_a11_ignore_unrecognized_element",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/baz", "value": "qux", "xyz": 123}]`,
+			want:     `{"foo": "bar", "baz": "qux"}`,
+		},
+		{
+			name:     "rfc6902_a12_add_to_nonexistent_target",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`,
+			wantErr:  ErrInvalidPath,
+		},
+		{
+			name:     "rfc6902_a14_escape_ordering",
+			document: `{"/": 9, "~1": 10}`,
+			patch:    `[{"op": "test", "path": "/~01", "value": 10}]`,
+			want:     `{"/": 9, "~1": 10}`,
+		},
+		{
+			name:     "rfc6902_a15_compare_string_and_number",
+			document: `{"/": 9, "~1": 10}`,
+			patch:    `[{"op": "test", "path": "/~01", "value": "10"}]`,
+			wantErr:  ErrTestFailed,
+		},
+		{
+			name:     "rfc6902_a16_add_array_value",
+			document: `{"foo": ["bar"]}`,
+			patch:    `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`,
+			want:     `{"foo": ["bar", ["abc", "def"]]}`,
+		},
+		{
+			name:     "escape_slash_and_tilde",
+			document: `{"a/b": 1, "c~d": 2}`,
+			patch:    `[{"op": "replace", "path": "/a~1b", "value": 3}, {"op": "remove", "path": "/c~0d"}]`,
+			want:     `{"a/b": 3}`,
+		},
+		{
+			name:     "move_into_own_child",
+			document: `{"a": {"b": {}}}`,
+			patch:    `[{"op": "move", "from": "/a", "path": "/a/b/c"}]`,
+			wantErr:  ErrInvalidPath,
+		},
+		{
+			name:     "move_to_same_path",
+			document: `{"a": {"b": 1}}`,
+			patch:    `[{"op": "move", "from": "/a", "path": "/a"}]`,
+			want:     `{"a": {"b": 1}}`,
+		},
+		{
+			name:     "copy_is_not_shared_with_source",
+			document: `{"a": {"b": 1}}`,
+			patch:    `[{"op": "copy", "from": "/a", "path": "/c"}, {"op": "replace", "path": "/c/b", "value": 2}]`,
+			want:     `{"a": {"b": 1}, "c": {"b": 2}}`,
+		},
+		{
+			name:     "end_of_array_only_for_add",
+			document: `{"foo": ["bar"]}`,
+			patch:    `[{"op": "remove", "path": "/foo/-"}]`,
+			wantErr:  ErrInvalidPath,
+		},
+		{
+			name:     "end_of_array_for_test",
+			document: `{"foo": ["bar"]}`,
+			patch:    `[{"op": "test", "path": "/foo/-", "value": "bar"}]`,
+			wantErr:  ErrInvalidPath,
+		},
+		{
+			name:     "array_index_with_leading_zero",
+			document: `{"foo": ["bar", "baz"]}`,
+			patch:    `[{"op": "replace", "path": "/foo/01", "value": "qux"}]`,
+			wantErr:  ErrInvalidPath,
+		},
+		{
+			name:     "array_index_out_of_range",
+			document: `{"foo": ["bar"]}`,
+			patch:    `[{"op": "add", "path": "/foo/2", "value": "qux"}]`,
+			wantErr:  ErrInvalidPath,
+		},
+		{
+			name:     "replace_missing_member",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "replace", "path": "/baz", "value": "qux"}]`,
+			wantErr:  ErrInvalidPath,
+		},
+		{
+			name:     "replace_whole_document",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "replace", "path": "", "value": ["qux"]}]`,
+			want:     `["qux"]`,
+		},
+		{
+			name:     "remove_whole_document",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "remove", "path": ""}]`,
+			wantErr:  ErrInvalidPath,
+		},
+		{
+			name:     "path_without_leading_slash",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "remove", "path": "foo"}]`,
+			wantErr:  ErrInvalidPath,
+		},
+		{
+			name:     "add_without_value",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/baz"}]`,
+			wantErr:  ErrInvalidOperation,
+		},
+		{
+			name:     "add_null_value",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/baz", "value": null}]`,
+			want:     `{"foo": "bar", "baz": null}`,
+		},
+		{
+			name:     "unknown_operation",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "merge", "path": "/foo", "value": "baz"}]`,
+			wantErr:  ErrInvalidOperation,
+		},
+		{
+			name:     "failed_operation_discard_previous_operation",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/baz", "value": "bar"}]`,
+			wantErr:  ErrTestFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var operations []Operation
+			if err := json.Unmarshal([]byte(tt.patch), &operations); err != nil {
+				t.Fatalf("unmarshal patch error: %v", err)
+			}
+
+			got, err := Apply([]byte(tt.document), operations)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if got != nil {
+					t.Errorf("Apply() = %s, want nil when operation fail", got)
+				}
+				return
+			}
+			if !equalJSON(t, got, []byte(tt.want)) {
+				t.Errorf("Apply() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestMergePatch
+// ======================================================================
+
+// case that start with `rfc7386_` is example of appendix A of RFC 7386
+func TestMergePatch(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		document string
+		patch    string
+		want     string
+	}{
+		{name: "rfc7386_replace_member", document: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{name: "rfc7386_add_member", document: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{name: "rfc7386_null_remove_member", document: `{"a":"b"}`, patch: `{"a":null}`, want: `{}`},
+		{name: "rfc7386_null_remove_only_named_member", document: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{name: "rfc7386_replace_array_with_string", document: `{"a":["b"]}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{name: "rfc7386_replace_string_with_array", document: `{"a":"c"}`, patch: `{"a":["b"]}`, want: `{"a":["b"]}`},
+		{name: "rfc7386_merge_nested_object", document: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, want: `{"a":{"b":"d"}}`},
+		{name: "rfc7386_array_is_replaced", document: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, want: `{"a":[1]}`},
+		{name: "rfc7386_array_document", document: `["a","b"]`, patch: `["c","d"]`, want: `["c","d"]`},
+		{name: "rfc7386_array_patch_replace_object", document: `{"a":"b"}`, patch: `["c"]`, want: `["c"]`},
+		{name: "rfc7386_null_patch", document: `{"a":"foo"}`, patch: `null`, want: `null`},
+		{name: "rfc7386_string_patch", document: `{"a":"foo"}`, patch: `"bar"`, want: `"bar"`},
+		{name: "rfc7386_keep_null_in_document", document: `{"e":null}`, patch: `{"a":1}`, want: `{"e":null,"a":1}`},
+		{name: "rfc7386_object_patch_replace_array", document: `[1,2]`, patch: `{"a":"b","c":null}`, want: `{"a":"b"}`},
+		{name: "rfc7386_null_in_new_object_is_removed", document: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, want: `{"a":{"bb":{}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergePatch([]byte(tt.document), []byte(tt.patch))
+			if err != nil {
+				t.Errorf("MergePatch() error = %v", err)
+				return
+			}
+			if !equalJSON(t, got, []byte(tt.want)) {
+				t.Errorf("MergePatch() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// invalid json is rejected instead of treated as null
+func TestMergePatch_InvalidJSON(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		document string
+		patch    string
+	}{
+		{name: "invalid_document", document: `{"a":`, patch: `{"a":"b"}`},
+		{name: "invalid_patch", document: `{"a":"b"}`, patch: `{"a":`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := MergePatch([]byte(tt.document), []byte(tt.patch)); err == nil {
+				t.Errorf("MergePatch() error = nil, want error")
+			}
+		})
+	}
+}
+
+// **********************************************************************
@@ -1,83 +1,195 @@
 package jwt_service
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"spider-go/model"
+	"spider-go/utils/uuid"
+	"sync"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
 )
 
+var (
+	ErrorNoActiveSigningKey = errors.New("no active signing key")
+	ErrorUnknownKID         = errors.New("unknown kid of token")
+)
+
 type JWTService struct {
 	SecretKey  string
 	ExpireTime time.Duration
 	Issure     string
+	// HS256 sign with SecretKey, RS256 and EdDSA sign with key set that set by SetSigningKeys
+	Algorithm string
+
+	mu         sync.RWMutex
+	activeKey  *signingKey
+	verifyKeys map[string]*signingKey
+}
+
+type signingKey struct {
+	kid        string
+	method     jwt.SigningMethod
+	privateKey interface{}
+	publicKey  interface{}
 }
 
 type AuthCustomClaims struct {
-	Username string
-	Role     string
+	Username  string
+	Role      string
+	SessionID string
+	// IssuedAtMilli is issued time in unix millisecond, iat is only second so it can not order token and revoke in same second
+	IssuedAtMilli int64 `json:"iat_ms,omitempty"`
 	jwt.StandardClaims
 }
 
-func NewJWTService(secretKey string, expireTime time.Duration, issure string) *JWTService {
+func NewJWTService(secretKey string, expireTime time.Duration, issure, algorithm string) *JWTService {
+	if algorithm == "" {
+		algorithm = model.SIGNING_ALGORITHM_HS256
+	}
+
 	return &JWTService{
 		SecretKey:  secretKey,
 		ExpireTime: expireTime,
 		Issure:     issure,
+		Algorithm:  algorithm,
+		verifyKeys: map[string]*signingKey{},
 	}
 }
 
-func (service *JWTService) GenerateNewToken(username, role string) (string, error) {
+// GenerateNewToken create access token, sessionID is refresh token session family that token belong to
+func (service *JWTService) GenerateNewToken(username, role, sessionID string) (string, error) {
+	now := time.Now()
+
 	claims := &AuthCustomClaims{
 		username,
 		role,
+		sessionID,
+		now.UnixMilli(),
 		jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(service.ExpireTime).Unix(),
+			Id:        uuid.GernerateUUID32(),
+			ExpiresAt: now.Add(service.ExpireTime).Unix(),
 			Issuer:    service.Issure,
-			IssuedAt:  time.Now().Unix(),
+			IssuedAt:  now.Unix(),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenStr, err := token.SignedString([]byte(service.SecretKey))
-	if err != nil {
-		return "", err
+	if service.Algorithm == model.SIGNING_ALGORITHM_HS256 {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(service.SecretKey))
+	}
+
+	service.mu.RLock()
+	activeKey := service.activeKey
+	service.mu.RUnlock()
+
+	if activeKey == nil {
+		return "", ErrorNoActiveSigningKey
 	}
 
-	return tokenStr, nil
+	token := jwt.NewWithClaims(activeKey.method, claims)
+	token.Header["kid"] = activeKey.kid
+
+	return token.SignedString(activeKey.privateKey)
 }
 
+// ValidateToken parse token with AuthCustomClaims, so caller can read claims with
+// token.Claims.(*AuthCustomClaims) after validate success
 func (service *JWTService) ValidateToken(encodedToken string) (*jwt.Token, error) {
-	return jwt.Parse(encodedToken, func(token *jwt.Token) (interface{}, error) {
-		if _, isvalid := token.Method.(*jwt.SigningMethodHMAC); !isvalid {
-			return nil, fmt.Errorf("invalid token %v", token.Header["alg"])
+	return jwt.ParseWithClaims(encodedToken, &AuthCustomClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if service.Algorithm == model.SIGNING_ALGORITHM_HS256 {
+			if _, isvalid := token.Method.(*jwt.SigningMethodHMAC); !isvalid {
+				return nil, fmt.Errorf("invalid token %v", token.Header["alg"])
+			}
+			return []byte(service.SecretKey), nil
+		}
 
+		kid, _ := token.Header["kid"].(string)
+
+		service.mu.RLock()
+		key, found := service.verifyKeys[kid]
+		service.mu.RUnlock()
+
+		if !found {
+			return nil, ErrorUnknownKID
 		}
-		return []byte(service.SecretKey), nil
+
+		// algorithm of token must be algorithm of key, prevent algorithm confusion
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("invalid token %v", token.Header["alg"])
+		}
+
+		return key.publicKey, nil
 	})
 }
 
-func (service *JWTService) RefreshToken(currenToken *jwt.Token) (string, error) {
-	claims := currenToken.Claims.(jwt.MapClaims)
+// SetSigningKeys replace key set, newest active key of service algorithm is used for signing
+// and every key is used for verify token
+func (service *JWTService) SetSigningKeys(keys []model.SigningKey) error {
+	verifyKeys := make(map[string]*signingKey, len(keys))
 
-	username := fmt.Sprint(claims["Username"])
-	role := fmt.Sprint(claims["Role"])
+	var activeKey *signingKey
+	var activeCreatedAt time.Time
 
-	newClaims := &AuthCustomClaims{
-		username,
-		role,
-		jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(service.ExpireTime).Unix(),
-			Issuer:    service.Issure,
-			IssuedAt:  time.Now().Unix(),
-		},
+	for _, key := range keys {
+		method := jwt.GetSigningMethod(key.Algorithm)
+		if method == nil || key.Algorithm == model.SIGNING_ALGORITHM_HS256 {
+			return fmt.Errorf("key `%v`: %w", key.KID, ErrorUnsupportedAlgorithm)
+		}
+
+		publicKey, err := parsePublicKey(key.PublicKey)
+		if err != nil {
+			return fmt.Errorf("parse public key `%v`: %w", key.KID, err)
+		}
+
+		parsed := &signingKey{
+			kid:       key.KID,
+			method:    method,
+			publicKey: publicKey,
+		}
+
+		if key.Active && key.Algorithm == service.Algorithm && (activeKey == nil || key.CreatedAt.After(activeCreatedAt)) {
+			if parsed.privateKey, err = parsePrivateKey(key.PrivateKey); err != nil {
+				return fmt.Errorf("parse private key `%v`: %w", key.KID, err)
+			}
+			activeKey = parsed
+			activeCreatedAt = key.CreatedAt
+		}
+
+		verifyKeys[key.KID] = parsed
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims)
-	tokenStr, err := token.SignedString([]byte(service.SecretKey))
-	if err != nil {
-		return "", err
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	service.activeKey = activeKey
+	service.verifyKeys = verifyKeys
+
+	return nil
+}
+
+// GetJWKS return public key of every key that can verify token
+func (service *JWTService) GetJWKS() (model.JWKS, error) {
+	service.mu.RLock()
+	defer service.mu.RUnlock()
+
+	jwks := model.JWKS{
+		Keys: make([]model.JWK, 0, len(service.verifyKeys)),
 	}
 
-	return tokenStr, nil
+	for kid, key := range service.verifyKeys {
+		jwk, err := toJWK(kid, key.method.Alg(), key.publicKey)
+		if err != nil {
+			return model.JWKS{}, err
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	sort.Slice(jwks.Keys, func(i, j int) bool {
+		return jwks.Keys[i].Kid < jwks.Keys[j].Kid
+	})
+
+	return jwks, nil
 }
@@ -0,0 +1,345 @@
+package jwt_service
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"spider-go/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// small rsa key is enough for test and keep test fast
+const unittestRSAKeySize = 1024
+
+func generateUnittestSigningKey(t *testing.T, algorithm string) model.SigningKey {
+	t.Helper()
+
+	key, err := GenerateSigningKey(algorithm, unittestRSAKeySize)
+	if err != nil {
+		t.Fatalf("GenerateSigningKey(%v) error: %v", algorithm, err)
+	}
+
+	return key
+}
+
+func newUnittestJWTService(t *testing.T, algorithm string, keys ...model.SigningKey) *JWTService {
+	t.Helper()
+
+	service := NewJWTService("unittest-secret", time.Minute, "unittest", algorithm)
+	if err := service.SetSigningKeys(keys); err != nil {
+		t.Fatalf("SetSigningKeys() error: %v", err)
+	}
+
+	return service
+}
+
+// validationInner return error of key func that jwt-go wrap in validation error
+func validationInner(err error) error {
+	if ve, ok := err.(*jwt.ValidationError); ok {
+		return ve.Inner
+	}
+	return err
+}
+
+// ======================================================================
+// TestJWTService_GenerateAndValidateToken
+// ======================================================================
+func TestJWTService_GenerateAndValidateToken(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		algorithm string
+	}{
+		{name: "eddsa", algorithm: model.SIGNING_ALGORITHM_EDDSA},
+		{name: "rs256", algorithm: model.SIGNING_ALGORITHM_RS256},
+		{name: "hs256", algorithm: model.SIGNING_ALGORITHM_HS256},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys []model.SigningKey
+			if tt.algorithm != model.SIGNING_ALGORITHM_HS256 {
+				keys = append(keys, generateUnittestSigningKey(t, tt.algorithm))
+			}
+			service := newUnittestJWTService(t, tt.algorithm, keys...)
+
+			encodedToken, err := service.GenerateNewToken("unittest", model.ACCOUNT_ROLE_ADMIN, "session")
+			if err != nil {
+				t.Fatalf("JWTService.GenerateNewToken() error = %v", err)
+			}
+
+			token, err := service.ValidateToken(encodedToken)
+			if err != nil {
+				t.Fatalf("JWTService.ValidateToken() error = %v", err)
+			}
+
+			if token.Method.Alg() != tt.algorithm {
+				t.Errorf("JWTService.ValidateToken() alg = %v, want %v", token.Method.Alg(), tt.algorithm)
+			}
+			if len(keys) > 0 && token.Header["kid"] != keys[0].KID {
+				t.Errorf("JWTService.ValidateToken() kid = %v, want %v", token.Header["kid"], keys[0].KID)
+			}
+
+			claims := token.Claims.(*AuthCustomClaims)
+			if claims.Username != "unittest" || claims.Role != model.ACCOUNT_ROLE_ADMIN || claims.SessionID != "session" {
+				t.Errorf("JWTService.ValidateToken() claims = %+v", claims)
+			}
+		})
+	}
+}
+
+// token that is changed after signed is rejected
+func TestJWTService_ValidateTokenTampered(t *testing.T) {
+	service := newUnittestJWTService(t, model.SIGNING_ALGORITHM_EDDSA, generateUnittestSigningKey(t, model.SIGNING_ALGORITHM_EDDSA))
+
+	encodedToken, err := service.GenerateNewToken("unittest", model.ACCOUNT_ROLE_GENERAL, "session")
+	if err != nil {
+		t.Fatalf("JWTService.GenerateNewToken() error = %v", err)
+	}
+
+	// sign token of admin with the same signature
+	claims := &AuthCustomClaims{Username: "unittest", Role: model.ACCOUNT_ROLE_ADMIN, SessionID: "session"}
+	tampered := jwt.NewWithClaims(SigningMethodEd25519, claims)
+	signingString, err := tampered.SigningString()
+	if err != nil {
+		t.Fatalf("SigningString() error: %v", err)
+	}
+	signature := encodedToken[strings.LastIndex(encodedToken, ".")+1:]
+
+	if _, err := service.ValidateToken(signingString + "." + signature); err == nil {
+		t.Errorf("JWTService.ValidateToken() error = nil, want error")
+	}
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestJWTService_ValidateTokenRetiredKey
+// ======================================================================
+
+// token signed by key that is retired after rotation is verified by kid until key is removed from key set
+func TestJWTService_ValidateTokenRetiredKey(t *testing.T) {
+	oldKey := generateUnittestSigningKey(t, model.SIGNING_ALGORITHM_EDDSA)
+	service := newUnittestJWTService(t, model.SIGNING_ALGORITHM_EDDSA, oldKey)
+
+	oldToken, err := service.GenerateNewToken("unittest", model.ACCOUNT_ROLE_GENERAL, "session")
+	if err != nil {
+		t.Fatalf("JWTService.GenerateNewToken() error = %v", err)
+	}
+
+	// rotate, old key is retired and kept for verify only
+	newKey := generateUnittestSigningKey(t, model.SIGNING_ALGORITHM_EDDSA)
+	retiredKey := oldKey
+	retiredKey.Active = false
+	retiredKey.VerifyUntil = time.Now().Add(time.Minute)
+	retiredKey.RetiredAt = time.Now()
+
+	if err := service.SetSigningKeys([]model.SigningKey{retiredKey, newKey}); err != nil {
+		t.Fatalf("SetSigningKeys() error: %v", err)
+	}
+
+	token, err := service.ValidateToken(oldToken)
+	if err != nil {
+		t.Fatalf("JWTService.ValidateToken() of token signed by retired key error = %v", err)
+	}
+	if token.Header["kid"] != oldKey.KID {
+		t.Errorf("JWTService.ValidateToken() kid = %v, want %v", token.Header["kid"], oldKey.KID)
+	}
+
+	newToken, err := service.GenerateNewToken("unittest", model.ACCOUNT_ROLE_GENERAL, "session")
+	if err != nil {
+		t.Fatalf("JWTService.GenerateNewToken() after rotate error = %v", err)
+	}
+	token, err = service.ValidateToken(newToken)
+	if err != nil {
+		t.Fatalf("JWTService.ValidateToken() of new token error = %v", err)
+	}
+	if token.Header["kid"] != newKey.KID {
+		t.Errorf("JWTService.ValidateToken() of new token kid = %v, want %v", token.Header["kid"], newKey.KID)
+	}
+
+	// retired key is removed after last token that signed by it expire
+	if err := service.SetSigningKeys([]model.SigningKey{newKey}); err != nil {
+		t.Fatalf("SetSigningKeys() error: %v", err)
+	}
+	if _, err := service.ValidateToken(oldToken); validationInner(err) != ErrorUnknownKID {
+		t.Errorf("JWTService.ValidateToken() of removed key error = %v, wantErr %v", err, ErrorUnknownKID)
+	}
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestJWTService_ValidateTokenAlgorithmMismatch
+// ======================================================================
+
+// algorithm in token header must be algorithm of key that kid point to
+func TestJWTService_ValidateTokenAlgorithmMismatch(t *testing.T) {
+	rsaKey := generateUnittestSigningKey(t, model.SIGNING_ALGORITHM_RS256)
+	eddsaKey := generateUnittestSigningKey(t, model.SIGNING_ALGORITHM_EDDSA)
+	eddsaPrivateKey, err := parsePrivateKey(eddsaKey.PrivateKey)
+	if err != nil {
+		t.Fatalf("parsePrivateKey() error: %v", err)
+	}
+
+	service := newUnittestJWTService(t, model.SIGNING_ALGORITHM_RS256, rsaKey, eddsaKey)
+
+	claims := &AuthCustomClaims{
+		Username: "unittest",
+		Role:     model.ACCOUNT_ROLE_ADMIN,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(time.Minute).Unix(),
+		},
+	}
+
+	tests := []struct {
+		name   string
+		method jwt.SigningMethod
+		kid    string
+		key    interface{}
+	}{
+		{
+			// public key of rsa kid is known by everyone, it must not be used as hmac secret
+			name:   "hs256_with_rsa_kid",
+			method: jwt.SigningMethodHS256,
+			kid:    rsaKey.KID,
+			key:    []byte(rsaKey.PublicKey),
+		},
+		{
+			name:   "eddsa_with_rsa_kid",
+			method: SigningMethodEd25519,
+			kid:    rsaKey.KID,
+			key:    eddsaPrivateKey,
+		},
+		{
+			name:   "hs256_without_kid",
+			method: jwt.SigningMethodHS256,
+			kid:    "",
+			key:    []byte("unittest-secret"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := jwt.NewWithClaims(tt.method, claims)
+			if tt.kid != "" {
+				token.Header["kid"] = tt.kid
+			}
+
+			encodedToken, err := token.SignedString(tt.key)
+			if err != nil {
+				t.Fatalf("SignedString() error: %v", err)
+			}
+
+			// token is rejected when key is looked up, before signature is verified
+			_, err = service.ValidateToken(encodedToken)
+			if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&jwt.ValidationErrorUnverifiable == 0 {
+				t.Errorf("JWTService.ValidateToken() error = %v, want unverifiable token", err)
+			}
+		})
+	}
+}
+
+// service that sign with secret does not accept token of key pair
+func TestJWTService_ValidateTokenHS256RejectKeyPair(t *testing.T) {
+	eddsaKey := generateUnittestSigningKey(t, model.SIGNING_ALGORITHM_EDDSA)
+	eddsaService := newUnittestJWTService(t, model.SIGNING_ALGORITHM_EDDSA, eddsaKey)
+
+	encodedToken, err := eddsaService.GenerateNewToken("unittest", model.ACCOUNT_ROLE_ADMIN, "session")
+	if err != nil {
+		t.Fatalf("JWTService.GenerateNewToken() error = %v", err)
+	}
+
+	service := newUnittestJWTService(t, model.SIGNING_ALGORITHM_HS256)
+	if _, err := service.ValidateToken(encodedToken); err == nil {
+		t.Errorf("JWTService.ValidateToken() error = nil, want error")
+	}
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestJWTService_GetJWKS
+// ======================================================================
+func TestJWTService_GetJWKS(t *testing.T) {
+	rsaKey := generateUnittestSigningKey(t, model.SIGNING_ALGORITHM_RS256)
+	eddsaKey := generateUnittestSigningKey(t, model.SIGNING_ALGORITHM_EDDSA)
+
+	rsaKey.KID = "kid-b"
+	eddsaKey.KID = "kid-a"
+
+	service := newUnittestJWTService(t, model.SIGNING_ALGORITHM_EDDSA, rsaKey, eddsaKey)
+
+	jwks, err := service.GetJWKS()
+	if err != nil {
+		t.Fatalf("JWTService.GetJWKS() error = %v", err)
+	}
+
+	// keys are sorted by kid
+	if len(jwks.Keys) != 2 || jwks.Keys[0].Kid != "kid-a" || jwks.Keys[1].Kid != "kid-b" {
+		t.Fatalf("JWTService.GetJWKS() = %+v, want kid-a and kid-b", jwks.Keys)
+	}
+
+	// Ed25519
+	eddsaJWK := jwks.Keys[0]
+	if eddsaJWK.Kty != "OKP" || eddsaJWK.Crv != "Ed25519" || eddsaJWK.Alg != model.SIGNING_ALGORITHM_EDDSA || eddsaJWK.Use != "sig" {
+		t.Errorf("JWTService.GetJWKS() Ed25519 key = %+v", eddsaJWK)
+	}
+	if eddsaJWK.N != "" || eddsaJWK.E != "" {
+		t.Errorf("JWTService.GetJWKS() Ed25519 key has rsa field = %+v", eddsaJWK)
+	}
+
+	eddsaPublicKey, err := parsePublicKey(eddsaKey.PublicKey)
+	if err != nil {
+		t.Fatalf("parsePublicKey() error: %v", err)
+	}
+	x, err := base64.RawURLEncoding.DecodeString(eddsaJWK.X)
+	if err != nil || !eddsaPublicKey.(ed25519.PublicKey).Equal(ed25519.PublicKey(x)) {
+		t.Errorf("JWTService.GetJWKS() Ed25519 x = %v, error = %v, not match public key", eddsaJWK.X, err)
+	}
+
+	// RSA
+	rsaJWK := jwks.Keys[1]
+	if rsaJWK.Kty != "RSA" || rsaJWK.Alg != model.SIGNING_ALGORITHM_RS256 || rsaJWK.Use != "sig" {
+		t.Errorf("JWTService.GetJWKS() RSA key = %+v", rsaJWK)
+	}
+	if rsaJWK.Crv != "" || rsaJWK.X != "" {
+		t.Errorf("JWTService.GetJWKS() RSA key has Ed25519 field = %+v", rsaJWK)
+	}
+
+	rsaPublicKey, err := parsePublicKey(rsaKey.PublicKey)
+	if err != nil {
+		t.Fatalf("parsePublicKey() error: %v", err)
+	}
+	n, errN := base64.RawURLEncoding.DecodeString(rsaJWK.N)
+	e, errE := base64.RawURLEncoding.DecodeString(rsaJWK.E)
+	if errN != nil || errE != nil {
+		t.Fatalf("decode RSA n error = %v, e error = %v", errN, errE)
+	}
+	decoded := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	if !rsaPublicKey.(*rsa.PublicKey).Equal(decoded) {
+		t.Errorf("JWTService.GetJWKS() RSA n = %v, e = %v, not match public key", rsaJWK.N, rsaJWK.E)
+	}
+	// exponent 65537 is encoded without leading zero
+	if rsaJWK.E != "AQAB" {
+		t.Errorf("JWTService.GetJWKS() RSA e = %v, want AQAB", rsaJWK.E)
+	}
+}
+
+// empty key set encode to empty list, not null
+func TestJWTService_GetJWKSEmpty(t *testing.T) {
+	service := newUnittestJWTService(t, model.SIGNING_ALGORITHM_HS256)
+
+	jwks, err := service.GetJWKS()
+	if err != nil {
+		t.Fatalf("JWTService.GetJWKS() error = %v", err)
+	}
+	if jwks.Keys == nil || len(jwks.Keys) != 0 {
+		t.Errorf("JWTService.GetJWKS() keys = %#v, want empty list", jwks.Keys)
+	}
+}
+
+// **********************************************************************
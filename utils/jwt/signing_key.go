@@ -0,0 +1,106 @@
+package jwt_service
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"spider-go/model"
+	"spider-go/utils/uuid"
+	"time"
+)
+
+var (
+	ErrorUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrorInvalidKeyPEM        = errors.New("invalid pem of signing key")
+)
+
+const DEFAULT_RSA_SIGNING_KEY_SIZE = 2048
+
+// GenerateSigningKey create new active key pair of algorithm, key is encoded as PEM (PKCS8 / PKIX)
+func GenerateSigningKey(algorithm string, rsaKeySize int) (model.SigningKey, error) {
+	var privateKey crypto.Signer
+	var err error
+
+	switch algorithm {
+	case model.SIGNING_ALGORITHM_RS256:
+		if rsaKeySize <= 0 {
+			rsaKeySize = DEFAULT_RSA_SIGNING_KEY_SIZE
+		}
+		privateKey, err = rsa.GenerateKey(rand.Reader, rsaKeySize)
+	case model.SIGNING_ALGORITHM_EDDSA:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return model.SigningKey{}, ErrorUnsupportedAlgorithm
+	}
+	if err != nil {
+		return model.SigningKey{}, err
+	}
+
+	privateBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return model.SigningKey{}, err
+	}
+
+	publicBytes, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	if err != nil {
+		return model.SigningKey{}, err
+	}
+
+	return model.SigningKey{
+		KID:        uuid.GernerateUUID32(),
+		Algorithm:  algorithm,
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateBytes})),
+		PublicKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicBytes})),
+		Active:     true,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+func parsePrivateKey(privateKeyPEM string) (interface{}, error) {
+	block, _ := pem.Decode([]byte(privateKeyPEM))
+	if block == nil {
+		return nil, ErrorInvalidKeyPEM
+	}
+
+	return x509.ParsePKCS8PrivateKey(block.Bytes)
+}
+
+func parsePublicKey(publicKeyPEM string) (interface{}, error) {
+	block, _ := pem.Decode([]byte(publicKeyPEM))
+	if block == nil {
+		return nil, ErrorInvalidKeyPEM
+	}
+
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// toJWK convert public key to json web key
+func toJWK(kid, algorithm string, publicKey interface{}) (model.JWK, error) {
+	jwk := model.JWK{
+		Kid: kid,
+		Use: "sig",
+		Alg: algorithm,
+	}
+
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(key)
+	default:
+		return model.JWK{}, fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+
+	return jwk, nil
+}
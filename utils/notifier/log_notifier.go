@@ -0,0 +1,46 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"spider-go/logger"
+	"spider-go/model"
+	"sync"
+	"time"
+)
+
+// LogNotifier write message to file or application log instead of deliver it,
+// for local development only because message may contain secret token
+type LogNotifier struct {
+	filePath string
+	mu       sync.Mutex
+	log      *logger.Logger
+}
+
+func NewLogNotifier(filePath string) *LogNotifier {
+	return &LogNotifier{
+		filePath: filePath,
+		log:      logger.L().Named("LogNotifier"),
+	}
+}
+
+func (n *LogNotifier) Send(ctx context.Context, notification model.Notification) error {
+	if n.filePath == "" {
+		n.log.WithContext(ctx).Infof("[Send] to: %v, subject: %v, body: %v", notification.To, notification.Subject, notification.Body)
+		return nil
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "==== %v\nTo: %v\nSubject: %v\n\n%v\n\n", time.Now().Format(time.RFC3339), notification.To, notification.Subject, notification.Body)
+
+	return err
+}
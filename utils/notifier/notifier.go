@@ -0,0 +1,19 @@
+package notifier
+
+import (
+	"spider-go/config"
+	"spider-go/domain"
+)
+
+var (
+	NOTIFIER_TYPE_SMTP = "smtp"
+	NOTIFIER_TYPE_LOG  = "log"
+)
+
+// NewNotifier select notifier by config, log notifier is default for local development
+func NewNotifier(conf config.Notifier) domain.Notifier {
+	if conf.Type == NOTIFIER_TYPE_SMTP {
+		return NewSMTPNotifier(conf.SMTP, conf.From)
+	}
+	return NewLogNotifier(conf.FilePath)
+}
@@ -0,0 +1,58 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"spider-go/config"
+	"spider-go/model"
+	"strconv"
+	"strings"
+)
+
+type SMTPNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPNotifier(conf config.SMTP, from string) *SMTPNotifier {
+	n := &SMTPNotifier{
+		addr: net.JoinHostPort(conf.Host, strconv.Itoa(conf.Port)),
+		from: from,
+	}
+
+	// server that not require authentication, e.g. local relay
+	if conf.Username != "" {
+		n.auth = smtp.PlainAuth("", conf.Username, conf.Password, conf.Host)
+	}
+
+	return n
+}
+
+func (n *SMTPNotifier) Send(ctx context.Context, notification model.Notification) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := smtp.SendMail(n.addr, n.auth, n.from, []string{notification.To}, n.buildMessage(notification)); err != nil {
+		return fmt.Errorf("send mail to %v error: %w", notification.To, err)
+	}
+
+	return nil
+}
+
+func (n *SMTPNotifier) buildMessage(notification model.Notification) []byte {
+	var b strings.Builder
+
+	b.WriteString("From: " + n.from + "\r\n")
+	b.WriteString("To: " + notification.To + "\r\n")
+	b.WriteString("Subject: " + notification.Subject + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(notification.Body, "\n", "\r\n"))
+
+	return []byte(b.String())
+}
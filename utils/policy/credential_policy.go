@@ -0,0 +1,148 @@
+package policy
+
+import (
+	"fmt"
+	"regexp"
+	"spider-go/config"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrorPasswordTooShort       = fmt.Errorf("[Credential Policy]: password is too short")
+	ErrorPasswordTooLong        = fmt.Errorf("[Credential Policy]: password is too long")
+	ErrorPasswordMissingUpper   = fmt.Errorf("[Credential Policy]: password must contain upper case letter")
+	ErrorPasswordMissingLower   = fmt.Errorf("[Credential Policy]: password must contain lower case letter")
+	ErrorPasswordMissingDigit   = fmt.Errorf("[Credential Policy]: password must contain digit")
+	ErrorPasswordMissingSpecial = fmt.Errorf("[Credential Policy]: password must contain special character")
+	ErrorPasswordCommon         = fmt.Errorf("[Credential Policy]: password is too common")
+	ErrorPasswordContainUser    = fmt.Errorf("[Credential Policy]: password must not contain username")
+	ErrorUsernameLength         = fmt.Errorf("[Credential Policy]: username length is invalid")
+	ErrorUsernamePattern        = fmt.Errorf("[Credential Policy]: username contains invalid character")
+)
+
+// default value when config is not set
+var (
+	DEFAULT_PASSWORD_MIN_LENGTH = 8
+	// bcrypt use only first 72 bytes
+	DEFAULT_PASSWORD_MAX_LENGTH = 72
+	DEFAULT_USERNAME_MIN_LENGTH = 4
+	DEFAULT_USERNAME_MAX_LENGTH = 32
+	DEFAULT_USERNAME_PATTERN    = `^[a-zA-Z0-9._-]+$`
+)
+
+// common password that always not allowed
+var COMMON_PASSWORDS = []string{
+	"123456", "12345678", "123456789", "1234567890", "111111", "000000",
+	"password", "password1", "password123", "passw0rd", "p@ssw0rd",
+	"qwerty", "qwerty123", "qwertyuiop", "abc123", "abcd1234", "1q2w3e4r",
+	"iloveyou", "admin", "admin123", "administrator", "welcome", "welcome1",
+	"letmein", "monkey", "dragon", "football", "baseball", "sunshine",
+	"princess", "master", "superman", "trustno1", "changeme", "secret",
+	"spider", "spider123",
+}
+
+// username shorter than this is not checked in password, it is too easy to match by chance
+const MIN_USERNAME_LENGTH_FOR_CONTAIN_CHECK = 3
+
+type CredentialPolicy struct {
+	password        config.PasswordPolicy
+	username        config.UsernamePolicy
+	usernamePattern *regexp.Regexp
+	denylist        map[string]struct{}
+}
+
+func NewCredentialPolicy(conf config.CredentialPolicy) *CredentialPolicy {
+	p := &CredentialPolicy{
+		password: conf.Password,
+		username: conf.Username,
+		denylist: map[string]struct{}{},
+	}
+
+	if p.password.MinLength <= 0 {
+		p.password.MinLength = DEFAULT_PASSWORD_MIN_LENGTH
+	}
+	if p.password.MaxLength <= 0 {
+		p.password.MaxLength = DEFAULT_PASSWORD_MAX_LENGTH
+	}
+	if p.username.MinLength <= 0 {
+		p.username.MinLength = DEFAULT_USERNAME_MIN_LENGTH
+	}
+	if p.username.MaxLength <= 0 {
+		p.username.MaxLength = DEFAULT_USERNAME_MAX_LENGTH
+	}
+	if p.username.Pattern == "" {
+		p.username.Pattern = DEFAULT_USERNAME_PATTERN
+	}
+
+	p.usernamePattern = regexp.MustCompile(p.username.Pattern)
+
+	for _, password := range append(COMMON_PASSWORDS, conf.Password.Denylist...) {
+		p.denylist[strings.ToLower(password)] = struct{}{}
+	}
+
+	return p
+}
+
+func (p *CredentialPolicy) ValidateUsername(username string) error {
+	length := utf8.RuneCountInString(username)
+	if length < p.username.MinLength || length > p.username.MaxLength {
+		return ErrorUsernameLength
+	}
+
+	if !p.usernamePattern.MatchString(username) {
+		return ErrorUsernamePattern
+	}
+
+	return nil
+}
+
+func (p *CredentialPolicy) ValidatePassword(username, password string) error {
+	length := utf8.RuneCountInString(password)
+	if length < p.password.MinLength {
+		return ErrorPasswordTooShort
+	}
+	if length > p.password.MaxLength {
+		return ErrorPasswordTooLong
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSpecial bool
+	for _, c := range password {
+		switch {
+		case unicode.IsUpper(c):
+			hasUpper = true
+		case unicode.IsLower(c):
+			hasLower = true
+		case unicode.IsDigit(c):
+			hasDigit = true
+		case !unicode.IsLetter(c):
+			hasSpecial = true
+		}
+	}
+
+	if p.password.RequireUpper && !hasUpper {
+		return ErrorPasswordMissingUpper
+	}
+	if p.password.RequireLower && !hasLower {
+		return ErrorPasswordMissingLower
+	}
+	if p.password.RequireDigit && !hasDigit {
+		return ErrorPasswordMissingDigit
+	}
+	if p.password.RequireSpecial && !hasSpecial {
+		return ErrorPasswordMissingSpecial
+	}
+
+	lowerPassword := strings.ToLower(password)
+
+	if _, found := p.denylist[lowerPassword]; found {
+		return ErrorPasswordCommon
+	}
+
+	if len(username) >= MIN_USERNAME_LENGTH_FOR_CONTAIN_CHECK && strings.Contains(lowerPassword, strings.ToLower(username)) {
+		return ErrorPasswordContainUser
+	}
+
+	return nil
+}
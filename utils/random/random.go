@@ -1,6 +1,8 @@
 package random
 
 import (
+	crand "crypto/rand"
+	"encoding/base64"
 	"math/rand"
 	"time"
 )
@@ -26,3 +28,15 @@ func (r *Random) RandomString(size int) string {
 	return string(ranByte)
 
 }
+
+// RandomToken return url safe string from crypto random bytes, used for secret token
+func (r *Random) RandomToken(size int) (string, error) {
+
+	ranByte := make([]byte, size)
+
+	if _, err := crand.Read(ranByte); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(ranByte), nil
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"spider-go/config"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IMAGE_URL_PATH is path of image endpoint, params are spider uuid and image name
+const IMAGE_URL_PATH = "/spider/%s/image/%s"
+
+var (
+	ErrorImageURLSignatureInvalid = errors.New("[image url] signature of image url is invalid")
+	ErrorImageURLExpired          = errors.New("[image url] image url is expired")
+)
+
+// ImageURLSigner sign url of image that is not public with hmac, signed url is valid until expires
+type ImageURLSigner struct {
+	conf config.ImageURL
+	now  func() time.Time
+}
+
+func NewImageURLSigner(conf config.ImageURL) *ImageURLSigner {
+	return &ImageURLSigner{
+		conf: conf,
+		now:  time.Now,
+	}
+}
+
+func (s *ImageURLSigner) ImageURL(spiderUUID, imageName string, public bool) string {
+	imageURL := strings.TrimSuffix(s.conf.BaseURL, "/") + fmt.Sprintf(IMAGE_URL_PATH, url.PathEscape(spiderUUID), url.PathEscape(imageName))
+	if public {
+		return imageURL
+	}
+
+	expires := strconv.FormatInt(s.now().Add(s.conf.SignedURLTTL).Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(spiderUUID, imageName, expires))
+
+	return imageURL + "?" + query.Encode()
+}
+
+func (s *ImageURLSigner) VerifySignature(spiderUUID, imageName, expires, signature string) (time.Time, error) {
+	if s.conf.SignSecret == "" || signature == "" {
+		return time.Time{}, ErrorImageURLSignatureInvalid
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.sign(spiderUUID, imageName, expires))) {
+		return time.Time{}, ErrorImageURLSignatureInvalid
+	}
+
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrorImageURLSignatureInvalid
+	}
+
+	expiresAt := time.Unix(unix, 0)
+	if !s.now().Before(expiresAt) {
+		return time.Time{}, ErrorImageURLExpired
+	}
+
+	return expiresAt, nil
+}
+
+// *************************************************
+
+func (s *ImageURLSigner) sign(spiderUUID, imageName, expires string) string {
+	message := strings.Join([]string{spiderUUID, imageName, expires}, "\n")
+	return hex.EncodeToString(hmacSHA256([]byte(s.conf.SignSecret), []byte(message)))
+}
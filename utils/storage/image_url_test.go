@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"net/url"
+	"spider-go/config"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ======================================================================
+// TestImageURLSigner_VerifySignature
+// ======================================================================
+func TestImageURLSigner_VerifySignature(t *testing.T) {
+
+	signedAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	conf := config.ImageURL{
+		BaseURL:      "https://api.example.com/",
+		SignSecret:   "image-secret",
+		SignedURLTTL: 15 * time.Minute,
+	}
+
+	tests := []struct {
+		name       string
+		signSecret string
+		verifyAt   time.Time
+		spiderUUID string
+		imageName  string
+		modify     func(url.Values)
+		wantErr    error
+	}{
+		{
+			name:       "valid_signature",
+			signSecret: conf.SignSecret,
+			verifyAt:   signedAt.Add(15*time.Minute - time.Second),
+			spiderUUID: "spider-uuid",
+			imageName:  "spider.png",
+			wantErr:    nil,
+		},
+		{
+			name:       "expired_at_expires",
+			signSecret: conf.SignSecret,
+			verifyAt:   signedAt.Add(15 * time.Minute),
+			spiderUUID: "spider-uuid",
+			imageName:  "spider.png",
+			wantErr:    ErrorImageURLExpired,
+		},
+		{
+			name:       "expired_after_expires",
+			signSecret: conf.SignSecret,
+			verifyAt:   signedAt.Add(time.Hour),
+			spiderUUID: "spider-uuid",
+			imageName:  "spider.png",
+			wantErr:    ErrorImageURLExpired,
+		},
+		{
+			name:       "extended_expires",
+			signSecret: conf.SignSecret,
+			verifyAt:   signedAt.Add(time.Hour),
+			spiderUUID: "spider-uuid",
+			imageName:  "spider.png",
+			modify: func(query url.Values) {
+				query.Set("expires", "9999999999")
+			},
+			wantErr: ErrorImageURLSignatureInvalid,
+		},
+		{
+			name:       "signature_of_other_image",
+			signSecret: conf.SignSecret,
+			verifyAt:   signedAt,
+			spiderUUID: "spider-uuid",
+			imageName:  "other.png",
+			wantErr:    ErrorImageURLSignatureInvalid,
+		},
+		{
+			name:       "signature_of_other_spider",
+			signSecret: conf.SignSecret,
+			verifyAt:   signedAt,
+			spiderUUID: "other-uuid",
+			imageName:  "spider.png",
+			wantErr:    ErrorImageURLSignatureInvalid,
+		},
+		{
+			name:       "empty_signature",
+			signSecret: conf.SignSecret,
+			verifyAt:   signedAt,
+			spiderUUID: "spider-uuid",
+			imageName:  "spider.png",
+			modify: func(query url.Values) {
+				query.Set("signature", "")
+			},
+			wantErr: ErrorImageURLSignatureInvalid,
+		},
+		{
+			name:       "empty_secret",
+			signSecret: "",
+			verifyAt:   signedAt,
+			spiderUUID: "spider-uuid",
+			imageName:  "spider.png",
+			wantErr:    ErrorImageURLSignatureInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signerConf := conf
+			signerConf.SignSecret = tt.signSecret
+
+			signer := NewImageURLSigner(signerConf)
+			signer.now = func() time.Time { return signedAt }
+
+			imageURL, err := url.Parse(signer.ImageURL("spider-uuid", "spider.png", false))
+			if err != nil {
+				t.Fatalf("ImageURLSigner.ImageURL() is not url: %v", err)
+			}
+
+			query := imageURL.Query()
+			if tt.modify != nil {
+				tt.modify(query)
+			}
+
+			signer.now = func() time.Time { return tt.verifyAt }
+
+			expiresAt, err := signer.VerifySignature(tt.spiderUUID, tt.imageName, query.Get("expires"), query.Get("signature"))
+			if err != tt.wantErr {
+				t.Errorf("ImageURLSigner.VerifySignature() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err == nil && !expiresAt.Equal(signedAt.Add(conf.SignedURLTTL)) {
+				t.Errorf("ImageURLSigner.VerifySignature() = %v, want %v", expiresAt, signedAt.Add(conf.SignedURLTTL))
+			}
+		})
+	}
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestImageURLSigner_ImageURL
+// ======================================================================
+func TestImageURLSigner_ImageURL(t *testing.T) {
+
+	signer := NewImageURLSigner(config.ImageURL{
+		BaseURL:      "https://api.example.com/",
+		SignSecret:   "image-secret",
+		SignedURLTTL: 15 * time.Minute,
+	})
+
+	if got, want := signer.ImageURL("spider-uuid", "spider.png", true), "https://api.example.com/spider/spider-uuid/image/spider.png"; got != want {
+		t.Errorf("ImageURLSigner.ImageURL() of public image = %v, want %v", got, want)
+	}
+
+	got := signer.ImageURL("spider-uuid", "spider.png", false)
+	if !strings.HasPrefix(got, "https://api.example.com/spider/spider-uuid/image/spider.png?") ||
+		!strings.Contains(got, "expires=") || !strings.Contains(got, "signature=") {
+		t.Errorf("ImageURLSigner.ImageURL() of image that is not public = %v", got)
+	}
+}
+
+// **********************************************************************
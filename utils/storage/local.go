@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"spider-go/model"
+)
+
+// LocalImageStorage keep image as file in directory of this machine
+type LocalImageStorage struct {
+	rootPath string
+}
+
+func NewLocalImageStorage(rootPath string) *LocalImageStorage {
+	return &LocalImageStorage{
+		rootPath: rootPath,
+	}
+}
+
+// Save write to temp file then rename it, reader never see partial image
+func (s *LocalImageStorage) Save(ctx context.Context, name string, content io.Reader, size int64, contentType string) error {
+	if err := validateImageName(name); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(s.rootPath, "."+name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(file.Name(), 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), filepath.Join(s.rootPath, name))
+}
+
+func (s *LocalImageStorage) Open(ctx context.Context, name string) (io.ReadCloser, *model.ImageObject, error) {
+	if err := validateImageName(name); err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.Open(filepath.Join(s.rootPath, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, ErrorImageNotFound
+		}
+		return nil, nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	return file, s.imageObject(name, info), nil
+}
+
+func (s *LocalImageStorage) Stat(ctx context.Context, name string) (*model.ImageObject, error) {
+	if err := validateImageName(name); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(filepath.Join(s.rootPath, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrorImageNotFound
+		}
+		return nil, err
+	}
+
+	return s.imageObject(name, info), nil
+}
+
+func (s *LocalImageStorage) Delete(ctx context.Context, name string) error {
+	if err := validateImageName(name); err != nil {
+		return err
+	}
+
+	if err := os.Remove(filepath.Join(s.rootPath, name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// *************************************************
+
+// file has no content type and entity tag, they are derived from extension, size and modify time
+func (s *LocalImageStorage) imageObject(name string, info os.FileInfo) *model.ImageObject {
+	return &model.ImageObject{
+		Name:        name,
+		ContentType: contentTypeByName(name),
+		Size:        info.Size(),
+		ModTime:     info.ModTime(),
+		ETag:        fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()),
+	}
+}
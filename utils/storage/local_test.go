@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// ======================================================================
+// TestLocalImageStorage_ImageName
+// ======================================================================
+
+// name of image can not leave root path of storage
+func TestLocalImageStorage_ImageName(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		imageName string
+		wantErr   error
+	}{
+		{name: "valid_name", imageName: "spider.png", wantErr: nil},
+		{name: "empty_name", imageName: "", wantErr: ErrorInvalidImageName},
+		{name: "current_directory", imageName: ".", wantErr: ErrorInvalidImageName},
+		{name: "parent_directory", imageName: "..", wantErr: ErrorInvalidImageName},
+		{name: "traversal_to_parent", imageName: "../outside.png", wantErr: ErrorInvalidImageName},
+		{name: "traversal_in_sub_directory", imageName: "sub/../../outside.png", wantErr: ErrorInvalidImageName},
+		{name: "absolute_path", imageName: "/etc/passwd", wantErr: ErrorInvalidImageName},
+		{name: "windows_traversal", imageName: "..\\outside.png", wantErr: ErrorInvalidImageName},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := t.TempDir()
+			rootPath := filepath.Join(parent, "image")
+			if err := os.Mkdir(rootPath, 0755); err != nil {
+				t.Fatalf("mkdir error: %v", err)
+			}
+			s := NewLocalImageStorage(rootPath)
+
+			if err := s.Save(context.TODO(), tt.imageName, strings.NewReader("image"), 5, "image/png"); err != tt.wantErr {
+				t.Errorf("LocalImageStorage.Save() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if _, _, err := s.Open(context.TODO(), tt.imageName); err != tt.wantErr {
+				t.Errorf("LocalImageStorage.Open() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if _, err := s.Stat(context.TODO(), tt.imageName); err != tt.wantErr {
+				t.Errorf("LocalImageStorage.Stat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := s.Delete(context.TODO(), tt.imageName); err != tt.wantErr {
+				t.Errorf("LocalImageStorage.Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			// nothing is written outside of root path
+			if _, err := os.Stat(filepath.Join(parent, "outside.png")); !os.IsNotExist(err) {
+				t.Errorf("image is written outside of root path, error = %v", err)
+			}
+		})
+	}
+}
+
+// **********************************************************************
+
+// ======================================================================
+// TestLocalImageStorage_Object
+// ======================================================================
+func TestLocalImageStorage_Object(t *testing.T) {
+
+	s := NewLocalImageStorage(t.TempDir())
+
+	if err := s.Save(context.TODO(), "spider.jpeg", strings.NewReader("image"), -1, "image/jpeg"); err != nil {
+		t.Fatalf("LocalImageStorage.Save() error = %v", err)
+	}
+
+	body, object, err := s.Open(context.TODO(), "spider.jpeg")
+	if err != nil {
+		t.Fatalf("LocalImageStorage.Open() error = %v", err)
+	}
+	content, err := io.ReadAll(body)
+	body.Close()
+	if err != nil || string(content) != "image" {
+		t.Errorf("LocalImageStorage.Open() content = %q, %v, want %q", content, err, "image")
+	}
+	if object.Size != 5 || object.ContentType != "image/jpeg" || object.ETag == "" {
+		t.Errorf("LocalImageStorage.Open() object = %+v", object)
+	}
+
+	// temp file of save is not left in root path
+	entries, _ := os.ReadDir(s.rootPath)
+	if len(entries) != 1 {
+		t.Errorf("LocalImageStorage.Save() left %d files in root path, want 1", len(entries))
+	}
+
+	if err := s.Delete(context.TODO(), "spider.jpeg"); err != nil {
+		t.Errorf("LocalImageStorage.Delete() error = %v", err)
+	}
+	if _, err := s.Stat(context.TODO(), "spider.jpeg"); err != ErrorImageNotFound {
+		t.Errorf("LocalImageStorage.Stat() error = %v, wantErr %v", err, ErrorImageNotFound)
+	}
+
+	// delete of image that is not found is not error
+	if err := s.Delete(context.TODO(), "spider.jpeg"); err != nil {
+		t.Errorf("LocalImageStorage.Delete() error = %v", err)
+	}
+}
+
+// **********************************************************************
@@ -0,0 +1,426 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"spider-go/config"
+	"spider-go/model"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	S3_SIGN_ALGORITHM    = "AWS4-HMAC-SHA256"
+	S3_UNSIGNED_PAYLOAD  = "UNSIGNED-PAYLOAD"
+	S3_AMZ_DATE_FORMAT   = "20060102T150405Z"
+	S3_SCOPE_DATE_FORMAT = "20060102"
+)
+
+// S3ImageStorage keep image as object in bucket of S3 or S3-compatible service,
+// request is signed with signature version 4 and payload is not signed so content can be streamed
+type S3ImageStorage struct {
+	conf   config.S3Storage
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewS3ImageStorage(conf config.S3Storage) *S3ImageStorage {
+	return &S3ImageStorage{
+		conf:   conf,
+		client: &http.Client{Timeout: 5 * time.Minute},
+		now:    time.Now,
+	}
+}
+
+func (s *S3ImageStorage) Save(ctx context.Context, name string, content io.Reader, size int64, contentType string) error {
+	if err := validateImageName(name); err != nil {
+		return err
+	}
+
+	// PUT object require content length, content that size is unknown is spooled to temp file
+	if size < 0 {
+		spool, err := os.CreateTemp("", ".s3-upload.*.tmp")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(spool.Name())
+		defer spool.Close()
+
+		if size, err = io.Copy(spool, content); err != nil {
+			return err
+		}
+		if _, err := spool.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		content = spool
+	}
+
+	req, err := s.newRequest(ctx, http.MethodPut, name, content)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+func (s *S3ImageStorage) Open(ctx context.Context, name string) (io.ReadCloser, *model.ImageObject, error) {
+	if err := validateImageName(name); err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodGet, name, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	object := s.imageObject(name, resp)
+
+	// object without content length can only be read from start
+	if resp.ContentLength < 0 {
+		return resp.Body, object, nil
+	}
+
+	return &s3Object{
+		storage:  s,
+		ctx:      ctx,
+		name:     name,
+		size:     resp.ContentLength,
+		body:     resp.Body,
+		bodyFrom: 0,
+	}, object, nil
+}
+
+func (s *S3ImageStorage) Stat(ctx context.Context, name string) (*model.ImageObject, error) {
+	if err := validateImageName(name); err != nil {
+		return nil, err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodHead, name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return s.imageObject(name, resp), nil
+}
+
+// Delete of S3 succeed even object is not found
+func (s *S3ImageStorage) Delete(ctx context.Context, name string) error {
+	if err := validateImageName(name); err != nil {
+		return err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodDelete, name, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		if err == ErrorImageNotFound {
+			return nil
+		}
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// *************************************************
+
+// s3Object is body of object that can be seeked, so image can be served with range request.
+// after seek, next read request the rest of object from new offset by ranged get,
+// body that is already opened is used when offset is not changed
+type s3Object struct {
+	storage *S3ImageStorage
+	ctx     context.Context
+	name    string
+	size    int64
+	offset  int64
+	body    io.ReadCloser
+	// offset of object that body is read from
+	bodyFrom int64
+}
+
+func (o *s3Object) Read(p []byte) (int, error) {
+	if o.offset >= o.size {
+		return 0, io.EOF
+	}
+
+	if o.body != nil && o.bodyFrom != o.offset {
+		o.body.Close()
+		o.body = nil
+	}
+
+	if o.body == nil {
+		body, err := o.storage.openRange(o.ctx, o.name, o.offset)
+		if err != nil {
+			return 0, err
+		}
+		o.body = body
+		o.bodyFrom = o.offset
+	}
+
+	n, err := o.body.Read(p)
+	o.offset += int64(n)
+	o.bodyFrom += int64(n)
+
+	return n, err
+}
+
+func (o *s3Object) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += o.offset
+	case io.SeekEnd:
+		offset += o.size
+	default:
+		return 0, ErrorInvalidSeek
+	}
+
+	if offset < 0 {
+		return 0, ErrorInvalidSeek
+	}
+
+	o.offset = offset
+
+	return offset, nil
+}
+
+func (o *s3Object) Close() error {
+	if o.body == nil {
+		return nil
+	}
+	return o.body.Close()
+}
+
+// openRange get object from offset to the end
+func (s *S3ImageStorage) openRange(ctx context.Context, name string, offset int64) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, name, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// server that ignore range send whole object
+	if resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return nil, fmt.Errorf("[image storage] s3 range get `%s` failed, status: %d", req.URL.Path, resp.StatusCode)
+	}
+
+	return resp.Body, nil
+}
+
+func (s *S3ImageStorage) objectURL(name string) (*url.URL, error) {
+	endpoint, err := url.Parse(s.conf.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	key := s.conf.Prefix + name
+
+	if s.conf.UsePathStyle {
+		endpoint.Path = "/" + s.conf.Bucket + "/" + key
+	} else {
+		endpoint.Host = s.conf.Bucket + "." + endpoint.Host
+		endpoint.Path = "/" + key
+	}
+
+	return endpoint, nil
+}
+
+func (s *S3ImageStorage) newRequest(ctx context.Context, method, name string, body io.Reader) (*http.Request, error) {
+	objectURL, err := s.objectURL(name)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, objectURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	// path is sent as it is signed
+	req.URL.RawPath = s3EscapePath(req.URL.Path)
+
+	return req, nil
+}
+
+// do sign and send request, response that is not success is closed and returned as error
+func (s *S3ImageStorage) do(req *http.Request) (*http.Response, error) {
+	s.sign(req, s.now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrorImageNotFound
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	return nil, fmt.Errorf("[image storage] s3 %s `%s` failed, status: %d, response: %s", req.Method, req.URL.Path, resp.StatusCode, message)
+}
+
+// sign add authorization header of signature version 4, payload is not signed unless
+// hash of payload is already set in x-amz-content-sha256
+func (s *S3ImageStorage) sign(req *http.Request, t time.Time) {
+	amzDate := t.Format(S3_AMZ_DATE_FORMAT)
+	scopeDate := t.Format(S3_SCOPE_DATE_FORMAT)
+
+	payloadHash := req.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash == "" {
+		payloadHash = S3_UNSIGNED_PAYLOAD
+	}
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	// host, content type, range and every amz header are signed
+	headerValues := map[string]string{
+		"host": req.URL.Host,
+	}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "content-type" || name == "range" || strings.HasPrefix(name, "x-amz-") {
+			headerValues[name] = strings.Join(values, ",")
+		}
+	}
+
+	signedHeaders := make([]string, 0, len(headerValues))
+	for header := range headerValues {
+		signedHeaders = append(signedHeaders, header)
+	}
+	sort.Strings(signedHeaders)
+
+	var canonicalHeaders strings.Builder
+	for _, header := range signedHeaders {
+		canonicalHeaders.WriteString(header + ":" + strings.TrimSpace(headerValues[header]) + "\n")
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		// query is sorted by key and space is encoded as %20
+		strings.ReplaceAll(req.URL.Query().Encode(), "+", "%20"),
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{scopeDate, s.conf.Region, "s3", "aws4_request"}, "/")
+
+	stringToSign := strings.Join([]string{
+		S3_SIGN_ALGORITHM,
+		amzDate,
+		scope,
+		hex.EncodeToString(sha256Sum([]byte(canonicalRequest))),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.conf.SecretKey), []byte(scopeDate))
+	signingKey = hmacSHA256(signingKey, []byte(s.conf.Region))
+	signingKey = hmacSHA256(signingKey, []byte("s3"))
+	signingKey = hmacSHA256(signingKey, []byte("aws4_request"))
+
+	signature := hex.EncodeToString(hmacSHA256(signingKey, []byte(stringToSign)))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		S3_SIGN_ALGORITHM,
+		s.conf.AccessKey,
+		scope,
+		strings.Join(signedHeaders, ";"),
+		signature,
+	))
+}
+
+func (s *S3ImageStorage) imageObject(name string, resp *http.Response) *model.ImageObject {
+	object := &model.ImageObject{
+		Name:        name,
+		ContentType: resp.Header.Get("Content-Type"),
+		ETag:        resp.Header.Get("ETag"),
+	}
+
+	if object.ContentType == "" {
+		object.ContentType = contentTypeByName(name)
+	}
+
+	if size, err := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64); err == nil {
+		object.Size = size
+	}
+
+	if modTime, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		object.ModTime = modTime
+	}
+
+	return object
+}
+
+// s3EscapePath encode every byte except unreserved character and slash as required by signature version 4
+func s3EscapePath(path string) string {
+	var escaped strings.Builder
+
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			escaped.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&escaped, "%%%02X", c)
+	}
+
+	return escaped.String()
+}
+
+func sha256Sum(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
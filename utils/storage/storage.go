@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"errors"
+	"mime"
+	"path"
+	"spider-go/config"
+	"spider-go/domain"
+	"strings"
+)
+
+var (
+	STORAGE_TYPE_LOCAL = "local"
+	STORAGE_TYPE_S3    = "s3"
+)
+
+var (
+	ErrorImageNotFound    = errors.New("[image storage] image not found")
+	ErrorInvalidImageName = errors.New("[image storage] invalid image name")
+	ErrorInvalidSeek      = errors.New("[image storage] invalid seek offset")
+)
+
+// NewImageStorage select image storage by config, local storage is default
+func NewImageStorage(conf config.File) domain.ImageStorage {
+	if conf.StorageType == STORAGE_TYPE_S3 {
+		return NewS3ImageStorage(conf.S3)
+	}
+	return NewLocalImageStorage(conf.FileImagePath)
+}
+
+// validateImageName allow only plain file name so image can not be read or written outside of storage
+func validateImageName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, "/\\") {
+		return ErrorInvalidImageName
+	}
+	return nil
+}
+
+// DerivativeImageName is name of resized image of original image, e.g. `name_thumbnail.png`
+func DerivativeImageName(name, size string) string {
+	extension := path.Ext(name)
+	return strings.TrimSuffix(name, extension) + "_" + size + extension
+}
+
+// contentTypeByName is used when storage has no content type of image
+func contentTypeByName(name string) string {
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		return "application/octet-stream"
+	}
+	return contentType
+}
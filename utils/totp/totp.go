@@ -0,0 +1,99 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// parameter of RFC 6238 that every authenticator app support
+var (
+	SECRET_SIZE = 20
+	DIGITS      = 6
+	PERIOD      = int64(30)
+	// accepted step before and after current step for clock drift
+	SKEW = int64(1)
+)
+
+var b32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret return base32 secret without padding
+func GenerateSecret() (string, error) {
+	secret := make([]byte, SECRET_SIZE)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return b32.EncodeToString(secret), nil
+}
+
+// URI return otpauth uri for enroll by scan qr code
+func URI(issuer, accountName, secret string) string {
+	label := url.PathEscape(issuer + ":" + accountName)
+
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(DIGITS))
+	query.Set("period", fmt.Sprint(PERIOD))
+
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// Step return time step of t
+func Step(t time.Time) int64 {
+	return t.Unix() / PERIOD
+}
+
+// GenerateCode return code of secret at time step
+func GenerateCode(secret string, step int64) (string, error) {
+	key, err := b32.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	// dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < DIGITS; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", DIGITS, value%mod), nil
+}
+
+// Validate return matched time step of code, step is used for reject code that already used
+func Validate(secret, code string, t time.Time) (step int64, ok bool) {
+	if len(code) != DIGITS {
+		return 0, false
+	}
+
+	current := Step(t)
+
+	for s := current - SKEW; s <= current+SKEW; s++ {
+		expected, err := GenerateCode(secret, s)
+		if err != nil {
+			return 0, false
+		}
+		if hmac.Equal([]byte(expected), []byte(code)) {
+			return s, true
+		}
+	}
+
+	return 0, false
+}